@@ -0,0 +1,72 @@
+// Package main runs a standalone mock bidder for test=1 auctions, QA and offline load tests
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/mockbidder"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+func main() {
+	port := flag.String("port", getEnvOrDefault("MOCK_BIDDER_PORT", "8090"), "Listen port")
+	configPath := flag.String("config", os.Getenv("MOCK_BIDDER_CONFIG"), "JSON file with per-bidder response profiles")
+	seed := flag.Int64("seed", 0, "Random seed for reproducible responses (0 = time-based)")
+	flag.Parse()
+
+	logger.Init(logger.DefaultConfig())
+	log := logger.Log
+
+	config := mockbidder.DefaultConfig()
+	if *configPath != "" {
+		loaded, err := mockbidder.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid mock bidder config")
+		}
+		config = loaded
+	}
+	if *seed != 0 {
+		config.Seed = *seed
+	}
+
+	server := &http.Server{
+		Addr:              ":" + *port,
+		Handler:           mockbidder.NewHandler(mockbidder.New(config)),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		log.Info().
+			Str("port", *port).
+			Int("profiles", len(config.Bidders)).
+			Msg("Mock bidder listening")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Mock bidder server error")
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Mock bidder forced to shutdown")
+	}
+}
+
+// getEnvOrDefault returns the environment variable value or a default
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
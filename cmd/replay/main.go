@@ -0,0 +1,174 @@
+// Package main replays captured auctions and diffs the outcomes of two builds.
+//
+//	replay run -in capture.jsonl -out baseline.jsonl     # on the baseline build
+//	replay run -in capture.jsonl -out candidate.jsonl    # on the candidate build
+//	replay diff baseline.jsonl candidate.jsonl           # exits 1 on differences
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/appnexus"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/kargo"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/oms"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/pubmatic"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/rubicon"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/sovrn"
+	_ "github.com/thenexusengine/tne_springwire/internal/adapters/triplelift"
+	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/replay"
+	"github.com/thenexusengine/tne_springwire/pkg/currency"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "run":
+		err = runCommand(os.Args[2:])
+	case "diff":
+		var changed bool
+		changed, err = diffCommand(os.Args[2:])
+		if err == nil && changed {
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "replay:", err)
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: replay run -in capture.jsonl -out results.jsonl [flags]")
+	fmt.Fprintln(os.Stderr, "       replay diff baseline.jsonl candidate.jsonl")
+	os.Exit(2)
+}
+
+// runCommand replays every captured auction and writes one result per line
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	in := fs.String("in", "", "Captured auctions (JSONL, see AUCTION_CAPTURE_PATH)")
+	out := fs.String("out", "", "Results file (default stdout)")
+	auctionType := fs.Int("auction-type", int(exchange.FirstPriceAuction), "1 = first price, 2 = second price")
+	priceIncrement := fs.Float64("price-increment", 0.01, "Second-price increment")
+	minBidPrice := fs.Float64("min-bid-price", 0, "Minimum valid bid price")
+	ratesFile := fs.String("rates-file", "", "Prebid currency file for non-USD bids")
+	aliases := fs.String("aliases", "", "Bidder aliases JSON, as in BIDDER_ALIASES")
+	verbose := fs.Bool("v", false, "Log exchange output")
+	_ = fs.Parse(args)
+
+	if *in == "" {
+		return fmt.Errorf("-in is required")
+	}
+	logConfig := logger.DefaultConfig()
+	if !*verbose {
+		logConfig.Level = "error"
+	}
+	logger.Init(logConfig)
+
+	if *aliases != "" {
+		var configs map[string]adapters.AliasConfig
+		if err := json.Unmarshal([]byte(*aliases), &configs); err != nil {
+			return fmt.Errorf("invalid -aliases: %w", err)
+		}
+		for alias, config := range configs {
+			if err := adapters.DefaultRegistry.RegisterAlias(alias, config); err != nil {
+				return fmt.Errorf("invalid alias %s: %w", alias, err)
+			}
+		}
+	}
+
+	config := exchange.DefaultConfig()
+	config.IDREnabled = false
+	config.EventRecordEnabled = false
+	config.AuctionType = exchange.AuctionType(*auctionType)
+	config.PriceIncrement = *priceIncrement
+	config.MinBidPrice = *minBidPrice
+	if *ratesFile != "" {
+		currencyConfig := currency.DefaultConfig()
+		currencyConfig.Providers = []string{"file"}
+		currencyConfig.RatesFile = *ratesFile
+		converter := currency.NewConverter(currencyConfig)
+		if err := converter.Start(context.Background()); err != nil {
+			return fmt.Errorf("loading rates: %w", err)
+		}
+		defer converter.Stop()
+		config.CurrencyConverter = converter
+	}
+	ex := exchange.New(adapters.DefaultRegistry, config)
+	defer ex.Close()
+
+	input, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	output := os.Stdout
+	if *out != "" {
+		output, err = os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+	}
+	enc := json.NewEncoder(output)
+
+	count := 0
+	err = replay.ReadRecordings(input, func(rec *exchange.RecordedAuction) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		count++
+		return enc.Encode(replay.Run(ctx, ex, rec))
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "replayed %d auctions\n", count)
+	return nil
+}
+
+// diffCommand compares two result files; it reports whether anything changed
+func diffCommand(args []string) (bool, error) {
+	if len(args) != 2 {
+		usage()
+	}
+	baseline, err := readResults(args[0])
+	if err != nil {
+		return false, err
+	}
+	candidate, err := readResults(args[1])
+	if err != nil {
+		return false, err
+	}
+
+	report := replay.Compare(baseline, candidate)
+	fmt.Print(report.String())
+	return report.HasDifferences(), nil
+}
+
+func readResults(path string) (map[string]*replay.Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	results, err := replay.ReadResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
@@ -1,20 +1,26 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
 	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+	"github.com/thenexusengine/tne_springwire/pkg/tracing"
 )
 
 // ServerConfig holds all server configuration
 type ServerConfig struct {
 	// Server
-	Port    string
-	Timeout time.Duration
+	Port                string
+	Timeout             time.Duration
+	BidderNetworkBuffer time.Duration // Held back from tmax to collect and return bids
 
 	// Database
 	DatabaseConfig *DatabaseConfig
@@ -27,9 +33,44 @@ type ServerConfig struct {
 	IDRUrl     string
 	IDRAPIKey  string
 
+	// In-process bidder selection when IDR is unavailable
+	FallbackSelectorEnabled     bool
+	FallbackSelectorMaxBidders  int
+	FallbackSelectorExploration float64 // Chance each slot goes to a random bidder (0-1)
+
+	// Runtime config (FPD, EID sources, max bidders, timeouts) pulled while running
+	RuntimeConfigFile         string        // Local JSON file source
+	RuntimeConfigFromIDR      bool          // Use IDR's /api/config as the source
+	RuntimeConfigSyncInterval time.Duration // Poll interval
+
+	// Durable IDR event delivery
+	EventSpoolDir   string // Spool directory for IDR bid events (empty = in-memory only)
+	EventSpoolMaxMB int    // Disk quota for the spool
+
 	// Currency
 	CurrencyConversionEnabled bool
 	DefaultCurrency           string
+	CurrencyRatesFile         string   // Local Prebid currency file for the "file" rate provider
+	CurrencyProviders         []string // Rate providers in priority order (empty = admin, http, file)
+
+	// Bidders
+	BidderAliases         string // JSON object of alias code -> adapters.AliasConfig
+	BidderCircuitBreakers string // JSON object of bidder code ("*" = all bidders) -> breaker settings, for bidders without database settings
+	SharedCircuitBreakers bool   // Share bidder breaker state across replicas through Redis
+
+	// Per-bidder timeouts
+	BidderTimeouts            string  // JSON object of bidder code -> timeout in ms
+	AdaptiveTimeoutsEnabled   bool    // Lower bidder timeouts to their observed latency percentile
+	AdaptiveTimeoutPercentile float64 // 0.95 or 0.99
+
+	// Test mode (test=1 requests are answered by a mock bidder)
+	MockBidderEnabled bool
+	MockBidderURL     string // cmd/mockbidder server (empty = in-process mock)
+	MockBidderConfig  string // JSON file with per-bidder mock response profiles
+
+	// Auction capture for offline replay (cmd/replay)
+	AuctionCapturePath       string  // JSONL file of recorded auctions (empty = disabled)
+	AuctionCaptureSampleRate float64 // Share of auctions recorded (0-1)
 
 	// Privacy
 	DisableGDPREnforcement bool
@@ -39,6 +80,22 @@ type ServerConfig struct {
 
 	// CORS
 	CORSOrigins []string
+
+	// Analytics
+	AnalyticsFilePath  string // JSONL file for the file logger module (empty = disabled)
+	AnalyticsDBEnabled bool   // Write auctions to the analytics tables in PostgreSQL
+	AnalyticsQueueSize int    // Per-module queue depth
+
+	// Distributed tracing (OTLP over HTTP)
+	TracingEnabled             bool
+	TracingEndpoint            string  // Collector host:port
+	TracingInsecure            bool    // Plain HTTP to the collector
+	TracingSampleRate          float64 // Share of requests traced (0-1)
+	TracingEndpointSampleRates string  // JSON object of URL path prefix -> sample rate
+
+	// Live config file (rate limits, CORS, size limits, privacy, IVT), reloaded on SIGHUP or change
+	ConfigFile          string        // YAML or JSON file (empty = environment only)
+	ConfigWatchInterval time.Duration // File poll interval (0 = reload on SIGHUP only)
 }
 
 // DatabaseConfig holds database connection configuration
@@ -64,16 +121,47 @@ func ParseConfig() *ServerConfig {
 	flag.Parse()
 
 	cfg := &ServerConfig{
-		Port:                      *port,
-		Timeout:                   *timeout,
-		RedisURL:                  os.Getenv("REDIS_URL"),
-		IDREnabled:                *idrEnabled,
-		IDRUrl:                    *idrURL,
-		IDRAPIKey:                 os.Getenv("IDR_API_KEY"),
-		CurrencyConversionEnabled: os.Getenv("CURRENCY_CONVERSION_ENABLED") != "false",
-		DefaultCurrency:           "USD",
-		DisableGDPREnforcement:    os.Getenv("PBS_DISABLE_GDPR_ENFORCEMENT") == "true",
-		HostURL:                   getEnvOrDefault("PBS_HOST_URL", "https://ads.thenexusengine.com"),
+		Port:                        *port,
+		Timeout:                     *timeout,
+		BidderNetworkBuffer:         time.Duration(getEnvIntOrDefault("BIDDER_NETWORK_BUFFER_MS", 50)) * time.Millisecond,
+		RedisURL:                    os.Getenv("REDIS_URL"),
+		IDREnabled:                  *idrEnabled,
+		IDRUrl:                      *idrURL,
+		IDRAPIKey:                   os.Getenv("IDR_API_KEY"),
+		FallbackSelectorEnabled:     getEnvBoolOrDefault("FALLBACK_SELECTOR_ENABLED", false),
+		FallbackSelectorMaxBidders:  getEnvIntOrDefault("FALLBACK_SELECTOR_MAX_BIDDERS", 8),
+		FallbackSelectorExploration: getEnvFloatOrDefault("FALLBACK_SELECTOR_EXPLORATION_RATE", 0.1),
+		RuntimeConfigFile:           os.Getenv("RUNTIME_CONFIG_FILE"),
+		RuntimeConfigFromIDR:        getEnvBoolOrDefault("RUNTIME_CONFIG_FROM_IDR", false),
+		RuntimeConfigSyncInterval:   time.Duration(getEnvIntOrDefault("RUNTIME_CONFIG_SYNC_INTERVAL_SECONDS", 60)) * time.Second,
+		EventSpoolDir:               os.Getenv("IDR_EVENT_SPOOL_DIR"),
+		EventSpoolMaxMB:             getEnvIntOrDefault("IDR_EVENT_SPOOL_MAX_MB", 512),
+		CurrencyConversionEnabled:   os.Getenv("CURRENCY_CONVERSION_ENABLED") != "false",
+		DefaultCurrency:             "USD",
+		CurrencyRatesFile:           os.Getenv("CURRENCY_RATES_FILE"),
+		BidderAliases:               os.Getenv("BIDDER_ALIASES"),
+		BidderCircuitBreakers:       os.Getenv("BIDDER_CIRCUIT_BREAKERS"),
+		SharedCircuitBreakers:       getEnvBoolOrDefault("SHARED_CIRCUIT_BREAKERS_ENABLED", false),
+		BidderTimeouts:              os.Getenv("BIDDER_TIMEOUTS"),
+		AdaptiveTimeoutsEnabled:     getEnvBoolOrDefault("ADAPTIVE_BIDDER_TIMEOUTS_ENABLED", false),
+		AdaptiveTimeoutPercentile:   getEnvFloatOrDefault("ADAPTIVE_BIDDER_TIMEOUT_PERCENTILE", 0.95),
+		MockBidderEnabled:           getEnvBoolOrDefault("MOCK_BIDDER_ENABLED", false),
+		MockBidderURL:               os.Getenv("MOCK_BIDDER_URL"),
+		MockBidderConfig:            os.Getenv("MOCK_BIDDER_CONFIG"),
+		AuctionCapturePath:          os.Getenv("AUCTION_CAPTURE_PATH"),
+		AuctionCaptureSampleRate:    getEnvFloatOrDefault("AUCTION_CAPTURE_SAMPLE_RATE", 0.01),
+		DisableGDPREnforcement:      os.Getenv("PBS_DISABLE_GDPR_ENFORCEMENT") == "true",
+		HostURL:                     getEnvOrDefault("PBS_HOST_URL", "https://ads.thenexusengine.com"),
+		AnalyticsFilePath:           os.Getenv("ANALYTICS_FILE_PATH"),
+		AnalyticsDBEnabled:          getEnvBoolOrDefault("ANALYTICS_DB_ENABLED", false),
+		AnalyticsQueueSize:          getEnvIntOrDefault("ANALYTICS_QUEUE_SIZE", 1000),
+		TracingEnabled:              getEnvBoolOrDefault("TRACING_ENABLED", false),
+		TracingEndpoint:             getEnvOrDefault("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+		TracingInsecure:             getEnvBoolOrDefault("TRACING_OTLP_INSECURE", true),
+		TracingSampleRate:           getEnvFloatOrDefault("TRACING_SAMPLE_RATE", 0.01),
+		TracingEndpointSampleRates:  os.Getenv("TRACING_ENDPOINT_SAMPLE_RATES"),
+		ConfigFile:                  os.Getenv("CONFIG_FILE"),
+		ConfigWatchInterval:         time.Duration(getEnvIntOrDefault("CONFIG_WATCH_INTERVAL_SECONDS", 5)) * time.Second,
 	}
 
 	// Parse database config if DB_HOST is set
@@ -104,22 +192,158 @@ func ParseConfig() *ServerConfig {
 		cfg.CORSOrigins = origins
 	}
 
+	if providers := os.Getenv("CURRENCY_PROVIDERS"); providers != "" {
+		cfg.CurrencyProviders = splitAndTrim(providers, ",")
+	}
+
 	return cfg
 }
 
 // ToExchangeConfig converts ServerConfig to exchange.Config
 func (c *ServerConfig) ToExchangeConfig() *exchange.Config {
+	adaptive := exchange.DefaultAdaptiveTimeoutConfig()
+	adaptive.Enabled = c.AdaptiveTimeoutsEnabled
+	if c.AdaptiveTimeoutPercentile > 0 {
+		adaptive.Percentile = c.AdaptiveTimeoutPercentile
+	}
+	bidderTimeouts, _ := c.ParseBidderTimeouts()        // Checked by Validate
+	bidderBreakers, _ := c.ParseBidderCircuitBreakers() // Checked by Validate
+
+	fallback := idr.DefaultBanditConfig()
+	fallback.Enabled = c.FallbackSelectorEnabled
+	if c.FallbackSelectorMaxBidders > 0 {
+		fallback.MaxBidders = c.FallbackSelectorMaxBidders
+	}
+	fallback.ExplorationRate = c.FallbackSelectorExploration
+
+	var eventSpool *idr.SpoolConfig
+	if c.EventSpoolDir != "" {
+		eventSpool = idr.DefaultSpoolConfig(c.EventSpoolDir)
+		eventSpool.MaxDiskBytes = int64(c.EventSpoolMaxMB) << 20
+	}
+
 	return &exchange.Config{
-		DefaultTimeout:     c.Timeout,
-		MaxBidders:         50,
-		IDREnabled:         c.IDREnabled,
-		IDRServiceURL:      c.IDRUrl,
-		IDRAPIKey:          c.IDRAPIKey,
-		EventRecordEnabled: true,
-		EventBufferSize:    100,
-		CurrencyConv:       c.CurrencyConversionEnabled,
-		DefaultCurrency:    c.DefaultCurrency,
+		DefaultTimeout:        c.Timeout,
+		BidderNetworkBuffer:   c.BidderNetworkBuffer,
+		BidderTimeouts:        bidderTimeouts,
+		AdaptiveTimeouts:      adaptive,
+		BidderCircuitBreakers: bidderBreakers,
+		FallbackSelector:      fallback,
+		MaxBidders:            50,
+		IDREnabled:            c.IDREnabled,
+		IDRServiceURL:         c.IDRUrl,
+		IDRAPIKey:             c.IDRAPIKey,
+		EventRecordEnabled:    true,
+		EventBufferSize:       100,
+		EventSpool:            eventSpool,
+		CurrencyConv:          c.CurrencyConversionEnabled,
+		DefaultCurrency:       c.DefaultCurrency,
+	}
+}
+
+// ParseBidderTimeouts parses the configured static bidder timeouts, e.g. {"rubicon": 300, "appnexus": 250}
+func (c *ServerConfig) ParseBidderTimeouts() (map[string]time.Duration, error) {
+	if c.BidderTimeouts == "" {
+		return nil, nil
+	}
+	var timeoutsMS map[string]int
+	if err := json.Unmarshal([]byte(c.BidderTimeouts), &timeoutsMS); err != nil {
+		return nil, fmt.Errorf("invalid BIDDER_TIMEOUTS: %w", err)
+	}
+	timeouts := make(map[string]time.Duration, len(timeoutsMS))
+	for code, ms := range timeoutsMS {
+		if ms <= 0 {
+			return nil, fmt.Errorf("invalid BIDDER_TIMEOUTS: %s timeout must be positive, got %d", code, ms)
+		}
+		timeouts[code] = time.Duration(ms) * time.Millisecond
+	}
+	return timeouts, nil
+}
+
+// ParseBidderCircuitBreakers parses the fallback per-bidder circuit breaker settings, e.g.
+// {"*": {"failure_threshold": 5}, "rubicon": {"error_rate": 0.5, "window_seconds": 60, "min_requests": 50}}.
+// Settings in a bidder's database configuration take precedence over these.
+func (c *ServerConfig) ParseBidderCircuitBreakers() (map[string]*idr.CircuitBreakerConfig, error) {
+	if c.BidderCircuitBreakers == "" {
+		return nil, nil
+	}
+	var settings map[string]storage.BidderCircuitBreaker
+	if err := json.Unmarshal([]byte(c.BidderCircuitBreakers), &settings); err != nil {
+		return nil, fmt.Errorf("invalid BIDDER_CIRCUIT_BREAKERS: %w", err)
+	}
+
+	breakers := make(map[string]*idr.CircuitBreakerConfig, len(settings))
+	for code, s := range settings {
+		config, err := bidderCircuitBreakerConfig(&s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BIDDER_CIRCUIT_BREAKERS: %s: %w", code, err)
+		}
+		breakers[code] = config
+	}
+	return breakers, nil
+}
+
+// bidderCircuitBreakerConfig applies a bidder's breaker settings over the exchange defaults.
+// Unset fields take the defaults; a positive error rate switches the bidder to error-rate mode.
+func bidderCircuitBreakerConfig(s *storage.BidderCircuitBreaker) (*idr.CircuitBreakerConfig, error) {
+	config := exchange.DefaultBidderCircuitBreakerConfig()
+	if s.FailureThreshold != 0 {
+		config.FailureThreshold = s.FailureThreshold
+	}
+	if s.SuccessThreshold != 0 {
+		config.SuccessThreshold = s.SuccessThreshold
+	}
+	if s.TimeoutSeconds != 0 {
+		config.Timeout = time.Duration(s.TimeoutSeconds) * time.Second
+	}
+	if s.WindowSeconds != 0 {
+		config.Window = time.Duration(s.WindowSeconds) * time.Second
+	}
+	if s.MinRequests != 0 {
+		config.MinRequests = s.MinRequests
+	}
+	config.ErrorRateThreshold = s.ErrorRate
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// TracingConfig builds the tracing configuration, parsing per-endpoint sample rates such as
+// {"/openrtb2/auction": 0.05, "/health": 0}
+func (c *ServerConfig) TracingConfig() (*tracing.Config, error) {
+	config := tracing.DefaultConfig()
+	config.Enabled = c.TracingEnabled
+	config.Endpoint = c.TracingEndpoint
+	config.Insecure = c.TracingInsecure
+	config.SampleRate = c.TracingSampleRate
+	if c.TracingEndpointSampleRates != "" {
+		if err := json.Unmarshal([]byte(c.TracingEndpointSampleRates), &config.EndpointSampleRates); err != nil {
+			return nil, fmt.Errorf("invalid TRACING_ENDPOINT_SAMPLE_RATES: %w", err)
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
+	return config, nil
+}
+
+// ParseBidderAliases parses the configured bidder aliases, e.g.
+// {"rubicon2": {"aliasOf": "rubicon", "endpoint": "https://...", "gvlVendorId": 52}}
+func (c *ServerConfig) ParseBidderAliases() (map[string]adapters.AliasConfig, error) {
+	if c.BidderAliases == "" {
+		return nil, nil
+	}
+	var aliases map[string]adapters.AliasConfig
+	if err := json.Unmarshal([]byte(c.BidderAliases), &aliases); err != nil {
+		return nil, fmt.Errorf("invalid BIDDER_ALIASES: %w", err)
+	}
+	for alias, config := range aliases {
+		if config.AliasOf == "" {
+			return nil, fmt.Errorf("invalid BIDDER_ALIASES: %s is missing aliasOf", alias)
+		}
+	}
+	return aliases, nil
 }
 
 // getEnvOrDefault returns the environment variable value or a default
@@ -152,6 +376,19 @@ func getEnvIntOrDefault(key string, defaultValue int) int {
 	return intVal
 }
 
+// getEnvFloatOrDefault returns the environment variable as float64 or a default
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	floatVal, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return floatVal
+}
+
 // splitAndTrim splits a string by delimiter and trims whitespace from each part
 func splitAndTrim(s, delimiter string) []string {
 	parts := []string{}
@@ -241,6 +478,10 @@ func (c *ServerConfig) Validate() error {
 		return fmt.Errorf("timeout must be less than 30s, got %v", c.Timeout)
 	}
 
+	if c.BidderNetworkBuffer < 0 || c.BidderNetworkBuffer >= c.Timeout {
+		return fmt.Errorf("bidder network buffer must be in range 0-%v, got %v", c.Timeout, c.BidderNetworkBuffer)
+	}
+
 	// Validate IDR configuration when enabled
 	if c.IDREnabled {
 		if c.IDRUrl == "" {
@@ -250,6 +491,32 @@ func (c *ServerConfig) Validate() error {
 		if c.IDRAPIKey == "" {
 			return fmt.Errorf("IDR API key is required when IDR is enabled")
 		}
+
+		if c.FallbackSelectorEnabled && c.FallbackSelectorMaxBidders <= 0 {
+			return fmt.Errorf("fallback selector max bidders must be positive, got %d", c.FallbackSelectorMaxBidders)
+		}
+
+		if c.FallbackSelectorEnabled && (c.FallbackSelectorExploration < 0 || c.FallbackSelectorExploration > 1) {
+			return fmt.Errorf("fallback selector exploration rate must be in range 0-1, got %v", c.FallbackSelectorExploration)
+		}
+	}
+
+	if c.RuntimeConfigFile != "" && c.RuntimeConfigFromIDR {
+		return fmt.Errorf("runtime config source must be a file or IDR, not both")
+	}
+	if c.RuntimeConfigFromIDR && !c.IDREnabled {
+		return fmt.Errorf("runtime config from IDR requires IDR to be enabled")
+	}
+	if (c.RuntimeConfigFile != "" || c.RuntimeConfigFromIDR) && c.RuntimeConfigSyncInterval <= 0 {
+		return fmt.Errorf("runtime config sync interval must be positive, got %v", c.RuntimeConfigSyncInterval)
+	}
+
+	if c.ConfigFile != "" && c.ConfigWatchInterval < 0 {
+		return fmt.Errorf("config watch interval must not be negative, got %v", c.ConfigWatchInterval)
+	}
+
+	if c.EventSpoolDir != "" && c.EventSpoolMaxMB <= 0 {
+		return fmt.Errorf("IDR event spool max MB must be positive, got %d", c.EventSpoolMaxMB)
 	}
 
 	// Validate database configuration when present
@@ -269,6 +536,37 @@ func (c *ServerConfig) Validate() error {
 		return fmt.Errorf("default currency is required")
 	}
 
+	// Validate bidder aliases
+	if _, err := c.ParseBidderAliases(); err != nil {
+		return err
+	}
+
+	// Validate per-bidder timeouts
+	if _, err := c.ParseBidderTimeouts(); err != nil {
+		return err
+	}
+
+	// Validate per-bidder circuit breakers
+	if _, err := c.ParseBidderCircuitBreakers(); err != nil {
+		return err
+	}
+	if c.SharedCircuitBreakers && c.RedisURL == "" {
+		return fmt.Errorf("shared circuit breakers require REDIS_URL")
+	}
+	if c.AdaptiveTimeoutsEnabled && (c.AdaptiveTimeoutPercentile <= 0 || c.AdaptiveTimeoutPercentile > 1) {
+		return fmt.Errorf("adaptive timeout percentile must be in range 0-1, got %v", c.AdaptiveTimeoutPercentile)
+	}
+
+	// Validate tracing
+	if _, err := c.TracingConfig(); err != nil {
+		return err
+	}
+
+	// Validate auction capture sampling
+	if c.AuctionCapturePath != "" && (c.AuctionCaptureSampleRate < 0 || c.AuctionCaptureSampleRate > 1) {
+		return fmt.Errorf("auction capture sample rate must be in range 0-1, got %v", c.AuctionCaptureSampleRate)
+	}
+
 	// SECURITY: Validate CORS origins in production
 	if isProduction() {
 		if len(c.CORSOrigins) == 0 {
@@ -125,6 +125,21 @@ func TestParseConfig_EnvironmentOverrides(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Currency rate providers",
+			envVars: map[string]string{
+				"CURRENCY_PROVIDERS":  "file, http",
+				"CURRENCY_RATES_FILE": "/etc/pbs/rates.json",
+			},
+			validate: func(t *testing.T, cfg *ServerConfig) {
+				if len(cfg.CurrencyProviders) != 2 || cfg.CurrencyProviders[0] != "file" || cfg.CurrencyProviders[1] != "http" {
+					t.Errorf("Expected providers [file http], got %v", cfg.CurrencyProviders)
+				}
+				if cfg.CurrencyRatesFile != "/etc/pbs/rates.json" {
+					t.Errorf("Expected rates file, got '%s'", cfg.CurrencyRatesFile)
+				}
+			},
+		},
 		{
 			name: "GDPR enforcement disabled",
 			envVars: map[string]string{
@@ -470,6 +485,8 @@ func clearEnvVars(t *testing.T) {
 		"DB_SSL_MODE",
 		"REDIS_URL",
 		"CURRENCY_CONVERSION_ENABLED",
+		"CURRENCY_PROVIDERS",
+		"CURRENCY_RATES_FILE",
 		"PBS_DISABLE_GDPR_ENFORCEMENT",
 		"PBS_HOST_URL",
 	}
@@ -1676,3 +1693,258 @@ func TestParseConfig_WithCORSOrigins(t *testing.T) {
 		}
 	}
 }
+
+func TestParseBidderAliases(t *testing.T) {
+	cfg := &ServerConfig{BidderAliases: `{"rubicon2": {"aliasOf": "rubicon", "endpoint": "https://alt.example.com/bid", "gvlVendorId": 52}}`}
+	aliases, err := cfg.ParseBidderAliases()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := aliases["rubicon2"]; got.AliasOf != "rubicon" || got.Endpoint != "https://alt.example.com/bid" || got.GVLVendorID != 52 {
+		t.Errorf("Unexpected alias config: %+v", got)
+	}
+
+	for _, raw := range []string{`not json`, `{"rubicon2": {"endpoint": "https://alt.example.com"}}`} {
+		cfg := &ServerConfig{BidderAliases: raw}
+		if _, err := cfg.ParseBidderAliases(); err == nil {
+			t.Errorf("Expected error for %s", raw)
+		}
+	}
+}
+
+func TestGetEnvFloatOrDefault(t *testing.T) {
+	t.Setenv("TEST_FLOAT", "0.25")
+	if got := getEnvFloatOrDefault("TEST_FLOAT", 0.01); got != 0.25 {
+		t.Errorf("Expected 0.25, got %v", got)
+	}
+	t.Setenv("TEST_FLOAT", "not-a-number")
+	if got := getEnvFloatOrDefault("TEST_FLOAT", 0.01); got != 0.01 {
+		t.Errorf("Expected default for invalid value, got %v", got)
+	}
+}
+
+func TestServerConfigValidate_AuctionCaptureSampleRate(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:                     "8000",
+		Timeout:                  time.Second,
+		HostURL:                  "https://ads.example.com",
+		DefaultCurrency:          "USD",
+		AuctionCapturePath:       "/tmp/capture.jsonl",
+		AuctionCaptureSampleRate: 1.5,
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a sample rate above 1")
+	}
+	cfg.AuctionCaptureSampleRate = 0.05
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestServerConfigValidate_BidderNetworkBuffer(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:                "8000",
+		Timeout:             time.Second,
+		BidderNetworkBuffer: time.Second,
+		HostURL:             "https://ads.example.com",
+		DefaultCurrency:     "USD",
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a buffer as long as the timeout")
+	}
+	cfg.BidderNetworkBuffer = 50 * time.Millisecond
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := cfg.ToExchangeConfig().BidderNetworkBuffer; got != 50*time.Millisecond {
+		t.Errorf("Expected the buffer passed to the exchange, got %v", got)
+	}
+}
+
+func TestParseBidderTimeouts(t *testing.T) {
+	cfg := &ServerConfig{BidderTimeouts: `{"rubicon": 300, "appnexus": 250}`}
+	timeouts, err := cfg.ParseBidderTimeouts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if timeouts["rubicon"] != 300*time.Millisecond || timeouts["appnexus"] != 250*time.Millisecond {
+		t.Errorf("Unexpected timeouts: %v", timeouts)
+	}
+	if got := cfg.ToExchangeConfig().BidderTimeouts["rubicon"]; got != 300*time.Millisecond {
+		t.Errorf("Expected the timeouts passed to the exchange, got %v", got)
+	}
+
+	for _, invalid := range []string{`{"rubicon": 0}`, `not-json`} {
+		cfg.BidderTimeouts = invalid
+		if _, err := cfg.ParseBidderTimeouts(); err == nil {
+			t.Errorf("Expected error for %s", invalid)
+		}
+	}
+}
+
+func TestToExchangeConfig_FallbackSelector(t *testing.T) {
+	cfg := &ServerConfig{
+		Timeout:                     time.Second,
+		FallbackSelectorEnabled:     true,
+		FallbackSelectorMaxBidders:  4,
+		FallbackSelectorExploration: 0.2,
+	}
+	fallback := cfg.ToExchangeConfig().FallbackSelector
+	if !fallback.Enabled || fallback.MaxBidders != 4 || fallback.ExplorationRate != 0.2 {
+		t.Errorf("Unexpected fallback selector config: %+v", fallback)
+	}
+}
+
+func TestServerConfig_EventSpool(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:            "8000",
+		Timeout:         time.Second,
+		HostURL:         "https://ads.example.com",
+		DefaultCurrency: "USD",
+		EventSpoolDir:   "/var/spool/idr",
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a spool without a disk quota")
+	}
+	cfg.EventSpoolMaxMB = 64
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	spool := cfg.ToExchangeConfig().EventSpool
+	if spool == nil || spool.Dir != "/var/spool/idr" || spool.MaxDiskBytes != 64<<20 {
+		t.Errorf("Unexpected spool config: %+v", spool)
+	}
+
+	cfg.EventSpoolDir = ""
+	if spool := cfg.ToExchangeConfig().EventSpool; spool != nil {
+		t.Errorf("Expected no spool without a directory, got %+v", spool)
+	}
+}
+
+func TestServerConfigValidate_RuntimeConfig(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:                      "8000",
+		Timeout:                   time.Second,
+		HostURL:                   "https://ads.example.com",
+		DefaultCurrency:           "USD",
+		RuntimeConfigFile:         "/etc/pbs/runtime.json",
+		RuntimeConfigSyncInterval: time.Minute,
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.RuntimeConfigFromIDR = true
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for both a file and IDR source")
+	}
+
+	cfg.RuntimeConfigFile = ""
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for an IDR source with IDR disabled")
+	}
+
+	cfg.RuntimeConfigFromIDR = false
+	cfg.RuntimeConfigFile = "/etc/pbs/runtime.json"
+	cfg.RuntimeConfigSyncInterval = 0
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a non-positive sync interval")
+	}
+}
+
+func TestServerConfigValidate_ConfigFile(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:            "8000",
+		Timeout:         time.Second,
+		HostURL:         "https://ads.example.com",
+		DefaultCurrency: "USD",
+		ConfigFile:      "/etc/pbs/live.yaml",
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error with SIGHUP-only reload: %v", err)
+	}
+
+	cfg.ConfigWatchInterval = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a negative watch interval")
+	}
+}
+
+func TestServerConfig_ParseBidderCircuitBreakers(t *testing.T) {
+	cfg := &ServerConfig{
+		BidderCircuitBreakers: `{"*": {"failure_threshold": 8}, "rubicon": {"error_rate": 0.5, "window_seconds": 30, "min_requests": 50, "timeout_seconds": 10}}`,
+	}
+	breakers, err := cfg.ParseBidderCircuitBreakers()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if all := breakers["*"]; all.FailureThreshold != 8 || all.SuccessThreshold != 2 || all.ErrorRateThreshold != 0 {
+		t.Errorf("Unexpected default breaker: %+v", all)
+	}
+	if r := breakers["rubicon"]; r.ErrorRateThreshold != 0.5 || r.Window != 30*time.Second || r.MinRequests != 50 || r.Timeout != 10*time.Second {
+		t.Errorf("Unexpected rubicon breaker: %+v", r)
+	}
+	if got := cfg.ToExchangeConfig().BidderCircuitBreakers; len(got) != 2 {
+		t.Errorf("Expected breakers passed to the exchange, got %v", got)
+	}
+
+	for _, value := range []string{`not json`, `{"rubicon": {"error_rate": 2}}`, `{"rubicon": {"failure_threshold": -1}}`} {
+		cfg.BidderCircuitBreakers = value
+		if _, err := cfg.ParseBidderCircuitBreakers(); err == nil {
+			t.Errorf("Expected error for %s", value)
+		}
+	}
+}
+
+func TestServerConfigValidate_SharedCircuitBreakers(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:                  "8000",
+		Timeout:               time.Second,
+		HostURL:               "https://ads.example.com",
+		DefaultCurrency:       "USD",
+		SharedCircuitBreakers: true,
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for shared circuit breakers without Redis")
+	}
+	cfg.RedisURL = "redis://localhost:6379"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestServerConfigTracingConfig(t *testing.T) {
+	cfg := &ServerConfig{
+		Port:                       "8000",
+		Timeout:                    time.Second,
+		HostURL:                    "https://ads.example.com",
+		DefaultCurrency:            "USD",
+		TracingEnabled:             true,
+		TracingEndpoint:            "collector:4318",
+		TracingSampleRate:          0.1,
+		TracingEndpointSampleRates: `{"/openrtb2/auction": 0.5, "/health": 0}`,
+	}
+	tc, err := cfg.TracingConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tc.Enabled || tc.Endpoint != "collector:4318" || tc.SampleRate != 0.1 || tc.EndpointSampleRates["/openrtb2/auction"] != 0.5 {
+		t.Errorf("Unexpected tracing config: %+v", tc)
+	}
+
+	for _, rates := range []string{`{`, `{"/health": 2}`, `{"health": 0.5}`} {
+		cfg.TracingEndpointSampleRates = rates
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected error for sample rates %s", rates)
+		}
+	}
+	cfg.TracingEndpointSampleRates = ""
+	cfg.TracingSampleRate = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for a negative sample rate")
+	}
+	cfg.TracingEnabled = false
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected disabled tracing settings to be ignored, got %v", err)
+	}
+}
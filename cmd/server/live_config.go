@@ -0,0 +1,556 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// LiveSettings are the settings that can change without a restart. CONFIG_FILE overlays them
+// on the values read from the environment at startup; keys left out of the file keep (or
+// return to) their environment values.
+type LiveSettings struct {
+	RateLimit RateLimitSettings `json:"rate_limit" yaml:"rate_limit"`
+	CORS      CORSSettings      `json:"cors" yaml:"cors"`
+	SizeLimit SizeLimitSettings `json:"size_limit" yaml:"size_limit"`
+	Privacy   PrivacySettings   `json:"privacy" yaml:"privacy"`
+	IVT       IVTSettings       `json:"ivt" yaml:"ivt"`
+}
+
+// RateLimitSettings are the reloadable per-client rate limits
+type RateLimitSettings struct {
+	Enabled           bool `json:"enabled" yaml:"enabled"`
+	RequestsPerSecond int  `json:"requests_per_second" yaml:"requests_per_second"`
+	BurstSize         int  `json:"burst_size" yaml:"burst_size"`
+}
+
+// CORSSettings are the reloadable CORS settings
+type CORSSettings struct {
+	Enabled        bool     `json:"enabled" yaml:"enabled"`
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
+}
+
+// SizeLimitSettings are the reloadable request size limits
+type SizeLimitSettings struct {
+	Enabled      bool  `json:"enabled" yaml:"enabled"`
+	MaxBodyBytes int64 `json:"max_body_bytes" yaml:"max_body_bytes"`
+	MaxURLLength int   `json:"max_url_length" yaml:"max_url_length"`
+}
+
+// PrivacySettings are the reloadable privacy enforcement settings
+type PrivacySettings struct {
+	EnforceGDPR      bool  `json:"enforce_gdpr" yaml:"enforce_gdpr"`
+	EnforceCOPPA     bool  `json:"enforce_coppa" yaml:"enforce_coppa"`
+	EnforceCCPA      bool  `json:"enforce_ccpa" yaml:"enforce_ccpa"`
+	GeoEnforcement   bool  `json:"geo_enforcement" yaml:"geo_enforcement"`
+	StrictMode       bool  `json:"strict_mode" yaml:"strict_mode"`
+	AnonymizeIP      bool  `json:"anonymize_ip" yaml:"anonymize_ip"`
+	RequiredPurposes []int `json:"required_purposes" yaml:"required_purposes"`
+}
+
+// IVTSettings are the reloadable invalid traffic detection settings
+type IVTSettings struct {
+	MonitoringEnabled    bool     `json:"monitoring_enabled" yaml:"monitoring_enabled"`
+	BlockingEnabled      bool     `json:"blocking_enabled" yaml:"blocking_enabled"`
+	CheckUserAgent       bool     `json:"check_user_agent" yaml:"check_user_agent"`
+	CheckReferer         bool     `json:"check_referer" yaml:"check_referer"`
+	CheckGeo             bool     `json:"check_geo" yaml:"check_geo"`
+	RequireReferer       bool     `json:"require_referer" yaml:"require_referer"`
+	AllowedCountries     []string `json:"allowed_countries" yaml:"allowed_countries"`
+	BlockedCountries     []string `json:"blocked_countries" yaml:"blocked_countries"`
+	SuspiciousUAPatterns []string `json:"suspicious_ua_patterns" yaml:"suspicious_ua_patterns"`
+}
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Validate checks live settings before they are applied
+func (s *LiveSettings) Validate() error {
+	if s.RateLimit.RequestsPerSecond <= 0 {
+		return fmt.Errorf("rate_limit.requests_per_second must be positive, got %d", s.RateLimit.RequestsPerSecond)
+	}
+	if s.RateLimit.BurstSize <= 0 {
+		return fmt.Errorf("rate_limit.burst_size must be positive, got %d", s.RateLimit.BurstSize)
+	}
+	for _, origin := range s.CORS.AllowedOrigins {
+		if origin == "*" {
+			if isProduction() {
+				return fmt.Errorf("cors.allowed_origins: wildcard '*' is not allowed in production")
+			}
+			continue
+		}
+		if !strings.HasPrefix(origin, "https://") && !strings.HasPrefix(origin, "http://") {
+			return fmt.Errorf("cors.allowed_origins: %q must start with http:// or https://", origin)
+		}
+	}
+	if s.SizeLimit.MaxBodyBytes <= 0 {
+		return fmt.Errorf("size_limit.max_body_bytes must be positive, got %d", s.SizeLimit.MaxBodyBytes)
+	}
+	if s.SizeLimit.MaxURLLength <= 0 {
+		return fmt.Errorf("size_limit.max_url_length must be positive, got %d", s.SizeLimit.MaxURLLength)
+	}
+	for _, purpose := range s.Privacy.RequiredPurposes {
+		if purpose < middleware.PurposeStorageAccess || purpose > middleware.PurposeProductDevelopment {
+			return fmt.Errorf("privacy.required_purposes: %d is not a TCF purpose", purpose)
+		}
+	}
+	if s.IVT.BlockingEnabled && !s.IVT.MonitoringEnabled {
+		return fmt.Errorf("ivt.blocking_enabled requires ivt.monitoring_enabled")
+	}
+	for _, code := range append(append([]string(nil), s.IVT.AllowedCountries...), s.IVT.BlockedCountries...) {
+		if !countryCodePattern.MatchString(code) {
+			return fmt.Errorf("ivt: %q is not an ISO 3166-1 alpha-2 country code", code)
+		}
+	}
+	for _, pattern := range s.IVT.SuspiciousUAPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("ivt.suspicious_ua_patterns: %w", err)
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy, so overlaying a file never touches the original's slices
+func (s LiveSettings) clone() LiveSettings {
+	s.CORS.AllowedOrigins = append([]string(nil), s.CORS.AllowedOrigins...)
+	s.Privacy.RequiredPurposes = append([]int(nil), s.Privacy.RequiredPurposes...)
+	s.IVT.AllowedCountries = append([]string(nil), s.IVT.AllowedCountries...)
+	s.IVT.BlockedCountries = append([]string(nil), s.IVT.BlockedCountries...)
+	s.IVT.SuspiciousUAPatterns = append([]string(nil), s.IVT.SuspiciousUAPatterns...)
+	return s
+}
+
+// parseLiveSettings overlays a config file on base and validates the result. Files ending in
+// .json are parsed as JSON, anything else as YAML; unknown keys are rejected.
+func parseLiveSettings(path string, data []byte, base LiveSettings) (LiveSettings, error) {
+	settings := base.clone()
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&settings)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&settings)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return LiveSettings{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return LiveSettings{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// liveTargets are the running components live settings are applied to
+type liveTargets struct {
+	rateLimiter   *middleware.RateLimiter
+	cors          *middleware.CORS
+	sizeLimiter   *middleware.SizeLimiter
+	privacy       *middleware.Privacy
+	publisherAuth *middleware.PublisherAuth // Owns the IVT detector
+}
+
+// current reads the settings the components are running with
+func (t liveTargets) current() LiveSettings {
+	var s LiveSettings
+	if t.rateLimiter != nil {
+		rl := t.rateLimiter.GetConfig()
+		s.RateLimit = RateLimitSettings{Enabled: rl.Enabled, RequestsPerSecond: rl.RequestsPerSecond, BurstSize: rl.BurstSize}
+	}
+	if t.cors != nil {
+		cors := t.cors.GetConfig()
+		s.CORS = CORSSettings{Enabled: cors.Enabled, AllowedOrigins: cors.AllowedOrigins}
+	}
+	if t.sizeLimiter != nil {
+		sl := t.sizeLimiter.GetConfig()
+		s.SizeLimit = SizeLimitSettings{Enabled: sl.Enabled, MaxBodyBytes: sl.MaxBodySize, MaxURLLength: sl.MaxURLLength}
+	}
+	if t.privacy != nil {
+		p := t.privacy.GetConfig()
+		s.Privacy = PrivacySettings{
+			EnforceGDPR: p.EnforceGDPR, EnforceCOPPA: p.EnforceCOPPA, EnforceCCPA: p.EnforceCCPA,
+			GeoEnforcement: p.GeoEnforcement, StrictMode: p.StrictMode, AnonymizeIP: p.AnonymizeIP,
+			RequiredPurposes: p.RequiredPurposes,
+		}
+	}
+	if t.publisherAuth != nil {
+		if ivt := t.publisherAuth.GetIVTConfig(); ivt != nil {
+			s.IVT = IVTSettings{
+				MonitoringEnabled: ivt.MonitoringEnabled, BlockingEnabled: ivt.BlockingEnabled,
+				CheckUserAgent: ivt.CheckUserAgent, CheckReferer: ivt.CheckReferer, CheckGeo: ivt.CheckGeo,
+				RequireReferer:       ivt.RequireReferer,
+				AllowedCountries:     append([]string(nil), ivt.AllowedCountries...),
+				BlockedCountries:     append([]string(nil), ivt.BlockedCountries...),
+				SuspiciousUAPatterns: append([]string(nil), ivt.SuspiciousUAPatterns...),
+			}
+		}
+	}
+	return s
+}
+
+// changeList collects "setting: old -> new" descriptions of applied changes
+type changeList []string
+
+// diff records a change when from and to differ, reporting whether they did
+func (c *changeList) diff(name string, from, to interface{}) bool {
+	if reflect.DeepEqual(from, to) || (isEmptySlice(from) && isEmptySlice(to)) {
+		return false
+	}
+	*c = append(*c, fmt.Sprintf("%s: %v -> %v", name, from, to))
+	return true
+}
+
+// isEmptySlice treats nil and empty lists alike, so "[]" in a file is not a change
+func isEmptySlice(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Slice && rv.Len() == 0
+}
+
+// apply moves the components from their current settings to desired, calling a setter only
+// for what changed, and returns the changes made
+func (t liveTargets) apply(desired LiveSettings) []string {
+	current := t.current()
+	var changes changeList
+
+	if rl := t.rateLimiter; rl != nil {
+		if changes.diff("rate_limit.enabled", current.RateLimit.Enabled, desired.RateLimit.Enabled) {
+			rl.SetEnabled(desired.RateLimit.Enabled)
+		}
+		if changes.diff("rate_limit.requests_per_second", current.RateLimit.RequestsPerSecond, desired.RateLimit.RequestsPerSecond) {
+			rl.SetRPS(desired.RateLimit.RequestsPerSecond)
+		}
+		if changes.diff("rate_limit.burst_size", current.RateLimit.BurstSize, desired.RateLimit.BurstSize) {
+			rl.SetBurstSize(desired.RateLimit.BurstSize)
+		}
+	}
+
+	if cors := t.cors; cors != nil {
+		if changes.diff("cors.enabled", current.CORS.Enabled, desired.CORS.Enabled) {
+			cors.SetEnabled(desired.CORS.Enabled)
+		}
+		if changes.diff("cors.allowed_origins", current.CORS.AllowedOrigins, desired.CORS.AllowedOrigins) {
+			cors.SetAllowedOrigins(desired.CORS.AllowedOrigins)
+		}
+	}
+
+	if sl := t.sizeLimiter; sl != nil {
+		if changes.diff("size_limit.enabled", current.SizeLimit.Enabled, desired.SizeLimit.Enabled) {
+			sl.SetEnabled(desired.SizeLimit.Enabled)
+		}
+		if changes.diff("size_limit.max_body_bytes", current.SizeLimit.MaxBodyBytes, desired.SizeLimit.MaxBodyBytes) {
+			sl.SetMaxBodySize(desired.SizeLimit.MaxBodyBytes)
+		}
+		if changes.diff("size_limit.max_url_length", current.SizeLimit.MaxURLLength, desired.SizeLimit.MaxURLLength) {
+			sl.SetMaxURLLength(desired.SizeLimit.MaxURLLength)
+		}
+	}
+
+	if p := t.privacy; p != nil {
+		from, to := current.Privacy, desired.Privacy
+		changed := changes.diff("privacy.enforce_gdpr", from.EnforceGDPR, to.EnforceGDPR)
+		changed = changes.diff("privacy.enforce_coppa", from.EnforceCOPPA, to.EnforceCOPPA) || changed
+		changed = changes.diff("privacy.enforce_ccpa", from.EnforceCCPA, to.EnforceCCPA) || changed
+		changed = changes.diff("privacy.geo_enforcement", from.GeoEnforcement, to.GeoEnforcement) || changed
+		changed = changes.diff("privacy.strict_mode", from.StrictMode, to.StrictMode) || changed
+		changed = changes.diff("privacy.anonymize_ip", from.AnonymizeIP, to.AnonymizeIP) || changed
+		changed = changes.diff("privacy.required_purposes", from.RequiredPurposes, to.RequiredPurposes) || changed
+		if changed {
+			config := p.GetConfig()
+			config.EnforceGDPR = to.EnforceGDPR
+			config.EnforceCOPPA = to.EnforceCOPPA
+			config.EnforceCCPA = to.EnforceCCPA
+			config.GeoEnforcement = to.GeoEnforcement
+			config.StrictMode = to.StrictMode
+			config.AnonymizeIP = to.AnonymizeIP
+			config.RequiredPurposes = to.RequiredPurposes
+			p.SetConfig(config)
+		}
+	}
+
+	if t.publisherAuth != nil {
+		if ivt := t.publisherAuth.GetIVTConfig(); ivt != nil {
+			from, to := current.IVT, desired.IVT
+			changed := changes.diff("ivt.monitoring_enabled", from.MonitoringEnabled, to.MonitoringEnabled)
+			changed = changes.diff("ivt.blocking_enabled", from.BlockingEnabled, to.BlockingEnabled) || changed
+			changed = changes.diff("ivt.check_user_agent", from.CheckUserAgent, to.CheckUserAgent) || changed
+			changed = changes.diff("ivt.check_referer", from.CheckReferer, to.CheckReferer) || changed
+			changed = changes.diff("ivt.check_geo", from.CheckGeo, to.CheckGeo) || changed
+			changed = changes.diff("ivt.require_referer", from.RequireReferer, to.RequireReferer) || changed
+			changed = changes.diff("ivt.allowed_countries", from.AllowedCountries, to.AllowedCountries) || changed
+			changed = changes.diff("ivt.blocked_countries", from.BlockedCountries, to.BlockedCountries) || changed
+			changed = changes.diff("ivt.suspicious_ua_patterns", from.SuspiciousUAPatterns, to.SuspiciousUAPatterns) || changed
+			if changed {
+				// The detector is handed a new config rather than mutated, so running checks keep a consistent view
+				config := *ivt
+				config.MonitoringEnabled = to.MonitoringEnabled
+				config.BlockingEnabled = to.BlockingEnabled
+				config.CheckUserAgent = to.CheckUserAgent
+				config.CheckReferer = to.CheckReferer
+				config.CheckGeo = to.CheckGeo
+				config.RequireReferer = to.RequireReferer
+				config.AllowedCountries = to.AllowedCountries
+				config.BlockedCountries = to.BlockedCountries
+				config.SuspiciousUAPatterns = to.SuspiciousUAPatterns
+				t.publisherAuth.SetIVTConfig(&config)
+			}
+		}
+	}
+
+	return changes
+}
+
+// ConfigReloadStatus describes the config file currently applied
+type ConfigReloadStatus struct {
+	Path        string    `json:"path"`
+	Version     string    `json:"version,omitempty"`   // Content hash of the applied file
+	LoadedAt    time.Time `json:"loaded_at,omitempty"` // Last successful reload
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastChanges []string  `json:"last_changes,omitempty"` // Changes made by the last successful reload
+}
+
+// ConfigReloader applies a config file to the running server on SIGHUP and whenever the file's
+// content changes. A file that fails to parse or validate is rejected and the current settings kept.
+type ConfigReloader struct {
+	path     string
+	interval time.Duration // File poll interval (0 = reload on SIGHUP only)
+	targets  liveTargets
+	base     LiveSettings // Settings from the environment, before any file
+
+	mu       sync.Mutex // Serializes reloads
+	status   ConfigReloadStatus
+	rejected string // Version of the last file that failed, so polling doesn't retry it
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewConfigReloader creates a reloader for path; the targets' current settings become the base
+// the file is overlaid on. Call Reload to apply the file and Start to watch it.
+func NewConfigReloader(path string, interval time.Duration, targets liveTargets) *ConfigReloader {
+	return &ConfigReloader{
+		path:     path,
+		interval: interval,
+		targets:  targets,
+		base:     targets.current(),
+		status:   ConfigReloadStatus{Path: path},
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Reload reads, validates and applies the config file, returning the changes made
+func (r *ConfigReloader) Reload() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloadLocked()
+}
+
+func (r *ConfigReloader) reloadLocked() ([]string, error) {
+	r.status.LastAttempt = time.Now()
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		err = fmt.Errorf("failed to read config file: %w", err)
+		r.status.LastError = err.Error()
+		return nil, err
+	}
+	settings, err := parseLiveSettings(r.path, data, r.base)
+	if err != nil {
+		r.status.LastError = err.Error()
+		r.rejected = contentVersion(data)
+		return nil, err
+	}
+
+	changes := r.targets.apply(settings)
+	r.status.Version = contentVersion(data)
+	r.status.LoadedAt = r.status.LastAttempt
+	r.status.LastError = ""
+	r.status.LastChanges = changes
+	return changes, nil
+}
+
+// Start polls the file and reloads it when its content changes
+func (r *ConfigReloader) Start() {
+	if r.interval <= 0 {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.reloadIfChanged()
+			case <-r.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// reloadIfChanged reloads when the file's content differs from the version last applied or tried
+func (r *ConfigReloader) reloadIfChanged() {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return // Editors often replace the file; the next poll picks the new one up
+	}
+	version := contentVersion(data)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if version == r.status.Version || version == r.rejected {
+		return
+	}
+	changes, err := r.reloadLocked()
+	if err != nil {
+		logger.Log.Error().Err(err).Str("path", r.path).Msg("Config file change rejected, keeping current settings")
+		return
+	}
+	logConfigChanges(r.path, "file change", changes)
+}
+
+// Stop stops watching the file
+func (r *ConfigReloader) Stop() {
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	r.wg.Wait()
+}
+
+// Status returns the state of the applied config file
+func (r *ConfigReloader) Status() ConfigReloadStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	status := r.status
+	status.LastChanges = append([]string(nil), r.status.LastChanges...)
+	return status
+}
+
+// contentVersion identifies a config file version by its content
+func contentVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// logConfigChanges logs an applied reload
+func logConfigChanges(path, trigger string, changes []string) {
+	event := logger.Log.Info().Str("path", path).Str("trigger", trigger)
+	if len(changes) == 0 {
+		event.Msg("Config file reloaded, no changes")
+		return
+	}
+	event.Strs("changes", changes).Msg("Config file reloaded")
+}
+
+// initConfigReloader applies CONFIG_FILE over the environment settings and starts watching it.
+// A file that doesn't load at startup is an error; later bad edits are rejected and logged.
+func (s *Server) initConfigReloader() error {
+	if s.config.ConfigFile == "" {
+		return nil
+	}
+	reloader := NewConfigReloader(s.config.ConfigFile, s.config.ConfigWatchInterval, s.liveTargets())
+	changes, err := reloader.Reload()
+	if err != nil {
+		return err
+	}
+	logConfigChanges(s.config.ConfigFile, "startup", changes)
+	reloader.Start()
+	s.configReloader = reloader
+	return nil
+}
+
+// ReloadConfig reapplies CONFIG_FILE (on SIGHUP)
+func (s *Server) ReloadConfig() {
+	if s.configReloader == nil {
+		logger.Log.Warn().Msg("Config reload requested but CONFIG_FILE is not set")
+		return
+	}
+	changes, err := s.configReloader.Reload()
+	if err != nil {
+		logger.Log.Error().Err(err).Str("path", s.config.ConfigFile).Msg("Config reload rejected, keeping current settings")
+		return
+	}
+	logConfigChanges(s.config.ConfigFile, "SIGHUP", changes)
+}
+
+// liveTargets returns the running components live settings apply to
+func (s *Server) liveTargets() liveTargets {
+	return liveTargets{
+		rateLimiter:   s.rateLimiter,
+		cors:          s.cors,
+		sizeLimiter:   s.sizeLimiter,
+		privacy:       s.privacy,
+		publisherAuth: s.publisherAuth,
+	}
+}
+
+// effectiveConfigHandler serves the configuration the server is running with, secrets redacted
+//
+//	GET /admin/config
+func (s *Server) effectiveConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := map[string]interface{}{
+		"live":   s.liveTargets().current(),
+		"server": s.config.Redacted(),
+	}
+	if s.configReloader != nil {
+		response["config_file"] = s.configReloader.Status()
+	} else {
+		response["config_file"] = map[string]string{"status": "disabled"}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode effective config")
+	}
+}
+
+// redactedSecret replaces secret config values in admin output
+const redactedSecret = "[REDACTED]"
+
+// Redacted returns a copy of the config with credentials removed, for display
+func (c *ServerConfig) Redacted() *ServerConfig {
+	redacted := *c
+	if redacted.IDRAPIKey != "" {
+		redacted.IDRAPIKey = redactedSecret
+	}
+	if redacted.RedisURL != "" {
+		if u, err := url.Parse(redacted.RedisURL); err == nil {
+			redacted.RedisURL = u.Redacted()
+		} else {
+			redacted.RedisURL = redactedSecret
+		}
+	}
+	if c.DatabaseConfig != nil {
+		db := *c.DatabaseConfig
+		if db.Password != "" {
+			db.Password = redactedSecret
+		}
+		redacted.DatabaseConfig = &db
+	}
+	return &redacted
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"crypto/rand"
+	"database/sql"
 	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
@@ -17,15 +19,22 @@ import (
 	_ "github.com/thenexusengine/tne_springwire/internal/adapters/rubicon"
 	_ "github.com/thenexusengine/tne_springwire/internal/adapters/sovrn"
 	_ "github.com/thenexusengine/tne_springwire/internal/adapters/triplelift"
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/internal/analytics/filelogger"
+	"github.com/thenexusengine/tne_springwire/internal/analytics/pgwriter"
+	"github.com/thenexusengine/tne_springwire/internal/analytics/rollup"
 	pbsconfig "github.com/thenexusengine/tne_springwire/internal/config"
 	"github.com/thenexusengine/tne_springwire/internal/endpoints"
 	"github.com/thenexusengine/tne_springwire/internal/exchange"
 	"github.com/thenexusengine/tne_springwire/internal/metrics"
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/mockbidder"
 	"github.com/thenexusengine/tne_springwire/internal/storage"
 	"github.com/thenexusengine/tne_springwire/pkg/currency"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 	"github.com/thenexusengine/tne_springwire/pkg/redis"
+	"github.com/thenexusengine/tne_springwire/pkg/tracing"
 )
 
 // Server represents the PBS server
@@ -35,10 +44,25 @@ type Server struct {
 	metrics           *metrics.Metrics
 	exchange          *exchange.Exchange
 	rateLimiter       *middleware.RateLimiter
+	publisherAuth     *middleware.PublisherAuth
+	adminAuth         *middleware.AdminAuth
+	audit             *storage.AuditStore
+	dbConn            *sql.DB
 	db                *storage.BidderStore
 	publisher         *storage.PublisherStore
 	redisClient       *redis.Client
 	currencyConverter *currency.Converter
+	analytics         *analytics.Runner
+	dashboardStore    *rollup.Store
+	captureFile       *os.File                  // Recorded auctions for cmd/replay
+	auctionRecorder   *exchange.AuctionRecorder // Writes captureFile
+	configSyncer      *exchange.ConfigSyncer
+	sharedBreakers    *exchange.SharedBreakers
+	tracingShutdown   func(context.Context) error // Flushes buffered spans
+	cors              *middleware.CORS
+	sizeLimiter       *middleware.SizeLimiter
+	privacy           *middleware.Privacy
+	configReloader    *ConfigReloader // Applies CONFIG_FILE to the middleware above
 }
 
 // NewServer creates a new PBS server instance
@@ -69,24 +93,33 @@ func (s *Server) initialize() error {
 	s.metrics = metrics.NewMetrics("pbs")
 	log.Info().Msg("Prometheus metrics enabled")
 
+	// Initialize tracing before anything makes traced requests
+	if err := s.initTracing(); err != nil {
+		// Tracing failures are non-fatal, log and continue
+		log.Warn().Err(err).Msg("Tracing initialization failed, continuing without traces")
+	}
+
 	// Initialize database if configured
 	if err := s.initDatabase(); err != nil {
 		// Database failures are non-fatal, log and continue
 		log.Warn().Err(err).Msg("Database initialization failed, continuing with reduced functionality")
 	}
 
-	// Initialize middleware
-	s.initMiddleware()
-
-	// Initialize exchange
-	s.initExchange()
-
 	// Initialize Redis if configured
 	if err := s.initRedis(); err != nil {
 		// Redis failures are non-fatal, log and continue
 		log.Warn().Err(err).Msg("Redis initialization failed, continuing with reduced functionality")
 	}
 
+	// Initialize analytics modules
+	s.initAnalytics()
+
+	// Initialize middleware
+	s.initMiddleware()
+
+	// Initialize exchange
+	s.initExchange()
+
 	// List registered bidders
 	bidders := adapters.DefaultRegistry.ListBidders()
 	log.Info().
@@ -97,6 +130,32 @@ func (s *Server) initialize() error {
 	// Initialize handlers and build HTTP server
 	s.initHandlers()
 
+	// Overlay the live config file on the environment settings
+	if err := s.initConfigReloader(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// initTracing installs the OTLP trace exporter when tracing is enabled
+func (s *Server) initTracing() error {
+	config, err := s.config.TracingConfig()
+	if err != nil {
+		return err
+	}
+	shutdown, err := tracing.Init(context.Background(), config)
+	if err != nil {
+		return err
+	}
+	s.tracingShutdown = shutdown
+	if config.Enabled {
+		logger.Log.Info().
+			Str("endpoint", config.Endpoint).
+			Float64("sample_rate", config.SampleRate).
+			Interface("endpoint_sample_rates", config.EndpointSampleRates).
+			Msg("OTLP tracing enabled")
+	}
 	return nil
 }
 
@@ -128,6 +187,7 @@ func (s *Server) initDatabase() error {
 		return err
 	}
 
+	s.dbConn = dbConn
 	s.db = storage.NewBidderStore(dbConn)
 	s.publisher = storage.NewPublisherStore(dbConn)
 
@@ -154,16 +214,65 @@ func (s *Server) initDatabase() error {
 	return nil
 }
 
+// initAnalytics registers the analytics modules that receive auction, video and cookie sync objects
+func (s *Server) initAnalytics() {
+	log := logger.Log
+
+	s.analytics = analytics.NewRunner(s.config.AnalyticsQueueSize)
+
+	// Dashboard is always fed so /admin/dashboard reflects every auction
+	if err := s.analytics.Register("dashboard", endpoints.NewDashboardModule()); err != nil {
+		log.Warn().Err(err).Msg("Failed to register dashboard analytics module")
+	}
+
+	// Shared rollups let the dashboard aggregate across all instances
+	if s.redisClient != nil {
+		s.dashboardStore = rollup.New(s.redisClient, rollup.DefaultConfig())
+		if err := s.analytics.Register("rollup", s.dashboardStore); err != nil {
+			log.Warn().Err(err).Msg("Failed to register dashboard rollup analytics module")
+			s.dashboardStore.Shutdown()
+			s.dashboardStore = nil
+		}
+	}
+
+	if s.config.AnalyticsFilePath != "" {
+		fileModule, err := filelogger.New(filelogger.DefaultConfig(s.config.AnalyticsFilePath))
+		if err != nil {
+			log.Warn().Err(err).Str("path", s.config.AnalyticsFilePath).Msg("Failed to open analytics file, file logger disabled")
+		} else if err := s.analytics.Register("file", fileModule); err != nil {
+			log.Warn().Err(err).Msg("Failed to register file analytics module")
+		}
+	}
+
+	if s.config.AnalyticsDBEnabled {
+		if s.dbConn == nil {
+			log.Warn().Msg("ANALYTICS_DB_ENABLED set but database is not available, Postgres analytics disabled")
+		} else if err := s.analytics.Register("postgres", pgwriter.New(s.dbConn, pgwriter.DefaultConfig())); err != nil {
+			log.Warn().Err(err).Msg("Failed to register Postgres analytics module")
+		}
+	}
+
+	log.Info().Strs("modules", s.analytics.Modules()).Msg("Analytics modules initialized")
+}
+
 // initMiddleware initializes all middleware components
 func (s *Server) initMiddleware() {
 	log := logger.Log
 
-	// Initialize PublisherAuth
-	publisherAuth := middleware.NewPublisherAuth(middleware.DefaultPublisherAuthConfig())
-	if publisherAuth.IsEnabled() {
+	// Initialize PublisherAuth (shared with the publisher admin API for cache invalidation)
+	s.publisherAuth = middleware.NewPublisherAuth(middleware.DefaultPublisherAuthConfig())
+	if s.publisherAuth.IsEnabled() {
 		log.Info().Msg("PublisherAuth enabled for /openrtb2/auction endpoint")
 	}
 
+	// Initialize AdminAuth (API keys / signed tokens with per-route roles on /admin/*)
+	s.adminAuth = middleware.NewAdminAuth(middleware.DefaultAdminAuthConfig())
+	if s.adminAuth.IsEnabled() {
+		log.Info().Msg("AdminAuth enabled for /admin/ endpoints")
+	} else {
+		log.Warn().Msg("AdminAuth disabled - /admin/ endpoints are unauthenticated")
+	}
+
 	// Store rate limiter for graceful shutdown
 	s.rateLimiter = middleware.NewRateLimiter(middleware.DefaultRateLimitConfig())
 
@@ -176,7 +285,15 @@ func (s *Server) initExchange() {
 
 	// Initialize currency converter if enabled
 	if s.config.CurrencyConversionEnabled {
-		s.currencyConverter = currency.NewConverter(currency.DefaultConfig())
+		currencyConfig := currency.DefaultConfig()
+		currencyConfig.RatesFile = s.config.CurrencyRatesFile
+		if len(s.config.CurrencyProviders) > 0 {
+			currencyConfig.Providers = s.config.CurrencyProviders
+		}
+		s.currencyConverter = currency.NewConverter(currencyConfig)
+		if s.dbConn != nil {
+			s.currencyConverter.SetSnapshotStore(storage.NewCurrencyRateStore(s.dbConn))
+		}
 
 		// Start background rate updates
 		ctx := context.Background()
@@ -196,12 +313,184 @@ func (s *Server) initExchange() {
 	exchangeConfig := s.config.ToExchangeConfig()
 	exchangeConfig.CurrencyConverter = s.currencyConverter
 
+	// Register config-defined aliases before the exchange creates per-bidder circuit breakers
+	s.registerBidderAliases()
+
+	if s.dbConn != nil {
+		s.loadBidderCircuitBreakers(exchangeConfig)
+	}
+
 	// Create exchange with default registry
 	s.exchange = exchange.New(adapters.DefaultRegistry, exchangeConfig)
 
+	if s.dbConn != nil {
+		s.loadBidderTimeouts()
+	}
+
+	if s.config.RuntimeConfigFile != "" || s.config.RuntimeConfigFromIDR {
+		s.initConfigSync()
+	}
+
+	if s.config.SharedCircuitBreakers {
+		s.initSharedBreakers()
+	}
+
+	if s.config.MockBidderEnabled {
+		s.initMockBidder()
+	}
+
+	if s.config.AuctionCapturePath != "" {
+		s.initAuctionCapture()
+	}
+
 	// Wire up metrics for margin tracking
 	s.exchange.SetMetrics(s.metrics)
 	log.Info().Msg("Metrics connected to exchange for margin tracking")
+
+	if s.analytics != nil {
+		s.exchange.SetAnalytics(s.analytics)
+	}
+}
+
+// loadBidderTimeouts applies the timeout_ms of active bidders in the database.
+// BIDDER_TIMEOUTS entries take precedence.
+func (s *Server) loadBidderTimeouts() {
+	bidders, err := storage.NewBidderStore(s.dbConn).ListActive(context.Background())
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("Failed to load bidder timeouts, using the auction timeout")
+		return
+	}
+
+	timeouts := make(map[string]time.Duration)
+	for _, b := range bidders {
+		if b.TimeoutMs > 0 {
+			timeouts[b.BidderCode] = time.Duration(b.TimeoutMs) * time.Millisecond
+		}
+	}
+	configured, _ := s.config.ParseBidderTimeouts() // Checked by Validate
+	for code, timeout := range configured {
+		timeouts[code] = timeout
+	}
+	s.exchange.SetBidderTimeouts(timeouts)
+	logger.Log.Info().Int("bidders", len(timeouts)).Msg("Per-bidder timeouts loaded")
+}
+
+// loadBidderCircuitBreakers applies the circuit_breaker settings of active bidders in the database.
+// BIDDER_CIRCUIT_BREAKERS entries only apply to bidders without their own.
+func (s *Server) loadBidderCircuitBreakers(config *exchange.Config) {
+	bidders, err := storage.NewBidderStore(s.dbConn).ListActive(context.Background())
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("Failed to load bidder circuit breakers, using BIDDER_CIRCUIT_BREAKERS")
+		return
+	}
+
+	loaded := 0
+	for _, b := range bidders {
+		if b.CircuitBreaker == nil {
+			continue
+		}
+		breaker, err := bidderCircuitBreakerConfig(b.CircuitBreaker)
+		if err != nil {
+			logger.Log.Warn().Err(err).Str("bidder", b.BidderCode).Msg("Invalid bidder circuit breaker settings, using BIDDER_CIRCUIT_BREAKERS")
+			continue
+		}
+		if config.BidderCircuitBreakers == nil {
+			config.BidderCircuitBreakers = make(map[string]*idr.CircuitBreakerConfig)
+		}
+		config.BidderCircuitBreakers[b.BidderCode] = breaker
+		loaded++
+	}
+	logger.Log.Info().Int("bidders", loaded).Msg("Per-bidder circuit breakers loaded")
+}
+
+// initConfigSync starts pulling runtime config (FPD, EID sources, max bidders, timeouts)
+// from IDR or a local file
+func (s *Server) initConfigSync() {
+	source := exchange.NewFileConfigSource(s.config.RuntimeConfigFile)
+	if s.config.RuntimeConfigFromIDR {
+		if s.exchange.GetIDRClient() == nil {
+			logger.Log.Warn().Msg("Runtime config from IDR requested without an IDR client, sync disabled")
+			return
+		}
+		source = exchange.NewIDRConfigSource(s.exchange.GetIDRClient())
+	}
+	s.configSyncer = exchange.NewConfigSyncer(s.exchange, source, s.config.RuntimeConfigSyncInterval)
+	s.configSyncer.Start()
+	logger.Log.Info().
+		Str("source", source.Name()).
+		Dur("interval", s.config.RuntimeConfigSyncInterval).
+		Msg("Runtime config sync enabled")
+}
+
+// initSharedBreakers shares bidder circuit breaker state with the other replicas through Redis
+func (s *Server) initSharedBreakers() {
+	if s.redisClient == nil {
+		logger.Log.Warn().Msg("Shared circuit breakers requested without Redis, using local breakers")
+		return
+	}
+	s.sharedBreakers = exchange.NewSharedBreakers(s.exchange, s.redisClient, exchange.DefaultSharedBreakerConfig())
+	s.sharedBreakers.Start()
+	logger.Log.Info().Msg("Shared circuit breaker state enabled")
+}
+
+// initMockBidder routes test=1 auctions to a mock bidder: the cmd/mockbidder server at
+// MockBidderURL, or an in-process mock when no URL is set
+func (s *Server) initMockBidder() {
+	log := logger.Log
+
+	if s.config.MockBidderURL != "" {
+		s.exchange.SetTestHTTPClient(mockbidder.NewRemoteClient(s.config.MockBidderURL, s.config.Timeout))
+		log.Info().Str("url", s.config.MockBidderURL).Msg("Test mode enabled: test=1 requests use the remote mock bidder")
+		return
+	}
+
+	mockConfig := mockbidder.DefaultConfig()
+	if s.config.MockBidderConfig != "" {
+		loaded, err := mockbidder.LoadConfig(s.config.MockBidderConfig)
+		if err != nil {
+			log.Error().Err(err).Msg("Invalid mock bidder config, test mode disabled")
+			return
+		}
+		mockConfig = loaded
+	}
+	s.exchange.SetTestHTTPClient(mockbidder.NewClient(mockbidder.New(mockConfig)))
+	log.Info().Msg("Test mode enabled: test=1 requests use the in-process mock bidder")
+}
+
+// initAuctionCapture records a sample of auctions with their bidder responses for cmd/replay
+func (s *Server) initAuctionCapture() {
+	f, err := os.OpenFile(s.config.AuctionCapturePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("path", s.config.AuctionCapturePath).Msg("Failed to open auction capture file, capture disabled")
+		return
+	}
+	s.captureFile = f
+	s.auctionRecorder = exchange.NewAuctionRecorder(f, s.config.AuctionCaptureSampleRate)
+	s.exchange.SetAuctionRecorder(s.auctionRecorder)
+	logger.Log.Info().
+		Str("path", s.config.AuctionCapturePath).
+		Float64("sample_rate", s.config.AuctionCaptureSampleRate).
+		Msg("Auction capture enabled")
+}
+
+// registerBidderAliases registers the configured bidder aliases in the default registry.
+// Invalid aliases are logged and skipped.
+func (s *Server) registerBidderAliases() {
+	aliases, err := s.config.ParseBidderAliases()
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to parse bidder aliases")
+		return
+	}
+	for alias, aliasConfig := range aliases {
+		if err := adapters.DefaultRegistry.RegisterAlias(alias, aliasConfig); err != nil {
+			logger.Log.Error().Err(err).Str("alias", alias).Msg("Failed to register bidder alias")
+			continue
+		}
+		logger.Log.Info().
+			Str("alias", alias).
+			Str("alias_of", aliasConfig.AliasOf).
+			Msg("Bidder alias registered")
+	}
 }
 
 // initRedis initializes Redis client
@@ -235,19 +524,27 @@ func (s *Server) initHandlers() {
 
 	// Video handlers
 	videoHandler := endpoints.NewVideoHandler(s.exchange, s.config.HostURL)
-	videoEventHandler := endpoints.NewVideoEventHandler(nil) // Analytics can be added later
+	videoEventHandler := endpoints.NewVideoEventHandler(endpoints.NewAnalyticsVideoTracker(s.analytics))
 
 	log.Info().Msg("Video handlers initialized")
 
 	// Ad tag handlers (direct publisher integration)
 	adTagHandler := endpoints.NewAdTagHandler(s.exchange)
+	adTagHandler.SetAnalytics(s.analytics)
 	adTagGenerator := endpoints.NewAdTagGeneratorHandler(s.config.HostURL)
 
 	log.Info().Msg("Ad tag handlers initialized")
 
 	// Cookie sync handlers
 	cookieSyncConfig := endpoints.DefaultCookieSyncConfig(s.config.HostURL)
+	cookieSyncConfig.SyncerKeys = make(map[string]string)
+	for alias := range adapters.DefaultRegistry.ListAliases() {
+		if awi, ok := adapters.DefaultRegistry.Get(alias); ok {
+			cookieSyncConfig.SyncerKeys[alias] = awi.Info.SyncerKey(alias)
+		}
+	}
 	cookieSyncHandler := endpoints.NewCookieSyncHandler(cookieSyncConfig)
+	cookieSyncHandler.SetAnalytics(s.analytics)
 	setuidHandler := endpoints.NewSetUIDHandler(cookieSyncHandler.ListBidders())
 	optoutHandler := endpoints.NewOptOutHandler()
 
@@ -262,10 +559,10 @@ func (s *Server) initHandlers() {
 		privacyConfig.EnforceGDPR = false
 		log.Warn().Msg("GDPR enforcement disabled via PBS_DISABLE_GDPR_ENFORCEMENT")
 	}
-	privacyMiddleware := middleware.NewPrivacyMiddleware(privacyConfig)
+	s.privacy = middleware.NewPrivacy(privacyConfig)
 
 	// Wrap auction handler with privacy middleware
-	privacyProtectedAuction := privacyMiddleware(auctionHandler)
+	privacyProtectedAuction := s.privacy.Middleware(auctionHandler)
 
 	log.Info().
 		Bool("gdpr_enforcement", privacyConfig.EnforceGDPR).
@@ -278,7 +575,7 @@ func (s *Server) initHandlers() {
 	mux.Handle("/openrtb2/auction", privacyProtectedAuction)
 	mux.Handle("/status", statusHandler)
 	mux.Handle("/health", healthHandler())
-	mux.Handle("/health/ready", readyHandler(s.redisClient, s.publisher, s.exchange, s.currencyConverter))
+	mux.Handle("/health/ready", readyHandler(s.redisClient, s.publisher, s.exchange, s.currencyConverter, s.configSyncer))
 	mux.Handle("/info/bidders", biddersHandler)
 
 	// Cookie sync endpoints
@@ -340,20 +637,55 @@ func (s *Server) initHandlers() {
 	mux.Handle("/metrics", metrics.Handler())
 
 	// Admin endpoints
-	mux.HandleFunc("/admin/circuit-breaker", s.circuitBreakerHandler)
+	mux.HandleFunc("/admin/bidder-timeouts", s.bidderTimeoutsHandler)
 	mux.HandleFunc("/admin/currency", s.currencyStatsHandler)
+	mux.HandleFunc("/admin/config", s.effectiveConfigHandler)
 	mux.HandleFunc("/admin/adtag/generator", adTagGenerator.HandleGeneratorUI)
 	mux.HandleFunc("/admin/adtag/generate", adTagGenerator.HandleGenerateTag)
 	dashboardHandler := endpoints.NewDashboardHandler()
 	metricsAPIHandler := endpoints.NewMetricsAPIHandler()
-	publisherAdminHandler := endpoints.NewPublisherAdminHandler(s.redisClient)
+	if s.dashboardStore != nil {
+		metricsAPIHandler.SetStore(s.dashboardStore)
+	}
+	publisherAdminHandler := endpoints.NewPublisherAdminHandler(nil, s.redisClient)
+	if s.publisher != nil {
+		publisherAdminHandler = endpoints.NewPublisherAdminHandler(s.publisher, s.redisClient)
+	}
+	publisherAdminHandler.SetCacheInvalidator(s.publisherAuth)
+	var rateSource endpoints.RateSource
+	if s.currencyConverter != nil {
+		rateSource = s.currencyConverter
+	}
+	currencyAdminHandler := endpoints.NewCurrencyAdminHandler(rateSource, nil)
+	circuitBreakerHandler := endpoints.NewCircuitBreakerAdminHandler(s.exchange, s.idrCircuitBreakerStats)
+	auditLogHandler := endpoints.NewAuditLogHandler(nil)
+	if s.dbConn != nil {
+		s.audit = storage.NewAuditStore(s.dbConn)
+		publisherAdminHandler.SetAuditRecorder(s.audit)
+		auditLogHandler = endpoints.NewAuditLogHandler(s.audit)
+		currencyAdminHandler = endpoints.NewCurrencyAdminHandler(rateSource, storage.NewCurrencyRateStore(s.dbConn))
+		currencyAdminHandler.SetAuditRecorder(s.audit)
+		circuitBreakerHandler.SetAuditRecorder(s.audit)
+	}
+	mux.Handle("/admin/circuit-breaker", circuitBreakerHandler)
 	mux.Handle("/admin/dashboard", dashboardHandler)
 	mux.Handle("/admin/metrics", metricsAPIHandler)
 	mux.Handle("/admin/publishers", publisherAdminHandler)
 	mux.Handle("/admin/publishers/", publisherAdminHandler)
+	mux.Handle("/admin/audit", auditLogHandler)
+	mux.Handle("/admin/currency/", currencyAdminHandler)
 
 	log.Info().Msg("Admin tag generator registered: /admin/adtag/generator")
 
+	// Publisher reporting API (authenticated by per-publisher API key)
+	reportsHandler := endpoints.NewReportsHandler(nil, nil, s.config.DefaultCurrency)
+	if s.dbConn != nil {
+		reportsHandler = endpoints.NewReportsHandler(storage.NewReportStore(s.dbConn), s.publisher, s.config.DefaultCurrency)
+	}
+	mux.Handle("/api/v1/reports", reportsHandler)
+
+	log.Info().Msg("Publisher reporting endpoint registered: /api/v1/reports")
+
 	// Build middleware chain
 	handler := s.buildHandler(mux)
 
@@ -372,10 +704,19 @@ func (s *Server) buildHandler(mux *http.ServeMux) http.Handler {
 	log := logger.Log
 
 	// Initialize middleware
-	cors := middleware.NewCORS(middleware.DefaultCORSConfig())
+	s.cors = middleware.NewCORS(middleware.DefaultCORSConfig())
+	cors := s.cors
 	security := middleware.NewSecurity(nil)
-	publisherAuth := middleware.NewPublisherAuth(middleware.DefaultPublisherAuthConfig())
-	sizeLimiter := middleware.NewSizeLimiter(middleware.DefaultSizeLimitConfig())
+	if s.publisherAuth == nil {
+		s.publisherAuth = middleware.NewPublisherAuth(middleware.DefaultPublisherAuthConfig())
+	}
+	publisherAuth := s.publisherAuth
+	if s.adminAuth == nil {
+		s.adminAuth = middleware.NewAdminAuth(middleware.DefaultAdminAuthConfig())
+	}
+	adminAuth := s.adminAuth
+	s.sizeLimiter = middleware.NewSizeLimiter(middleware.DefaultSizeLimitConfig())
+	sizeLimiter := s.sizeLimiter
 	gzipMiddleware := middleware.NewGzip(middleware.DefaultGzipConfig())
 
 	// Wire up metrics
@@ -397,41 +738,46 @@ func (s *Server) buildHandler(mux *http.ServeMux) http.Handler {
 		Bool("cors_enabled", true).
 		Bool("security_headers_enabled", security.GetConfig().Enabled).
 		Bool("rate_limiting_enabled", s.rateLimiter != nil).
+		Bool("admin_auth_enabled", adminAuth.IsEnabled()).
 		Msg("Middleware chain built")
 
-	// Build chain: CORS -> Security -> Logging -> Size Limit -> PublisherAuth -> Rate Limit -> Metrics -> Gzip -> Handler
+	// Build chain: Tracing -> CORS -> Security -> Logging -> Size Limit -> AdminAuth -> PublisherAuth -> Rate Limit -> Metrics -> Gzip -> Handler
 	handler := http.Handler(mux)
 	handler = gzipMiddleware.Middleware(handler)
 	handler = s.metrics.Middleware(handler)
 	handler = s.rateLimiter.Middleware(handler)
 	handler = publisherAuth.Middleware(handler)
+	handler = adminAuth.Middleware(handler)
 	handler = sizeLimiter.Middleware(handler)
 	handler = loggingMiddleware(handler)
 	handler = security.Middleware(handler)
 	handler = cors.Middleware(handler)
+	handler = tracing.Middleware(handler)
 
 	return handler
 }
 
-// circuitBreakerHandler returns circuit breaker stats
-func (s *Server) circuitBreakerHandler(w http.ResponseWriter, r *http.Request) {
-	log := logger.Log
-	w.Header().Set("Content-Type", "application/json")
-
-	response := make(map[string]interface{})
-
-	// Include IDR circuit breaker stats
-	if s.exchange.GetIDRClient() != nil {
-		response["idr"] = s.exchange.GetIDRClient().CircuitBreakerStats()
-	} else {
-		response["idr"] = map[string]string{"status": "disabled"}
+// idrCircuitBreakerStats returns the IDR client's circuit breaker stats, or nil when IDR is disabled
+func (s *Server) idrCircuitBreakerStats() *idr.CircuitBreakerStats {
+	client := s.exchange.GetIDRClient()
+	if client == nil {
+		return nil
 	}
+	stats := client.CircuitBreakerStats()
+	return &stats
+}
 
-	// Include bidder circuit breaker stats
-	response["bidders"] = s.exchange.GetBidderCircuitBreakerStats()
+// bidderTimeoutsHandler returns per-bidder static and adaptive timeout stats
+func (s *Server) bidderTimeoutsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
 
+	response := map[string]interface{}{
+		"auction_timeout_ms": s.config.Timeout.Milliseconds(),
+		"adaptive_enabled":   s.config.AdaptiveTimeoutsEnabled,
+		"bidders":            s.exchange.GetBidderTimeoutStats(),
+	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		log.Error().Err(err).Msg("failed to encode circuit breaker stats")
+		logger.Log.Error().Err(err).Msg("failed to encode bidder timeout stats")
 	}
 }
 
@@ -479,6 +825,18 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.rateLimiter.Stop()
 	}
 
+	if s.configSyncer != nil {
+		s.configSyncer.Stop()
+	}
+
+	if s.configReloader != nil {
+		s.configReloader.Stop()
+	}
+
+	if s.sharedBreakers != nil {
+		s.sharedBreakers.Stop()
+	}
+
 	// Stop currency converter background refresh
 	if s.currencyConverter != nil {
 		s.currencyConverter.Stop()
@@ -499,6 +857,26 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	// Drain analytics queues once no more requests can arrive
+	if s.analytics != nil {
+		s.analytics.Shutdown()
+		log.Info().Msg("Analytics modules flushed")
+	}
+
+	if s.captureFile != nil {
+		s.exchange.SetAuctionRecorder(nil)
+		s.auctionRecorder.Close()
+		if err := s.captureFile.Close(); err != nil {
+			log.Warn().Err(err).Msg("Error closing auction capture file")
+		}
+	}
+
+	if s.tracingShutdown != nil {
+		if err := s.tracingShutdown(ctx); err != nil {
+			log.Warn().Err(err).Msg("Error flushing traces")
+		}
+	}
+
 	log.Info().Msg("Server stopped gracefully")
 	return nil
 }
@@ -531,8 +909,8 @@ func loggingMiddleware(next http.Handler) http.Handler {
 		// Add request ID to response
 		w.Header().Set("X-Request-ID", requestID)
 
-		// Process request
-		next.ServeHTTP(wrapped, r)
+		// Process request; logger.FromContext picks the request ID up all the way down
+		next.ServeHTTP(wrapped, r.WithContext(logger.WithRequestID(r.Context(), requestID)))
 
 		// Log request completion
 		duration := time.Since(start)
@@ -594,7 +972,7 @@ func sanitizeHealthCheckError(service string, err error) string {
 // readyHandler returns a readiness check with dependency verification
 // SECURITY: Error messages are sanitized to prevent information disclosure.
 // Raw errors may contain connection strings, hostnames, or internal network details.
-func readyHandler(redisClient *redis.Client, publisherStore *storage.PublisherStore, ex *exchange.Exchange, currencyConverter *currency.Converter) http.Handler {
+func readyHandler(redisClient *redis.Client, publisherStore *storage.PublisherStore, ex *exchange.Exchange, currencyConverter *currency.Converter, configSyncer *exchange.ConfigSyncer) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
 		defer cancel()
@@ -692,6 +1070,31 @@ func readyHandler(redisClient *redis.Client, publisherStore *storage.PublisherSt
 			}
 		}
 
+		// Report the runtime config version and last sync
+		if configSyncer != nil {
+			sync := configSyncer.Status()
+			check := map[string]interface{}{
+				"status":  "healthy",
+				"source":  sync.Source,
+				"version": sync.Version,
+			}
+			if !sync.LastSync.IsZero() {
+				check["lastSync"] = sync.LastSync.UTC().Format(time.RFC3339)
+			}
+			if sync.LastError != "" {
+				check["error"] = "last sync failed" // Details are logged by the syncer
+			}
+			if configSyncer.Stale() {
+				// Don't mark as unhealthy - the last applied config keeps serving
+				check["status"] = "degraded"
+			}
+			checks["config"] = check
+		} else {
+			checks["config"] = map[string]interface{}{
+				"status": "disabled",
+			}
+		}
+
 		status := http.StatusOK
 		if !allHealthy {
 			status = http.StatusServiceUnavailable
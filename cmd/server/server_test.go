@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/alicebob/miniredis/v2"
+	"github.com/thenexusengine/tne_springwire/internal/endpoints"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 	"github.com/thenexusengine/tne_springwire/pkg/redis"
 )
@@ -21,15 +24,30 @@ func init() {
 	})
 }
 
+// TestMain runs the tests from the repository root, where the server is started and
+// relative paths such as the bidder mapping in config/ resolve
+func TestMain(m *testing.M) {
+	if err := os.Chdir("../.."); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
 // Global test server instance to avoid metrics registration conflicts
 var testServer *Server
 
+// testAdminKey is the admin API key configured on testServer
+const testAdminKey = "test-admin-key"
+
 func TestNewServer_MinimalConfig(t *testing.T) {
 	// Skip if server was already created
 	if testServer != nil {
 		t.Skip("Skipping to avoid Prometheus metrics conflict")
 	}
 
+	// Admin routes on the shared server authenticate with this key
+	t.Setenv("ADMIN_API_KEYS", "tests:admin:"+testAdminKey)
+
 	cfg := &ServerConfig{
 		Port:                      "8080",
 		Timeout:                   1000 * time.Millisecond,
@@ -128,7 +146,7 @@ func TestServer_ReadyHandler_NoRedis(t *testing.T) {
 		t.Skip("Test server not initialized")
 	}
 
-	handler := readyHandler(nil, nil, testServer.exchange) // nil Redis client
+	handler := readyHandler(nil, nil, testServer.exchange, nil, nil) // nil Redis client
 
 	req := httptest.NewRequest("GET", "/health/ready", nil)
 	rr := httptest.NewRecorder()
@@ -245,7 +263,7 @@ func TestServer_CircuitBreakerHandler(t *testing.T) {
 	req := httptest.NewRequest("GET", "/admin/circuit-breaker", nil)
 	rr := httptest.NewRecorder()
 
-	testServer.circuitBreakerHandler(rr, req)
+	endpoints.NewCircuitBreakerAdminHandler(testServer.exchange, testServer.idrCircuitBreakerStats).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", rr.Code)
@@ -345,6 +363,7 @@ func TestServer_AllRoutes(t *testing.T) {
 	for _, route := range routes {
 		t.Run(route.path, func(t *testing.T) {
 			req := httptest.NewRequest("GET", route.path, nil)
+			req.Header.Set("X-Admin-Key", testAdminKey)
 			rr := httptest.NewRecorder()
 
 			testServer.httpServer.Handler.ServeHTTP(rr, req)
@@ -620,7 +639,7 @@ func TestReadyHandler_IDRDisabled(t *testing.T) {
 	}
 
 	// Test with IDR disabled (our test server has IDR disabled)
-	handler := readyHandler(nil, nil, testServer.exchange)
+	handler := readyHandler(nil, nil, testServer.exchange, nil, nil)
 
 	req := httptest.NewRequest("GET", "/health/ready", nil)
 	rr := httptest.NewRecorder()
@@ -697,7 +716,7 @@ func TestReadyHandler_WithMockRedis(t *testing.T) {
 		t.Fatalf("Failed to create Redis client: %v", err)
 	}
 
-	handler := readyHandler(testRedis, nil, testServer.exchange)
+	handler := readyHandler(testRedis, nil, testServer.exchange, nil, nil)
 
 	req := httptest.NewRequest("GET", "/health/ready", nil)
 	rr := httptest.NewRecorder()
@@ -751,7 +770,7 @@ func TestReadyHandler_RedisConnectionClosed(t *testing.T) {
 	// Close miniredis to simulate unhealthy connection
 	mr.Close()
 
-	handler := readyHandler(testRedis, nil, testServer.exchange)
+	handler := readyHandler(testRedis, nil, testServer.exchange, nil, nil)
 
 	req := httptest.NewRequest("GET", "/health/ready", nil)
 	rr := httptest.NewRecorder()
@@ -998,7 +1017,7 @@ func TestReadyHandler_JSONFormat(t *testing.T) {
 		t.Skip("Test server not initialized")
 	}
 
-	handler := readyHandler(nil, nil, testServer.exchange)
+	handler := readyHandler(nil, nil, testServer.exchange, nil, nil)
 
 	req := httptest.NewRequest("GET", "/health/ready", nil)
 	rr := httptest.NewRecorder()
@@ -1111,7 +1130,7 @@ func TestReadyHandler_ChecksStructure(t *testing.T) {
 		t.Skip("Test server or exchange not initialized")
 	}
 
-	handler := readyHandler(nil, nil, testServer.exchange)
+	handler := readyHandler(nil, nil, testServer.exchange, nil, nil)
 
 	req := httptest.NewRequest("GET", "/health/ready", nil)
 	rr := httptest.NewRecorder()
@@ -1139,3 +1158,48 @@ func TestReadyHandler_ChecksStructure(t *testing.T) {
 		t.Error("Expected 'idr' check in response")
 	}
 }
+
+func TestServer_LoadBidderCircuitBreakers(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{
+		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
+		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
+		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
+		"version", "created_at", "updated_at", "circuit_breaker",
+	}
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("1", "appnexus", "AppNexus", "https://example.com", 500, true, "active", true, false, false, false,
+				nil, []byte(`{}`), "", "", "", 1, now, now, nil).
+			AddRow("2", "rubicon", "Rubicon", "https://example.com", 500, true, "active", true, false, false, false,
+				nil, []byte(`{}`), "", "", "", 1, now, now, []byte(`{"error_rate": 0.25, "min_requests": 40}`)).
+			AddRow("3", "openx", "OpenX", "https://example.com", 500, true, "active", true, false, false, false,
+				nil, []byte(`{}`), "", "", "", 1, now, now, []byte(`{"error_rate": 3}`)))
+
+	cfg := &ServerConfig{
+		BidderCircuitBreakers: `{"*": {"failure_threshold": 8}, "rubicon": {"failure_threshold": 3}, "openx": {"failure_threshold": 4}}`,
+	}
+	server := &Server{config: cfg, dbConn: db}
+	exchangeConfig := cfg.ToExchangeConfig()
+	server.loadBidderCircuitBreakers(exchangeConfig)
+
+	breakers := exchangeConfig.BidderCircuitBreakers
+	if r := breakers["rubicon"]; r == nil || r.ErrorRateThreshold != 0.25 || r.MinRequests != 40 || r.FailureThreshold != 5 {
+		t.Errorf("Expected rubicon's database settings to replace its env settings, got %+v", r)
+	}
+	if o := breakers["openx"]; o == nil || o.FailureThreshold != 4 {
+		t.Errorf("Expected invalid database settings to fall back to env settings, got %+v", o)
+	}
+	if _, ok := breakers["appnexus"]; ok || breakers["*"].FailureThreshold != 8 {
+		t.Errorf("Expected appnexus to use the env default, got %+v", breakers)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+-- =====================================================
+-- Auction Analytics Schema
+-- =====================================================
+-- This migration creates the tables written by the
+-- Postgres analytics module (internal/analytics/pgwriter).
+--
+-- One row per auction in analytics_auctions, with child
+-- rows per requested impression, per bidder called and
+-- per valid bid. Winning bids are flagged with won=true
+-- and carry both the gross price (after auction logic)
+-- and the clearing price returned to the publisher.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS analytics_auctions (
+    id BIGSERIAL PRIMARY KEY,
+    auction_id VARCHAR(255) NOT NULL,
+    publisher_id VARCHAR(255),
+    status VARCHAR(32) NOT NULL,                  -- 'success', 'no_bids', 'timeout', ...
+    imp_count INTEGER NOT NULL DEFAULT 0,
+    bidders_selected INTEGER NOT NULL DEFAULT 0,
+    bidders_excluded INTEGER NOT NULL DEFAULT 0,
+    bid_count INTEGER NOT NULL DEFAULT 0,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    currency VARCHAR(3),
+    country VARCHAR(3),
+    device_type VARCHAR(16),
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_auctions_created ON analytics_auctions(created_at);
+CREATE INDEX idx_analytics_auctions_publisher ON analytics_auctions(publisher_id, created_at);
+
+CREATE TABLE IF NOT EXISTS analytics_imps (
+    id BIGSERIAL PRIMARY KEY,
+    auction_id VARCHAR(255) NOT NULL,
+    publisher_id VARCHAR(255),
+    imp_id VARCHAR(255) NOT NULL,
+    ad_unit VARCHAR(255),
+    media_type VARCHAR(16),
+    floor NUMERIC(10, 4) NOT NULL DEFAULT 0,
+    country VARCHAR(3),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_imps_publisher ON analytics_imps(publisher_id, created_at);
+
+CREATE TABLE IF NOT EXISTS analytics_bidder_results (
+    id BIGSERIAL PRIMARY KEY,
+    auction_id VARCHAR(255) NOT NULL,
+    publisher_id VARCHAR(255),
+    bidder_code VARCHAR(50) NOT NULL,
+    latency_ms INTEGER NOT NULL DEFAULT 0,
+    bid_count INTEGER NOT NULL DEFAULT 0,
+    timed_out BOOLEAN NOT NULL DEFAULT false,
+    had_error BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_bidder_results_bidder ON analytics_bidder_results(bidder_code, created_at);
+
+CREATE TABLE IF NOT EXISTS analytics_bids (
+    id BIGSERIAL PRIMARY KEY,
+    auction_id VARCHAR(255) NOT NULL,
+    publisher_id VARCHAR(255),
+    imp_id VARCHAR(255) NOT NULL,
+    bid_id VARCHAR(255) NOT NULL,
+    bidder_code VARCHAR(50) NOT NULL,
+    seat VARCHAR(255),
+    deal_id VARCHAR(255),
+    media_type VARCHAR(16),
+    ad_unit VARCHAR(255),
+    country VARCHAR(3),
+    bid_price NUMERIC(10, 4) NOT NULL,            -- CPM as returned by the bidder
+    gross_price NUMERIC(10, 4) NOT NULL,          -- CPM after auction logic
+    clearing_price NUMERIC(10, 4) NOT NULL,       -- CPM returned to the publisher
+    won BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_bids_publisher ON analytics_bids(publisher_id, created_at);
+CREATE INDEX idx_analytics_bids_bidder ON analytics_bids(bidder_code, created_at);
+
+CREATE TABLE IF NOT EXISTS analytics_video_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(32) NOT NULL,
+    bid_id VARCHAR(255) NOT NULL,
+    account_id VARCHAR(255),
+    bidder_code VARCHAR(50),
+    progress NUMERIC(5, 2),
+    error_code VARCHAR(32),
+    session_id VARCHAR(255),
+    content_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_video_events_created ON analytics_video_events(created_at);
+
+CREATE TABLE IF NOT EXISTS analytics_cookie_syncs (
+    id BIGSERIAL PRIMARY KEY,
+    status VARCHAR(32) NOT NULL,
+    gdpr BOOLEAN NOT NULL DEFAULT false,
+    opt_out BOOLEAN NOT NULL DEFAULT false,
+    sync_limit INTEGER NOT NULL DEFAULT 0,
+    bidders_returned INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_analytics_cookie_syncs_created ON analytics_cookie_syncs(created_at);
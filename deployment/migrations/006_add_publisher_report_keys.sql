@@ -0,0 +1,18 @@
+-- =====================================================
+-- Publisher Reporting API Keys
+-- =====================================================
+-- This migration adds a hashed API key to publishers so
+-- they can authenticate against /api/v1/reports.
+--
+-- Only the SHA-256 hex digest of the key is stored; keys
+-- are issued out of band and never persisted in clear.
+-- =====================================================
+
+ALTER TABLE publishers
+ADD COLUMN IF NOT EXISTS report_api_key_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_publishers_report_api_key_hash
+    ON publishers(report_api_key_hash)
+    WHERE report_api_key_hash IS NOT NULL;
+
+COMMENT ON COLUMN publishers.report_api_key_hash IS 'SHA-256 hex digest of the publisher reporting API key';
@@ -0,0 +1,51 @@
+-- =====================================================
+-- Admin Audit Log
+-- =====================================================
+-- This migration creates the append-only audit log for
+-- changes made through the /admin endpoints.
+--
+-- Each row records the authenticated admin, the action,
+-- the affected resource and its JSON state before and
+-- after the change, plus a field-level diff. Triggers
+-- reject UPDATE, DELETE and TRUNCATE so history can't
+-- be edited.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    actor_role VARCHAR(32) NOT NULL,
+    action VARCHAR(64) NOT NULL,                  -- 'publisher.update', 'circuit_breaker.reset', ...
+    resource_type VARCHAR(64) NOT NULL,           -- 'publisher', 'circuit_breaker', 'currency_rates'
+    resource_id VARCHAR(255) NOT NULL,
+    before_state JSONB,
+    after_state JSONB,
+    diff JSONB,
+    remote_addr VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_admin_audit_log_created ON admin_audit_log(created_at);
+CREATE INDEX idx_admin_audit_log_resource ON admin_audit_log(resource_type, resource_id, created_at);
+CREATE INDEX idx_admin_audit_log_actor ON admin_audit_log(actor, created_at);
+
+CREATE OR REPLACE FUNCTION admin_audit_log_append_only()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'admin_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS admin_audit_log_no_modify ON admin_audit_log;
+CREATE TRIGGER admin_audit_log_no_modify
+    BEFORE UPDATE OR DELETE ON admin_audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION admin_audit_log_append_only();
+
+DROP TRIGGER IF EXISTS admin_audit_log_no_truncate ON admin_audit_log;
+CREATE TRIGGER admin_audit_log_no_truncate
+    BEFORE TRUNCATE ON admin_audit_log
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION admin_audit_log_append_only();
+
+COMMENT ON TABLE admin_audit_log IS 'Append-only record of admin changes with before/after state';
@@ -0,0 +1,34 @@
+-- =====================================================
+-- Currency Rate Snapshots
+-- =====================================================
+-- This migration stores every currency rate set the
+-- converter has used, keyed by its version ID, so a
+-- converted price can be reconciled against the exact
+-- rates that produced it.
+--
+-- The version is a content hash of the source and rates;
+-- reloading identical rates does not add a row. Bids and
+-- auctions in the analytics tables record the version.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS currency_rate_snapshots (
+    version VARCHAR(32) PRIMARY KEY,
+    source VARCHAR(32) NOT NULL,                  -- 'http', 'file', 'admin'
+    generated_at VARCHAR(64),
+    data_as_of VARCHAR(64),
+    conversions JSONB NOT NULL,
+    loaded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_currency_rate_snapshots_loaded ON currency_rate_snapshots(loaded_at);
+
+ALTER TABLE analytics_auctions
+ADD COLUMN IF NOT EXISTS rate_version VARCHAR(32);
+
+ALTER TABLE analytics_bids
+ADD COLUMN IF NOT EXISTS original_currency VARCHAR(3),
+ADD COLUMN IF NOT EXISTS rate_version VARCHAR(32);
+
+COMMENT ON COLUMN analytics_auctions.rate_version IS 'Rate snapshot version used to convert the response to the request currency';
+COMMENT ON COLUMN analytics_bids.original_currency IS 'Currency the bidder responded in, when it differs from the auction currency';
+COMMENT ON COLUMN analytics_bids.rate_version IS 'Rate snapshot version (or ''request'' for ext.prebid.currency.rates) used to convert the bid';
@@ -0,0 +1,28 @@
+-- =====================================================
+-- Win, Billing and Impression Notifications
+-- =====================================================
+-- This migration stores the notifications that confirm a
+-- winning bid was delivered: impression pixels and win or
+-- billing notices from /ad/track, and video impression
+-- events. Publisher reports join them to analytics_bids
+-- on bid_id to count impressions and gross revenue.
+--
+-- It also indexes analytics_bidder_results by auction so
+-- the per-bidder report grouping can join on auction_id.
+-- =====================================================
+
+CREATE TABLE IF NOT EXISTS analytics_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    notification_type VARCHAR(16) NOT NULL,       -- 'win', 'billing', 'impression'
+    bid_id VARCHAR(255) NOT NULL,
+    bidder_code VARCHAR(50),
+    account_id VARCHAR(255),
+    placement VARCHAR(255),
+    source VARCHAR(16) NOT NULL,                  -- 'display', 'video'
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_notifications_bid ON analytics_notifications(bid_id, notification_type);
+CREATE INDEX IF NOT EXISTS idx_analytics_notifications_created ON analytics_notifications(created_at);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_bidder_results_auction ON analytics_bidder_results(auction_id);
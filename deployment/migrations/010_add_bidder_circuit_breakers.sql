@@ -0,0 +1,16 @@
+-- =====================================================
+-- Bidder Circuit Breaker Settings
+-- =====================================================
+-- This migration adds per-bidder circuit breaker
+-- settings to the bidders table. The server reads them
+-- for active bidders at startup; BIDDER_CIRCUIT_BREAKERS
+-- only applies to bidders where this column is NULL.
+--
+-- Example: {"error_rate": 0.5, "window_seconds": 60, "min_requests": 50}
+-- Unset fields take the exchange defaults.
+-- =====================================================
+
+ALTER TABLE bidders
+ADD COLUMN IF NOT EXISTS circuit_breaker JSONB;
+
+COMMENT ON COLUMN bidders.circuit_breaker IS 'Circuit breaker settings (failure_threshold, success_threshold, timeout_seconds, error_rate, window_seconds, min_requests)';
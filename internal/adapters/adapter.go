@@ -13,11 +13,15 @@ import (
 
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/tracing"
 )
 
 // maxResponseSize limits bidder response size to prevent OOM attacks
 const maxResponseSize = 1024 * 1024 // 1MB
 
+// TestBidderHeader carries the bidder code on requests sent to the test-mode HTTPClient (test=1)
+const TestBidderHeader = "X-Test-Bidder"
+
 // Adapter defines the interface for bidder adapters
 type Adapter interface {
 	// MakeRequests builds HTTP requests for the bidder
@@ -72,6 +76,7 @@ type TypedBid struct {
 	BidVideo     *BidVideo
 	BidMeta      *openrtb.ExtBidPrebidMeta
 	DealPriority int
+	Seat         string // Alternate bidder code the bid is made under (empty = the bidder's own code)
 }
 
 // BidType represents the type of bid
@@ -115,6 +120,8 @@ type BidderInfo struct {
 	Endpoint                string
 	ExtraInfo               string
 	DemandType              DemandType // platform (obfuscated) or publisher (transparent)
+	Currencies              []string   // Currencies the bidder accepts, preferred first (empty = auction currency only)
+	AliasOf                 string     // Core bidder code when this bidder is an alias (empty for core bidders)
 }
 
 // MaintainerInfo contains maintainer info
@@ -141,6 +148,7 @@ type DebugInfo struct {
 // SyncerInfo contains user sync configuration
 type SyncerInfo struct {
 	Supports []string
+	Key      string // Cookie key the bidder's user ID is stored under (empty = bidder code)
 }
 
 // AdapterConfig holds runtime adapter configuration
@@ -207,8 +215,9 @@ func NewHTTPClient(timeout time.Duration) *DefaultHTTPClient {
 
 	return &DefaultHTTPClient{
 		client: &http.Client{
-			Timeout:   timeout,
-			Transport: transport,
+			Timeout: timeout,
+			// Bidders get client spans with connection timings but never our trace context
+			Transport: &tracing.Transport{Base: transport, Component: "bidder"},
 		},
 	}
 }
@@ -278,7 +287,8 @@ func (c *DefaultHTTPClient) Do(ctx context.Context, req *RequestData, timeout ti
 		if result.err != nil && !errors.Is(result.err, io.EOF) {
 			// Log non-EOF errors that occurred during cancellation for debugging
 			// These are typically network errors masked by the context cancellation
-			logger.Log.Debug().
+			log := logger.FromContext(ctx)
+			log.Debug().
 				Err(result.err).
 				Str("uri", req.URI).
 				Msg("read error during context cancellation (masked by timeout)")
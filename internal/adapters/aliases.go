@@ -0,0 +1,150 @@
+package adapters
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// AliasConfig defines a bidder alias: a new bidder code served by an existing adapter.
+// Zero-valued overrides inherit the core bidder's settings.
+type AliasConfig struct {
+	AliasOf     string `json:"aliasOf"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	GVLVendorID int    `json:"gvlVendorId,omitempty"`
+	SyncerKey   string `json:"syncerKey,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// RegisterAlias registers alias as a bidder backed by an already registered core adapter.
+// The alias is a bidder in its own right (own seat, params, circuit breaker) and can
+// override the endpoint, GVL vendor ID and syncer key. Aliases of aliases are rejected.
+func (r *Registry) RegisterAlias(alias string, config AliasConfig) error {
+	if alias == "" || config.AliasOf == "" {
+		return fmt.Errorf("alias and aliasOf are required")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.adapters[alias]; exists {
+		return fmt.Errorf("adapter already registered: %s", alias)
+	}
+	core, ok := r.adapters[config.AliasOf]
+	if !ok {
+		return fmt.Errorf("alias %s: unknown core bidder %s", alias, config.AliasOf)
+	}
+	if core.Info.AliasOf != "" {
+		return fmt.Errorf("alias %s: %s is itself an alias of %s", alias, config.AliasOf, core.Info.AliasOf)
+	}
+
+	awi, err := aliasAdapter(core, config)
+	if err != nil {
+		return fmt.Errorf("alias %s: %w", alias, err)
+	}
+	r.adapters[alias] = awi
+	return nil
+}
+
+// ListAliases returns every registered alias mapped to its core bidder code
+func (r *Registry) ListAliases() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	aliases := make(map[string]string)
+	for code, awi := range r.adapters {
+		if awi.Info.AliasOf != "" {
+			aliases[code] = awi.Info.AliasOf
+		}
+	}
+	return aliases
+}
+
+// AliasOf returns core's adapter presented as an alias of it, for request-scoped aliases
+// (ext.prebid.aliases) that only exist for the duration of one auction
+func AliasOf(coreCode string, core AdapterWithInfo) AdapterWithInfo {
+	awi, _ := aliasAdapter(core, AliasConfig{AliasOf: coreCode})
+	return awi
+}
+
+// aliasAdapter derives an alias's adapter and info from its core bidder
+func aliasAdapter(core AdapterWithInfo, config AliasConfig) (AdapterWithInfo, error) {
+	info := core.Info
+	info.AliasOf = config.AliasOf
+	if config.Disabled {
+		info.Enabled = false
+	}
+	if config.GVLVendorID > 0 {
+		info.GVLVendorID = config.GVLVendorID
+	}
+
+	// Aliases share the core bidder's user ID unless they sync under their own key
+	syncer := SyncerInfo{Key: config.AliasOf}
+	if core.Info.Syncer != nil {
+		syncer = *core.Info.Syncer
+		syncer.Supports = append([]string(nil), core.Info.Syncer.Supports...)
+		if syncer.Key == "" {
+			syncer.Key = config.AliasOf
+		}
+	}
+	if config.SyncerKey != "" {
+		syncer.Key = config.SyncerKey
+	}
+	info.Syncer = &syncer
+
+	adapter := &aliasedAdapter{Adapter: core.Adapter, coreCode: config.AliasOf}
+	if config.Endpoint != "" {
+		endpoint, err := url.Parse(config.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return AdapterWithInfo{}, fmt.Errorf("invalid endpoint %q", config.Endpoint)
+		}
+		info.Endpoint = config.Endpoint
+		adapter.endpoint = endpoint
+	}
+	return AdapterWithInfo{Adapter: adapter, Info: info}, nil
+}
+
+// SyncerKey returns the cookie key the bidder's user ID is stored under
+func (i BidderInfo) SyncerKey(bidderCode string) string {
+	if i.Syncer != nil && i.Syncer.Key != "" {
+		return i.Syncer.Key
+	}
+	return bidderCode
+}
+
+// aliasedAdapter runs the core adapter for an alias. It reports the core bidder code in
+// ExtraRequestInfo.BidderCoreName and, when the alias has its own endpoint, sends requests
+// there instead (keeping the adapter's query string unless the endpoint sets its own).
+type aliasedAdapter struct {
+	Adapter
+	coreCode string
+	endpoint *url.URL
+}
+
+// MakeRequests builds the core adapter's requests for the alias
+func (a *aliasedAdapter) MakeRequests(request *openrtb.BidRequest, extraInfo *ExtraRequestInfo) ([]*RequestData, []error) {
+	aliasInfo := ExtraRequestInfo{}
+	if extraInfo != nil {
+		aliasInfo = *extraInfo
+	}
+	aliasInfo.BidderCoreName = a.coreCode
+
+	requests, errs := a.Adapter.MakeRequests(request, &aliasInfo)
+	if a.endpoint == nil {
+		return requests, errs
+	}
+	for _, req := range requests {
+		if req == nil {
+			continue
+		}
+		target := *a.endpoint
+		if target.RawQuery == "" {
+			if original, err := url.Parse(req.URI); err == nil {
+				target.RawQuery = original.RawQuery
+			}
+		}
+		req.URI = target.String()
+	}
+	return requests, errs
+}
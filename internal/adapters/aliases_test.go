@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// uriAdapter returns one request to a fixed URI and records the extra info it was given
+type uriAdapter struct {
+	mockAdapter
+	uri       string
+	extraInfo *ExtraRequestInfo
+}
+
+func (a *uriAdapter) MakeRequests(request *openrtb.BidRequest, extraInfo *ExtraRequestInfo) ([]*RequestData, []error) {
+	a.extraInfo = extraInfo
+	return []*RequestData{{Method: "POST", URI: a.uri}}, nil
+}
+
+func TestRegistry_RegisterAlias(t *testing.T) {
+	r := NewRegistry()
+	core := &uriAdapter{uri: "https://core.example.com/auction?src=pbs"}
+	r.Register("rubicon", core, BidderInfo{
+		Enabled:     true,
+		GVLVendorID: 52,
+		Endpoint:    "https://core.example.com/auction",
+		Syncer:      &SyncerInfo{Supports: []string{"redirect"}},
+	})
+
+	err := r.RegisterAlias("rubicon2", AliasConfig{AliasOf: "rubicon", Endpoint: "https://alias.example.com/bid", SyncerKey: "rubicon2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.RegisterAlias("rubicon3", AliasConfig{AliasOf: "rubicon", GVLVendorID: 99}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	awi, ok := r.Get("rubicon2")
+	if !ok {
+		t.Fatal("expected alias to be registered")
+	}
+	if awi.Info.AliasOf != "rubicon" || awi.Info.GVLVendorID != 52 || !awi.Info.Enabled {
+		t.Errorf("Unexpected alias info: %+v", awi.Info)
+	}
+	if awi.Info.SyncerKey("rubicon2") != "rubicon2" || awi.Info.Syncer.Supports[0] != "redirect" {
+		t.Errorf("Unexpected alias syncer: %+v", awi.Info.Syncer)
+	}
+
+	requests, _ := awi.Adapter.MakeRequests(&openrtb.BidRequest{}, &ExtraRequestInfo{PbsEntryPoint: "auction"})
+	if len(requests) != 1 || requests[0].URI != "https://alias.example.com/bid?src=pbs" {
+		t.Errorf("Expected requests sent to the alias endpoint, got %+v", requests)
+	}
+	if core.extraInfo.BidderCoreName != "rubicon" || core.extraInfo.PbsEntryPoint != "auction" {
+		t.Errorf("Expected core bidder name in extra info, got %+v", core.extraInfo)
+	}
+
+	other, _ := r.Get("rubicon3")
+	if other.Info.GVLVendorID != 99 || other.Info.SyncerKey("rubicon3") != "rubicon" {
+		t.Errorf("Expected GVL override and shared syncer key, got %+v", other.Info)
+	}
+	requests, _ = other.Adapter.MakeRequests(&openrtb.BidRequest{}, nil)
+	if len(requests) != 1 || requests[0].URI != core.uri {
+		t.Errorf("Expected the core endpoint without an override, got %+v", requests)
+	}
+
+	if aliases := r.ListAliases(); len(aliases) != 2 || aliases["rubicon2"] != "rubicon" {
+		t.Errorf("Unexpected aliases: %v", aliases)
+	}
+	if core, _ := r.Get("rubicon"); core.Info.Syncer.Key != "" {
+		t.Error("Registering an alias must not modify the core bidder")
+	}
+}
+
+func TestRegistry_RegisterAlias_Errors(t *testing.T) {
+	r := NewRegistry()
+	r.Register("appnexus", &mockAdapter{}, BidderInfo{Enabled: true})
+	r.RegisterAlias("apn", AliasConfig{AliasOf: "appnexus"})
+
+	tests := []struct {
+		name   string
+		alias  string
+		config AliasConfig
+	}{
+		{"missing core", "x", AliasConfig{}},
+		{"unknown core", "x", AliasConfig{AliasOf: "unknown"}},
+		{"alias of alias", "x", AliasConfig{AliasOf: "apn"}},
+		{"duplicate code", "appnexus", AliasConfig{AliasOf: "appnexus"}},
+		{"bad endpoint", "x", AliasConfig{AliasOf: "appnexus", Endpoint: "not a url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.RegisterAlias(tt.alias, tt.config); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestAliasOf(t *testing.T) {
+	core := AdapterWithInfo{Adapter: &mockAdapter{}, Info: BidderInfo{Enabled: true, GVLVendorID: 32}}
+	awi := AliasOf("appnexus", core)
+	if awi.Info.AliasOf != "appnexus" || awi.Info.GVLVendorID != 32 || awi.Info.SyncerKey("apn") != "appnexus" {
+		t.Errorf("Unexpected request alias: %+v", awi.Info)
+	}
+}
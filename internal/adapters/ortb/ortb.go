@@ -494,6 +494,12 @@ func (a *GenericAdapter) Info() adapters.BidderInfo {
 		}
 	}
 
+	for _, cur := range config.Capabilities.Currencies {
+		if cur = strings.ToUpper(strings.TrimSpace(cur)); cur != "" {
+			info.Currencies = append(info.Currencies, cur)
+		}
+	}
+
 	return info
 }
 
@@ -575,6 +575,16 @@ func TestGenericAdapter_Info_Capabilities(t *testing.T) {
 	}
 }
 
+func TestGenericAdapter_Info_Currencies(t *testing.T) {
+	config := basicConfig()
+	config.Capabilities.Currencies = []string{"eur", " USD ", ""}
+	info := New(config).Info()
+
+	if len(info.Currencies) != 2 || info.Currencies[0] != "EUR" || info.Currencies[1] != "USD" {
+		t.Errorf("expected [EUR USD], got %v", info.Currencies)
+	}
+}
+
 func TestGenericAdapter_IsEnabled(t *testing.T) {
 	tests := []struct {
 		status   string
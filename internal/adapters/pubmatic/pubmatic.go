@@ -87,6 +87,7 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 			response.Bids = append(response.Bids, &adapters.TypedBid{
 				Bid:     bid,
 				BidType: bidType,
+				Seat:    marketplaceSeat(bid),
 			})
 		}
 	}
@@ -94,6 +95,24 @@ func (a *Adapter) MakeBids(request *openrtb.BidRequest, responseData *adapters.R
 	return response, nil
 }
 
+// bidExt is the part of PubMatic's bid ext the adapter reads
+type bidExt struct {
+	Marketplace string `json:"marketplace"` // Alternate bidder code for marketplace demand
+}
+
+// marketplaceSeat returns the alternate bidder code PubMatic reports in bid.ext.marketplace,
+// or "" for bids made under PubMatic's own code. seatbid.seat is the buyer seat, not a bidder code.
+func marketplaceSeat(bid *openrtb.Bid) string {
+	if len(bid.Ext) == 0 {
+		return ""
+	}
+	var ext bidExt
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil || ext.Marketplace == "pubmatic" {
+		return ""
+	}
+	return ext.Marketplace
+}
+
 // Info returns bidder information
 func Info() adapters.BidderInfo {
 	return adapters.BidderInfo{
@@ -83,6 +83,29 @@ func TestMakeBids_Success(t *testing.T) {
 	}
 }
 
+func TestMakeBids_MarketplaceSeat(t *testing.T) {
+	adapter := New("")
+	request := &openrtb.BidRequest{
+		ID:  "test-request-1",
+		Imp: []openrtb.Imp{{ID: "imp-1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	responseBody := `{"id": "response-1", "seatbid": [{"seat": "dsp-42", "bid": [
+		{"id": "bid-1", "impid": "imp-1", "price": 1.75},
+		{"id": "bid-2", "impid": "imp-1", "price": 1.50, "ext": {"marketplace": "groupm"}},
+		{"id": "bid-3", "impid": "imp-1", "price": 1.25, "ext": {"marketplace": "pubmatic"}}
+	]}]}`
+
+	bidderResponse, errs := adapter.MakeBids(request, &adapters.ResponseData{StatusCode: http.StatusOK, Body: []byte(responseBody)})
+	if len(errs) > 0 || bidderResponse == nil || len(bidderResponse.Bids) != 3 {
+		t.Fatalf("Expected 3 bids, got %v %v", bidderResponse, errs)
+	}
+	for i, want := range []string{"", "groupm", ""} {
+		if got := bidderResponse.Bids[i].Seat; got != want {
+			t.Errorf("Expected bid %d seat %q, got %q", i+1, want, got)
+		}
+	}
+}
+
 func TestMakeBids_NoContent(t *testing.T) {
 	adapter := New("")
 	response := &adapters.ResponseData{StatusCode: http.StatusNoContent}
@@ -0,0 +1,159 @@
+// Package analytics provides pluggable analytics modules that receive a complete
+// view of every auction, video event and cookie sync handled by the server.
+package analytics
+
+import (
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// Module is implemented by analytics sinks.
+// Objects passed to a module are shared with other modules and must be treated as read-only.
+type Module interface {
+	LogAuctionObject(ao *AuctionObject)
+	LogVideoObject(vo *VideoObject)
+	LogCookieSyncObject(cso *CookieSyncObject)
+	LogNotificationObject(no *NotificationObject)
+	Shutdown()
+}
+
+// Auction status values reported in AuctionObject.Status
+const (
+	AuctionStatusSuccess   = "success"
+	AuctionStatusNoBids    = "no_bids"
+	AuctionStatusNoBidders = "no_bidders"
+	AuctionStatusTimeout   = "timeout"
+	AuctionStatusRejected  = "rejected"
+	AuctionStatusError     = "error"
+)
+
+// AuctionObject is the full record of a single auction
+type AuctionObject struct {
+	AuctionID       string                   `json:"auction_id"`
+	PublisherID     string                   `json:"publisher_id,omitempty"`
+	Status          string                   `json:"status"`
+	Timestamp       time.Time                `json:"timestamp"`
+	LatencyMs       int64                    `json:"latency_ms"`
+	IDRLatencyMs    int64                    `json:"idr_latency_ms,omitempty"`
+	Currency        string                   `json:"currency,omitempty"`     // Auction currency of bid prices and floors; Response.Cur may differ
+	RateVersion     string                   `json:"rate_version,omitempty"` // Rates used to convert the response currency
+	Country         string                   `json:"country,omitempty"`
+	DeviceType      string                   `json:"device_type,omitempty"`
+	Request         *openrtb.BidRequest      `json:"request,omitempty"` // Personal data scrubbed before modules see it
+	Response        *openrtb.BidResponse     `json:"response,omitempty"`
+	SelectedBidders []string                 `json:"selected_bidders,omitempty"`
+	ExcludedBidders []string                 `json:"excluded_bidders,omitempty"`
+	Imps            []ImpObject              `json:"imps,omitempty"`
+	Bidders         map[string]*BidderObject `json:"bidders,omitempty"`
+	Bids            []BidObject              `json:"bids,omitempty"`
+	Errors          map[string][]string      `json:"errors,omitempty"`
+	Error           string                   `json:"error,omitempty"`
+}
+
+// ImpObject describes a requested impression
+type ImpObject struct {
+	ImpID     string  `json:"imp_id"`
+	AdUnit    string  `json:"ad_unit,omitempty"` // imp.tagid
+	MediaType string  `json:"media_type,omitempty"`
+	Floor     float64 `json:"floor,omitempty"`
+}
+
+// BidderObject describes the outcome of calling a single bidder
+type BidderObject struct {
+	BidderCode string   `json:"bidder_code"`
+	LatencyMs  int64    `json:"latency_ms"`
+	BidCount   int      `json:"bid_count"`
+	TimedOut   bool     `json:"timed_out,omitempty"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// BidObject describes a bid that passed validation.
+// BidPrice is the price as returned by the bidder (after currency conversion),
+// GrossPrice is the price after auction logic, and ClearingPrice is the price
+// returned to the publisher after the bid multiplier.
+// OriginalCurrency and RateVersion are set when the bid was converted from the bidder's currency.
+type BidObject struct {
+	BidID         string  `json:"bid_id"`
+	ImpID         string  `json:"imp_id"`
+	BidderCode    string  `json:"bidder_code"`
+	Seat          string  `json:"seat,omitempty"`
+	DealID        string  `json:"deal_id,omitempty"`
+	MediaType     string  `json:"media_type,omitempty"`
+	BidPrice      float64 `json:"bid_price"`
+	GrossPrice    float64 `json:"gross_price"`
+	ClearingPrice float64 `json:"clearing_price"`
+	Won           bool    `json:"won"`
+
+	OriginalCurrency string `json:"original_currency,omitempty"`
+	RateVersion      string `json:"rate_version,omitempty"`
+}
+
+// Winners returns the winning bids of the auction
+func (ao *AuctionObject) Winners() []BidObject {
+	winners := make([]BidObject, 0, len(ao.Imps))
+	for _, b := range ao.Bids {
+		if b.Won {
+			winners = append(winners, b)
+		}
+	}
+	return winners
+}
+
+// VideoObject is the record of a video tracking event
+type VideoObject struct {
+	EventType    string    `json:"event_type"`
+	BidID        string    `json:"bid_id"`
+	AccountID    string    `json:"account_id,omitempty"`
+	Bidder       string    `json:"bidder,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+	Progress     float64   `json:"progress,omitempty"`
+	ErrorCode    string    `json:"error_code,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	SessionID    string    `json:"session_id,omitempty"`
+	ContentID    string    `json:"content_id,omitempty"`
+	IPAddress    string    `json:"ip_address,omitempty"` // Anonymized
+	UserAgent    string    `json:"user_agent,omitempty"` // Anonymized
+}
+
+// Notification types reported in NotificationObject.Type
+const (
+	NotificationWin        = "win"
+	NotificationBilling    = "billing"
+	NotificationImpression = "impression"
+)
+
+// Notification sources reported in NotificationObject.Source
+const (
+	NotificationSourceDisplay = "display"
+	NotificationSourceVideo   = "video"
+)
+
+// NotificationObject is the record of a win, billing or impression notification for a bid.
+// Reports join notifications to analytics bids on BidID to count delivered impressions.
+type NotificationObject struct {
+	Type      string    `json:"type"`
+	BidID     string    `json:"bid_id"`
+	Bidder    string    `json:"bidder,omitempty"`
+	AccountID string    `json:"account_id,omitempty"`
+	Placement string    `json:"placement,omitempty"`
+	Source    string    `json:"source"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CookieSyncObject is the record of a cookie sync request
+type CookieSyncObject struct {
+	Status    string             `json:"status"`
+	Timestamp time.Time          `json:"timestamp"`
+	GDPR      bool               `json:"gdpr"`
+	OptOut    bool               `json:"opt_out,omitempty"`
+	Limit     int                `json:"limit"`
+	Bidders   []CookieSyncBidder `json:"bidders,omitempty"`
+}
+
+// CookieSyncBidder is the sync result for a single bidder
+type CookieSyncBidder struct {
+	BidderCode string `json:"bidder_code"`
+	SyncType   string `json:"sync_type,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
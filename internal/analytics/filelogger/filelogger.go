@@ -0,0 +1,166 @@
+// Package filelogger implements an analytics module that appends JSON lines to a file
+package filelogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// Record types written to the "type" field of each line
+const (
+	RecordTypeAuction      = "auction"
+	RecordTypeVideo        = "video"
+	RecordTypeCookieSync   = "cookie_sync"
+	RecordTypeNotification = "notification"
+)
+
+// Config holds file logger configuration
+type Config struct {
+	// Path of the JSONL file; created if missing, appended otherwise
+	Path string
+	// FlushInterval controls how often buffered lines are written to disk
+	FlushInterval time.Duration
+}
+
+// DefaultConfig returns the default configuration for the given path
+func DefaultConfig(path string) *Config {
+	return &Config{
+		Path:          path,
+		FlushInterval: time.Second,
+	}
+}
+
+// Record is a single line of the log file
+type Record struct {
+	Type         string                        `json:"type"`
+	Auction      *analytics.AuctionObject      `json:"auction,omitempty"`
+	Video        *analytics.VideoObject        `json:"video,omitempty"`
+	CookieSync   *analytics.CookieSyncObject   `json:"cookie_sync,omitempty"`
+	Notification *analytics.NotificationObject `json:"notification,omitempty"`
+}
+
+// Module writes analytics objects as JSON lines
+type Module struct {
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+	closed bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New opens the log file and starts the periodic flush loop
+func New(config *Config) (*Module, error) {
+	if config == nil || config.Path == "" {
+		return nil, fmt.Errorf("file logger path is required")
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+
+	f, err := os.OpenFile(config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("open analytics log: %w", err)
+	}
+
+	m := &Module{
+		file:   f,
+		writer: bufio.NewWriter(f),
+		stopCh: make(chan struct{}),
+	}
+
+	m.wg.Add(1)
+	go m.flushLoop(config.FlushInterval)
+
+	return m, nil
+}
+
+// LogAuctionObject writes an auction record
+func (m *Module) LogAuctionObject(ao *analytics.AuctionObject) {
+	m.write(&Record{Type: RecordTypeAuction, Auction: ao})
+}
+
+// LogVideoObject writes a video event record
+func (m *Module) LogVideoObject(vo *analytics.VideoObject) {
+	m.write(&Record{Type: RecordTypeVideo, Video: vo})
+}
+
+// LogCookieSyncObject writes a cookie sync record
+func (m *Module) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	m.write(&Record{Type: RecordTypeCookieSync, CookieSync: cso})
+}
+
+// LogNotificationObject writes a win, billing or impression notification record
+func (m *Module) LogNotificationObject(no *analytics.NotificationObject) {
+	m.write(&Record{Type: RecordTypeNotification, Notification: no})
+}
+
+// Shutdown flushes buffered lines and closes the file
+func (m *Module) Shutdown() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	close(m.stopCh)
+	m.wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.writer.Flush(); err != nil {
+		logger.Log.Warn().Err(err).Msg("failed to flush analytics log")
+	}
+	if err := m.file.Close(); err != nil {
+		logger.Log.Warn().Err(err).Msg("failed to close analytics log")
+	}
+}
+
+// write marshals a record and appends it to the buffer
+func (m *Module) write(rec *Record) {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("type", rec.Type).Msg("failed to marshal analytics record")
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	if _, err := m.writer.Write(append(line, '\n')); err != nil {
+		logger.Log.Warn().Err(err).Str("type", rec.Type).Msg("failed to write analytics record")
+	}
+}
+
+// flushLoop periodically flushes the buffered writer
+func (m *Module) flushLoop(interval time.Duration) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.mu.Lock()
+			if err := m.writer.Flush(); err != nil {
+				logger.Log.Warn().Err(err).Msg("failed to flush analytics log")
+			}
+			m.mu.Unlock()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package filelogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+)
+
+func readRecords(t *testing.T, path string) []Record {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestModule_WritesJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+
+	m, err := New(DefaultConfig(path))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	m.LogAuctionObject(&analytics.AuctionObject{
+		AuctionID: "auction-1",
+		Status:    analytics.AuctionStatusSuccess,
+		Bids: []analytics.BidObject{
+			{BidID: "bid-1", ImpID: "imp-1", BidderCode: "appnexus", BidPrice: 2.5, ClearingPrice: 2.38, Won: true},
+		},
+	})
+	m.LogVideoObject(&analytics.VideoObject{EventType: "start", BidID: "bid-1"})
+	m.LogCookieSyncObject(&analytics.CookieSyncObject{Status: "ok", Limit: 8})
+	m.Shutdown()
+
+	records := readRecords(t, path)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+
+	if records[0].Type != RecordTypeAuction || records[0].Auction == nil || records[0].Auction.AuctionID != "auction-1" {
+		t.Errorf("unexpected auction record: %+v", records[0])
+	}
+	if len(records[0].Auction.Bids) != 1 || !records[0].Auction.Bids[0].Won {
+		t.Errorf("expected winning bid in auction record")
+	}
+	if records[1].Type != RecordTypeVideo || records[1].Video.EventType != "start" {
+		t.Errorf("unexpected video record: %+v", records[1])
+	}
+	if records[2].Type != RecordTypeCookieSync || records[2].CookieSync.Limit != 8 {
+		t.Errorf("unexpected cookie sync record: %+v", records[2])
+	}
+}
+
+func TestModule_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+
+	for i := 0; i < 2; i++ {
+		m, err := New(DefaultConfig(path))
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		m.LogAuctionObject(&analytics.AuctionObject{AuctionID: "a"})
+		m.Shutdown()
+	}
+
+	if records := readRecords(t, path); len(records) != 2 {
+		t.Errorf("expected 2 records after reopening, got %d", len(records))
+	}
+}
+
+func TestModule_PeriodicFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+
+	m, err := New(&Config{Path: path, FlushInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer m.Shutdown()
+
+	m.LogAuctionObject(&analytics.AuctionObject{AuctionID: "a"})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("expected record to be flushed to disk without shutdown")
+}
+
+func TestNew_RequiresPath(t *testing.T) {
+	if _, err := New(&Config{}); err == nil {
+		t.Error("expected error for empty path")
+	}
+	if _, err := New(DefaultConfig(filepath.Join(t.TempDir(), "missing", "dir", "x.jsonl"))); err == nil {
+		t.Error("expected error for unwritable path")
+	}
+}
@@ -0,0 +1,318 @@
+// Package pgwriter implements an analytics module that persists auctions to PostgreSQL
+package pgwriter
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// Config holds Postgres writer configuration
+type Config struct {
+	// BatchSize is the number of auctions written per transaction
+	BatchSize int
+	// FlushInterval bounds how long an auction may wait in the batch
+	FlushInterval time.Duration
+	// WriteTimeout is the timeout for a single batch or event write
+	WriteTimeout time.Duration
+}
+
+// DefaultConfig returns recommended configuration
+func DefaultConfig() *Config {
+	return &Config{
+		BatchSize:     100,
+		FlushInterval: 2 * time.Second,
+		WriteTimeout:  5 * time.Second,
+	}
+}
+
+// Module batches auction objects and writes them to the analytics tables
+type Module struct {
+	db     *sql.DB
+	config *Config
+
+	mu     sync.Mutex
+	batch  []*analytics.AuctionObject
+	closed bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a Postgres analytics module and starts its flush loop
+func New(db *sql.DB, config *Config) *Module {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 2 * time.Second
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = 5 * time.Second
+	}
+
+	m := &Module{
+		db:     db,
+		config: config,
+		batch:  make([]*analytics.AuctionObject, 0, config.BatchSize),
+		stopCh: make(chan struct{}),
+	}
+
+	m.wg.Add(1)
+	go m.flushLoop()
+
+	return m
+}
+
+// LogAuctionObject adds an auction to the current batch, writing it when full
+func (m *Module) LogAuctionObject(ao *analytics.AuctionObject) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.batch = append(m.batch, ao)
+	var toWrite []*analytics.AuctionObject
+	if len(m.batch) >= m.config.BatchSize {
+		toWrite = m.batch
+		m.batch = make([]*analytics.AuctionObject, 0, m.config.BatchSize)
+	}
+	m.mu.Unlock()
+
+	if toWrite != nil {
+		m.writeBatch(toWrite)
+	}
+}
+
+// LogVideoObject writes a video event row
+func (m *Module) LogVideoObject(vo *analytics.VideoObject) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.WriteTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO analytics_video_events (
+			event_type, bid_id, account_id, bidder_code, progress, error_code, session_id, content_id, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err := m.db.ExecContext(ctx, query,
+		vo.EventType,
+		vo.BidID,
+		vo.AccountID,
+		vo.Bidder,
+		vo.Progress,
+		vo.ErrorCode,
+		vo.SessionID,
+		vo.ContentID,
+		vo.Timestamp,
+	)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("event", vo.EventType).Msg("failed to write video analytics event")
+	}
+}
+
+// LogCookieSyncObject writes a cookie sync row
+func (m *Module) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.WriteTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO analytics_cookie_syncs (
+			status, gdpr, opt_out, sync_limit, bidders_returned, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := m.db.ExecContext(ctx, query,
+		cso.Status,
+		cso.GDPR,
+		cso.OptOut,
+		cso.Limit,
+		len(cso.Bidders),
+		cso.Timestamp,
+	)
+	if err != nil {
+		logger.Log.Warn().Err(err).Msg("failed to write cookie sync analytics event")
+	}
+}
+
+// LogNotificationObject writes a win, billing or impression notification row
+func (m *Module) LogNotificationObject(no *analytics.NotificationObject) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.WriteTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO analytics_notifications (
+			notification_type, bid_id, bidder_code, account_id, placement, source, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := m.db.ExecContext(ctx, query,
+		no.Type,
+		no.BidID,
+		no.Bidder,
+		no.AccountID,
+		no.Placement,
+		no.Source,
+		no.Timestamp,
+	)
+	if err != nil {
+		logger.Log.Warn().Err(err).Str("type", no.Type).Msg("failed to write notification analytics event")
+	}
+}
+
+// Shutdown stops the flush loop and writes any pending auctions
+func (m *Module) Shutdown() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	m.mu.Unlock()
+
+	close(m.stopCh)
+	m.wg.Wait()
+	m.flush()
+}
+
+// flushLoop writes partial batches on an interval
+func (m *Module) flushLoop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.flush()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// flush takes the current batch and writes it
+func (m *Module) flush() {
+	m.mu.Lock()
+	if len(m.batch) == 0 {
+		m.mu.Unlock()
+		return
+	}
+	toWrite := m.batch
+	m.batch = make([]*analytics.AuctionObject, 0, m.config.BatchSize)
+	m.mu.Unlock()
+
+	m.writeBatch(toWrite)
+}
+
+// writeBatch writes a batch of auctions in a single transaction
+func (m *Module) writeBatch(batch []*analytics.AuctionObject) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.WriteTimeout)
+	defer cancel()
+
+	if err := m.insertAuctions(ctx, batch); err != nil {
+		logger.Log.Warn().Err(err).Int("auctions", len(batch)).Msg("failed to write auction analytics batch")
+	}
+}
+
+// insertAuctions inserts auctions and their child rows
+func (m *Module) insertAuctions(ctx context.Context, batch []*analytics.AuctionObject) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, ao := range batch {
+		if err := insertAuction(ctx, tx, ao); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit analytics batch: %w", err)
+	}
+	return nil
+}
+
+// insertAuction inserts a single auction with its imps, bidder results and bids
+func insertAuction(ctx context.Context, tx *sql.Tx, ao *analytics.AuctionObject) error {
+	impCount := len(ao.Imps)
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO analytics_auctions (
+			auction_id, publisher_id, status, imp_count, bidders_selected, bidders_excluded,
+			bid_count, latency_ms, currency, country, device_type, error, created_at, rate_version
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`,
+		ao.AuctionID,
+		ao.PublisherID,
+		ao.Status,
+		impCount,
+		len(ao.SelectedBidders),
+		len(ao.ExcludedBidders),
+		len(ao.Bids),
+		ao.LatencyMs,
+		ao.Currency,
+		ao.Country,
+		ao.DeviceType,
+		ao.Error,
+		ao.Timestamp,
+		ao.RateVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert auction %s: %w", ao.AuctionID, err)
+	}
+
+	adUnits := make(map[string]string, impCount)
+	for _, imp := range ao.Imps {
+		adUnits[imp.ImpID] = imp.AdUnit
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO analytics_imps (
+				auction_id, publisher_id, imp_id, ad_unit, media_type, floor, country, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			ao.AuctionID, ao.PublisherID, imp.ImpID, imp.AdUnit, imp.MediaType, imp.Floor, ao.Country, ao.Timestamp,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert imp %s: %w", imp.ImpID, err)
+		}
+	}
+
+	for _, br := range ao.Bidders {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO analytics_bidder_results (
+				auction_id, publisher_id, bidder_code, latency_ms, bid_count, timed_out, had_error, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			ao.AuctionID, ao.PublisherID, br.BidderCode, br.LatencyMs, br.BidCount, br.TimedOut, len(br.Errors) > 0, ao.Timestamp,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert bidder result %s: %w", br.BidderCode, err)
+		}
+	}
+
+	for _, b := range ao.Bids {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO analytics_bids (
+				auction_id, publisher_id, imp_id, bid_id, bidder_code, seat, deal_id, media_type,
+				ad_unit, country, bid_price, gross_price, clearing_price, won, created_at,
+				original_currency, rate_version
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		`,
+			ao.AuctionID, ao.PublisherID, b.ImpID, b.BidID, b.BidderCode, b.Seat, b.DealID, b.MediaType,
+			adUnits[b.ImpID], ao.Country, b.BidPrice, b.GrossPrice, b.ClearingPrice, b.Won, ao.Timestamp,
+			b.OriginalCurrency, b.RateVersion,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert bid %s: %w", b.BidID, err)
+		}
+	}
+
+	return nil
+}
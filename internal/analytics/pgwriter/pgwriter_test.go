@@ -0,0 +1,168 @@
+package pgwriter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+)
+
+func testAuction(id string) *analytics.AuctionObject {
+	return &analytics.AuctionObject{
+		AuctionID:       id,
+		PublisherID:     "pub-1",
+		Status:          analytics.AuctionStatusSuccess,
+		Timestamp:       time.Now(),
+		LatencyMs:       42,
+		Currency:        "USD",
+		Country:         "USA",
+		SelectedBidders: []string{"appnexus"},
+		Imps:            []analytics.ImpObject{{ImpID: "imp-1", AdUnit: "top-banner", MediaType: "banner", Floor: 0.5}},
+		Bidders: map[string]*analytics.BidderObject{
+			"appnexus": {BidderCode: "appnexus", LatencyMs: 30, BidCount: 1},
+		},
+		Bids: []analytics.BidObject{
+			{BidID: "bid-1", ImpID: "imp-1", BidderCode: "appnexus", MediaType: "banner", BidPrice: 2.0, GrossPrice: 2.0, ClearingPrice: 1.9, Won: true},
+		},
+	}
+}
+
+func expectAuctionInserts(mock sqlmock.Sqlmock, id string) {
+	mock.ExpectExec("INSERT INTO analytics_auctions").
+		WithArgs(id, "pub-1", analytics.AuctionStatusSuccess, 1, 1, 0, 1, int64(42), "USD", "USA", "", "", sqlmock.AnyArg(), "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO analytics_imps").
+		WithArgs(id, "pub-1", "imp-1", "top-banner", "banner", 0.5, "USA", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO analytics_bidder_results").
+		WithArgs(id, "pub-1", "appnexus", int64(30), 1, false, false, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO analytics_bids").
+		WithArgs(id, "pub-1", "imp-1", "bid-1", "appnexus", "", "", "banner", "top-banner", "USA", 2.0, 2.0, 1.9, true, sqlmock.AnyArg(), "", "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func TestModule_WritesBatchWhenFull(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	expectAuctionInserts(mock, "a1")
+	expectAuctionInserts(mock, "a2")
+	mock.ExpectCommit()
+
+	m := New(db, &Config{BatchSize: 2, FlushInterval: time.Hour, WriteTimeout: time.Second})
+	m.LogAuctionObject(testAuction("a1"))
+	m.LogAuctionObject(testAuction("a2"))
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+	m.Shutdown()
+}
+
+func TestModule_ShutdownFlushesPartialBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	expectAuctionInserts(mock, "a1")
+	mock.ExpectCommit()
+
+	m := New(db, &Config{BatchSize: 100, FlushInterval: time.Hour, WriteTimeout: time.Second})
+	m.LogAuctionObject(testAuction("a1"))
+	m.Shutdown()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+
+	// Objects logged after shutdown are ignored
+	m.LogAuctionObject(testAuction("late"))
+}
+
+func TestModule_RollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO analytics_auctions").WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	m := New(db, &Config{BatchSize: 1, FlushInterval: time.Hour, WriteTimeout: time.Second})
+	m.LogAuctionObject(testAuction("a1"))
+	m.Shutdown()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestModule_VideoAndCookieSync(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec("INSERT INTO analytics_video_events").
+		WithArgs("complete", "bid-1", "acct", "appnexus", 100.0, "", "s1", "c1", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO analytics_cookie_syncs").
+		WithArgs("ok", true, false, 8, 1, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	m := New(db, nil)
+	defer m.Shutdown()
+
+	m.LogVideoObject(&analytics.VideoObject{
+		EventType: "complete", BidID: "bid-1", AccountID: "acct", Bidder: "appnexus",
+		Progress: 100, SessionID: "s1", ContentID: "c1", Timestamp: now,
+	})
+	m.LogCookieSyncObject(&analytics.CookieSyncObject{
+		Status: "ok", GDPR: true, Limit: 8, Timestamp: now,
+		Bidders: []analytics.CookieSyncBidder{{BidderCode: "appnexus", SyncType: "redirect"}},
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestModule_Notification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectExec("INSERT INTO analytics_notifications").
+		WithArgs(analytics.NotificationImpression, "bid-1", "appnexus", "pub-1", "top-banner", analytics.NotificationSourceDisplay, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	m := New(db, nil)
+	defer m.Shutdown()
+
+	m.LogNotificationObject(&analytics.NotificationObject{
+		Type: analytics.NotificationImpression, BidID: "bid-1", Bidder: "appnexus", AccountID: "pub-1",
+		Placement: "top-banner", Source: analytics.NotificationSourceDisplay, Timestamp: now,
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
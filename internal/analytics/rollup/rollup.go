@@ -0,0 +1,452 @@
+// Package rollup implements an analytics module that aggregates auctions into
+// time-bucketed Redis counters shared by all instances
+package rollup
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+	"github.com/thenexusengine/tne_springwire/pkg/redis"
+)
+
+// Counter names stored in each bucket hash
+const (
+	metricRequests      = "requests"
+	metricBidResponses  = "bid_responses"
+	metricBids          = "bids"
+	metricWins          = "wins"
+	metricBidderCalls   = "bidder_calls"
+	metricTimeouts      = "timeouts"
+	metricRevenueMicros = "revenue_micros"
+	metricPayoutMicros  = "payout_micros"
+)
+
+// Dimensions counters are grouped by
+const (
+	dimensionPublisher = "publisher"
+	dimensionBidder    = "bidder"
+)
+
+// unknownPublisher groups auctions without a publisher ID
+const unknownPublisher = "unknown"
+
+// Bucket sizes; fine buckets serve short windows, coarse buckets long ones
+var bucketSizes = []time.Duration{5 * time.Minute, time.Hour}
+
+// Window is a selectable reporting window
+type Window struct {
+	Name     string
+	Duration time.Duration
+	Bucket   time.Duration
+}
+
+// Windows lists the supported reporting windows
+var Windows = []Window{
+	{Name: "1h", Duration: time.Hour, Bucket: 5 * time.Minute},
+	{Name: "24h", Duration: 24 * time.Hour, Bucket: time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour, Bucket: time.Hour},
+}
+
+// DefaultWindow is used when no window is requested
+const DefaultWindow = "1h"
+
+// LookupWindow returns the window with the given name
+func LookupWindow(name string) (Window, bool) {
+	for _, w := range Windows {
+		if w.Name == name {
+			return w, true
+		}
+	}
+	return Window{}, false
+}
+
+// Config holds rollup configuration
+type Config struct {
+	// KeyPrefix namespaces bucket keys in Redis
+	KeyPrefix string
+	// FlushInterval is how often locally accumulated counters are written
+	FlushInterval time.Duration
+	// Retention is the TTL applied to bucket keys; must exceed the longest window
+	Retention time.Duration
+	// Timeout bounds a single flush or query
+	Timeout time.Duration
+}
+
+// DefaultConfig returns recommended configuration
+func DefaultConfig() *Config {
+	return &Config{
+		KeyPrefix:     "tne_catalyst:stats",
+		FlushInterval: 5 * time.Second,
+		Retention:     8 * 24 * time.Hour,
+		Timeout:       3 * time.Second,
+	}
+}
+
+// Stats holds aggregated counters and derived rates for one publisher, bidder or total.
+// Revenue is what buyers pay (gross clearing price), payout is what publishers
+// receive after the bid multiplier; both are in the exchange currency.
+type Stats struct {
+	Requests     int64   `json:"requests"`
+	BidResponses int64   `json:"bid_responses"`
+	Bids         int64   `json:"bids"`
+	Wins         int64   `json:"wins"`
+	BidderCalls  int64   `json:"bidder_calls"`
+	Timeouts     int64   `json:"timeouts"`
+	Revenue      float64 `json:"revenue"`
+	Payout       float64 `json:"payout"`
+	Margin       float64 `json:"margin"`
+	BidRate      float64 `json:"bid_rate"`
+	WinRate      float64 `json:"win_rate"`
+	TimeoutRate  float64 `json:"timeout_rate"`
+	MarginRate   float64 `json:"margin_rate"`
+
+	revenueMicros int64
+	payoutMicros  int64
+}
+
+// Summary is the aggregated view of a window across all instances
+type Summary struct {
+	Window     string            `json:"window"`
+	From       time.Time         `json:"from"`
+	To         time.Time         `json:"to"`
+	Totals     *Stats            `json:"totals"`
+	Publishers map[string]*Stats `json:"publishers"`
+	Bidders    map[string]*Stats `json:"bidders"`
+}
+
+// Store accumulates auction counters locally and flushes them to Redis buckets
+type Store struct {
+	client *redis.Client
+	config *Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]map[string]int64 // bucket key -> field -> increment
+	closed  bool
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a rollup store and starts its flush loop
+func New(client *redis.Client, config *Config) *Store {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	if config.KeyPrefix == "" {
+		config.KeyPrefix = "tne_catalyst:stats"
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	if config.Retention <= 0 {
+		config.Retention = 8 * 24 * time.Hour
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 3 * time.Second
+	}
+
+	s := &Store{
+		client:  client,
+		config:  config,
+		now:     time.Now,
+		pending: make(map[string]map[string]int64),
+		stopCh:  make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.flushLoop()
+
+	return s
+}
+
+// LogAuctionObject adds the auction's counters to the pending buckets
+func (s *Store) LogAuctionObject(ao *analytics.AuctionObject) {
+	ts := ao.Timestamp
+	if ts.IsZero() {
+		ts = s.now()
+	}
+
+	publisherID := ao.PublisherID
+	if publisherID == "" {
+		publisherID = unknownPublisher
+	}
+
+	pub := make(map[string]int64)
+	bidders := make(map[string]map[string]int64)
+	bidder := func(code string) map[string]int64 {
+		counters, ok := bidders[code]
+		if !ok {
+			counters = make(map[string]int64)
+			bidders[code] = counters
+		}
+		return counters
+	}
+
+	pub[metricRequests] = 1
+	for code, result := range ao.Bidders {
+		b := bidder(code)
+		b[metricRequests]++
+		b[metricBidderCalls]++
+		pub[metricBidderCalls]++
+		if result.BidCount > 0 {
+			b[metricBidResponses]++
+		}
+		if result.TimedOut {
+			b[metricTimeouts]++
+			pub[metricTimeouts]++
+		}
+	}
+
+	if len(ao.Bids) > 0 {
+		pub[metricBidResponses] = 1
+	}
+	for _, bid := range ao.Bids {
+		b := bidder(bid.BidderCode)
+		b[metricBids]++
+		pub[metricBids]++
+		if !bid.Won {
+			continue
+		}
+		revenue := cpmToMicros(bid.GrossPrice)
+		payout := cpmToMicros(bid.ClearingPrice)
+		b[metricWins]++
+		b[metricRevenueMicros] += revenue
+		b[metricPayoutMicros] += payout
+		pub[metricWins]++
+		pub[metricRevenueMicros] += revenue
+		pub[metricPayoutMicros] += payout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	for _, size := range bucketSizes {
+		s.addPending(s.bucketKey(size, ts, dimensionPublisher), publisherID, pub)
+		for code, counters := range bidders {
+			s.addPending(s.bucketKey(size, ts, dimensionBidder), code, counters)
+		}
+	}
+}
+
+// LogVideoObject is a no-op; rollups only cover auctions
+func (s *Store) LogVideoObject(*analytics.VideoObject) {}
+
+// LogCookieSyncObject is a no-op; rollups only cover auctions
+func (s *Store) LogCookieSyncObject(*analytics.CookieSyncObject) {}
+
+// LogNotificationObject is a no-op; rollups only cover auctions
+func (s *Store) LogNotificationObject(*analytics.NotificationObject) {}
+
+// Shutdown stops the flush loop and writes any pending counters
+func (s *Store) Shutdown() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stopCh)
+	s.wg.Wait()
+	s.Flush()
+}
+
+// Flush writes pending counters to Redis
+func (s *Store) Flush() {
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	toWrite := s.pending
+	s.pending = make(map[string]map[string]int64)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	for key, increments := range toWrite {
+		if err := s.client.HIncrByBatch(ctx, key, increments, s.config.Retention); err != nil {
+			logger.Log.Warn().Err(err).Str("key", key).Msg("failed to flush dashboard rollup counters")
+		}
+	}
+}
+
+// Query aggregates all buckets in the named window
+func (s *Store) Query(ctx context.Context, windowName string) (*Summary, error) {
+	window, ok := LookupWindow(windowName)
+	if !ok {
+		return nil, fmt.Errorf("unknown window %q", windowName)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.config.Timeout)
+	defer cancel()
+
+	now := s.now()
+	last := now.Truncate(window.Bucket)
+	first := last.Add(-window.Duration + window.Bucket)
+
+	var keys []string
+	var dimensions []string
+	for t := first; !t.After(last); t = t.Add(window.Bucket) {
+		keys = append(keys, s.bucketKey(window.Bucket, t, dimensionPublisher), s.bucketKey(window.Bucket, t, dimensionBidder))
+		dimensions = append(dimensions, dimensionPublisher, dimensionBidder)
+	}
+
+	buckets, err := s.client.HGetAllBatch(ctx, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollup buckets: %w", err)
+	}
+
+	summary := &Summary{
+		Window:     window.Name,
+		From:       first,
+		To:         now,
+		Totals:     &Stats{},
+		Publishers: make(map[string]*Stats),
+		Bidders:    make(map[string]*Stats),
+	}
+	for i, bucket := range buckets {
+		target := summary.Publishers
+		if dimensions[i] == dimensionBidder {
+			target = summary.Bidders
+		}
+		for field, value := range bucket {
+			id, metric, ok := splitField(field)
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			stats, exists := target[id]
+			if !exists {
+				stats = &Stats{}
+				target[id] = stats
+			}
+			stats.add(metric, n)
+			if dimensions[i] == dimensionPublisher {
+				summary.Totals.add(metric, n)
+			}
+		}
+	}
+
+	summary.Totals.finalize()
+	for _, stats := range summary.Publishers {
+		stats.finalize()
+	}
+	for _, stats := range summary.Bidders {
+		stats.finalize()
+	}
+	return summary, nil
+}
+
+// flushLoop writes pending counters on an interval
+func (s *Store) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.stopCh:
+			return
+		}
+	}
+}
+
+// addPending merges counters for one entity into a bucket; caller holds s.mu
+func (s *Store) addPending(key, id string, counters map[string]int64) {
+	fields, ok := s.pending[key]
+	if !ok {
+		fields = make(map[string]int64)
+		s.pending[key] = fields
+	}
+	for metric, n := range counters {
+		if n != 0 {
+			fields[id+"|"+metric] += n
+		}
+	}
+}
+
+// bucketKey returns the Redis key for a bucket, e.g. tne_catalyst:stats:5m:1700000100:bidder
+func (s *Store) bucketKey(size time.Duration, ts time.Time, dimension string) string {
+	return fmt.Sprintf("%s:%s:%d:%s", s.config.KeyPrefix, bucketName(size), ts.Truncate(size).Unix(), dimension)
+}
+
+// bucketName formats a bucket size as used in keys
+func bucketName(size time.Duration) string {
+	if size%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(size/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int(size/time.Minute))
+}
+
+// splitField splits "id|metric"; the ID may itself contain '|'
+func splitField(field string) (string, string, bool) {
+	idx := strings.LastIndex(field, "|")
+	if idx <= 0 || idx == len(field)-1 {
+		return "", "", false
+	}
+	return field[:idx], field[idx+1:], true
+}
+
+// cpmToMicros converts a CPM price to micro-units of revenue for a single impression
+func cpmToMicros(cpm float64) int64 {
+	return int64(math.Round(cpm * 1000))
+}
+
+// add applies a counter value
+func (st *Stats) add(metric string, n int64) {
+	switch metric {
+	case metricRequests:
+		st.Requests += n
+	case metricBidResponses:
+		st.BidResponses += n
+	case metricBids:
+		st.Bids += n
+	case metricWins:
+		st.Wins += n
+	case metricBidderCalls:
+		st.BidderCalls += n
+	case metricTimeouts:
+		st.Timeouts += n
+	case metricRevenueMicros:
+		st.revenueMicros += n
+	case metricPayoutMicros:
+		st.payoutMicros += n
+	}
+}
+
+// finalize derives monetary values and rates from the raw counters
+func (st *Stats) finalize() {
+	st.Revenue = float64(st.revenueMicros) / 1e6
+	st.Payout = float64(st.payoutMicros) / 1e6
+	st.Margin = float64(st.revenueMicros-st.payoutMicros) / 1e6
+	st.BidRate = ratio(st.BidResponses, st.Requests)
+	st.WinRate = ratio(st.Wins, st.Bids)
+	st.TimeoutRate = ratio(st.Timeouts, st.BidderCalls)
+	st.MarginRate = ratio(st.revenueMicros-st.payoutMicros, st.revenueMicros)
+}
+
+func ratio(num, denom int64) float64 {
+	if denom == 0 {
+		return 0
+	}
+	return float64(num) / float64(denom)
+}
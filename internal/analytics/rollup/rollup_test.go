@@ -0,0 +1,169 @@
+package rollup
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/pkg/redis"
+)
+
+func newTestStore(t *testing.T, now time.Time) (*Store, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.New("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("failed to create redis client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	store := New(client, &Config{FlushInterval: time.Hour})
+	store.now = func() time.Time { return now }
+	t.Cleanup(store.Shutdown)
+	return store, mr
+}
+
+func testAuction(ts time.Time) *analytics.AuctionObject {
+	return &analytics.AuctionObject{
+		AuctionID:   "a1",
+		PublisherID: "pub-1",
+		Timestamp:   ts,
+		Bidders: map[string]*analytics.BidderObject{
+			"rubicon":  {BidderCode: "rubicon", BidCount: 1},
+			"appnexus": {BidderCode: "appnexus", BidCount: 1},
+			"slowbid":  {BidderCode: "slowbid", TimedOut: true},
+		},
+		Bids: []analytics.BidObject{
+			{BidID: "b1", ImpID: "1", BidderCode: "rubicon", GrossPrice: 2.00, ClearingPrice: 1.50, Won: true},
+			{BidID: "b2", ImpID: "1", BidderCode: "appnexus", GrossPrice: 1.00, ClearingPrice: 0.75},
+		},
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStore_QueryAggregatesAcrossInstances(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	store, mr := newTestStore(t, now)
+
+	// A second instance sharing the same Redis
+	client, err := redis.New("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("failed to create redis client: %v", err)
+	}
+	defer client.Close()
+	other := New(client, &Config{FlushInterval: time.Hour})
+	other.now = store.now
+
+	store.LogAuctionObject(testAuction(now.Add(-10 * time.Minute)))
+	other.LogAuctionObject(testAuction(now.Add(-20 * time.Minute)))
+	store.Flush()
+	other.Shutdown()
+
+	summary, err := store.Query(context.Background(), "1h")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	pub := summary.Publishers["pub-1"]
+	if pub == nil {
+		t.Fatalf("expected publisher stats, got %+v", summary.Publishers)
+	}
+	if pub.Requests != 2 || pub.Bids != 4 || pub.Wins != 2 || pub.BidderCalls != 6 || pub.Timeouts != 2 {
+		t.Errorf("unexpected publisher counters: %+v", pub)
+	}
+	// Two wins at $2.00 CPM gross / $1.50 CPM payout
+	if !approx(pub.Revenue, 0.004) || !approx(pub.Payout, 0.003) || !approx(pub.Margin, 0.001) {
+		t.Errorf("unexpected revenue/payout/margin: %v/%v/%v", pub.Revenue, pub.Payout, pub.Margin)
+	}
+	if !approx(pub.MarginRate, 0.25) || !approx(pub.WinRate, 0.5) || !approx(pub.TimeoutRate, 1.0/3) || !approx(pub.BidRate, 1) {
+		t.Errorf("unexpected rates: %+v", pub)
+	}
+
+	rubicon := summary.Bidders["rubicon"]
+	if rubicon == nil || rubicon.Requests != 2 || rubicon.Wins != 2 || !approx(rubicon.WinRate, 1) {
+		t.Errorf("unexpected rubicon stats: %+v", rubicon)
+	}
+	slow := summary.Bidders["slowbid"]
+	if slow == nil || !approx(slow.TimeoutRate, 1) || slow.BidRate != 0 {
+		t.Errorf("unexpected slowbid stats: %+v", slow)
+	}
+	if summary.Totals.Requests != 2 || !approx(summary.Totals.Revenue, 0.004) {
+		t.Errorf("unexpected totals: %+v", summary.Totals)
+	}
+}
+
+func TestStore_QueryWindows(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	store, _ := newTestStore(t, now)
+
+	store.LogAuctionObject(testAuction(now.Add(-5 * time.Minute)))
+	store.LogAuctionObject(testAuction(now.Add(-3 * time.Hour)))
+	store.LogAuctionObject(testAuction(now.Add(-3 * 24 * time.Hour)))
+	store.Flush()
+
+	expected := map[string]int64{"1h": 1, "24h": 2, "7d": 3}
+	for window, requests := range expected {
+		summary, err := store.Query(context.Background(), window)
+		if err != nil {
+			t.Fatalf("%s: query failed: %v", window, err)
+		}
+		if summary.Totals.Requests != requests {
+			t.Errorf("%s: expected %d requests, got %d", window, requests, summary.Totals.Requests)
+		}
+	}
+
+	if _, err := store.Query(context.Background(), "30d"); err == nil {
+		t.Error("expected error for unknown window")
+	}
+}
+
+func TestStore_UnknownPublisherAndTTL(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	store, mr := newTestStore(t, now)
+
+	ao := testAuction(now)
+	ao.PublisherID = ""
+	store.LogAuctionObject(ao)
+	store.Flush()
+
+	key := store.bucketKey(time.Hour, now, dimensionPublisher)
+	if got := mr.HGet(key, "unknown|requests"); got != "1" {
+		t.Errorf("expected unknown publisher counter, got %q", got)
+	}
+	if ttl := mr.TTL(key); ttl != DefaultConfig().Retention {
+		t.Errorf("expected retention TTL, got %v", ttl)
+	}
+}
+
+func TestStore_IgnoresAuctionsAfterShutdown(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	store, _ := newTestStore(t, now)
+
+	store.Shutdown()
+	store.LogAuctionObject(testAuction(now))
+	store.Flush()
+
+	summary, err := store.Query(context.Background(), "1h")
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if summary.Totals.Requests != 0 {
+		t.Errorf("expected no counters after shutdown, got %d", summary.Totals.Requests)
+	}
+}
+
+func TestSplitField(t *testing.T) {
+	id, metric, ok := splitField("pub|with|pipes|wins")
+	if !ok || id != "pub|with|pipes" || metric != "wins" {
+		t.Errorf("unexpected split: %q %q %v", id, metric, ok)
+	}
+	if _, _, ok := splitField("nopipe"); ok {
+		t.Error("expected malformed field to be rejected")
+	}
+}
@@ -0,0 +1,236 @@
+package analytics
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// DefaultQueueSize is the per-module queue depth used when none is configured
+const DefaultQueueSize = 1000
+
+// ModuleStats reports per-module queue statistics
+type ModuleStats struct {
+	Queued    int   `json:"queued"`
+	Processed int64 `json:"processed"`
+	Dropped   int64 `json:"dropped"`
+	Panics    int64 `json:"panics"`
+}
+
+// Runner fans analytics objects out to registered modules.
+// Each module gets its own bounded queue and goroutine so a slow module never
+// blocks the request path or other modules; objects are dropped when a queue is full.
+type Runner struct {
+	mu        sync.RWMutex
+	workers   []*moduleWorker
+	closed    bool
+	queueSize int
+}
+
+type moduleWorker struct {
+	name      string
+	module    Module
+	events    chan event
+	done      chan struct{}
+	processed atomic.Int64
+	dropped   atomic.Int64
+	panics    atomic.Int64
+}
+
+// event carries exactly one of the analytics objects
+type event struct {
+	auction      *AuctionObject
+	video        *VideoObject
+	cookieSync   *CookieSyncObject
+	notification *NotificationObject
+}
+
+// NewRunner creates a runner with the given per-module queue size
+func NewRunner(queueSize int) *Runner {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	return &Runner{queueSize: queueSize}
+}
+
+// Register adds a module and starts its worker
+func (r *Runner) Register(name string, m Module) error {
+	if m == nil {
+		return fmt.Errorf("analytics module %s is nil", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return fmt.Errorf("analytics runner is shut down")
+	}
+	for _, w := range r.workers {
+		if w.name == name {
+			return fmt.Errorf("analytics module %s already registered", name)
+		}
+	}
+
+	w := &moduleWorker{
+		name:   name,
+		module: m,
+		events: make(chan event, r.queueSize),
+		done:   make(chan struct{}),
+	}
+	r.workers = append(r.workers, w)
+	go w.run()
+
+	logger.Log.Info().Str("module", name).Int("queue_size", r.queueSize).Msg("Analytics module registered")
+	return nil
+}
+
+// Modules returns the names of registered modules
+func (r *Runner) Modules() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, len(r.workers))
+	for i, w := range r.workers {
+		names[i] = w.name
+	}
+	return names
+}
+
+// LogAuctionObject queues an auction object for all modules
+func (r *Runner) LogAuctionObject(ao *AuctionObject) {
+	if ao != nil {
+		r.dispatch(event{auction: ao})
+	}
+}
+
+// LogVideoObject queues a video object for all modules
+func (r *Runner) LogVideoObject(vo *VideoObject) {
+	if vo != nil {
+		r.dispatch(event{video: vo})
+	}
+}
+
+// LogCookieSyncObject queues a cookie sync object for all modules
+func (r *Runner) LogCookieSyncObject(cso *CookieSyncObject) {
+	if cso != nil {
+		r.dispatch(event{cookieSync: cso})
+	}
+}
+
+// LogNotificationObject queues a notification object for all modules
+func (r *Runner) LogNotificationObject(no *NotificationObject) {
+	if no != nil {
+		r.dispatch(event{notification: no})
+	}
+}
+
+// dispatch performs a non-blocking send to every module queue.
+// The read lock is held during sends so Shutdown cannot close a channel mid-send.
+func (r *Runner) dispatch(ev event) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	for _, w := range r.workers {
+		select {
+		case w.events <- ev:
+		default:
+			if w.dropped.Add(1)%1000 == 1 {
+				logger.Log.Warn().
+					Str("module", w.name).
+					Int64("dropped", w.dropped.Load()).
+					Msg("Analytics queue full, dropping objects")
+			}
+		}
+	}
+}
+
+// Shutdown drains all module queues and shuts the modules down
+func (r *Runner) Shutdown() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	for _, w := range r.workers {
+		close(w.events)
+	}
+	workers := r.workers
+	r.mu.Unlock()
+
+	for _, w := range workers {
+		<-w.done
+	}
+}
+
+// Stats returns queue statistics keyed by module name
+func (r *Runner) Stats() map[string]ModuleStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stats := make(map[string]ModuleStats, len(r.workers))
+	for _, w := range r.workers {
+		stats[w.name] = ModuleStats{
+			Queued:    len(w.events),
+			Processed: w.processed.Load(),
+			Dropped:   w.dropped.Load(),
+			Panics:    w.panics.Load(),
+		}
+	}
+	return stats
+}
+
+// run processes queued objects until the queue is closed, then shuts the module down
+func (w *moduleWorker) run() {
+	defer close(w.done)
+
+	for ev := range w.events {
+		w.handle(ev)
+	}
+
+	w.shutdown()
+}
+
+// handle delivers one object, isolating the runner from module panics
+func (w *moduleWorker) handle(ev event) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			w.panics.Add(1)
+			logger.Log.Error().
+				Str("module", w.name).
+				Interface("panic", rec).
+				Msg("Analytics module panicked")
+		}
+	}()
+
+	switch {
+	case ev.auction != nil:
+		w.module.LogAuctionObject(ev.auction)
+	case ev.video != nil:
+		w.module.LogVideoObject(ev.video)
+	case ev.cookieSync != nil:
+		w.module.LogCookieSyncObject(ev.cookieSync)
+	case ev.notification != nil:
+		w.module.LogNotificationObject(ev.notification)
+	}
+	w.processed.Add(1)
+}
+
+// shutdown calls the module's Shutdown with panic protection
+func (w *moduleWorker) shutdown() {
+	defer func() {
+		if rec := recover(); rec != nil {
+			logger.Log.Error().
+				Str("module", w.name).
+				Interface("panic", rec).
+				Msg("Analytics module panicked during shutdown")
+		}
+	}()
+	w.module.Shutdown()
+}
@@ -0,0 +1,193 @@
+package analytics
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingModule captures everything it receives
+type recordingModule struct {
+	mu            sync.Mutex
+	auctions      []*AuctionObject
+	videos        []*VideoObject
+	cookieSyncs   []*CookieSyncObject
+	notifications []*NotificationObject
+	shutdown      bool
+	block         chan struct{}
+}
+
+func (m *recordingModule) LogAuctionObject(ao *AuctionObject) {
+	if m.block != nil {
+		<-m.block
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctions = append(m.auctions, ao)
+}
+
+func (m *recordingModule) LogVideoObject(vo *VideoObject) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.videos = append(m.videos, vo)
+}
+
+func (m *recordingModule) LogCookieSyncObject(cso *CookieSyncObject) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cookieSyncs = append(m.cookieSyncs, cso)
+}
+
+func (m *recordingModule) LogNotificationObject(no *NotificationObject) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifications = append(m.notifications, no)
+}
+
+func (m *recordingModule) Shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shutdown = true
+}
+
+type panickingModule struct{}
+
+func (panickingModule) LogAuctionObject(*AuctionObject)           { panic("boom") }
+func (panickingModule) LogVideoObject(*VideoObject)               {}
+func (panickingModule) LogCookieSyncObject(*CookieSyncObject)     {}
+func (panickingModule) LogNotificationObject(*NotificationObject) {}
+func (panickingModule) Shutdown()                                 {}
+
+func TestRunner_DeliversToAllModules(t *testing.T) {
+	r := NewRunner(10)
+	a := &recordingModule{}
+	b := &recordingModule{}
+	if err := r.Register("a", a); err != nil {
+		t.Fatalf("register a: %v", err)
+	}
+	if err := r.Register("b", b); err != nil {
+		t.Fatalf("register b: %v", err)
+	}
+
+	r.LogAuctionObject(&AuctionObject{AuctionID: "auction-1"})
+	r.LogVideoObject(&VideoObject{EventType: "start"})
+	r.LogCookieSyncObject(&CookieSyncObject{Status: "ok"})
+	r.LogNotificationObject(&NotificationObject{Type: NotificationWin, BidID: "bid-1"})
+	r.Shutdown()
+
+	for name, m := range map[string]*recordingModule{"a": a, "b": b} {
+		if len(m.auctions) != 1 || m.auctions[0].AuctionID != "auction-1" {
+			t.Errorf("%s: expected 1 auction, got %d", name, len(m.auctions))
+		}
+		if len(m.videos) != 1 {
+			t.Errorf("%s: expected 1 video event, got %d", name, len(m.videos))
+		}
+		if len(m.cookieSyncs) != 1 {
+			t.Errorf("%s: expected 1 cookie sync, got %d", name, len(m.cookieSyncs))
+		}
+		if len(m.notifications) != 1 || m.notifications[0].BidID != "bid-1" {
+			t.Errorf("%s: expected 1 notification, got %d", name, len(m.notifications))
+		}
+		if !m.shutdown {
+			t.Errorf("%s: expected module to be shut down", name)
+		}
+	}
+}
+
+func TestRunner_RegisterRejectsDuplicates(t *testing.T) {
+	r := NewRunner(1)
+	defer r.Shutdown()
+
+	if err := r.Register("file", &recordingModule{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := r.Register("file", &recordingModule{}); err == nil {
+		t.Error("expected duplicate registration to fail")
+	}
+	if err := r.Register("nil", nil); err == nil {
+		t.Error("expected nil module registration to fail")
+	}
+}
+
+func TestRunner_DropsWhenQueueFull(t *testing.T) {
+	r := NewRunner(1)
+	slow := &recordingModule{block: make(chan struct{})}
+	if err := r.Register("slow", slow); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < 50; i++ {
+		r.LogAuctionObject(&AuctionObject{AuctionID: "a"})
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("dispatch blocked for %v", elapsed)
+	}
+
+	stats := r.Stats()["slow"]
+	if stats.Dropped == 0 {
+		t.Error("expected dropped objects when queue is full")
+	}
+
+	close(slow.block)
+	r.Shutdown()
+}
+
+func TestRunner_RecoversFromModulePanic(t *testing.T) {
+	r := NewRunner(10)
+	good := &recordingModule{}
+	if err := r.Register("bad", panickingModule{}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := r.Register("good", good); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	r.LogAuctionObject(&AuctionObject{AuctionID: "a1"})
+	r.LogAuctionObject(&AuctionObject{AuctionID: "a2"})
+	r.Shutdown()
+
+	if len(good.auctions) != 2 {
+		t.Errorf("expected good module to receive 2 auctions, got %d", len(good.auctions))
+	}
+	if panics := r.Stats()["bad"].Panics; panics != 2 {
+		t.Errorf("expected 2 recorded panics, got %d", panics)
+	}
+}
+
+func TestRunner_IgnoresObjectsAfterShutdown(t *testing.T) {
+	r := NewRunner(10)
+	m := &recordingModule{}
+	if err := r.Register("m", m); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	r.Shutdown()
+	r.Shutdown() // idempotent
+
+	r.LogAuctionObject(&AuctionObject{AuctionID: "late"})
+	if len(m.auctions) != 0 {
+		t.Errorf("expected no auctions after shutdown, got %d", len(m.auctions))
+	}
+	if err := r.Register("late", &recordingModule{}); err == nil {
+		t.Error("expected register after shutdown to fail")
+	}
+}
+
+func TestAuctionObject_Winners(t *testing.T) {
+	ao := &AuctionObject{
+		Imps: []ImpObject{{ImpID: "1"}, {ImpID: "2"}},
+		Bids: []BidObject{
+			{BidID: "b1", ImpID: "1", Won: true},
+			{BidID: "b2", ImpID: "1"},
+			{BidID: "b3", ImpID: "2", Won: true},
+		},
+	}
+
+	winners := ao.Winners()
+	if len(winners) != 2 {
+		t.Fatalf("expected 2 winners, got %d", len(winners))
+	}
+	if winners[0].BidID != "b1" || winners[1].BidID != "b3" {
+		t.Errorf("unexpected winners: %+v", winners)
+	}
+}
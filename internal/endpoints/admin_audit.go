@@ -0,0 +1,122 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// AuditRecorder appends entries to the admin audit log (implemented by storage.AuditStore)
+type AuditRecorder interface {
+	Record(ctx context.Context, e *storage.AuditEntry) error
+}
+
+// AuditLister reads the admin audit log (implemented by storage.AuditStore)
+type AuditLister interface {
+	List(ctx context.Context, f storage.AuditFilter) ([]*storage.AuditEntry, error)
+}
+
+// RecordAdminChange writes an audit entry for a change made by the authenticated admin.
+// Failures are logged rather than returned because the change itself has already been applied.
+func RecordAdminChange(r *http.Request, recorder AuditRecorder, action, resourceType, resourceID string, before, after interface{}) {
+	if recorder == nil {
+		return
+	}
+
+	entry, err := storage.NewAuditEntry(action, resourceType, resourceID, before, after)
+	if err != nil {
+		logger.Log.Error().Err(err).Str("action", action).Str("resource_id", resourceID).Msg("Failed to build audit entry")
+		return
+	}
+	entry.Actor = "anonymous"
+	if p := middleware.AdminPrincipalFromContext(r.Context()); p != nil {
+		entry.Actor = p.Name
+		entry.ActorRole = string(p.Role)
+	}
+	entry.RemoteAddr = r.RemoteAddr
+
+	// Detach from the request so a client disconnect doesn't drop the audit row
+	if err := recorder.Record(context.WithoutCancel(r.Context()), entry); err != nil {
+		logger.Log.Error().
+			Err(err).
+			Str("action", action).
+			Str("resource_type", resourceType).
+			Str("resource_id", resourceID).
+			Str("actor", entry.Actor).
+			Msg("Failed to write admin audit log")
+	}
+}
+
+// AuditLogResponse is the response for GET /admin/audit
+type AuditLogResponse struct {
+	Entries []*storage.AuditEntry `json:"entries"`
+	Count   int                   `json:"count"`
+}
+
+// AuditLogHandler serves the admin audit log
+type AuditLogHandler struct {
+	lister AuditLister
+}
+
+// NewAuditLogHandler creates a new audit log handler; lister may be nil without a database
+func NewAuditLogHandler(lister AuditLister) *AuditLogHandler {
+	return &AuditLogHandler{lister: lister}
+}
+
+// ServeHTTP handles GET /admin/audit
+//
+// Query parameters: resource_type, resource_id, actor, since (RFC3339), limit
+func (h *AuditLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.lister == nil {
+		writeError(w, "audit log requires a database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	params := r.URL.Query()
+	filter := storage.AuditFilter{
+		ResourceType: params.Get("resource_type"),
+		ResourceID:   params.Get("resource_id"),
+		Actor:        params.Get("actor"),
+	}
+	if v := params.Get("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, "since must be an RFC3339 timestamp", http.StatusBadRequest)
+			return
+		}
+		filter.Since = since
+	}
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			writeError(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.lister.List(r.Context(), filter)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("failed to list audit log")
+		writeError(w, "failed to read audit log", http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []*storage.AuditEntry{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(AuditLogResponse{Entries: entries, Count: len(entries)}); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode audit log response")
+	}
+}
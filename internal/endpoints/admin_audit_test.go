@@ -0,0 +1,165 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+)
+
+// memoryAuditLog records and lists audit entries in memory
+type memoryAuditLog struct {
+	mu      sync.Mutex
+	entries []*storage.AuditEntry
+	filter  storage.AuditFilter
+	err     error
+}
+
+func (l *memoryAuditLog) Record(_ context.Context, e *storage.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return l.err
+	}
+	e.ID = int64(len(l.entries) + 1)
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+func (l *memoryAuditLog) List(_ context.Context, f storage.AuditFilter) ([]*storage.AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.filter = f
+	if l.err != nil {
+		return nil, l.err
+	}
+	return l.entries, nil
+}
+
+func asAdmin(r *http.Request, name string, role middleware.AdminRole) *http.Request {
+	return r.WithContext(middleware.WithAdminPrincipal(r.Context(), &middleware.AdminPrincipal{Name: name, Role: role}))
+}
+
+func TestPublisherAdmin_AuditLog(t *testing.T) {
+	handler, _, _, _ := setupPublisherAdmin(t, testAdminPublisher("pub-a"))
+	audit := &memoryAuditLog{}
+	handler.SetAuditRecorder(audit)
+
+	serve := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		return doAdminRequest(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler.ServeHTTP(w, asAdmin(r, "carol", middleware.AdminRoleFinance))
+		}), method, path, body)
+	}
+
+	if w := serve(http.MethodPut, "/admin/publishers/pub-a", map[string]interface{}{"bid_multiplier": 1.5, "version": 1}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodPost, "/admin/publishers", map[string]interface{}{"publisher_id": "pub-b", "allowed_domains": "b.com"}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := serve(http.MethodDelete, "/admin/publishers/pub-b", nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	// Failed writes are not audited
+	serve(http.MethodPut, "/admin/publishers/pub-a", map[string]interface{}{"bid_multiplier": 2.0, "version": 1})
+
+	if len(audit.entries) != 3 {
+		t.Fatalf("Expected 3 audit entries, got %d", len(audit.entries))
+	}
+
+	update := audit.entries[0]
+	if update.Action != "publisher.update" || update.ResourceType != storage.AuditResourcePublisher || update.ResourceID != "pub-a" {
+		t.Errorf("Unexpected update entry: %+v", update)
+	}
+	if update.Actor != "carol" || update.ActorRole != "finance" {
+		t.Errorf("Expected actor carol/finance, got %s/%s", update.Actor, update.ActorRole)
+	}
+	change, ok := update.Diff["bid_multiplier"]
+	if !ok || change.Before != 1.05 || change.After != 1.5 {
+		t.Errorf("Expected bid_multiplier 1.05 -> 1.5 in diff, got %+v", update.Diff)
+	}
+	if _, ok := update.Diff["name"]; ok {
+		t.Error("Expected unchanged fields to be left out of the diff")
+	}
+
+	create := audit.entries[1]
+	if create.Action != "publisher.create" || create.Before != nil || len(create.After) == 0 {
+		t.Errorf("Unexpected create entry: %+v", create)
+	}
+
+	archive := audit.entries[2]
+	if archive.Action != "publisher.archive" || archive.Diff["status"].After != "archived" {
+		t.Errorf("Unexpected archive entry: %+v", archive)
+	}
+}
+
+func TestRecordAdminChange_Failures(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/admin/publishers/pub-a", nil)
+
+	// nil recorder is a no-op
+	RecordAdminChange(req, nil, "publisher.update", storage.AuditResourcePublisher, "pub-a", nil, nil)
+
+	// Recorder errors are logged, not propagated
+	audit := &memoryAuditLog{err: errors.New("db down")}
+	RecordAdminChange(req, audit, "publisher.update", storage.AuditResourcePublisher, "pub-a", nil, map[string]int{"x": 1})
+
+	// Unauthenticated changes are attributed to anonymous
+	audit = &memoryAuditLog{}
+	RecordAdminChange(req, audit, "publisher.update", storage.AuditResourcePublisher, "pub-a", nil, map[string]int{"x": 1})
+	if len(audit.entries) != 1 || audit.entries[0].Actor != "anonymous" {
+		t.Errorf("Expected anonymous entry, got %+v", audit.entries)
+	}
+}
+
+func TestAuditLogHandler(t *testing.T) {
+	audit := &memoryAuditLog{entries: []*storage.AuditEntry{{ID: 1, Actor: "carol", Action: "publisher.update"}}}
+	handler := NewAuditLogHandler(audit)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit?resource_type=publisher&resource_id=pub-a&actor=carol&since=2026-03-01T00:00:00Z&limit=10", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp AuditLogResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Count != 1 || resp.Entries[0].Actor != "carol" {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	f := audit.filter
+	if f.ResourceType != "publisher" || f.ResourceID != "pub-a" || f.Actor != "carol" || f.Limit != 10 || f.Since.IsZero() {
+		t.Errorf("Unexpected filter: %+v", f)
+	}
+
+	tests := []struct {
+		name    string
+		handler *AuditLogHandler
+		method  string
+		query   string
+		want    int
+	}{
+		{"no database", NewAuditLogHandler(nil), http.MethodGet, "", http.StatusServiceUnavailable},
+		{"wrong method", handler, http.MethodPost, "", http.StatusMethodNotAllowed},
+		{"bad since", handler, http.MethodGet, "?since=yesterday", http.StatusBadRequest},
+		{"bad limit", handler, http.MethodGet, "?limit=-1", http.StatusBadRequest},
+		{"store error", NewAuditLogHandler(&memoryAuditLog{err: errors.New("db down")}), http.MethodGet, "", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/admin/audit"+tt.query, nil))
+			if w.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
 	"github.com/thenexusengine/tne_springwire/internal/exchange"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
@@ -16,7 +17,8 @@ import (
 
 // AdTagHandler handles direct ad tag requests
 type AdTagHandler struct {
-	exchange *exchange.Exchange
+	exchange  *exchange.Exchange
+	analytics analytics.Module
 }
 
 // NewAdTagHandler creates a new ad tag handler
@@ -26,6 +28,11 @@ func NewAdTagHandler(ex *exchange.Exchange) *AdTagHandler {
 	}
 }
 
+// SetAnalytics sets the analytics module that receives win, billing and impression notifications
+func (h *AdTagHandler) SetAnalytics(m analytics.Module) {
+	h.analytics = m
+}
+
 // HandleJavaScriptAd handles JavaScript ad requests
 func (h *AdTagHandler) HandleJavaScriptAd(w http.ResponseWriter, r *http.Request) {
 	log := logger.Log
@@ -421,6 +428,19 @@ func (h *AdTagHandler) HandleAdTracking(w http.ResponseWriter, r *http.Request)
 		Str("user_agent", r.Header.Get("User-Agent")).
 		Msg("Ad tracking event")
 
+	switch event {
+	case analytics.NotificationImpression, analytics.NotificationWin, analytics.NotificationBilling:
+		if h.analytics != nil && bidID != "" {
+			h.analytics.LogNotificationObject(&analytics.NotificationObject{
+				Type:      event,
+				BidID:     bidID,
+				Placement: placementID,
+				Source:    analytics.NotificationSourceDisplay,
+				Timestamp: time.Now(),
+			})
+		}
+	}
+
 	// Return 1x1 transparent GIF
 	gif := []byte{
 		0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00,
@@ -0,0 +1,40 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+)
+
+func TestHandleAdTracking_LogsNotifications(t *testing.T) {
+	recorder := &recordingAnalytics{}
+	handler := NewAdTagHandler(nil)
+	handler.SetAnalytics(recorder)
+
+	for _, target := range []string{
+		"/ad/track?bid=bid-1&placement=top&event=impression",
+		"/ad/track?bid=bid-1&placement=top&event=billing",
+		"/ad/track?bid=bid-1&placement=top&event=click",
+		"/ad/track?placement=top&event=impression",
+	} {
+		w := httptest.NewRecorder()
+		handler.HandleAdTracking(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/gif" {
+			t.Errorf("Expected a tracking pixel for %s, got %d %s", target, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+
+	if len(recorder.notifications) != 2 {
+		t.Fatalf("Expected impression and billing notifications, got %+v", recorder.notifications)
+	}
+	no := recorder.notifications[0]
+	if no.Type != analytics.NotificationImpression || no.BidID != "bid-1" || no.Placement != "top" ||
+		no.Source != analytics.NotificationSourceDisplay || no.Timestamp.IsZero() {
+		t.Errorf("Unexpected impression notification: %+v", no)
+	}
+	if recorder.notifications[1].Type != analytics.NotificationBilling {
+		t.Errorf("Expected a billing notification, got %+v", recorder.notifications[1])
+	}
+}
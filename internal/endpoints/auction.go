@@ -59,14 +59,19 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse OpenRTB request
+	log := logger.FromContext(r.Context())
 	var bidRequest openrtb.BidRequest
 	err = json.Unmarshal(body, &bidRequest)
 	if err != nil {
-		logger.Log.Warn().Err(err).Msg("Invalid JSON in bid request")
+		log.Warn().Err(err).Msg("Invalid JSON in bid request")
 		writeError(w, "Invalid JSON in request body", http.StatusBadRequest)
 		return
 	}
 
+	// Log lines for this auction carry the request, auction and publisher IDs
+	ctx := logger.WithAuctionID(r.Context(), bidRequest.ID)
+	log = logger.FromContext(ctx)
+
 	// Validate request
 	err = validateBidRequest(&bidRequest)
 	if err != nil {
@@ -84,7 +89,7 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			if hasAPIKey(r) {
 				debugEnabled = true
 			} else {
-				logger.Log.Debug().Msg("Debug mode requested without publisher context, ignoring")
+				log.Debug().Msg("Debug mode requested without publisher context, ignoring")
 			}
 		} else {
 			debugEnabled = true
@@ -97,7 +102,6 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Run auction
-	ctx := r.Context()
 	auctionStart := time.Now()
 	result, err := h.exchange.RunAuction(ctx, auctionReq)
 	auctionDuration := time.Since(auctionStart)
@@ -114,17 +118,13 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			errorMsg = validationErr.Message
 		}
 
-		logger.Log.Error().
+		log.Error().
 			Err(err).
-			Str("request_id", bidRequest.ID).
 			Int("imp_count", len(bidRequest.Imp)).
 			Dur("duration_ms", auctionDuration).
 			Int("status_code", statusCode).
 			Msg("Auction failed")
 
-		// Log to dashboard
-		LogAuction(bidRequest.ID, len(bidRequest.Imp), 0, nil, auctionDuration, false, err)
-
 		writeError(w, errorMsg, statusCode)
 		return
 	}
@@ -141,8 +141,7 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	logger.Log.Info().
-		Str("request_id", bidRequest.ID).
+	log.Info().
 		Int("imp_count", len(bidRequest.Imp)).
 		Int("bid_count", bidCount).
 		Strs("winning_bidders", winningBidders).
@@ -150,9 +149,6 @@ func (h *AuctionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Bool("debug", auctionReq.Debug).
 		Msg("Auction completed")
 
-	// Log to dashboard
-	LogAuction(bidRequest.ID, len(bidRequest.Imp), bidCount, winningBidders, auctionDuration, true, nil)
-
 	// Build response with extensions
 	response := result.BidResponse
 	if auctionReq.Debug && result.DebugInfo != nil {
@@ -226,11 +222,69 @@ func buildResponseExt(result *exchange.AuctionResponse) *openrtb.BidResponseExt
 		}
 
 		ext.TMMaxRequest = int(result.DebugInfo.TotalLatency.Milliseconds())
+
+		for _, c := range result.DebugInfo.CurrencyConversions {
+			ext.CurrencyConversions = append(ext.CurrencyConversions, openrtb.ExtCurrencyConversion{
+				BidID:       c.BidID,
+				Bidder:      c.BidderCode,
+				Stage:       c.Stage,
+				From:        c.From,
+				To:          c.To,
+				RateVersion: c.RateVersion,
+			})
+		}
+
+		ext.Debug = buildDebugExt(result.DebugInfo)
 	}
 
 	return ext
 }
 
+// buildDebugExt builds ext.debug from the bidder calls, resolved request and stage trace
+// of a debug auction; nil when none were captured
+func buildDebugExt(info *exchange.DebugInfo) *openrtb.ExtResponseDebug {
+	if len(info.HTTPCalls) == 0 && len(info.ResolvedRequest) == 0 && len(info.Trace) == 0 {
+		return nil
+	}
+	debug := &openrtb.ExtResponseDebug{ResolvedRequest: info.ResolvedRequest}
+
+	if len(info.HTTPCalls) > 0 {
+		debug.HTTPCalls = make(map[string][]openrtb.ExtHTTPCall, len(info.HTTPCalls))
+		for bidder, calls := range info.HTTPCalls {
+			for _, c := range calls {
+				debug.HTTPCalls[bidder] = append(debug.HTTPCalls[bidder], openrtb.ExtHTTPCall{
+					URI:            c.URI,
+					Method:         c.Method,
+					RequestHeaders: c.RequestHeaders,
+					RequestBody:    c.RequestBody,
+					Status:         c.StatusCode,
+					ResponseBody:   c.ResponseBody,
+					Error:          c.Error,
+				})
+			}
+		}
+	}
+
+	for _, stage := range info.Trace {
+		entry := openrtb.ExtTraceStage{
+			Stage:      stage.Stage,
+			DurationMS: float64(stage.Duration.Microseconds()) / 1000,
+			Messages:   stage.Messages,
+		}
+		for _, rb := range stage.RejectedBids {
+			entry.RejectedBids = append(entry.RejectedBids, openrtb.ExtRejectedBid{
+				Bidder: rb.BidderCode,
+				BidID:  rb.BidID,
+				ImpID:  rb.ImpID,
+				Price:  rb.Price,
+				Reason: rb.Reason,
+			})
+		}
+		debug.Trace = append(debug.Trace, entry)
+	}
+	return debug
+}
+
 // writeError writes an error response
 func writeError(w http.ResponseWriter, message string, status int) {
 	w.Header().Set("Content-Type", "application/json")
@@ -673,6 +673,40 @@ func TestBuildResponseExt_WithErrors(t *testing.T) {
 	}
 }
 
+func TestBuildResponseExt_WithDebug(t *testing.T) {
+	result := &exchange.AuctionResponse{
+		DebugInfo: &exchange.DebugInfo{
+			HTTPCalls: map[string][]exchange.HTTPCall{
+				"bidder1": {{URI: "https://bidder.example.com", RequestBody: "{}", StatusCode: 200, ResponseBody: `{"id":"1"}`}},
+			},
+			ResolvedRequest: json.RawMessage(`{"id":"1"}`),
+			Trace: []exchange.TraceStage{
+				{Stage: exchange.StageFloors, Duration: 1500 * time.Microsecond, RejectedBids: []exchange.RejectedBid{
+					{BidderCode: "bidder1", BidID: "b1", ImpID: "imp1", Price: 0.5, Reason: "price 0.5000 below floor 1.0000"},
+				}},
+			},
+		},
+	}
+	ext := buildResponseExt(result)
+
+	if ext.Debug == nil {
+		t.Fatal("expected ext.debug")
+	}
+	if calls := ext.Debug.HTTPCalls["bidder1"]; len(calls) != 1 || calls[0].Status != 200 || calls[0].ResponseBody != `{"id":"1"}` {
+		t.Errorf("unexpected httpcalls: %+v", ext.Debug.HTTPCalls)
+	}
+	if string(ext.Debug.ResolvedRequest) != `{"id":"1"}` {
+		t.Errorf("unexpected resolvedrequest: %s", ext.Debug.ResolvedRequest)
+	}
+	if len(ext.Debug.Trace) != 1 || ext.Debug.Trace[0].DurationMS != 1.5 || ext.Debug.Trace[0].RejectedBids[0].BidID != "b1" {
+		t.Errorf("unexpected trace: %+v", ext.Debug.Trace)
+	}
+
+	if buildResponseExt(&exchange.AuctionResponse{DebugInfo: &exchange.DebugInfo{}}).Debug != nil {
+		t.Error("expected no ext.debug without captured calls or trace")
+	}
+}
+
 // Test writeError
 func TestWriteError(t *testing.T) {
 	w := httptest.NewRecorder()
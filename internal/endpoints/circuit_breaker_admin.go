@@ -0,0 +1,130 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// BidderBreakerController reads and controls bidder circuit breakers (implemented by exchange.Exchange)
+type BidderBreakerController interface {
+	GetBidderCircuitBreakerStats() map[string]idr.CircuitBreakerStats
+	ControlBidderCircuitBreaker(bidderCode, action string) (before, after idr.CircuitBreakerStats, err error)
+}
+
+// CircuitBreakerActionRequest is the body for POST /admin/circuit-breaker
+type CircuitBreakerActionRequest struct {
+	Bidder string `json:"bidder"`
+	Action string `json:"action"` // force_open, reset or drain
+}
+
+// CircuitBreakerActionResponse is the response for POST /admin/circuit-breaker
+type CircuitBreakerActionResponse struct {
+	Bidder string                  `json:"bidder"`
+	Action string                  `json:"action"`
+	Before idr.CircuitBreakerStats `json:"before"`
+	After  idr.CircuitBreakerStats `json:"after"`
+}
+
+// CircuitBreakerAdminHandler serves circuit breaker stats and operator actions
+type CircuitBreakerAdminHandler struct {
+	bidders BidderBreakerController
+	idr     func() *idr.CircuitBreakerStats
+	audit   AuditRecorder
+}
+
+// NewCircuitBreakerAdminHandler creates a new circuit breaker admin handler.
+// idrStats returns nil when IDR is disabled.
+func NewCircuitBreakerAdminHandler(bidders BidderBreakerController, idrStats func() *idr.CircuitBreakerStats) *CircuitBreakerAdminHandler {
+	return &CircuitBreakerAdminHandler{bidders: bidders, idr: idrStats}
+}
+
+// SetAuditRecorder sets the audit log used to record breaker actions
+func (h *CircuitBreakerAdminHandler) SetAuditRecorder(recorder AuditRecorder) {
+	h.audit = recorder
+}
+
+// ServeHTTP routes circuit breaker admin requests
+//
+//	GET  /admin/circuit-breaker  - IDR and per-bidder breaker stats
+//	POST /admin/circuit-breaker  - Apply an action to a bidder's breaker: {"bidder": "rubicon", "action": "drain"}
+func (h *CircuitBreakerAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		response := map[string]interface{}{
+			"bidders": h.bidders.GetBidderCircuitBreakerStats(),
+		}
+		if stats := h.idrStats(); stats != nil {
+			response["idr"] = stats
+		} else {
+			response["idr"] = map[string]string{"status": "disabled"}
+		}
+		writeCircuitBreakerJSON(w, http.StatusOK, response)
+
+	case http.MethodPost:
+		var req CircuitBreakerActionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch req.Action {
+		case exchange.BreakerActionForceOpen, exchange.BreakerActionReset, exchange.BreakerActionDrain:
+		default:
+			writeError(w, "action must be one of force_open, reset, drain", http.StatusBadRequest)
+			return
+		}
+		if req.Bidder == "" {
+			writeError(w, "bidder is required", http.StatusBadRequest)
+			return
+		}
+
+		before, after, err := h.bidders.ControlBidderCircuitBreaker(req.Bidder, req.Action)
+		if errors.Is(err, exchange.ErrUnknownBidder) {
+			writeError(w, "bidder not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger.Log.Warn().
+			Str("bidder_code", req.Bidder).
+			Str("action", req.Action).
+			Str("from_state", before.State).
+			Str("to_state", after.State).
+			Msg("Bidder circuit breaker changed by admin")
+		RecordAdminChange(r, h.audit, "circuit_breaker."+req.Action, storage.AuditResourceCircuitBreaker, req.Bidder, before, after)
+		writeCircuitBreakerJSON(w, http.StatusOK, CircuitBreakerActionResponse{
+			Bidder: req.Bidder,
+			Action: req.Action,
+			Before: before,
+			After:  after,
+		})
+
+	default:
+		writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// idrStats returns the IDR client's breaker stats, or nil when IDR is disabled
+func (h *CircuitBreakerAdminHandler) idrStats() *idr.CircuitBreakerStats {
+	if h.idr == nil {
+		return nil
+	}
+	return h.idr()
+}
+
+// writeCircuitBreakerJSON writes a JSON response
+func writeCircuitBreakerJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode circuit breaker admin response")
+	}
+}
@@ -0,0 +1,92 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+func newBreakerTestExchange(t *testing.T) *exchange.Exchange {
+	t.Helper()
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := exchange.New(registry, &exchange.Config{IDREnabled: false})
+	t.Cleanup(func() { ex.Close() })
+	return ex
+}
+
+func TestCircuitBreakerAdmin_Stats(t *testing.T) {
+	handler := NewCircuitBreakerAdminHandler(newBreakerTestExchange(t), nil)
+
+	w := doAdminRequest(handler, http.MethodGet, "/admin/circuit-breaker", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	var resp struct {
+		IDR     map[string]string                  `json:"idr"`
+		Bidders map[string]idr.CircuitBreakerStats `json:"bidders"`
+	}
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.IDR["status"] != "disabled" || resp.Bidders["rubicon"].State != idr.StateClosed {
+		t.Errorf("Unexpected stats response: %+v", resp)
+	}
+}
+
+func TestCircuitBreakerAdmin_Actions(t *testing.T) {
+	ex := newBreakerTestExchange(t)
+	audit := &memoryAuditLog{}
+	handler := NewCircuitBreakerAdminHandler(ex, nil)
+	handler.SetAuditRecorder(audit)
+	serve := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, asAdmin(r, "dave", middleware.AdminRoleOps))
+	})
+
+	w := doAdminRequest(serve, http.MethodPost, "/admin/circuit-breaker", CircuitBreakerActionRequest{Bidder: "rubicon", Action: "drain"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp CircuitBreakerActionResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Before.State != idr.StateClosed || resp.After.State != idr.StateOpen || !resp.After.Drained {
+		t.Errorf("Unexpected drain response: %+v", resp)
+	}
+	if stats := ex.GetBidderCircuitBreakerStats()["rubicon"]; !stats.Drained {
+		t.Errorf("Expected the bidder drained, got %+v", stats)
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(audit.entries))
+	}
+	if e := audit.entries[0]; e.Action != "circuit_breaker.drain" || e.ResourceType != storage.AuditResourceCircuitBreaker || e.ResourceID != "rubicon" || e.Actor != "dave" {
+		t.Errorf("Unexpected audit entry: %+v", e)
+	}
+
+	if w := doAdminRequest(serve, http.MethodPost, "/admin/circuit-breaker", CircuitBreakerActionRequest{Bidder: "rubicon", Action: "reset"}); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+	if stats := ex.GetBidderCircuitBreakerStats()["rubicon"]; stats.State != idr.StateClosed || stats.Drained {
+		t.Errorf("Expected the bidder reset, got %+v", stats)
+	}
+
+	if w := doAdminRequest(serve, http.MethodPost, "/admin/circuit-breaker", CircuitBreakerActionRequest{Bidder: "missing", Action: "reset"}); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown bidder, got %d", w.Code)
+	}
+	if w := doAdminRequest(serve, http.MethodPost, "/admin/circuit-breaker", CircuitBreakerActionRequest{Bidder: "rubicon", Action: "explode"}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown action, got %d", w.Code)
+	}
+	if w := doAdminRequest(serve, http.MethodPost, "/admin/circuit-breaker", `{`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid JSON, got %d", w.Code)
+	}
+	if w := doAdminRequest(serve, http.MethodDelete, "/admin/circuit-breaker", nil); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", w.Code)
+	}
+	if len(audit.entries) != 2 {
+		t.Errorf("Expected only successful actions audited, got %d entries", len(audit.entries))
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
 	"github.com/thenexusengine/tne_springwire/internal/usersync"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 )
@@ -55,9 +57,11 @@ type BidderSyncStatus struct {
 
 // CookieSyncHandler handles cookie sync requests
 type CookieSyncHandler struct {
-	syncers  map[string]*usersync.Syncer
-	hostURL  string
-	maxSyncs int
+	syncers    map[string]*usersync.Syncer
+	syncerKeys map[string]string
+	hostURL    string
+	maxSyncs   int
+	analytics  analytics.Module
 }
 
 // CookieSyncConfig holds configuration for the cookie sync handler
@@ -65,6 +69,8 @@ type CookieSyncConfig struct {
 	HostURL     string
 	MaxSyncs    int
 	SyncConfigs map[string]usersync.SyncerConfig
+	// SyncerKeys maps bidder aliases to the syncer (and cookie key) they share with their core bidder
+	SyncerKeys map[string]string
 }
 
 // DefaultCookieSyncConfig returns default configuration
@@ -84,10 +90,16 @@ func NewCookieSyncHandler(config *CookieSyncConfig) *CookieSyncHandler {
 		syncers[code] = usersync.NewSyncer(syncConfig, config.HostURL)
 	}
 
+	syncerKeys := make(map[string]string, len(config.SyncerKeys))
+	for code, key := range config.SyncerKeys {
+		syncerKeys[strings.ToLower(code)] = strings.ToLower(key)
+	}
+
 	return &CookieSyncHandler{
-		syncers:  syncers,
-		hostURL:  config.HostURL,
-		maxSyncs: config.MaxSyncs,
+		syncers:    syncers,
+		syncerKeys: syncerKeys,
+		hostURL:    config.HostURL,
+		maxSyncs:   config.MaxSyncs,
 	}
 }
 
@@ -116,19 +128,23 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if req.GDPR == 1 {
 		if req.GDPRConsent == "" {
 			logger.Log.Warn().Msg("GDPR consent required but not provided for cookie sync")
-			h.respondJSON(w, CookieSyncResponse{
+			response := CookieSyncResponse{
 				Status:       "ok",
 				BidderStatus: []BidderSyncStatus{},
-			})
+			}
+			h.logAnalytics(req, false, &response)
+			h.respondJSON(w, response)
 			return
 		}
 		// Validate consent string format (minimum length for TCF v2)
 		if len(req.GDPRConsent) < 20 {
 			logger.Log.Warn().Msg("Invalid GDPR consent string for cookie sync")
-			h.respondJSON(w, CookieSyncResponse{
+			response := CookieSyncResponse{
 				Status:       "ok",
 				BidderStatus: []BidderSyncStatus{},
-			})
+			}
+			h.logAnalytics(req, false, &response)
+			h.respondJSON(w, response)
 			return
 		}
 	}
@@ -138,7 +154,9 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Check for opt-out
 	if cookie.IsOptOut() {
-		h.respondJSON(w, CookieSyncResponse{Status: "ok"})
+		response := CookieSyncResponse{Status: "ok"}
+		h.logAnalytics(req, true, &response)
+		h.respondJSON(w, response)
 		return
 	}
 
@@ -158,12 +176,19 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	syncCount := 0
+	syncedKeys := make(map[string]bool, len(biddersToSync))
 	for _, bidderCode := range biddersToSync {
 		if syncCount >= req.Limit {
 			break
 		}
 
-		syncer, ok := h.syncers[strings.ToLower(bidderCode)]
+		// Aliases sharing a syncer with their core bidder only need one sync
+		key := h.syncerKey(bidderCode)
+		if syncedKeys[strings.ToLower(key)] {
+			continue
+		}
+
+		syncer, ok := h.syncers[strings.ToLower(key)]
 		if !ok {
 			response.BidderStatus = append(response.BidderStatus, BidderSyncStatus{
 				Bidder: bidderCode,
@@ -177,7 +202,7 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Check if already synced
-		if cookie.HasUID(bidderCode) {
+		if cookie.HasUID(key) {
 			continue
 		}
 
@@ -204,6 +229,7 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			NoCookie: true,
 			UserSync: syncInfo,
 		})
+		syncedKeys[strings.ToLower(key)] = true
 		syncCount++
 	}
 
@@ -212,9 +238,39 @@ func (h *CookieSyncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.SetCookie(w, httpCookie)
 	}
 
+	h.logAnalytics(req, false, &response)
 	h.respondJSON(w, response)
 }
 
+// SetAnalytics sets the analytics module that receives one CookieSyncObject per request
+func (h *CookieSyncHandler) SetAnalytics(m analytics.Module) {
+	h.analytics = m
+}
+
+// logAnalytics reports the cookie sync outcome to the analytics module
+func (h *CookieSyncHandler) logAnalytics(req CookieSyncRequest, optOut bool, response *CookieSyncResponse) {
+	if h.analytics == nil {
+		return
+	}
+
+	cso := &analytics.CookieSyncObject{
+		Status:    response.Status,
+		Timestamp: time.Now(),
+		GDPR:      req.GDPR == 1,
+		OptOut:    optOut,
+		Limit:     req.Limit,
+		Bidders:   make([]analytics.CookieSyncBidder, 0, len(response.BidderStatus)),
+	}
+	for _, bs := range response.BidderStatus {
+		b := analytics.CookieSyncBidder{BidderCode: bs.Bidder, Error: bs.Error}
+		if bs.UserSync != nil {
+			b.SyncType = string(bs.UserSync.Type)
+		}
+		cso.Bidders = append(cso.Bidders, b)
+	}
+	h.analytics.LogCookieSyncObject(cso)
+}
+
 // getSyncTypeForBidder determines the sync type for a bidder based on filterSettings
 // Returns empty string if the bidder should be filtered out
 func (h *CookieSyncHandler) getSyncTypeForBidder(bidderCode string, filterSettings *FilterSettings) usersync.SyncType {
@@ -292,7 +348,7 @@ func (h *CookieSyncHandler) getBiddersToSync(req CookieSyncRequest, cookie *user
 	if cookie != nil {
 		needsSync := make([]string, 0, len(bidders))
 		for _, bidder := range bidders {
-			if !cookie.HasUID(bidder) {
+			if !cookie.HasUID(h.syncerKey(bidder)) {
 				needsSync = append(needsSync, bidder)
 			}
 		}
@@ -302,6 +358,15 @@ func (h *CookieSyncHandler) getBiddersToSync(req CookieSyncRequest, cookie *user
 	return bidders
 }
 
+// syncerKey returns the syncer key a bidder syncs under: its own code unless it's an alias
+// sharing its core bidder's syncer
+func (h *CookieSyncHandler) syncerKey(bidderCode string) string {
+	if key, ok := h.syncerKeys[strings.ToLower(bidderCode)]; ok {
+		return key
+	}
+	return bidderCode
+}
+
 // getCookieDomain extracts the domain for cookies
 func (h *CookieSyncHandler) getCookieDomain(r *http.Request) string {
 	host := r.Host
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
 	"github.com/thenexusengine/tne_springwire/internal/usersync"
 )
 
@@ -275,6 +276,31 @@ func TestCookieSyncHandler_AlreadySynced(t *testing.T) {
 	}
 }
 
+func TestCookieSyncHandler_AliasSyncerKeys(t *testing.T) {
+	handler := createTestHandler()
+	handler.syncerKeys = map[string]string{"rubicon2": "rubicon", "apn2": "appnexus"}
+
+	// The alias shares appnexus's UID, so it's already synced
+	cookie := usersync.NewCookie()
+	cookie.SetUID("appnexus", "existing-uid")
+	httpCookie, _ := cookie.ToHTTPCookie("example.com")
+
+	body, _ := json.Marshal(CookieSyncRequest{Bidders: []string{"apn2", "rubicon", "rubicon2"}})
+	req := httptest.NewRequest("POST", "/cookie_sync", bytes.NewReader(body))
+	req.AddCookie(httpCookie)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	var resp CookieSyncResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+
+	// rubicon2 shares rubicon's syncer, so only one sync is returned
+	if len(resp.BidderStatus) != 1 || resp.BidderStatus[0].Bidder != "rubicon" {
+		t.Errorf("expected a single rubicon sync, got %+v", resp.BidderStatus)
+	}
+}
+
 func TestCookieSyncHandler_UnsupportedBidder(t *testing.T) {
 	handler := createTestHandler()
 
@@ -671,3 +697,83 @@ func TestCookieSyncHandler_NoGDPR_Works(t *testing.T) {
 		t.Error("expected bidder status when GDPR=0")
 	}
 }
+
+// recordingAnalytics captures analytics objects for endpoint tests
+type recordingAnalytics struct {
+	videos        []*analytics.VideoObject
+	cookieSyncs   []*analytics.CookieSyncObject
+	notifications []*analytics.NotificationObject
+}
+
+func (r *recordingAnalytics) LogAuctionObject(*analytics.AuctionObject) {}
+func (r *recordingAnalytics) LogVideoObject(vo *analytics.VideoObject) {
+	r.videos = append(r.videos, vo)
+}
+func (r *recordingAnalytics) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	r.cookieSyncs = append(r.cookieSyncs, cso)
+}
+func (r *recordingAnalytics) LogNotificationObject(no *analytics.NotificationObject) {
+	r.notifications = append(r.notifications, no)
+}
+func (r *recordingAnalytics) Shutdown() {}
+
+func TestCookieSync_LogsAnalytics(t *testing.T) {
+	handler := NewCookieSyncHandler(&CookieSyncConfig{
+		HostURL:  "https://example.com",
+		MaxSyncs: 5,
+		SyncConfigs: map[string]usersync.SyncerConfig{
+			"appnexus": {
+				BidderCode:      "appnexus",
+				RedirectSyncURL: "https://ib.adnxs.com/getuid?{{redirect_url}}",
+				Enabled:         true,
+			},
+		},
+	})
+	recorder := &recordingAnalytics{}
+	handler.SetAnalytics(recorder)
+
+	body := `{"bidders":["appnexus","unknown"]}`
+	req := httptest.NewRequest(http.MethodPost, "/cookie_sync", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if len(recorder.cookieSyncs) != 1 {
+		t.Fatalf("expected 1 cookie sync object, got %d", len(recorder.cookieSyncs))
+	}
+	cso := recorder.cookieSyncs[0]
+	if cso.Status != "ok" || cso.Limit != 5 || cso.GDPR {
+		t.Errorf("unexpected cookie sync object: %+v", cso)
+	}
+	if len(cso.Bidders) != 2 {
+		t.Fatalf("expected 2 bidder entries, got %d", len(cso.Bidders))
+	}
+	for _, b := range cso.Bidders {
+		switch b.BidderCode {
+		case "appnexus":
+			if b.SyncType != string(usersync.SyncTypeRedirect) {
+				t.Errorf("expected redirect sync for appnexus, got %q", b.SyncType)
+			}
+		case "unknown":
+			if b.Error == "" {
+				t.Error("expected error for unknown bidder")
+			}
+		}
+	}
+}
+
+func TestCookieSync_LogsAnalyticsWithoutConsent(t *testing.T) {
+	handler := NewCookieSyncHandler(DefaultCookieSyncConfig("https://example.com"))
+	recorder := &recordingAnalytics{}
+	handler.SetAnalytics(recorder)
+
+	req := httptest.NewRequest(http.MethodPost, "/cookie_sync", strings.NewReader(`{"gdpr":1}`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if len(recorder.cookieSyncs) != 1 {
+		t.Fatalf("expected 1 cookie sync object, got %d", len(recorder.cookieSyncs))
+	}
+	if !recorder.cookieSyncs[0].GDPR || len(recorder.cookieSyncs[0].Bidders) != 0 {
+		t.Errorf("unexpected cookie sync object: %+v", recorder.cookieSyncs[0])
+	}
+}
@@ -0,0 +1,198 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/currency"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// RateSource is the currency converter as seen by the admin API (implemented by currency.Converter)
+type RateSource interface {
+	Snapshot() *currency.RateSnapshot
+	PushRates(ctx context.Context, file *currency.CurrencyFile) (*currency.RateSnapshot, error)
+	ClearPushedRates(ctx context.Context) error
+}
+
+// RateSnapshotReader reads persisted rate snapshots (implemented by storage.CurrencyRateStore)
+type RateSnapshotReader interface {
+	Get(ctx context.Context, version string) (*currency.RateSnapshot, error)
+	List(ctx context.Context, limit int) ([]*currency.RateSnapshot, error)
+}
+
+// RateSnapshotListResponse is the response for GET /admin/currency/snapshots
+type RateSnapshotListResponse struct {
+	Snapshots []*currency.RateSnapshot `json:"snapshots"`
+	Count     int                      `json:"count"`
+}
+
+// CurrencyAdminHandler serves admin-pushed rates and the rate snapshot history
+type CurrencyAdminHandler struct {
+	rates     RateSource
+	snapshots RateSnapshotReader
+	audit     AuditRecorder
+}
+
+// NewCurrencyAdminHandler creates a new currency admin handler.
+// rates is nil when conversion is disabled and snapshots is nil without a database.
+func NewCurrencyAdminHandler(rates RateSource, snapshots RateSnapshotReader) *CurrencyAdminHandler {
+	return &CurrencyAdminHandler{rates: rates, snapshots: snapshots}
+}
+
+// SetAuditRecorder sets the audit log used to record pushed rates
+func (h *CurrencyAdminHandler) SetAuditRecorder(recorder AuditRecorder) {
+	h.audit = recorder
+}
+
+// ServeHTTP routes currency admin requests
+//
+//	GET    /admin/currency/rates                - Current rate snapshot
+//	POST   /admin/currency/rates                - Push a rate set (Prebid currency file format)
+//	DELETE /admin/currency/rates                - Remove pushed rates and reload from other providers
+//	GET    /admin/currency/snapshots?limit=N    - Persisted snapshots, newest first
+//	GET    /admin/currency/snapshots/{version}  - One persisted snapshot
+func (h *CurrencyAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/admin/currency/rates":
+		h.handleRates(w, r)
+	case path == "/admin/currency/snapshots":
+		h.listSnapshots(w, r)
+	case strings.HasPrefix(path, "/admin/currency/snapshots/"):
+		h.getSnapshot(w, r, strings.TrimPrefix(path, "/admin/currency/snapshots/"))
+	default:
+		writeError(w, "not found", http.StatusNotFound)
+	}
+}
+
+// handleRates serves /admin/currency/rates
+func (h *CurrencyAdminHandler) handleRates(w http.ResponseWriter, r *http.Request) {
+	if h.rates == nil {
+		writeError(w, "currency conversion is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		snapshot := h.rates.Snapshot()
+		if snapshot == nil {
+			writeError(w, "no currency rates loaded", http.StatusNotFound)
+			return
+		}
+		writeCurrencyJSON(w, http.StatusOK, snapshot)
+
+	case http.MethodPost:
+		var file currency.CurrencyFile
+		if err := json.NewDecoder(r.Body).Decode(&file); err != nil {
+			writeError(w, "invalid JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := file.Validate(); err != nil {
+			writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		before := h.rates.Snapshot()
+		snapshot, err := h.rates.PushRates(r.Context(), &file)
+		if errors.Is(err, currency.ErrAdminProviderDisabled) {
+			writeError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			logger.Log.Error().Err(err).Msg("failed to push currency rates")
+			writeError(w, "failed to apply currency rates", http.StatusInternalServerError)
+			return
+		}
+		RecordAdminChange(r, h.audit, "currency.push_rates", storage.AuditResourceCurrency, snapshot.Version, before, snapshot)
+		writeCurrencyJSON(w, http.StatusOK, snapshot)
+
+	case http.MethodDelete:
+		before := h.rates.Snapshot()
+		if err := h.rates.ClearPushedRates(r.Context()); err != nil {
+			// Previous rates stay in use when no other provider answers
+			logger.Log.Warn().Err(err).Msg("no currency provider answered after clearing pushed rates")
+		}
+		after := h.rates.Snapshot()
+		resourceID := ""
+		if after != nil {
+			resourceID = after.Version
+		}
+		RecordAdminChange(r, h.audit, "currency.clear_rates", storage.AuditResourceCurrency, resourceID, before, after)
+		writeCurrencyJSON(w, http.StatusOK, after)
+
+	default:
+		writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// listSnapshots serves GET /admin/currency/snapshots
+func (h *CurrencyAdminHandler) listSnapshots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.snapshots == nil {
+		writeError(w, "rate history requires a database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeError(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	snapshots, err := h.snapshots.List(r.Context(), limit)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("failed to list currency rate snapshots")
+		writeError(w, "failed to read rate history", http.StatusInternalServerError)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []*currency.RateSnapshot{}
+	}
+	writeCurrencyJSON(w, http.StatusOK, RateSnapshotListResponse{Snapshots: snapshots, Count: len(snapshots)})
+}
+
+// getSnapshot serves GET /admin/currency/snapshots/{version}
+func (h *CurrencyAdminHandler) getSnapshot(w http.ResponseWriter, r *http.Request, version string) {
+	if r.Method != http.MethodGet {
+		writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.snapshots == nil {
+		writeError(w, "rate history requires a database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	snapshot, err := h.snapshots.Get(r.Context(), version)
+	if err != nil {
+		logger.Log.Error().Err(err).Str("version", version).Msg("failed to get currency rate snapshot")
+		writeError(w, "failed to read rate history", http.StatusInternalServerError)
+		return
+	}
+	if snapshot == nil {
+		writeError(w, "rate snapshot not found", http.StatusNotFound)
+		return
+	}
+	writeCurrencyJSON(w, http.StatusOK, snapshot)
+}
+
+// writeCurrencyJSON writes a JSON response
+func writeCurrencyJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode currency admin response")
+	}
+}
@@ -0,0 +1,140 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/currency"
+)
+
+// memorySnapshots serves rate snapshots from memory
+type memorySnapshots map[string]*currency.RateSnapshot
+
+func (m memorySnapshots) Get(_ context.Context, version string) (*currency.RateSnapshot, error) {
+	return m[version], nil
+}
+
+func (m memorySnapshots) List(_ context.Context, limit int) ([]*currency.RateSnapshot, error) {
+	out := make([]*currency.RateSnapshot, 0, len(m))
+	for _, s := range m {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func newTestConverter(t *testing.T) *currency.Converter {
+	t.Helper()
+	base := currency.NewStaticProvider("static")
+	base.Set(&currency.CurrencyFile{Conversions: map[string]map[string]float64{"USD": {"EUR": 0.9}}})
+	admin := currency.NewStaticProvider(currency.ProviderAdmin)
+	converter := currency.NewConverterWithProviders(nil, admin, base)
+	if err := converter.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	t.Cleanup(converter.Stop)
+	return converter
+}
+
+func TestCurrencyAdmin_PushAndClearRates(t *testing.T) {
+	converter := currency.NewConverter(&currency.Config{Providers: []string{currency.ProviderAdmin}})
+	audit := &memoryAuditLog{}
+	handler := NewCurrencyAdminHandler(converter, nil)
+	handler.SetAuditRecorder(audit)
+	serve := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, asAdmin(r, "carol", middleware.AdminRoleFinance))
+	})
+
+	if w := doAdminRequest(serve, http.MethodGet, "/admin/currency/rates", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 before any rates are loaded, got %d", w.Code)
+	}
+
+	w := doAdminRequest(serve, http.MethodPost, "/admin/currency/rates", map[string]interface{}{
+		"dataAsOf":    "2026-10-18",
+		"conversions": map[string]map[string]float64{"USD": {"EUR": 0.92}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var snapshot currency.RateSnapshot
+	json.NewDecoder(w.Body).Decode(&snapshot)
+	if snapshot.Source != currency.ProviderAdmin || snapshot.Version == "" {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+	if rate, _ := converter.GetRate("USD", "EUR"); rate != 0.92 {
+		t.Errorf("Expected pushed rate 0.92, got %v", rate)
+	}
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(audit.entries))
+	}
+	if e := audit.entries[0]; e.Action != "currency.push_rates" || e.ResourceType != storage.AuditResourceCurrency || e.ResourceID != snapshot.Version || e.Actor != "carol" {
+		t.Errorf("Unexpected audit entry: %+v", e)
+	}
+
+	if w := doAdminRequest(serve, http.MethodPost, "/admin/currency/rates", `{"conversions":{}}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for empty rates, got %d", w.Code)
+	}
+
+	// Clearing leaves the last rates in place when no other provider answers
+	if w := doAdminRequest(serve, http.MethodDelete, "/admin/currency/rates", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(audit.entries) != 2 || audit.entries[1].Action != "currency.clear_rates" {
+		t.Errorf("Expected clear to be audited, got %+v", audit.entries)
+	}
+}
+
+func TestCurrencyAdmin_PushWithoutAdminProvider(t *testing.T) {
+	handler := NewCurrencyAdminHandler(currency.NewConverter(&currency.Config{Providers: []string{currency.ProviderHTTP}}), nil)
+	w := doAdminRequest(handler, http.MethodPost, "/admin/currency/rates", map[string]interface{}{
+		"conversions": map[string]map[string]float64{"USD": {"EUR": 0.92}},
+	})
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409, got %d", w.Code)
+	}
+}
+
+func TestCurrencyAdmin_Snapshots(t *testing.T) {
+	converter := newTestConverter(t)
+	current := converter.Snapshot()
+	handler := NewCurrencyAdminHandler(converter, memorySnapshots{current.Version: current})
+
+	w := doAdminRequest(handler, http.MethodGet, "/admin/currency/rates", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	w = doAdminRequest(handler, http.MethodGet, "/admin/currency/snapshots", nil)
+	var list RateSnapshotListResponse
+	json.NewDecoder(w.Body).Decode(&list)
+	if w.Code != http.StatusOK || list.Count != 1 {
+		t.Errorf("Expected one snapshot, got %d %+v", w.Code, list)
+	}
+
+	w = doAdminRequest(handler, http.MethodGet, "/admin/currency/snapshots/"+current.Version, nil)
+	var got currency.RateSnapshot
+	json.NewDecoder(w.Body).Decode(&got)
+	if w.Code != http.StatusOK || got.Conversions["USD"]["EUR"] != 0.9 {
+		t.Errorf("Expected snapshot %s, got %d %+v", current.Version, w.Code, got)
+	}
+
+	if w := doAdminRequest(handler, http.MethodGet, "/admin/currency/snapshots/unknown", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown version, got %d", w.Code)
+	}
+	if w := doAdminRequest(handler, http.MethodGet, "/admin/currency/snapshots?limit=x", nil); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for bad limit, got %d", w.Code)
+	}
+
+	noDB := NewCurrencyAdminHandler(converter, nil)
+	if w := doAdminRequest(noDB, http.MethodGet, "/admin/currency/snapshots", nil); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without a database, got %d", w.Code)
+	}
+	disabled := NewCurrencyAdminHandler(nil, nil)
+	if w := doAdminRequest(disabled, http.MethodGet, "/admin/currency/rates", nil); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with conversion disabled, got %d", w.Code)
+	}
+}
@@ -2,12 +2,16 @@
 package endpoints
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"html/template"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/internal/analytics/rollup"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 )
 
@@ -91,6 +95,49 @@ func LogAuction(requestID string, impCount, bidCount int, winningBidders []strin
 	globalMetrics.LastUpdate = time.Now()
 }
 
+// DashboardModule is an analytics module that feeds auction objects into the dashboard
+type DashboardModule struct{}
+
+// NewDashboardModule creates a dashboard analytics module
+func NewDashboardModule() *DashboardModule {
+	return &DashboardModule{}
+}
+
+// LogAuctionObject records the auction in the dashboard metrics
+func (m *DashboardModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	bidCount := 0
+	if ao.Response != nil {
+		for _, seatBid := range ao.Response.SeatBid {
+			bidCount += len(seatBid.Bid)
+		}
+	}
+
+	winners := ao.Winners()
+	winningBidders := make([]string, 0, len(winners))
+	for _, w := range winners {
+		winningBidders = append(winningBidders, w.BidderCode)
+	}
+
+	var err error
+	if ao.Error != "" {
+		err = errors.New(ao.Error)
+	}
+
+	LogAuction(ao.AuctionID, len(ao.Imps), bidCount, winningBidders, time.Duration(ao.LatencyMs)*time.Millisecond, err == nil, err)
+}
+
+// LogVideoObject is a no-op; the dashboard only tracks auctions
+func (m *DashboardModule) LogVideoObject(*analytics.VideoObject) {}
+
+// LogCookieSyncObject is a no-op; the dashboard only tracks auctions
+func (m *DashboardModule) LogCookieSyncObject(*analytics.CookieSyncObject) {}
+
+// LogNotificationObject is a no-op; the dashboard only tracks auctions
+func (m *DashboardModule) LogNotificationObject(*analytics.NotificationObject) {}
+
+// Shutdown is a no-op
+func (m *DashboardModule) Shutdown() {}
+
 // DashboardHandler serves the live dashboard HTML
 type DashboardHandler struct{}
 
@@ -106,15 +153,54 @@ func (h *DashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// MetricsAPIHandler serves metrics as JSON for the dashboard
-type MetricsAPIHandler struct{}
+// DashboardStore provides windowed aggregates shared across all instances
+type DashboardStore interface {
+	Query(ctx context.Context, window string) (*rollup.Summary, error)
+}
+
+// MetricsAPIHandler serves metrics as JSON for the dashboard.
+// Counters from globalMetrics cover this instance only; when a store is set the
+// response also carries per-publisher and per-bidder aggregates for ?window=1h|24h|7d.
+type MetricsAPIHandler struct {
+	mu    sync.RWMutex
+	store DashboardStore
+}
 
 // NewMetricsAPIHandler creates a new metrics API handler
 func NewMetricsAPIHandler() *MetricsAPIHandler {
 	return &MetricsAPIHandler{}
 }
 
+// SetStore sets the shared store used for windowed aggregates
+func (h *MetricsAPIHandler) SetStore(store DashboardStore) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.store = store
+}
+
 func (h *MetricsAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	window := r.URL.Query().Get("window")
+	if window == "" {
+		window = rollup.DefaultWindow
+	}
+	if _, ok := rollup.LookupWindow(window); !ok {
+		writeError(w, "invalid window, expected one of 1h, 24h, 7d", http.StatusBadRequest)
+		return
+	}
+
+	h.mu.RLock()
+	store := h.store
+	h.mu.RUnlock()
+
+	var summary *rollup.Summary
+	var storeErr error
+	if store != nil {
+		summary, storeErr = store.Query(r.Context(), window)
+		if storeErr != nil {
+			logger.Log.Warn().Err(storeErr).Str("window", window).Msg("failed to query dashboard store")
+		}
+	}
+
 	globalMetrics.mu.RLock()
 	defer globalMetrics.mu.RUnlock()
 
@@ -131,6 +217,14 @@ func (h *MetricsAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"average_duration":    globalMetrics.AverageDuration,
 		"last_update":         globalMetrics.LastUpdate.Format(time.RFC3339),
 		"uptime_seconds":      int64(uptime.Seconds()),
+		"window":              window,
+	}
+	if summary != nil {
+		response["totals"] = summary.Totals
+		response["publishers"] = summary.Publishers
+		response["bidders"] = summary.Bidders
+	} else if storeErr != nil {
+		response["store_error"] = "shared metrics unavailable"
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -313,6 +407,45 @@ var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE
             font-size: 0.75rem;
             margin-top: 0.25rem;
         }
+        .section-header {
+            display: flex;
+            justify-content: space-between;
+            align-items: center;
+            margin-bottom: 1rem;
+        }
+        .section-header h2 { margin-bottom: 0; }
+        .window-selector button {
+            background: #0f172a;
+            color: #94a3b8;
+            border: 1px solid #334155;
+            border-radius: 0.25rem;
+            padding: 0.25rem 0.75rem;
+            margin-left: 0.25rem;
+            cursor: pointer;
+        }
+        .window-selector button.active {
+            background: #3b82f6;
+            color: #f1f5f9;
+            border-color: #3b82f6;
+        }
+        .rollup-table {
+            width: 100%;
+            border-collapse: collapse;
+            font-size: 0.875rem;
+            margin-bottom: 1.5rem;
+        }
+        .rollup-table th, .rollup-table td {
+            text-align: right;
+            padding: 0.5rem;
+            border-bottom: 1px solid #334155;
+        }
+        .rollup-table th:first-child, .rollup-table td:first-child { text-align: left; }
+        .rollup-table th {
+            color: #94a3b8;
+            font-size: 0.75rem;
+            text-transform: uppercase;
+            letter-spacing: 0.05em;
+        }
     </style>
 </head>
 <body>
@@ -348,6 +481,21 @@ var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE
         </div>
     </div>
 
+    <div class="section">
+        <div class="section-header">
+            <h2>All Instances</h2>
+            <div class="window-selector" id="window-selector">
+                <button data-window="1h" class="active">1h</button>
+                <button data-window="24h">24h</button>
+                <button data-window="7d">7d</button>
+            </div>
+        </div>
+        <h3 class="label">Publishers</h3>
+        <table class="rollup-table" id="publisher-rollup"></table>
+        <h3 class="label">Bidders</h3>
+        <table class="rollup-table" id="bidder-rollup"></table>
+    </div>
+
     <div class="section">
         <h2>Top Bidders</h2>
         <div class="bidder-stats" id="bidder-stats">
@@ -379,9 +527,47 @@ var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE
             return date.toLocaleTimeString('en-US', { hour12: false });
         }
 
+        let currentWindow = '1h';
+
+        document.querySelectorAll('#window-selector button').forEach(button => {
+            button.addEventListener('click', () => {
+                currentWindow = button.dataset.window;
+                document.querySelectorAll('#window-selector button').forEach(b => b.classList.toggle('active', b === button));
+                updateDashboard();
+            });
+        });
+
+        // Publisher IDs, bidder codes and request IDs come from auction requests: escape them
+        function escapeHTML(value) {
+            return String(value).replace(/[&<>"']/g, c => ({
+                '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;'
+            })[c]);
+        }
+
+        function formatPercent(value) {
+            return (value * 100).toFixed(1) + '%';
+        }
+
+        function renderRollup(elementId, rows, label) {
+            const el = document.getElementById(elementId);
+            const entries = Object.entries(rows || {}).sort((a, b) => b[1].revenue - a[1].revenue);
+            if (entries.length === 0) {
+                el.innerHTML = '<tr><td style="color: #64748b;">No data for this window</td></tr>';
+                return;
+            }
+            el.innerHTML = '<tr><th>' + label + '</th><th>Requests</th><th>Revenue</th><th>Margin</th>' +
+                '<th>Bid Rate</th><th>Win Rate</th><th>Timeout Rate</th></tr>' +
+                entries.map(([name, s]) =>
+                    '<tr><td>' + escapeHTML(name) + '</td><td>' + s.requests + '</td><td>' + s.revenue.toFixed(2) + '</td>' +
+                    '<td>' + s.margin.toFixed(2) + ' (' + formatPercent(s.margin_rate) + ')</td>' +
+                    '<td>' + formatPercent(s.bid_rate) + '</td><td>' + formatPercent(s.win_rate) + '</td>' +
+                    '<td>' + formatPercent(s.timeout_rate) + '</td></tr>'
+                ).join('');
+        }
+
         async function updateDashboard() {
             try {
-                const response = await fetch('/admin/metrics');
+                const response = await fetch('/admin/metrics?window=' + currentWindow);
                 const data = await response.json();
 
                 document.getElementById('total-auctions').textContent = data.total_auctions;
@@ -391,12 +577,16 @@ var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE
                 document.getElementById('avg-duration').textContent = Math.round(data.average_duration) + 'ms';
                 document.getElementById('uptime').textContent = formatUptime(data.uptime_seconds);
 
+                // Update shared rollups
+                renderRollup('publisher-rollup', data.publishers, 'Publisher');
+                renderRollup('bidder-rollup', data.bidders, 'Bidder');
+
                 // Update bidder stats
                 const bidderStatsEl = document.getElementById('bidder-stats');
                 const bidderEntries = Object.entries(data.bidder_stats || {}).sort((a, b) => b[1] - a[1]);
                 if (bidderEntries.length > 0) {
                     bidderStatsEl.innerHTML = bidderEntries.map(([name, count]) =>
-                        '<div class="bidder-stat"><div class="name">' + name + '</div><div class="count">' + count + '</div></div>'
+                        '<div class="bidder-stat"><div class="name">' + escapeHTML(name) + '</div><div class="count">' + count + '</div></div>'
                     ).join('');
                 } else {
                     bidderStatsEl.innerHTML = '<div style="color: #64748b; font-size: 0.875rem;">No bids yet</div>';
@@ -413,14 +603,14 @@ var dashboardTemplate = template.Must(template.New("dashboard").Parse(`<!DOCTYPE
                         return '<div class="auction-item ' + statusClass + '">' +
                             '<div class="auction-time">' + formatTime(auction.timestamp) + '</div>' +
                             '<div class="auction-info">' +
-                                '<div class="auction-id">' + auction.request_id + '</div>' +
+                                '<div class="auction-id">' + escapeHTML(auction.request_id) + '</div>' +
                                 '<div class="auction-metrics">' +
                                     '<span>' + auction.imp_count + ' imp</span>' +
                                     '<span>' + auction.bid_count + ' bids</span>' +
                                 '</div>' +
-                                (auction.error ? '<div class="error-msg">' + auction.error + '</div>' : '') +
+                                (auction.error ? '<div class="error-msg">' + escapeHTML(auction.error) + '</div>' : '') +
                                 (bidders.length > 0 ? '<div class="auction-bidders">' +
-                                    bidders.map(b => '<span class="bidder-tag">' + b + '</span>').join('') +
+                                    bidders.map(b => '<span class="bidder-tag">' + escapeHTML(b) + '</span>').join('') +
                                 '</div>' : '') +
                             '</div>' +
                             '<div class="auction-duration ' + durationClass + '">' + auction.duration_ms + 'ms</div>' +
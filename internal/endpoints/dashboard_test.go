@@ -1,12 +1,18 @@
 package endpoints
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/internal/analytics/rollup"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 )
 
 // TestLogAuction_EdgeCases tests LogAuction with various edge cases
@@ -288,6 +294,24 @@ func TestDashboardHandler_ServeHTTP(t *testing.T) {
 	}
 }
 
+// TestDashboardHandler_EscapesRequestValues checks request-supplied values are escaped before
+// they reach innerHTML
+func TestDashboardHandler_EscapesRequestValues(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewDashboardHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	body := w.Body.String()
+
+	for _, str := range []string{
+		"escapeHTML(name) + '</td>",
+		"escapeHTML(auction.request_id)",
+		"escapeHTML(b)",
+	} {
+		if !contains(body, str) {
+			t.Errorf("Expected dashboard script to contain %q", str)
+		}
+	}
+}
+
 // TestDashboardHandler_ServeHTTP_EmptyMetrics tests dashboard with no auctions
 func TestDashboardHandler_ServeHTTP_EmptyMetrics(t *testing.T) {
 	// Reset metrics to empty state
@@ -456,3 +480,127 @@ func indexOfSubstring(s, substr string) int {
 	}
 	return -1
 }
+
+// TestDashboardModule_LogAuctionObject tests that analytics auction objects feed the dashboard
+func TestDashboardModule_LogAuctionObject(t *testing.T) {
+	globalMetrics = &DashboardMetrics{
+		BidderStats:    make(map[string]int),
+		RecentAuctions: make([]AuctionLog, 0, 100),
+		StartTime:      time.Now(),
+	}
+
+	module := NewDashboardModule()
+	module.LogAuctionObject(&analytics.AuctionObject{
+		AuctionID: "auction-1",
+		LatencyMs: 120,
+		Imps:      []analytics.ImpObject{{ImpID: "imp-1"}},
+		Response: &openrtb.BidResponse{
+			SeatBid: []openrtb.SeatBid{{Seat: "thenexusengine", Bid: []openrtb.Bid{{ID: "b1"}}}},
+		},
+		Bids: []analytics.BidObject{
+			{BidID: "b1", BidderCode: "rubicon", Won: true},
+			{BidID: "b2", BidderCode: "pubmatic"},
+		},
+	})
+	module.LogAuctionObject(&analytics.AuctionObject{AuctionID: "auction-2", Error: "invalid bid request"})
+
+	globalMetrics.mu.RLock()
+	defer globalMetrics.mu.RUnlock()
+
+	if globalMetrics.TotalAuctions != 2 || globalMetrics.SuccessfulAuctions != 1 || globalMetrics.FailedAuctions != 1 {
+		t.Errorf("unexpected totals: total=%d success=%d failed=%d",
+			globalMetrics.TotalAuctions, globalMetrics.SuccessfulAuctions, globalMetrics.FailedAuctions)
+	}
+	if globalMetrics.BidderStats["rubicon"] != 1 || globalMetrics.BidderStats["pubmatic"] != 0 {
+		t.Errorf("expected a single win for rubicon, got %v", globalMetrics.BidderStats)
+	}
+	if globalMetrics.TotalBids != 1 {
+		t.Errorf("expected 1 bid, got %d", globalMetrics.TotalBids)
+	}
+	if globalMetrics.RecentAuctions[0].Error != "invalid bid request" {
+		t.Errorf("expected error on failed auction, got %q", globalMetrics.RecentAuctions[0].Error)
+	}
+}
+
+// fakeDashboardStore returns a fixed summary and records the requested window
+type fakeDashboardStore struct {
+	window string
+	err    error
+}
+
+func (s *fakeDashboardStore) Query(_ context.Context, window string) (*rollup.Summary, error) {
+	s.window = window
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &rollup.Summary{
+		Window:     window,
+		Totals:     &rollup.Stats{Requests: 10},
+		Publishers: map[string]*rollup.Stats{"pub-1": {Requests: 10, Revenue: 1.5}},
+		Bidders:    map[string]*rollup.Stats{"rubicon": {Requests: 10, WinRate: 0.5}},
+	}, nil
+}
+
+// TestMetricsAPIHandler_SharedStore tests windowed aggregates from the shared store
+func TestMetricsAPIHandler_SharedStore(t *testing.T) {
+	store := &fakeDashboardStore{}
+	handler := NewMetricsAPIHandler()
+	handler.SetStore(store)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/metrics?window=24h", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if store.window != "24h" {
+		t.Errorf("Expected store queried for 24h, got %q", store.window)
+	}
+
+	var response struct {
+		Window     string                   `json:"window"`
+		Totals     *rollup.Stats            `json:"totals"`
+		Publishers map[string]*rollup.Stats `json:"publishers"`
+		Bidders    map[string]*rollup.Stats `json:"bidders"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if response.Window != "24h" || response.Totals == nil || response.Totals.Requests != 10 {
+		t.Errorf("Unexpected window/totals: %+v", response)
+	}
+	if response.Publishers["pub-1"] == nil || response.Bidders["rubicon"] == nil {
+		t.Errorf("Expected publisher and bidder aggregates, got %+v", response)
+	}
+}
+
+// TestMetricsAPIHandler_StoreErrors tests invalid windows and store failures
+func TestMetricsAPIHandler_StoreErrors(t *testing.T) {
+	handler := NewMetricsAPIHandler()
+	handler.SetStore(&fakeDashboardStore{err: errors.New("redis down")})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/metrics?window=30d", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid window, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/metrics", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 when store fails, got %d", w.Code)
+	}
+	var response map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if _, ok := response["store_error"]; !ok {
+		t.Error("Expected store_error when the shared store fails")
+	}
+	if _, ok := response["total_auctions"]; !ok {
+		t.Error("Expected local counters when the shared store fails")
+	}
+}
@@ -2,43 +2,84 @@
 package endpoints
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/thenexusengine/tne_springwire/internal/storage"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
 	"github.com/thenexusengine/tne_springwire/pkg/redis"
 )
 
-// PublisherAdminHandler handles publisher CRUD operations via API
+// PublisherAdminStore is the system of record for publishers (implemented by storage.PublisherStore)
+type PublisherAdminStore interface {
+	Get(ctx context.Context, publisherID string) (*storage.Publisher, error)
+	ListByStatus(ctx context.Context, status string) ([]*storage.Publisher, error)
+	Create(ctx context.Context, p *storage.Publisher) error
+	Update(ctx context.Context, p *storage.Publisher) error
+	Delete(ctx context.Context, publisherID string) error
+}
+
+// PublisherCacheInvalidator drops in-process cached publisher records (implemented by middleware.PublisherAuth)
+type PublisherCacheInvalidator interface {
+	InvalidatePublisher(publisherID string)
+}
+
+// PublisherAdminHandler handles publisher CRUD operations via API.
+// PostgreSQL is the source of truth; every successful write is pushed through to the
+// Redis publishers hash and the PublisherAuth in-process cache so auth sees it immediately.
 type PublisherAdminHandler struct {
+	store       PublisherAdminStore
 	redisClient *redis.Client
+	invalidator PublisherCacheInvalidator
+	audit       AuditRecorder
 }
 
-// NewPublisherAdminHandler creates a new publisher admin handler
-func NewPublisherAdminHandler(redisClient *redis.Client) *PublisherAdminHandler {
+// NewPublisherAdminHandler creates a new publisher admin handler; redisClient may be nil
+func NewPublisherAdminHandler(store PublisherAdminStore, redisClient *redis.Client) *PublisherAdminHandler {
 	return &PublisherAdminHandler{
+		store:       store,
 		redisClient: redisClient,
 	}
 }
 
-// Publisher represents a publisher configuration
-type Publisher struct {
-	ID             string   `json:"id"`
-	AllowedDomains string   `json:"allowed_domains"` // Pipe-separated: "domain1.com|*.domain2.com"
-	DomainList     []string `json:"domain_list"`     // Parsed array for display
+// SetCacheInvalidator sets the in-process cache to invalidate after writes
+func (h *PublisherAdminHandler) SetCacheInvalidator(invalidator PublisherCacheInvalidator) {
+	h.invalidator = invalidator
+}
+
+// SetAuditRecorder sets the audit log that receives every publisher change
+func (h *PublisherAdminHandler) SetAuditRecorder(audit AuditRecorder) {
+	h.audit = audit
+}
+
+// PublisherResponse is a publisher as returned by the admin API
+type PublisherResponse struct {
+	*storage.Publisher
+	DomainList []string `json:"domain_list"` // Parsed allowed_domains for display
 }
 
 // PublisherListResponse is the response for listing publishers
 type PublisherListResponse struct {
-	Publishers []Publisher `json:"publishers"`
-	Count      int         `json:"count"`
+	Publishers []PublisherResponse `json:"publishers"`
+	Count      int                 `json:"count"`
 }
 
-// PublisherRequest is the request body for creating/updating publishers
+// PublisherRequest is the request body for creating/updating publishers.
+// On update, omitted fields keep their current value and Version must match the stored version.
 type PublisherRequest struct {
-	ID             string `json:"id"`
-	AllowedDomains string `json:"allowed_domains"`
+	PublisherID    string                 `json:"publisher_id"`
+	ID             string                 `json:"id,omitempty"` // Deprecated alias for publisher_id
+	Name           *string                `json:"name,omitempty"`
+	AllowedDomains *string                `json:"allowed_domains,omitempty"`
+	BidderParams   map[string]interface{} `json:"bidder_params,omitempty"`
+	BidMultiplier  *float64               `json:"bid_multiplier,omitempty"`
+	Status         *string                `json:"status,omitempty"`
+	Notes          *string                `json:"notes,omitempty"`
+	ContactEmail   *string                `json:"contact_email,omitempty"`
+	Version        int                    `json:"version,omitempty"`
 }
 
 // ErrorResponse is a standard error response
@@ -49,18 +90,30 @@ type ErrorResponse struct {
 
 const publishersHashKey = "tne_catalyst:publishers"
 
+// Bid multiplier bounds, matching the publishers.bid_multiplier CHECK constraint
+const (
+	minBidMultiplier = 1.0
+	maxBidMultiplier = 10.0
+)
+
+var validPublisherStatuses = map[string]bool{
+	"active":   true,
+	"paused":   true,
+	"archived": true,
+}
+
 // ServeHTTP handles publisher API requests
 // Routes:
 //
-//	GET    /admin/publishers       - List all publishers
-//	GET    /admin/publishers/:id   - Get specific publisher
-//	POST   /admin/publishers       - Create publisher
-//	PUT    /admin/publishers/:id   - Update publisher
-//	DELETE /admin/publishers/:id   - Delete publisher
+//	GET    /admin/publishers[?status=] - List publishers, optionally filtered by status
+//	GET    /admin/publishers/:id       - Get specific publisher
+//	POST   /admin/publishers           - Create publisher
+//	PUT    /admin/publishers/:id       - Update publisher (requires current version)
+//	DELETE /admin/publishers/:id       - Archive publisher
 func (h *PublisherAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Check if Redis is available
-	if h.redisClient == nil {
-		h.sendError(w, http.StatusServiceUnavailable, "Redis not available", "Publisher management requires Redis connection")
+	// Check if the database is available
+	if h.store == nil {
+		h.sendError(w, http.StatusServiceUnavailable, "database_unavailable", "Publisher management requires a database connection")
 		return
 	}
 
@@ -98,205 +151,286 @@ func (h *PublisherAdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// listPublishers returns all registered publishers
+// listPublishers returns publishers, optionally filtered by ?status=
 func (h *PublisherAdminHandler) listPublishers(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	status := r.URL.Query().Get("status")
+	if status != "" && !validPublisherStatuses[status] {
+		h.sendError(w, http.StatusBadRequest, "invalid_status", "Status must be active, paused or archived")
+		return
+	}
 
-	// Get all publishers from Redis hash
-	publishers, err := h.redisClient.HGetAll(ctx, publishersHashKey)
+	publishers, err := h.store.ListByStatus(r.Context(), status)
 	if err != nil {
-		logger.Log.Error().Err(err).Msg("Failed to list publishers from Redis")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to retrieve publishers")
+		logger.Log.Error().Err(err).Msg("Failed to list publishers")
+		h.sendError(w, http.StatusInternalServerError, "database_error", "Failed to retrieve publishers")
 		return
 	}
 
-	// Convert to response format
-	pubList := make([]Publisher, 0, len(publishers))
-	for id, domains := range publishers {
-		pubList = append(pubList, Publisher{
-			ID:             id,
-			AllowedDomains: domains,
-			DomainList:     parseDomains(domains),
-		})
+	pubList := make([]PublisherResponse, 0, len(publishers))
+	for _, p := range publishers {
+		pubList = append(pubList, newPublisherResponse(p))
 	}
 
-	response := PublisherListResponse{
+	h.sendJSON(w, http.StatusOK, PublisherListResponse{
 		Publishers: pubList,
 		Count:      len(pubList),
-	}
-
-	h.sendJSON(w, http.StatusOK, response)
+	})
 }
 
 // getPublisher returns a specific publisher by ID
 func (h *PublisherAdminHandler) getPublisher(w http.ResponseWriter, r *http.Request, publisherID string) {
-	ctx := r.Context()
-
-	// Get publisher from Redis
-	domains, err := h.redisClient.HGet(ctx, publishersHashKey, publisherID)
+	publisher, err := h.store.Get(r.Context(), publisherID)
 	if err != nil {
-		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to get publisher from Redis")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to retrieve publisher")
+		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to get publisher")
+		h.sendError(w, http.StatusInternalServerError, "database_error", "Failed to retrieve publisher")
 		return
 	}
-
-	if domains == "" {
+	if publisher == nil {
 		h.sendError(w, http.StatusNotFound, "not_found", "Publisher not found")
 		return
 	}
 
-	publisher := Publisher{
-		ID:             publisherID,
-		AllowedDomains: domains,
-		DomainList:     parseDomains(domains),
-	}
-
-	h.sendJSON(w, http.StatusOK, publisher)
+	h.sendJSON(w, http.StatusOK, newPublisherResponse(publisher))
 }
 
 // createPublisher creates a new publisher
 func (h *PublisherAdminHandler) createPublisher(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Parse request body
 	var req PublisherRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.sendError(w, http.StatusBadRequest, "invalid_json", "Invalid request body")
 		return
 	}
+	if req.PublisherID == "" {
+		req.PublisherID = req.ID
+	}
 
-	// Validate
-	if req.ID == "" {
+	if req.PublisherID == "" {
 		h.sendError(w, http.StatusBadRequest, "missing_id", "Publisher ID is required")
 		return
 	}
-	if req.AllowedDomains == "" {
+	if req.AllowedDomains == nil || *req.AllowedDomains == "" {
 		h.sendError(w, http.StatusBadRequest, "missing_domains", "Allowed domains are required")
 		return
 	}
 
-	// Check if publisher already exists
-	existing, err := h.redisClient.HGet(ctx, publishersHashKey, req.ID)
-	if err != nil {
-		logger.Log.Error().Err(err).Str("publisher_id", req.ID).Msg("Failed to check existing publisher")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to check existing publisher")
-		return
+	publisher := &storage.Publisher{
+		PublisherID:   req.PublisherID,
+		Name:          req.PublisherID,
+		BidMultiplier: minBidMultiplier,
+		Status:        "active",
+		BidderParams:  map[string]interface{}{},
 	}
-	if existing != "" {
-		h.sendError(w, http.StatusConflict, "already_exists", "Publisher already exists. Use PUT to update.")
+	req.applyTo(publisher)
+
+	if code, msg := validatePublisher(publisher); code != "" {
+		h.sendError(w, http.StatusBadRequest, code, msg)
 		return
 	}
 
-	// Create publisher in Redis
-	if err := h.redisClient.HSet(ctx, publishersHashKey, req.ID, req.AllowedDomains); err != nil {
-		logger.Log.Error().Err(err).Str("publisher_id", req.ID).Msg("Failed to create publisher in Redis")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to create publisher")
+	if err := h.store.Create(ctx, publisher); err != nil {
+		if errors.Is(err, storage.ErrPublisherExists) {
+			h.sendError(w, http.StatusConflict, "already_exists", "Publisher already exists. Use PUT to update.")
+			return
+		}
+		logger.Log.Error().Err(err).Str("publisher_id", publisher.PublisherID).Msg("Failed to create publisher")
+		h.sendError(w, http.StatusInternalServerError, "database_error", "Failed to create publisher")
 		return
 	}
 
+	h.syncCaches(ctx, publisher)
+	RecordAdminChange(r, h.audit, "publisher.create", storage.AuditResourcePublisher, publisher.PublisherID, nil, publisher)
+
 	logger.Log.Info().
-		Str("publisher_id", req.ID).
-		Str("domains", req.AllowedDomains).
+		Str("publisher_id", publisher.PublisherID).
+		Str("domains", publisher.AllowedDomains).
+		Float64("bid_multiplier", publisher.BidMultiplier).
+		Str("status", publisher.Status).
 		Msg("Publisher created")
 
-	// Return created publisher
-	publisher := Publisher{
-		ID:             req.ID,
-		AllowedDomains: req.AllowedDomains,
-		DomainList:     parseDomains(req.AllowedDomains),
-	}
-
-	h.sendJSON(w, http.StatusCreated, publisher)
+	h.sendJSON(w, http.StatusCreated, newPublisherResponse(publisher))
 }
 
-// updatePublisher updates an existing publisher
+// updatePublisher applies the supplied fields to an existing publisher
 func (h *PublisherAdminHandler) updatePublisher(w http.ResponseWriter, r *http.Request, publisherID string) {
 	ctx := r.Context()
 
-	// Parse request body
 	var req PublisherRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.sendError(w, http.StatusBadRequest, "invalid_json", "Invalid request body")
 		return
 	}
-
-	// Validate
-	if req.AllowedDomains == "" {
-		h.sendError(w, http.StatusBadRequest, "missing_domains", "Allowed domains are required")
+	if req.Version <= 0 {
+		h.sendError(w, http.StatusBadRequest, "missing_version", "Current version is required for updates")
+		return
+	}
+	if req.AllowedDomains != nil && *req.AllowedDomains == "" {
+		h.sendError(w, http.StatusBadRequest, "missing_domains", "Allowed domains cannot be empty")
 		return
 	}
 
-	// Check if publisher exists
-	existing, err := h.redisClient.HGet(ctx, publishersHashKey, publisherID)
+	existing, err := h.store.Get(ctx, publisherID)
 	if err != nil {
 		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to check existing publisher")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to check existing publisher")
+		h.sendError(w, http.StatusInternalServerError, "database_error", "Failed to check existing publisher")
 		return
 	}
-	if existing == "" {
+	if existing == nil {
 		h.sendError(w, http.StatusNotFound, "not_found", "Publisher not found. Use POST to create.")
 		return
 	}
 
-	// Update publisher in Redis
-	if err := h.redisClient.HSet(ctx, publishersHashKey, publisherID, req.AllowedDomains); err != nil {
-		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to update publisher in Redis")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to update publisher")
+	before := *existing
+	oldDomains, oldMultiplier, oldStatus := existing.AllowedDomains, existing.BidMultiplier, existing.Status
+	req.applyTo(existing)
+	existing.Version = req.Version
+
+	if code, msg := validatePublisher(existing); code != "" {
+		h.sendError(w, http.StatusBadRequest, code, msg)
 		return
 	}
 
+	if err := h.store.Update(ctx, existing); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrConcurrentModification):
+			h.sendError(w, http.StatusConflict, "version_conflict", "Publisher was modified by another request. Reload and retry.")
+		case errors.Is(err, storage.ErrPublisherNotFound):
+			h.sendError(w, http.StatusNotFound, "not_found", "Publisher not found")
+		default:
+			logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to update publisher")
+			h.sendError(w, http.StatusInternalServerError, "database_error", "Failed to update publisher")
+		}
+		return
+	}
+
+	h.syncCaches(ctx, existing)
+	RecordAdminChange(r, h.audit, "publisher.update", storage.AuditResourcePublisher, publisherID, &before, existing)
+
 	logger.Log.Info().
 		Str("publisher_id", publisherID).
-		Str("old_domains", existing).
-		Str("new_domains", req.AllowedDomains).
+		Str("old_domains", oldDomains).
+		Str("new_domains", existing.AllowedDomains).
+		Float64("old_bid_multiplier", oldMultiplier).
+		Float64("new_bid_multiplier", existing.BidMultiplier).
+		Str("old_status", oldStatus).
+		Str("new_status", existing.Status).
+		Int("version", existing.Version).
 		Msg("Publisher updated")
 
-	// Return updated publisher
-	publisher := Publisher{
-		ID:             publisherID,
-		AllowedDomains: req.AllowedDomains,
-		DomainList:     parseDomains(req.AllowedDomains),
-	}
-
-	h.sendJSON(w, http.StatusOK, publisher)
+	h.sendJSON(w, http.StatusOK, newPublisherResponse(existing))
 }
 
-// deletePublisher deletes a publisher
+// deletePublisher archives a publisher
 func (h *PublisherAdminHandler) deletePublisher(w http.ResponseWriter, r *http.Request, publisherID string) {
 	ctx := r.Context()
 
-	// Check if publisher exists
-	existing, err := h.redisClient.HGet(ctx, publishersHashKey, publisherID)
-	if err != nil {
-		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to check existing publisher")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to check existing publisher")
-		return
+	// Capture the prior state for the audit log
+	var before *storage.Publisher
+	if h.audit != nil {
+		var err error
+		if before, err = h.store.Get(ctx, publisherID); err != nil {
+			logger.Log.Warn().Err(err).Str("publisher_id", publisherID).Msg("Failed to load publisher for audit log")
+		}
 	}
-	if existing == "" {
-		h.sendError(w, http.StatusNotFound, "not_found", "Publisher not found")
+
+	if err := h.store.Delete(ctx, publisherID); err != nil {
+		if errors.Is(err, storage.ErrPublisherNotFound) {
+			h.sendError(w, http.StatusNotFound, "not_found", "Publisher not found")
+			return
+		}
+		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to delete publisher")
+		h.sendError(w, http.StatusInternalServerError, "database_error", "Failed to delete publisher")
 		return
 	}
 
-	// Delete publisher from Redis
-	if err := h.redisClient.HDel(ctx, publishersHashKey, publisherID); err != nil {
-		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("Failed to delete publisher from Redis")
-		h.sendError(w, http.StatusInternalServerError, "redis_error", "Failed to delete publisher")
-		return
+	h.syncCaches(ctx, &storage.Publisher{PublisherID: publisherID, Status: "archived"})
+	if h.audit != nil {
+		var after *storage.Publisher
+		if before != nil {
+			archived := *before
+			archived.Status = "archived"
+			after = &archived
+		}
+		RecordAdminChange(r, h.audit, "publisher.archive", storage.AuditResourcePublisher, publisherID, before, after)
 	}
 
 	logger.Log.Info().
 		Str("publisher_id", publisherID).
-		Str("domains", existing).
-		Msg("Publisher deleted")
+		Msg("Publisher archived")
+
+	h.sendJSON(w, http.StatusOK, map[string]interface{}{
+		"success":      true,
+		"publisher_id": publisherID,
+		"status":       "archived",
+	})
+}
+
+// syncCaches writes the publisher through to the Redis hash used by PublisherAuth
+// (active publishers only) and drops the in-process cache entry
+func (h *PublisherAdminHandler) syncCaches(ctx context.Context, p *storage.Publisher) {
+	if h.redisClient != nil {
+		var err error
+		if p.Status == "active" {
+			err = h.redisClient.HSet(ctx, publishersHashKey, p.PublisherID, p.AllowedDomains)
+		} else {
+			err = h.redisClient.HDel(ctx, publishersHashKey, p.PublisherID)
+		}
+		if err != nil {
+			// PublisherAuth reads Redis first, so a stale entry outlives the database change
+			logger.Log.Error().Err(err).Str("publisher_id", p.PublisherID).Msg("Failed to sync publisher to Redis cache")
+		}
+	}
 
-	// Return success with deleted info
-	response := map[string]interface{}{
-		"success":         true,
-		"publisher_id":    publisherID,
-		"deleted_domains": existing,
+	if h.invalidator != nil {
+		h.invalidator.InvalidatePublisher(p.PublisherID)
 	}
+}
 
-	h.sendJSON(w, http.StatusOK, response)
+// applyTo copies the supplied fields onto p
+func (req *PublisherRequest) applyTo(p *storage.Publisher) {
+	if req.Name != nil {
+		p.Name = *req.Name
+	}
+	if req.AllowedDomains != nil {
+		p.AllowedDomains = *req.AllowedDomains
+	}
+	if req.BidderParams != nil {
+		p.BidderParams = req.BidderParams
+	}
+	if req.BidMultiplier != nil {
+		p.BidMultiplier = *req.BidMultiplier
+	}
+	if req.Status != nil {
+		p.Status = *req.Status
+	}
+	if req.Notes != nil {
+		p.Notes = *req.Notes
+	}
+	if req.ContactEmail != nil {
+		p.ContactEmail = *req.ContactEmail
+	}
+}
+
+// validatePublisher returns an error code and message if the publisher is invalid
+func validatePublisher(p *storage.Publisher) (string, string) {
+	if p.Name == "" {
+		return "missing_name", "Publisher name is required"
+	}
+	if p.BidMultiplier < minBidMultiplier || p.BidMultiplier > maxBidMultiplier {
+		return "invalid_bid_multiplier", "Bid multiplier must be between 1.0 and 10.0"
+	}
+	if !validPublisherStatuses[p.Status] {
+		return "invalid_status", "Status must be active, paused or archived"
+	}
+	return "", ""
+}
+
+func newPublisherResponse(p *storage.Publisher) PublisherResponse {
+	return PublisherResponse{
+		Publisher:  p,
+		DomainList: parseDomains(p.AllowedDomains),
+	}
 }
 
 // parseDomains splits pipe-separated domains into array
@@ -2,653 +2,467 @@ package endpoints
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sort"
+	"sync"
 	"testing"
 
 	"github.com/alicebob/miniredis/v2"
 
+	"github.com/thenexusengine/tne_springwire/internal/storage"
 	"github.com/thenexusengine/tne_springwire/pkg/redis"
 )
 
-// setupTestRedisForPublisher creates a test Redis client with miniredis
-func setupTestRedisForPublisher(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
-	t.Helper()
-
-	// Create miniredis server
-	mr := miniredis.RunT(t)
-
-	// Create Redis client
-	client, err := redis.New("redis://" + mr.Addr())
-	if err != nil {
-		t.Fatalf("Failed to create Redis client: %v", err)
-	}
-
-	return client, mr
+// memoryPublisherStore is an in-memory PublisherAdminStore with the same version semantics as PostgreSQL
+type memoryPublisherStore struct {
+	mu         sync.Mutex
+	publishers map[string]*storage.Publisher
+	err        error
 }
 
-// TestNewPublisherAdminHandler tests handler creation
-func TestNewPublisherAdminHandler(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-	if handler == nil {
-		t.Fatal("Expected non-nil handler")
-	}
-
-	if handler.redisClient == nil {
-		t.Error("Expected non-nil Redis client")
+func newMemoryPublisherStore(pubs ...*storage.Publisher) *memoryPublisherStore {
+	s := &memoryPublisherStore{publishers: make(map[string]*storage.Publisher)}
+	for _, p := range pubs {
+		if p.Version == 0 {
+			p.Version = 1
+		}
+		s.publishers[p.PublisherID] = p
 	}
+	return s
 }
 
-// TestNewPublisherAdminHandler_NilRedis tests handler creation with nil Redis
-func TestNewPublisherAdminHandler_NilRedis(t *testing.T) {
-	handler := NewPublisherAdminHandler(nil)
-	if handler == nil {
-		t.Fatal("Expected non-nil handler")
+func (s *memoryPublisherStore) Get(_ context.Context, publisherID string) (*storage.Publisher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
 	}
-
-	if handler.redisClient != nil {
-		t.Error("Expected nil Redis client")
+	p, ok := s.publishers[publisherID]
+	if !ok {
+		return nil, nil
 	}
+	cp := *p
+	return &cp, nil
 }
 
-// TestPublisherAdminHandler_NoRedis tests that endpoints return 503 without Redis
-func TestPublisherAdminHandler_NoRedis(t *testing.T) {
-	handler := NewPublisherAdminHandler(nil)
-
-	req := httptest.NewRequest(http.MethodGet, "/admin/publishers", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusServiceUnavailable {
-		t.Errorf("Expected status 503, got %d", w.Code)
-	}
-
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
-	}
-
-	if errResp.Error != "Redis not available" {
-		t.Errorf("Expected 'Redis not available' error, got %s", errResp.Error)
-	}
+func (s *memoryPublisherStore) ListByStatus(_ context.Context, status string) ([]*storage.Publisher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	var result []*storage.Publisher
+	for _, p := range s.publishers {
+		if status == "" || p.Status == status {
+			cp := *p
+			result = append(result, &cp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PublisherID < result[j].PublisherID })
+	return result, nil
 }
 
-// TestListPublishers_Empty tests listing with no publishers
-func TestListPublishers_Empty(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodGet, "/admin/publishers", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
+func (s *memoryPublisherStore) Create(_ context.Context, p *storage.Publisher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
 	}
-
-	var resp PublisherListResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
-	if err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-
-	if resp.Count != 0 {
-		t.Errorf("Expected count 0, got %d", resp.Count)
-	}
-
-	if len(resp.Publishers) != 0 {
-		t.Errorf("Expected 0 publishers, got %d", len(resp.Publishers))
+	if _, exists := s.publishers[p.PublisherID]; exists {
+		return fmt.Errorf("%w: %s", storage.ErrPublisherExists, p.PublisherID)
 	}
+	p.Version = 1
+	cp := *p
+	s.publishers[p.PublisherID] = &cp
+	return nil
 }
 
-// TestListPublishers_WithData tests listing publishers
-func TestListPublishers_WithData(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Populate test data
-	mr.HSet(publishersHashKey, "pub1", "example.com|test.com")
-	mr.HSet(publishersHashKey, "pub2", "*.domain.com")
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodGet, "/admin/publishers", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
+func (s *memoryPublisherStore) Update(_ context.Context, p *storage.Publisher) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.publishers[p.PublisherID]
+	if !ok {
+		return fmt.Errorf("%w: %s", storage.ErrPublisherNotFound, p.PublisherID)
 	}
-
-	var resp PublisherListResponse
-	err := json.NewDecoder(w.Body).Decode(&resp)
-	if err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-
-	if resp.Count != 2 {
-		t.Errorf("Expected count 2, got %d", resp.Count)
+	if current.Version != p.Version {
+		return fmt.Errorf("%w: publisher %s", storage.ErrConcurrentModification, p.PublisherID)
 	}
+	p.Version++
+	cp := *p
+	s.publishers[p.PublisherID] = &cp
+	return nil
+}
 
-	if len(resp.Publishers) != 2 {
-		t.Errorf("Expected 2 publishers, got %d", len(resp.Publishers))
+func (s *memoryPublisherStore) Delete(_ context.Context, publisherID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.publishers[publisherID]
+	if !ok {
+		return fmt.Errorf("%w: %s", storage.ErrPublisherNotFound, publisherID)
 	}
+	p.Status = "archived"
+	p.Version++
+	return nil
 }
 
-// TestGetPublisher_Success tests getting a specific publisher
-func TestGetPublisher_Success(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Populate test data
-	mr.HSet(publishersHashKey, "pub1", "example.com|test.com")
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodGet, "/admin/publishers/pub1", nil)
-	w := httptest.NewRecorder()
+// recordingInvalidator records invalidated publisher IDs
+type recordingInvalidator struct {
+	ids []string
+}
 
-	handler.ServeHTTP(w, req)
+func (r *recordingInvalidator) InvalidatePublisher(publisherID string) {
+	r.ids = append(r.ids, publisherID)
+}
 
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
+// setupPublisherAdmin creates a handler backed by an in-memory store and miniredis
+func setupPublisherAdmin(t *testing.T, pubs ...*storage.Publisher) (*PublisherAdminHandler, *memoryPublisherStore, *miniredis.Miniredis, *recordingInvalidator) {
+	t.Helper()
 
-	var pub Publisher
-	err := json.NewDecoder(w.Body).Decode(&pub)
+	mr := miniredis.RunT(t)
+	client, err := redis.New("redis://" + mr.Addr())
 	if err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-
-	if pub.ID != "pub1" {
-		t.Errorf("Expected ID 'pub1', got '%s'", pub.ID)
+		t.Fatalf("Failed to create Redis client: %v", err)
 	}
+	t.Cleanup(func() { client.Close() })
 
-	if pub.AllowedDomains != "example.com|test.com" {
-		t.Errorf("Expected domains 'example.com|test.com', got '%s'", pub.AllowedDomains)
-	}
+	store := newMemoryPublisherStore(pubs...)
+	invalidator := &recordingInvalidator{}
+	handler := NewPublisherAdminHandler(store, client)
+	handler.SetCacheInvalidator(invalidator)
+	return handler, store, mr, invalidator
+}
 
-	if len(pub.DomainList) != 2 {
-		t.Errorf("Expected 2 domains in list, got %d", len(pub.DomainList))
+func testAdminPublisher(id string) *storage.Publisher {
+	return &storage.Publisher{
+		PublisherID:    id,
+		Name:           "Test " + id,
+		AllowedDomains: "example.com|*.example.com",
+		BidderParams:   map[string]interface{}{"rubicon": map[string]interface{}{"accountId": float64(1)}},
+		BidMultiplier:  1.05,
+		Status:         "active",
 	}
 }
 
-// TestGetPublisher_NotFound tests getting non-existent publisher
-func TestGetPublisher_NotFound(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodGet, "/admin/publishers/nonexistent", nil)
+func doAdminRequest(handler http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		if s, ok := body.(string); ok {
+			buf.WriteString(s)
+		} else {
+			json.NewEncoder(&buf).Encode(body)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
 	w := httptest.NewRecorder()
-
 	handler.ServeHTTP(w, req)
+	return w
+}
 
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", w.Code)
-	}
-
+func decodeErrorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
 	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
-
-	if errResp.Error != "not_found" {
-		t.Errorf("Expected error 'not_found', got '%s'", errResp.Error)
-	}
+	return errResp.Error
 }
 
-// TestCreatePublisher_Success tests creating a new publisher
-func TestCreatePublisher_Success(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
+// TestPublisherAdminHandler_NoStore tests that endpoints return 503 without a database
+func TestPublisherAdminHandler_NoStore(t *testing.T) {
+	handler := NewPublisherAdminHandler(nil, nil)
 
-	reqBody := PublisherRequest{
-		ID:             "newpub",
-		AllowedDomains: "example.com|test.com",
+	w := doAdminRequest(handler, http.MethodGet, "/admin/publishers", nil)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", w.Code)
 	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPost, "/admin/publishers", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
+	if code := decodeErrorCode(t, w); code != "database_unavailable" {
+		t.Errorf("Expected database_unavailable error, got %s", code)
+	}
+}
 
-	handler.ServeHTTP(w, req)
+// TestListPublishers tests listing with and without a status filter
+func TestListPublishers(t *testing.T) {
+	paused := testAdminPublisher("pub-b")
+	paused.Status = "paused"
+	handler, _, _, _ := setupPublisherAdmin(t, testAdminPublisher("pub-a"), paused)
 
-	if w.Code != http.StatusCreated {
-		t.Errorf("Expected status 201, got %d", w.Code)
+	w := doAdminRequest(handler, http.MethodGet, "/admin/publishers", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
 	}
-
-	var pub Publisher
-	err := json.NewDecoder(w.Body).Decode(&pub)
-	if err != nil {
+	var resp PublisherListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
-
-	if pub.ID != "newpub" {
-		t.Errorf("Expected ID 'newpub', got '%s'", pub.ID)
+	if resp.Count != 2 {
+		t.Errorf("Expected 2 publishers, got %d", resp.Count)
 	}
-
-	// Verify it was actually saved in Redis
-	saved := mr.HGet(publishersHashKey, "newpub")
-	if saved != "example.com|test.com" {
-		t.Errorf("Expected saved domains 'example.com|test.com', got '%s'", saved)
+	if resp.Publishers[0].BidMultiplier != 1.05 || len(resp.Publishers[0].DomainList) != 2 {
+		t.Errorf("Expected full publisher fields, got %+v", resp.Publishers[0])
 	}
-}
-
-// TestCreatePublisher_MissingID tests creating publisher without ID
-func TestCreatePublisher_MissingID(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		AllowedDomains: "example.com",
-	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPost, "/admin/publishers", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
 
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
+	w = doAdminRequest(handler, http.MethodGet, "/admin/publishers?status=paused", nil)
+	resp = PublisherListResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
 	}
-
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	if resp.Count != 1 || resp.Publishers[0].PublisherID != "pub-b" {
+		t.Errorf("Expected only paused publisher, got %+v", resp)
 	}
 
-	if errResp.Error != "missing_id" {
-		t.Errorf("Expected error 'missing_id', got '%s'", errResp.Error)
+	w = doAdminRequest(handler, http.MethodGet, "/admin/publishers?status=bogus", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid status, got %d", w.Code)
 	}
 }
 
-// TestCreatePublisher_MissingDomains tests creating publisher without domains
-func TestCreatePublisher_MissingDomains(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
+// TestGetPublisher tests fetching single publishers
+func TestGetPublisher(t *testing.T) {
+	handler, _, _, _ := setupPublisherAdmin(t, testAdminPublisher("pub-a"))
 
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		ID: "pub1",
+	w := doAdminRequest(handler, http.MethodGet, "/admin/publishers/pub-a", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
 	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPost, "/admin/publishers", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
+	var resp storage.Publisher
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
 	}
-
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	if resp.PublisherID != "pub-a" || resp.Version != 1 || resp.BidderParams["rubicon"] == nil {
+		t.Errorf("Unexpected publisher: %+v", resp)
 	}
 
-	if errResp.Error != "missing_domains" {
-		t.Errorf("Expected error 'missing_domains', got '%s'", errResp.Error)
+	w = doAdminRequest(handler, http.MethodGet, "/admin/publishers/missing", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
 	}
 }
 
-// TestCreatePublisher_AlreadyExists tests creating duplicate publisher
-func TestCreatePublisher_AlreadyExists(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Pre-populate existing publisher
-	mr.HSet(publishersHashKey, "existing", "old.com")
-
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		ID:             "existing",
-		AllowedDomains: "new.com",
+// TestCreatePublisher_Success tests creation with write-through to caches
+func TestCreatePublisher_Success(t *testing.T) {
+	handler, store, mr, invalidator := setupPublisherAdmin(t)
+
+	w := doAdminRequest(handler, http.MethodPost, "/admin/publishers", map[string]interface{}{
+		"publisher_id":    "new-pub",
+		"name":            "New Publisher",
+		"allowed_domains": "new.com",
+		"bid_multiplier":  1.2,
+		"bidder_params":   map[string]interface{}{"appnexus": map[string]interface{}{"placementId": 123}},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
 	}
-	bodyBytes, _ := json.Marshal(reqBody)
 
-	req := httptest.NewRequest(http.MethodPost, "/admin/publishers", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusConflict {
-		t.Errorf("Expected status 409, got %d", w.Code)
+	stored, _ := store.Get(context.Background(), "new-pub")
+	if stored == nil || stored.BidMultiplier != 1.2 || stored.Status != "active" || stored.BidderParams["appnexus"] == nil {
+		t.Errorf("Unexpected stored publisher: %+v", stored)
 	}
-
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	if got := mr.HGet(publishersHashKey, "new-pub"); got != "new.com" {
+		t.Errorf("Expected Redis write-through, got %q", got)
 	}
-
-	if errResp.Error != "already_exists" {
-		t.Errorf("Expected error 'already_exists', got '%s'", errResp.Error)
+	if len(invalidator.ids) != 1 || invalidator.ids[0] != "new-pub" {
+		t.Errorf("Expected auth cache invalidation, got %v", invalidator.ids)
 	}
 }
 
-// TestCreatePublisher_InvalidJSON tests creating publisher with invalid JSON
-func TestCreatePublisher_InvalidJSON(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodPost, "/admin/publishers", bytes.NewReader([]byte("invalid json")))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
-	}
+// TestCreatePublisher_LegacyIDField tests that the old "id" field is still accepted
+func TestCreatePublisher_LegacyIDField(t *testing.T) {
+	handler, store, _, _ := setupPublisherAdmin(t)
 
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	w := doAdminRequest(handler, http.MethodPost, "/admin/publishers", map[string]interface{}{
+		"id":              "legacy-pub",
+		"allowed_domains": "legacy.com",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
 	}
-
-	if errResp.Error != "invalid_json" {
-		t.Errorf("Expected error 'invalid_json', got '%s'", errResp.Error)
+	stored, _ := store.Get(context.Background(), "legacy-pub")
+	if stored == nil || stored.Name != "legacy-pub" || stored.BidMultiplier != 1.0 {
+		t.Errorf("Expected defaults applied, got %+v", stored)
 	}
 }
 
-// TestUpdatePublisher_Success tests updating an existing publisher
-func TestUpdatePublisher_Success(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Pre-populate existing publisher
-	mr.HSet(publishersHashKey, "pub1", "old.com")
+// TestCreatePublisher_Validation tests create validation errors
+func TestCreatePublisher_Validation(t *testing.T) {
+	handler, _, _, _ := setupPublisherAdmin(t, testAdminPublisher("existing"))
 
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		AllowedDomains: "new.com|another.com",
-	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPut, "/admin/publishers/pub1", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
-	}
-
-	var pub Publisher
-	err := json.NewDecoder(w.Body).Decode(&pub)
-	if err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
-	}
-
-	if pub.AllowedDomains != "new.com|another.com" {
-		t.Errorf("Expected domains 'new.com|another.com', got '%s'", pub.AllowedDomains)
+	tests := []struct {
+		name           string
+		body           interface{}
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"invalid json", "{invalid", http.StatusBadRequest, "invalid_json"},
+		{"missing id", map[string]interface{}{"allowed_domains": "a.com"}, http.StatusBadRequest, "missing_id"},
+		{"missing domains", map[string]interface{}{"publisher_id": "p"}, http.StatusBadRequest, "missing_domains"},
+		{"multiplier too low", map[string]interface{}{"publisher_id": "p", "allowed_domains": "a.com", "bid_multiplier": 0.5}, http.StatusBadRequest, "invalid_bid_multiplier"},
+		{"multiplier too high", map[string]interface{}{"publisher_id": "p", "allowed_domains": "a.com", "bid_multiplier": 11}, http.StatusBadRequest, "invalid_bid_multiplier"},
+		{"invalid status", map[string]interface{}{"publisher_id": "p", "allowed_domains": "a.com", "status": "deleted"}, http.StatusBadRequest, "invalid_status"},
+		{"already exists", map[string]interface{}{"publisher_id": "existing", "allowed_domains": "a.com"}, http.StatusConflict, "already_exists"},
 	}
 
-	// Verify it was actually updated in Redis
-	saved := mr.HGet(publishersHashKey, "pub1")
-	if saved != "new.com|another.com" {
-		t.Errorf("Expected saved domains 'new.com|another.com', got '%s'", saved)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAdminRequest(handler, http.MethodPost, "/admin/publishers", tt.body)
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if code := decodeErrorCode(t, w); code != tt.expectedCode {
+				t.Errorf("Expected error %s, got %s", tt.expectedCode, code)
+			}
+		})
 	}
 }
 
-// TestUpdatePublisher_NotFound tests updating non-existent publisher
-func TestUpdatePublisher_NotFound(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		AllowedDomains: "new.com",
-	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPut, "/admin/publishers/nonexistent", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusNotFound {
-		t.Errorf("Expected status 404, got %d", w.Code)
+// TestUpdatePublisher_Success tests partial updates with version checks and cache sync
+func TestUpdatePublisher_Success(t *testing.T) {
+	handler, store, mr, invalidator := setupPublisherAdmin(t, testAdminPublisher("pub-a"))
+	mr.HSet(publishersHashKey, "pub-a", "example.com|*.example.com")
+
+	w := doAdminRequest(handler, http.MethodPut, "/admin/publishers/pub-a", map[string]interface{}{
+		"allowed_domains": "updated.com",
+		"bid_multiplier":  1.5,
+		"version":         1,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
 	}
 
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	stored, _ := store.Get(context.Background(), "pub-a")
+	if stored.AllowedDomains != "updated.com" || stored.BidMultiplier != 1.5 || stored.Version != 2 {
+		t.Errorf("Unexpected stored publisher: %+v", stored)
 	}
-
-	if errResp.Error != "not_found" {
-		t.Errorf("Expected error 'not_found', got '%s'", errResp.Error)
+	if stored.Name != "Test pub-a" || stored.BidderParams["rubicon"] == nil {
+		t.Errorf("Expected omitted fields to be preserved, got %+v", stored)
 	}
-}
-
-// TestUpdatePublisher_MissingID tests updating without ID in path
-func TestUpdatePublisher_MissingID(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		AllowedDomains: "new.com",
+	if got := mr.HGet(publishersHashKey, "pub-a"); got != "updated.com" {
+		t.Errorf("Expected Redis write-through, got %q", got)
 	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPut, "/admin/publishers", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
+	if len(invalidator.ids) != 1 {
+		t.Errorf("Expected auth cache invalidation, got %v", invalidator.ids)
 	}
 
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	// Pausing removes the publisher from the Redis auth hash
+	w = doAdminRequest(handler, http.MethodPut, "/admin/publishers/pub-a", map[string]interface{}{
+		"status":  "paused",
+		"version": 2,
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
 	}
-
-	if errResp.Error != "missing_publisher_id" {
-		t.Errorf("Expected error 'missing_publisher_id', got '%s'", errResp.Error)
+	if mr.Exists(publishersHashKey) && mr.HGet(publishersHashKey, "pub-a") != "" {
+		t.Error("Expected paused publisher to be removed from Redis")
 	}
 }
 
-// TestUpdatePublisher_MissingDomains tests updating without domains
-func TestUpdatePublisher_MissingDomains(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Pre-populate existing publisher
-	mr.HSet(publishersHashKey, "pub1", "old.com")
+// TestUpdatePublisher_Errors tests update validation, not found and version conflicts
+func TestUpdatePublisher_Errors(t *testing.T) {
+	handler, _, mr, invalidator := setupPublisherAdmin(t, testAdminPublisher("pub-a"))
 
-	handler := NewPublisherAdminHandler(client)
-
-	reqBody := PublisherRequest{
-		// No domains
-	}
-	bodyBytes, _ := json.Marshal(reqBody)
-
-	req := httptest.NewRequest(http.MethodPut, "/admin/publishers/pub1", bytes.NewReader(bodyBytes))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
+	tests := []struct {
+		name           string
+		path           string
+		body           interface{}
+		expectedStatus int
+		expectedCode   string
+	}{
+		{"missing id", "/admin/publishers", map[string]interface{}{"version": 1}, http.StatusBadRequest, "missing_publisher_id"},
+		{"invalid json", "/admin/publishers/pub-a", "{invalid", http.StatusBadRequest, "invalid_json"},
+		{"missing version", "/admin/publishers/pub-a", map[string]interface{}{"name": "x"}, http.StatusBadRequest, "missing_version"},
+		{"empty domains", "/admin/publishers/pub-a", map[string]interface{}{"allowed_domains": "", "version": 1}, http.StatusBadRequest, "missing_domains"},
+		{"invalid multiplier", "/admin/publishers/pub-a", map[string]interface{}{"bid_multiplier": 20, "version": 1}, http.StatusBadRequest, "invalid_bid_multiplier"},
+		{"not found", "/admin/publishers/missing", map[string]interface{}{"version": 1}, http.StatusNotFound, "not_found"},
+		{"stale version", "/admin/publishers/pub-a", map[string]interface{}{"name": "x", "version": 7}, http.StatusConflict, "version_conflict"},
 	}
 
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAdminRequest(handler, http.MethodPut, tt.path, tt.body)
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if code := decodeErrorCode(t, w); code != tt.expectedCode {
+				t.Errorf("Expected error %s, got %s", tt.expectedCode, code)
+			}
+		})
 	}
 
-	if errResp.Error != "missing_domains" {
-		t.Errorf("Expected error 'missing_domains', got '%s'", errResp.Error)
+	if mr.Exists(publishersHashKey) || len(invalidator.ids) != 0 {
+		t.Error("Expected no cache writes for failed updates")
 	}
 }
 
-// TestDeletePublisher_Success tests deleting a publisher
-func TestDeletePublisher_Success(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Pre-populate existing publisher
-	mr.HSet(publishersHashKey, "pub1", "example.com")
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodDelete, "/admin/publishers/pub1", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
+// TestDeletePublisher tests archiving removes the publisher from caches
+func TestDeletePublisher(t *testing.T) {
+	handler, store, mr, invalidator := setupPublisherAdmin(t, testAdminPublisher("pub-a"))
+	mr.HSet(publishersHashKey, "pub-a", "example.com")
 
+	w := doAdminRequest(handler, http.MethodDelete, "/admin/publishers/pub-a", nil)
 	if w.Code != http.StatusOK {
-		t.Errorf("Expected status 200, got %d", w.Code)
+		t.Fatalf("Expected status 200, got %d", w.Code)
 	}
 
-	var resp map[string]interface{}
-	err := json.NewDecoder(w.Body).Decode(&resp)
-	if err != nil {
-		t.Fatalf("Failed to decode response: %v", err)
+	stored, _ := store.Get(context.Background(), "pub-a")
+	if stored.Status != "archived" {
+		t.Errorf("Expected publisher archived, got %s", stored.Status)
 	}
-
-	if success, ok := resp["success"].(bool); !ok || !success {
-		t.Error("Expected success=true in response")
+	if mr.Exists(publishersHashKey) && mr.HGet(publishersHashKey, "pub-a") != "" {
+		t.Error("Expected publisher removed from Redis")
 	}
-
-	if pubID, ok := resp["publisher_id"].(string); !ok || pubID != "pub1" {
-		t.Errorf("Expected publisher_id='pub1', got '%v'", resp["publisher_id"])
+	if len(invalidator.ids) != 1 || invalidator.ids[0] != "pub-a" {
+		t.Errorf("Expected auth cache invalidation, got %v", invalidator.ids)
 	}
 
-	// Verify it was actually deleted from Redis
-	deleted := mr.HGet(publishersHashKey, "pub1")
-	if deleted != "" {
-		t.Error("Expected publisher to be deleted from Redis")
-	}
-}
-
-// TestDeletePublisher_NotFound tests deleting non-existent publisher
-func TestDeletePublisher_NotFound(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodDelete, "/admin/publishers/nonexistent", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
+	w = doAdminRequest(handler, http.MethodDelete, "/admin/publishers/missing", nil)
 	if w.Code != http.StatusNotFound {
 		t.Errorf("Expected status 404, got %d", w.Code)
 	}
-
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
-	}
-
-	if errResp.Error != "not_found" {
-		t.Errorf("Expected error 'not_found', got '%s'", errResp.Error)
+	w = doAdminRequest(handler, http.MethodDelete, "/admin/publishers", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
 	}
 }
 
-// TestDeletePublisher_MissingID tests deleting without ID in path
-func TestDeletePublisher_MissingID(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
+// TestPublisherAdmin_StoreErrors tests database failures surface as 500s
+func TestPublisherAdmin_StoreErrors(t *testing.T) {
+	handler, store, _, _ := setupPublisherAdmin(t, testAdminPublisher("pub-a"))
+	store.err = errors.New("connection refused")
 
-	handler := NewPublisherAdminHandler(client)
-
-	req := httptest.NewRequest(http.MethodDelete, "/admin/publishers", nil)
-	w := httptest.NewRecorder()
-
-	handler.ServeHTTP(w, req)
-
-	if w.Code != http.StatusBadRequest {
-		t.Errorf("Expected status 400, got %d", w.Code)
+	for _, path := range []string{"/admin/publishers", "/admin/publishers/pub-a"} {
+		w := doAdminRequest(handler, http.MethodGet, path, nil)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("%s: expected status 500, got %d", path, w.Code)
+		}
 	}
+}
 
-	var errResp ErrorResponse
-	err := json.NewDecoder(w.Body).Decode(&errResp)
-	if err != nil {
-		t.Fatalf("Failed to decode error response: %v", err)
-	}
+// TestPublisherAdmin_WithoutRedis tests writes succeed when Redis is not configured
+func TestPublisherAdmin_WithoutRedis(t *testing.T) {
+	handler := NewPublisherAdminHandler(newMemoryPublisherStore(), nil)
 
-	if errResp.Error != "missing_publisher_id" {
-		t.Errorf("Expected error 'missing_publisher_id', got '%s'", errResp.Error)
+	w := doAdminRequest(handler, http.MethodPost, "/admin/publishers", map[string]interface{}{
+		"publisher_id":    "pub-a",
+		"allowed_domains": "a.com",
+	})
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", w.Code)
 	}
 }
 
 // TestMethodNotAllowed tests unsupported HTTP methods
 func TestMethodNotAllowed(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
+	handler, _, _, _ := setupPublisherAdmin(t)
 
-	handler := NewPublisherAdminHandler(client)
-
-	methods := []string{http.MethodPatch, http.MethodHead, http.MethodOptions}
-
-	for _, method := range methods {
-		t.Run(method, func(t *testing.T) {
-			req := httptest.NewRequest(method, "/admin/publishers", nil)
-			w := httptest.NewRecorder()
-
-			handler.ServeHTTP(w, req)
-
-			if w.Code != http.StatusMethodNotAllowed {
-				t.Errorf("Expected status 405 for %s, got %d", method, w.Code)
-			}
-
-			var errResp ErrorResponse
-			err := json.NewDecoder(w.Body).Decode(&errResp)
-			if err != nil {
-				t.Fatalf("Failed to decode error response: %v", err)
-			}
-
-			if errResp.Error != "method_not_allowed" {
-				t.Errorf("Expected error 'method_not_allowed', got '%s'", errResp.Error)
-			}
-		})
+	for _, method := range []string{http.MethodPatch, http.MethodOptions} {
+		w := doAdminRequest(handler, method, "/admin/publishers", nil)
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s: expected status 405, got %d", method, w.Code)
+		}
 	}
 }
 
@@ -719,13 +533,7 @@ func TestParseDomains(t *testing.T) {
 
 // TestServeHTTP_PathParsing tests various path formats
 func TestServeHTTP_PathParsing(t *testing.T) {
-	client, mr := setupTestRedisForPublisher(t)
-	defer mr.Close()
-
-	// Pre-populate test data
-	mr.HSet(publishersHashKey, "test-pub", "example.com")
-
-	handler := NewPublisherAdminHandler(client)
+	handler, _, _, _ := setupPublisherAdmin(t, testAdminPublisher("test-pub"))
 
 	tests := []struct {
 		name           string
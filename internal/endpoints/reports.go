@@ -0,0 +1,267 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// Report range limits
+const (
+	defaultReportRange = 7 * 24 * time.Hour
+	maxReportRange     = 93 * 24 * time.Hour
+)
+
+// ReportQuerier runs aggregate report queries
+type ReportQuerier interface {
+	Query(ctx context.Context, q *storage.ReportQuery) ([]*storage.ReportRow, error)
+}
+
+// ReportAuthenticator resolves a publisher from a reporting API key
+type ReportAuthenticator interface {
+	GetPublisherIDByReportAPIKey(ctx context.Context, apiKey string) (string, error)
+}
+
+// ReportResponse is the JSON body returned by /api/v1/reports
+type ReportResponse struct {
+	PublisherID string               `json:"publisher_id"`
+	From        time.Time            `json:"from"`
+	To          time.Time            `json:"to"`
+	GroupBy     []string             `json:"group_by"`
+	Currency    string               `json:"currency"`
+	Rows        []*storage.ReportRow `json:"rows"`
+}
+
+// ReportsHandler serves publisher-scoped revenue reports
+type ReportsHandler struct {
+	reports  ReportQuerier
+	auth     ReportAuthenticator
+	currency string
+}
+
+// NewReportsHandler creates a reports handler; currency labels monetary columns
+func NewReportsHandler(reports ReportQuerier, auth ReportAuthenticator, currency string) *ReportsHandler {
+	if currency == "" {
+		currency = "USD"
+	}
+	return &ReportsHandler{
+		reports:  reports,
+		auth:     auth,
+		currency: currency,
+	}
+}
+
+// ServeHTTP handles GET /api/v1/reports
+//
+// Query parameters:
+//
+//	from, to  - RFC3339 timestamps or YYYY-MM-DD dates (default: last 7 days)
+//	group_by  - comma-separated: day|hour, bidder, ad_unit, media_type, country
+//	format    - json (default) or csv
+//
+// The publisher is taken from the API key (Authorization: Bearer <key> or X-API-Key)
+// and can never be chosen by the caller.
+func (h *ReportsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.reports == nil || h.auth == nil {
+		writeError(w, "reporting requires a database connection", http.StatusServiceUnavailable)
+		return
+	}
+
+	apiKey := reportAPIKey(r)
+	if apiKey == "" {
+		writeError(w, "API key required", http.StatusUnauthorized)
+		return
+	}
+	publisherID, err := h.auth.GetPublisherIDByReportAPIKey(r.Context(), apiKey)
+	if err != nil {
+		logger.Log.Error().Err(err).Msg("failed to authenticate report request")
+		writeError(w, "failed to authenticate", http.StatusInternalServerError)
+		return
+	}
+	if publisherID == "" {
+		writeError(w, "invalid API key", http.StatusUnauthorized)
+		return
+	}
+
+	query, err := parseReportQuery(r, publisherID, time.Now().UTC())
+	if err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := query.Validate(); err != nil {
+		writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		writeError(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := h.reports.Query(r.Context(), query)
+	if err != nil {
+		logger.Log.Error().Err(err).Str("publisher_id", publisherID).Msg("failed to run report query")
+		writeError(w, "failed to run report", http.StatusInternalServerError)
+		return
+	}
+	if rows == nil {
+		rows = []*storage.ReportRow{}
+	}
+
+	if format == "csv" {
+		h.writeCSV(w, query, rows)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ReportResponse{
+		PublisherID: publisherID,
+		From:        query.From,
+		To:          query.To,
+		GroupBy:     query.GroupBy,
+		Currency:    h.currency,
+		Rows:        rows,
+	}); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to encode report response")
+	}
+}
+
+// writeCSV writes one header row with the grouped dimensions followed by the metrics
+func (h *ReportsHandler) writeCSV(w http.ResponseWriter, query *storage.ReportQuery, rows []*storage.ReportRow) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="report.csv"`)
+
+	cw := csv.NewWriter(w)
+	header := append([]string{}, query.GroupBy...)
+	header = append(header, "ad_requests", "bids", "wins", "impressions", "fill_rate", "ecpm", "gross_revenue", "payout", "margin", "currency")
+	if err := cw.Write(header); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to write report CSV header")
+		return
+	}
+
+	for _, row := range rows {
+		record := make([]string, 0, len(header))
+		for _, dim := range query.GroupBy {
+			switch dim {
+			case storage.ReportGroupDay:
+				record = append(record, formatReportPeriod(row.Period, "2006-01-02"))
+			case storage.ReportGroupHour:
+				record = append(record, formatReportPeriod(row.Period, time.RFC3339))
+			case storage.ReportGroupBidder:
+				record = append(record, row.Bidder)
+			case storage.ReportGroupAdUnit:
+				record = append(record, row.AdUnit)
+			case storage.ReportGroupMediaType:
+				record = append(record, row.MediaType)
+			case storage.ReportGroupCountry:
+				record = append(record, row.Country)
+			}
+		}
+		record = append(record,
+			strconv.FormatInt(row.AdRequests, 10),
+			strconv.FormatInt(row.Bids, 10),
+			strconv.FormatInt(row.Wins, 10),
+			strconv.FormatInt(row.Impressions, 10),
+			strconv.FormatFloat(row.FillRate, 'f', 4, 64),
+			strconv.FormatFloat(row.ECPM, 'f', 4, 64),
+			strconv.FormatFloat(row.GrossRevenue, 'f', 6, 64),
+			strconv.FormatFloat(row.Payout, 'f', 6, 64),
+			strconv.FormatFloat(row.Margin, 'f', 6, 64),
+			h.currency,
+		)
+		if err := cw.Write(record); err != nil {
+			logger.Log.Error().Err(err).Msg("failed to write report CSV row")
+			return
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to flush report CSV")
+	}
+}
+
+// parseReportQuery reads the date range and grouping from the request
+func parseReportQuery(r *http.Request, publisherID string, now time.Time) (*storage.ReportQuery, error) {
+	params := r.URL.Query()
+
+	to := now
+	if v := params.Get("to"); v != "" {
+		t, err := parseReportTime(v, true)
+		if err != nil {
+			return nil, err
+		}
+		to = t
+	}
+	from := to.Add(-defaultReportRange)
+	if v := params.Get("from"); v != "" {
+		t, err := parseReportTime(v, false)
+		if err != nil {
+			return nil, err
+		}
+		from = t
+	}
+	if to.Sub(from) > maxReportRange {
+		return nil, fmt.Errorf("report range cannot exceed 93 days")
+	}
+
+	var groupBy []string
+	for _, dim := range strings.Split(params.Get("group_by"), ",") {
+		if dim = strings.TrimSpace(dim); dim != "" {
+			groupBy = append(groupBy, dim)
+		}
+	}
+
+	return &storage.ReportQuery{
+		PublisherID: publisherID,
+		From:        from,
+		To:          to,
+		GroupBy:     groupBy,
+	}, nil
+}
+
+// parseReportTime accepts RFC3339 or a date; a date used as the end of a range includes the whole day
+func parseReportTime(value string, endOfRange bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC3339", value)
+	}
+	if endOfRange {
+		t = t.Add(24 * time.Hour)
+	}
+	return t, nil
+}
+
+// reportAPIKey extracts the API key from the Authorization or X-API-Key header
+func reportAPIKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return strings.TrimSpace(r.Header.Get("X-API-Key"))
+}
+
+func formatReportPeriod(period *time.Time, layout string) string {
+	if period == nil {
+		return ""
+	}
+	return period.Format(layout)
+}
@@ -0,0 +1,184 @@
+package endpoints
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/storage"
+)
+
+type fakeReportStore struct {
+	query *storage.ReportQuery
+	rows  []*storage.ReportRow
+	err   error
+}
+
+func (s *fakeReportStore) Query(_ context.Context, q *storage.ReportQuery) ([]*storage.ReportRow, error) {
+	s.query = q
+	return s.rows, s.err
+}
+
+type fakeReportAuth map[string]string
+
+func (a fakeReportAuth) GetPublisherIDByReportAPIKey(_ context.Context, apiKey string) (string, error) {
+	return a[apiKey], nil
+}
+
+func newTestReportsHandler() (*ReportsHandler, *fakeReportStore) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeReportStore{rows: []*storage.ReportRow{
+		{Period: &day, Bidder: "rubicon", AdRequests: 1000, Bids: 400, Wins: 120, Impressions: 100, FillRate: 0.1, ECPM: 4, GrossRevenue: 0.5, Payout: 0.4, Margin: 0.1},
+	}}
+	return NewReportsHandler(store, fakeReportAuth{"key-1": "pub-1"}, "USD"), store
+}
+
+func TestReportsHandler_RequiresAPIKey(t *testing.T) {
+	handler, _ := newTestReportsHandler()
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{"missing key", "", ""},
+		{"unknown key", "X-API-Key", "nope"},
+		{"unknown bearer", "Authorization", "Bearer nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reports", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("Expected 401, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestReportsHandler_JSON(t *testing.T) {
+	handler, store := newTestReportsHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports?from=2026-03-01&to=2026-03-02&group_by=day,bidder&publisher_id=other", nil)
+	req.Header.Set("Authorization", "Bearer key-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Publisher comes from the key, never from the query string
+	if store.query.PublisherID != "pub-1" {
+		t.Errorf("Expected query scoped to pub-1, got %s", store.query.PublisherID)
+	}
+	if !store.query.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || !store.query.To.Equal(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected range: %v - %v", store.query.From, store.query.To)
+	}
+
+	var resp ReportResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.PublisherID != "pub-1" || resp.Currency != "USD" || len(resp.Rows) != 1 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if resp.Rows[0].Bidder != "rubicon" || resp.Rows[0].Wins != 120 {
+		t.Errorf("Unexpected row: %+v", resp.Rows[0])
+	}
+}
+
+func TestReportsHandler_CSV(t *testing.T) {
+	handler, _ := newTestReportsHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports?group_by=day,bidder&format=csv", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Errorf("Expected CSV content type, got %s", ct)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and 1 row, got %d records", len(records))
+	}
+	if records[0][0] != "day" || records[0][1] != "bidder" || records[0][2] != "ad_requests" {
+		t.Errorf("Unexpected header: %v", records[0])
+	}
+	if records[1][0] != "2026-03-01" || records[1][1] != "rubicon" || records[1][4] != "120" || records[1][5] != "100" {
+		t.Errorf("Unexpected row: %v", records[1])
+	}
+}
+
+func TestReportsHandler_BadRequests(t *testing.T) {
+	handler, _ := newTestReportsHandler()
+
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"invalid date", "/api/v1/reports?from=yesterday"},
+		{"range too long", "/api/v1/reports?from=2026-01-01&to=2026-06-01"},
+		{"unknown dimension", "/api/v1/reports?group_by=device"},
+		{"day and hour", "/api/v1/reports?group_by=day,hour"},
+		{"unknown format", "/api/v1/reports?format=xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("X-API-Key", "key-1")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestReportsHandler_Errors(t *testing.T) {
+	handler, store := newTestReportsHandler()
+	store.err = errors.New("db down")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports", nil)
+	req.Header.Set("X-API-Key", "key-1")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/reports", nil)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", w.Code)
+	}
+
+	unconfigured := NewReportsHandler(nil, nil, "")
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/reports", nil)
+	w = httptest.NewRecorder()
+	unconfigured.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", w.Code)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/pkg/vast"
 )
@@ -61,6 +62,47 @@ type VideoEvent struct {
 	UserAgent    string
 }
 
+// analyticsVideoTracker forwards video events to analytics modules
+type analyticsVideoTracker struct {
+	module analytics.Module
+}
+
+// NewAnalyticsVideoTracker returns a VideoAnalytics that reports events as analytics VideoObjects
+func NewAnalyticsVideoTracker(module analytics.Module) VideoAnalytics {
+	return &analyticsVideoTracker{module: module}
+}
+
+// TrackEvent converts the event and hands it to the analytics module
+func (t *analyticsVideoTracker) TrackEvent(event *VideoEvent) error {
+	t.module.LogVideoObject(&analytics.VideoObject{
+		EventType:    string(event.EventType),
+		BidID:        event.BidID,
+		AccountID:    event.AccountID,
+		Bidder:       event.Bidder,
+		Timestamp:    event.Timestamp,
+		Progress:     event.Progress,
+		ErrorCode:    event.ErrorCode,
+		ErrorMessage: event.ErrorMessage,
+		SessionID:    event.SessionID,
+		ContentID:    event.ContentID,
+		IPAddress:    event.IPAddress,
+		UserAgent:    event.UserAgent,
+	})
+
+	// start is the first frame of the ad and counts as the video impression
+	if event.EventType == vast.EventTypeStart {
+		t.module.LogNotificationObject(&analytics.NotificationObject{
+			Type:      analytics.NotificationImpression,
+			BidID:     event.BidID,
+			Bidder:    event.Bidder,
+			AccountID: event.AccountID,
+			Source:    analytics.NotificationSourceVideo,
+			Timestamp: event.Timestamp,
+		})
+	}
+	return nil
+}
+
 // NewVideoEventHandler creates a new video event handler
 func NewVideoEventHandler(analytics VideoAnalytics) *VideoEventHandler {
 	return &VideoEventHandler{
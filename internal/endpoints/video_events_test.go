@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/pkg/vast"
 )
@@ -954,3 +955,46 @@ func TestHandleSpecificEvent_POST_InvalidJSON(t *testing.T) {
 		t.Errorf("expected status 400, got %d", w.Code)
 	}
 }
+
+func TestAnalyticsVideoTracker_TrackEvent(t *testing.T) {
+	recorder := &recordingAnalytics{}
+	handler := NewVideoEventHandler(NewAnalyticsVideoTracker(recorder))
+
+	body := `{"event":"complete","bid_id":"bid-9","account_id":"acct-1","bidder":"appnexus","progress":100}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/video/event", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.HandleVideoEvent(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if len(recorder.videos) != 1 {
+		t.Fatalf("expected 1 video object, got %d", len(recorder.videos))
+	}
+	vo := recorder.videos[0]
+	if vo.EventType != "complete" || vo.BidID != "bid-9" || vo.Bidder != "appnexus" || vo.Progress != 100 {
+		t.Errorf("unexpected video object: %+v", vo)
+	}
+	if len(recorder.notifications) != 0 {
+		t.Errorf("expected no impression notification for complete, got %+v", recorder.notifications)
+	}
+}
+
+func TestAnalyticsVideoTracker_StartIsImpression(t *testing.T) {
+	recorder := &recordingAnalytics{}
+	handler := NewVideoEventHandler(NewAnalyticsVideoTracker(recorder))
+
+	body := `{"event":"start","bid_id":"bid-9","account_id":"acct-1","bidder":"appnexus"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/video/event", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.HandleVideoEvent(w, req)
+
+	if len(recorder.videos) != 1 || len(recorder.notifications) != 1 {
+		t.Fatalf("expected 1 video object and 1 notification, got %d and %d", len(recorder.videos), len(recorder.notifications))
+	}
+	no := recorder.notifications[0]
+	if no.Type != analytics.NotificationImpression || no.Source != analytics.NotificationSourceVideo ||
+		no.BidID != "bid-9" || no.Bidder != "appnexus" || no.AccountID != "acct-1" {
+		t.Errorf("unexpected notification: %+v", no)
+	}
+}
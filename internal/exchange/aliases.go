@@ -0,0 +1,150 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+// alternateCodeWildcard allows a bidder to bid under any seat code
+const alternateCodeWildcard = "*"
+
+// maxRequestAliases caps ext.prebid.aliases, as every alias is another call to its core bidder
+const maxRequestAliases = 10
+
+// aliasCodePattern is the format of a request alias code. Alias codes become metric labels,
+// rollup keys and analytics values, so they're kept short and plain.
+var aliasCodePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// ExtAlternateBidderCodes is ext.prebid.alternatebiddercodes: the seat codes each bidder
+// may return bids under instead of its own bidder code
+type ExtAlternateBidderCodes struct {
+	Enabled bool                                      `json:"enabled"`
+	Bidders map[string]ExtAdapterAlternateBidderCodes `json:"bidders,omitempty"`
+}
+
+// ExtAdapterAlternateBidderCodes is the alternate code allow-list for one bidder
+type ExtAdapterAlternateBidderCodes struct {
+	Enabled            bool     `json:"enabled"`
+	AllowedBidderCodes []string `json:"allowedbiddercodes,omitempty"`
+}
+
+// IsValidBidderCode reports whether bidderCode may bid under seat.
+// Bidders are looked up by their own code, then by their core bidder code when aliased.
+func (a *ExtAlternateBidderCodes) IsValidBidderCode(bidderCode, coreCode, seat string) bool {
+	if strings.EqualFold(seat, bidderCode) {
+		return true
+	}
+	if a == nil || !a.Enabled {
+		return false
+	}
+
+	entry, ok := a.Bidders[bidderCode]
+	if !ok && coreCode != "" {
+		entry, ok = a.Bidders[coreCode]
+	}
+	if !ok || !entry.Enabled {
+		return false
+	}
+	for _, code := range entry.AllowedBidderCodes {
+		if code == alternateCodeWildcard || strings.EqualFold(code, seat) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestAliases holds the request-scoped aliases from ext.prebid.aliases, keyed by alias code
+type requestAliases map[string]adapters.AdapterWithInfo
+
+// parseRequestAliases resolves ext.prebid.aliases against the registry and reads
+// ext.prebid.alternatebiddercodes. Aliases with an invalid code, beyond maxRequestAliases,
+// that shadow a registered bidder, point at an unknown or disabled bidder, or alias another
+// alias are skipped and reported as warnings.
+func (e *Exchange) parseRequestAliases(req *openrtb.BidRequest) (requestAliases, *ExtAlternateBidderCodes, []string) {
+	if len(req.Ext) == 0 {
+		return nil, nil, nil
+	}
+	var ext RequestExt
+	if err := json.Unmarshal(req.Ext, &ext); err != nil || ext.Prebid == nil {
+		return nil, nil, nil
+	}
+
+	var warnings []string
+	var aliases requestAliases
+	// Sorted so the same aliases are kept when the request has too many
+	codes := make([]string, 0, len(ext.Prebid.Aliases))
+	for alias := range ext.Prebid.Aliases {
+		codes = append(codes, alias)
+	}
+	sort.Strings(codes)
+	for _, alias := range codes {
+		coreCode := ext.Prebid.Aliases[alias]
+		if !aliasCodePattern.MatchString(alias) {
+			warnings = append(warnings, fmt.Sprintf("alias %q: code must match %s, ignored", alias, aliasCodePattern))
+			continue
+		}
+		if len(aliases) >= maxRequestAliases {
+			warnings = append(warnings, fmt.Sprintf("alias %s: more than %d aliases, ignored", alias, maxRequestAliases))
+			continue
+		}
+		if _, exists := e.registry.Get(alias); exists {
+			warnings = append(warnings, fmt.Sprintf("alias %s: conflicts with a registered bidder, ignored", alias))
+			continue
+		}
+		core, ok := e.registry.Get(coreCode)
+		switch {
+		case !ok:
+			warnings = append(warnings, fmt.Sprintf("alias %s: unknown bidder %s, ignored", alias, coreCode))
+			continue
+		case !core.Info.Enabled:
+			warnings = append(warnings, fmt.Sprintf("alias %s: bidder %s is disabled, ignored", alias, coreCode))
+			continue
+		case core.Info.AliasOf != "":
+			warnings = append(warnings, fmt.Sprintf("alias %s: %s is itself an alias, ignored", alias, coreCode))
+			continue
+		}
+		if aliases == nil {
+			aliases = make(requestAliases)
+		}
+		aliases[alias] = adapters.AliasOf(coreCode, core)
+	}
+	return aliases, ext.Prebid.AlternateBidderCodes, warnings
+}
+
+// codes returns the request alias codes in sorted order
+func (a requestAliases) codes() []string {
+	codes := make([]string, 0, len(a))
+	for code := range a {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// lookupBidder finds a bidder in the registry, then among the request's aliases
+func (e *Exchange) lookupBidder(bidderCode string, aliases requestAliases) (adapters.AdapterWithInfo, bool) {
+	if awi, ok := e.registry.Get(bidderCode); ok {
+		return awi, true
+	}
+	awi, ok := aliases[bidderCode]
+	return awi, ok
+}
+
+// bidderCircuitBreaker returns the circuit breaker for a bidder.
+// Request-scoped aliases share their core bidder's breaker; config aliases have their own.
+func (e *Exchange) bidderCircuitBreaker(bidderCode string, aliases requestAliases) *idr.CircuitBreaker {
+	if breaker := e.getBidderCircuitBreaker(bidderCode); breaker != nil {
+		return breaker
+	}
+	if awi, ok := aliases[bidderCode]; ok {
+		return e.getBidderCircuitBreaker(awi.Info.AliasOf)
+	}
+	return nil
+}
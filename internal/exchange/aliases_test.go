@@ -0,0 +1,218 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/adapters/pubmatic"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestExtAlternateBidderCodes_IsValidBidderCode(t *testing.T) {
+	codes := &ExtAlternateBidderCodes{
+		Enabled: true,
+		Bidders: map[string]ExtAdapterAlternateBidderCodes{
+			"appnexus": {Enabled: true, AllowedBidderCodes: []string{"groupm"}},
+			"pubmatic": {Enabled: true, AllowedBidderCodes: []string{"*"}},
+			"openx":    {Enabled: false, AllowedBidderCodes: []string{"*"}},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		codes              *ExtAlternateBidderCodes
+		bidder, core, seat string
+		want               bool
+	}{
+		{"own code", nil, "appnexus", "", "appnexus", true},
+		{"not configured", nil, "appnexus", "", "groupm", false},
+		{"allowed", codes, "appnexus", "", "GroupM", true},
+		{"not in list", codes, "appnexus", "", "other", false},
+		{"wildcard", codes, "pubmatic", "", "anything", true},
+		{"bidder disabled", codes, "openx", "", "other", false},
+		{"alias uses core entry", codes, "apn2", "appnexus", "groupm", true},
+		{"disabled globally", &ExtAlternateBidderCodes{Bidders: codes.Bidders}, "appnexus", "", "groupm", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.codes.IsValidBidderCode(tt.bidder, tt.core, tt.seat); got != tt.want {
+				t.Errorf("IsValidBidderCode(%s, %s, %s) = %v, want %v", tt.bidder, tt.core, tt.seat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequestAliases(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{}, adapters.BidderInfo{Enabled: true, GVLVendorID: 32})
+	registry.Register("disabled", &mockAdapter{}, adapters.BidderInfo{Enabled: false})
+	registry.RegisterAlias("apnconfig", adapters.AliasConfig{AliasOf: "appnexus"})
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond})
+
+	req := &openrtb.BidRequest{Ext: json.RawMessage(`{"prebid":{
+		"aliases": {"apn2": "appnexus", "appnexus": "apnconfig", "x": "unknown", "y": "disabled", "z": "apnconfig"},
+		"alternatebiddercodes": {"enabled": true}
+	}}`)}
+	aliases, alternateCodes, warnings := ex.parseRequestAliases(req)
+
+	if len(aliases) != 1 || aliases["apn2"].Info.AliasOf != "appnexus" || aliases["apn2"].Info.GVLVendorID != 32 {
+		t.Errorf("Unexpected aliases: %+v", aliases)
+	}
+	if alternateCodes == nil || !alternateCodes.Enabled {
+		t.Errorf("Expected alternatebiddercodes to be parsed, got %+v", alternateCodes)
+	}
+	if len(warnings) != 4 {
+		t.Errorf("Expected 4 warnings, got %d: %v", len(warnings), warnings)
+	}
+	if got := aliases.codes(); len(got) != 1 || got[0] != "apn2" {
+		t.Errorf("Unexpected alias codes: %v", got)
+	}
+
+	if breaker := ex.bidderCircuitBreaker("apn2", aliases); breaker == nil || breaker != ex.getBidderCircuitBreaker("appnexus") {
+		t.Error("Expected request aliases to share the core bidder's circuit breaker")
+	}
+	if ex.getBidderCircuitBreaker("apnconfig") == nil || ex.getBidderCircuitBreaker("apnconfig") == ex.getBidderCircuitBreaker("appnexus") {
+		t.Error("Expected config aliases to have their own circuit breaker")
+	}
+}
+
+func TestParseRequestAliases_LimitsCodes(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond})
+
+	aliasMap := map[string]string{
+		"Bad Code":              "appnexus",
+		"<script>":              "appnexus",
+		strings.Repeat("a", 33): "appnexus",
+	}
+	for i := 0; i < maxRequestAliases+5; i++ {
+		aliasMap[fmt.Sprintf("apn%02d", i)] = "appnexus"
+	}
+	ext, _ := json.Marshal(map[string]interface{}{"prebid": map[string]interface{}{"aliases": aliasMap}})
+	aliases, _, warnings := ex.parseRequestAliases(&openrtb.BidRequest{Ext: ext})
+
+	if len(aliases) != maxRequestAliases {
+		t.Errorf("Expected %d aliases kept, got %d", maxRequestAliases, len(aliases))
+	}
+	if _, ok := aliases["apn00"]; !ok {
+		t.Errorf("Expected the first aliases in sorted order kept, got %v", aliases.codes())
+	}
+	if len(warnings) != 3+5 {
+		t.Errorf("Expected 8 warnings, got %d: %v", len(warnings), warnings)
+	}
+}
+
+func TestRunAuction_RequestAlias(t *testing.T) {
+	core := &captureAdapter{mockAdapter: mockAdapter{bids: multiBids("alias", "imp1", 2.0)}}
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", core, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, DefaultCurrency: "USD", AuctionType: FirstPriceAuction})
+	bidReq := &openrtb.BidRequest{
+		ID:   "alias",
+		Site: testSite(),
+		Imp: []openrtb.Imp{{
+			ID:     "imp1",
+			Banner: &openrtb.Banner{W: 300, H: 250},
+			Ext:    json.RawMessage(`{"prebid":{"bidder":{"apn2":{"placementId":2}}}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"aliases":{"apn2":"appnexus"}}}`),
+	}
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: bidReq})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := resp.BidderResults["appnexus"]; ok {
+		t.Error("Expected appnexus to be skipped without params")
+	}
+	sent := core.captured()
+	if sent == nil || string(sent.Imp[0].Ext) != `{"prebid":{"bidder":{"apn2":{"placementId":2}}}}` {
+		t.Fatalf("Expected the alias's params to be sent, got %+v", sent)
+	}
+	if got := seatBids(resp, "apn2"); len(got) != 1 || bidTargeting(t, got[0]).Targeting["hb_bidder"] != "apn2" {
+		t.Errorf("Expected a bid under the alias seat, got %+v", got)
+	}
+}
+
+func runAlternateCodeAuction(t *testing.T, ext string) *AuctionResponse {
+	t.Helper()
+	bids := multiBids("alt", "imp1", 2.0, 1.0)
+	bids[0].Seat = "groupm"
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{bids: bids}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, DefaultCurrency: "USD", AuctionType: FirstPriceAuction})
+	bidReq := &openrtb.BidRequest{
+		ID:   "altcodes",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	if ext != "" {
+		bidReq.Ext = json.RawMessage(ext)
+	}
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: bidReq})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func TestRunAuction_AlternateBidderCodes(t *testing.T) {
+	resp := runAlternateCodeAuction(t, "")
+	if got := seatBids(resp, "groupm"); len(got) != 0 {
+		t.Errorf("Expected bids under an unlisted code to be rejected, got %+v", got)
+	}
+	if len(resp.DebugInfo.Errors["appnexus"]) != 1 {
+		t.Errorf("Expected the rejection in debug errors, got %v", resp.DebugInfo.Errors)
+	}
+	if got := seatBids(resp, "appnexus"); len(got) != 1 {
+		t.Errorf("Expected the bid under the bidder's own code to remain, got %+v", got)
+	}
+
+	resp = runAlternateCodeAuction(t, `{"prebid":{"alternatebiddercodes":{"enabled":true,"bidders":{"appnexus":{"enabled":true,"allowedbiddercodes":["groupm"]}}}}}`)
+	got := seatBids(resp, "groupm")
+	if len(got) != 1 {
+		t.Fatalf("Expected the bid under the allowed code, got %+v", resp.BidResponse.SeatBid)
+	}
+	prebid := bidTargeting(t, got[0])
+	if prebid.Targeting["hb_bidder"] != "groupm" || prebid.Meta.AdapterCode != "appnexus" {
+		t.Errorf("Unexpected alternate code bid ext: %+v %+v", prebid.Targeting, prebid.Meta)
+	}
+	if got := seatBids(resp, "appnexus"); len(got) != 1 {
+		t.Errorf("Expected the bidder's own seat to keep its bid, got %+v", got)
+	}
+}
+
+func TestRunAuction_BuyerSeatIsNotAnAlternateCode(t *testing.T) {
+	// seatbid.seat is the buyer seat (DSP or member ID); only an alternate code the adapter
+	// reports explicitly is checked against alternatebiddercodes
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"buyer-seat","seatbid":[{"seat":"dsp-42","bid":[{"id":"b1","impid":"imp1","price":1.50,"adm":"<div></div>","crid":"c1","w":300,"h":250}]}]}`))
+	}))
+	defer server.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("pubmatic", pubmatic.New(server.URL), adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+	ex := New(registry, &Config{DefaultTimeout: time.Second, DefaultCurrency: "USD", AuctionType: FirstPriceAuction})
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "buyer-seat",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := seatBids(resp, "pubmatic"); len(got) != 1 || got[0].ID != "b1" {
+		t.Errorf("Expected the bid with a foreign buyer seat to win, got %+v (errors %v)", resp.BidResponse.SeatBid, resp.DebugInfo.Errors)
+	}
+}
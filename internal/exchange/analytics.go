@@ -0,0 +1,292 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// SetAnalytics sets the analytics module that receives one AuctionObject per auction
+func (e *Exchange) SetAnalytics(m analytics.Module) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.analytics = m
+}
+
+// auctionCapture records auction internals that are not visible in the AuctionResponse.
+// Prices are copied as they change because bids are adjusted in place and the
+// validated bid slice is returned to a pool before the auction object is built.
+type auctionCapture struct {
+	startTime time.Time
+	impFloors map[string]float64
+	bids      []analytics.BidObject
+	bidIndex  map[string]int // bid ID -> index in bids
+	// currency is the auction currency every captured price and floor is expressed in
+	currency string
+	// responseRateVersion is the rate version used to convert the response currency
+	responseRateVersion string
+}
+
+func newAuctionCapture() *auctionCapture {
+	return &auctionCapture{
+		startTime: time.Now(),
+		bidIndex:  make(map[string]int),
+	}
+}
+
+// recordValidBids captures bids as received, before auction logic
+func (c *auctionCapture) recordValidBids(validBids []ValidatedBid, impFloors map[string]float64) {
+	c.impFloors = impFloors
+	for _, vb := range validBids {
+		if vb.Bid == nil || vb.Bid.Bid == nil {
+			continue
+		}
+		seat := vb.seatCode()
+		if vb.DemandType != adapters.DemandTypePublisher {
+			seat = adapters.PlatformSeatName
+		}
+		c.bidIndex[vb.Bid.Bid.ID] = len(c.bids)
+		c.bids = append(c.bids, analytics.BidObject{
+			BidID:      vb.Bid.Bid.ID,
+			ImpID:      vb.Bid.Bid.ImpID,
+			BidderCode: vb.BidderCode,
+			Seat:       seat,
+			DealID:     vb.Bid.Bid.DealID,
+			MediaType:  string(vb.Bid.BidType),
+			BidPrice:   vb.Bid.Bid.Price,
+		})
+	}
+}
+
+// recordBidderConversions marks bids whose bidder responded in another currency
+func (c *auctionCapture) recordBidderConversions(results map[string]*BidderResult) {
+	for i := range c.bids {
+		result, ok := results[c.bids[i].BidderCode]
+		if !ok || result.OriginalCurrency == "" {
+			continue
+		}
+		c.bids[i].OriginalCurrency = result.OriginalCurrency
+		c.bids[i].RateVersion = result.RateVersion
+	}
+}
+
+// recordGrossPrices captures prices after auction logic, before the bid multiplier
+func (c *auctionCapture) recordGrossPrices(bidsByImp map[string][]ValidatedBid) {
+	for _, bids := range bidsByImp {
+		for _, vb := range bids {
+			if idx, ok := c.bidIndex[vb.Bid.Bid.ID]; ok {
+				c.bids[idx].GrossPrice = vb.Bid.Bid.Price
+			}
+		}
+	}
+}
+
+// recordClearingPrices captures final prices and marks the winner of each impression
+func (c *auctionCapture) recordClearingPrices(bidsByImp map[string][]ValidatedBid) {
+	for _, bids := range bidsByImp {
+		for i, vb := range bids {
+			if idx, ok := c.bidIndex[vb.Bid.Bid.ID]; ok {
+				c.bids[idx].ClearingPrice = vb.Bid.Bid.Price
+				c.bids[idx].Won = i == 0
+			}
+		}
+	}
+}
+
+// buildAuctionObject assembles the analytics record for a finished auction
+func (e *Exchange) buildAuctionObject(ctx context.Context, req *AuctionRequest, resp *AuctionResponse, err error, capture *auctionCapture) *analytics.AuctionObject {
+	if capture.currency == "" {
+		capture.currency = e.config.DefaultCurrency
+	}
+	ao := &analytics.AuctionObject{
+		Timestamp:   capture.startTime,
+		LatencyMs:   time.Since(capture.startTime).Milliseconds(),
+		Currency:    capture.currency,
+		RateVersion: capture.responseRateVersion,
+		Bids:        capture.bids,
+	}
+
+	if req != nil && req.BidRequest != nil {
+		br := req.BidRequest
+		ao.Request = analyticsRequest(br)
+		ao.AuctionID = br.ID
+		ao.PublisherID = requestPublisherID(ctx, br)
+		if br.Device != nil {
+			ao.DeviceType = deviceTypeName(br.Device.DeviceType)
+			if br.Device.Geo != nil {
+				ao.Country = br.Device.Geo.Country
+			}
+		}
+
+		ao.Imps = make([]analytics.ImpObject, 0, len(br.Imp))
+		for _, imp := range br.Imp {
+			floor, ok := capture.impFloors[imp.ID]
+			if !ok {
+				floor = imp.BidFloor
+			}
+			ao.Imps = append(ao.Imps, analytics.ImpObject{
+				ImpID:     imp.ID,
+				AdUnit:    imp.TagID,
+				MediaType: impMediaType(&imp),
+				Floor:     floor,
+			})
+		}
+	}
+
+	if resp != nil {
+		if resp.DebugInfo != nil {
+			if resp.DebugInfo.TotalLatency > 0 {
+				ao.LatencyMs = resp.DebugInfo.TotalLatency.Milliseconds()
+			}
+			ao.IDRLatencyMs = resp.DebugInfo.IDRLatency.Milliseconds()
+			ao.SelectedBidders = resp.DebugInfo.SelectedBidders
+			ao.ExcludedBidders = resp.DebugInfo.ExcludedBidders
+
+			resp.DebugInfo.errorsMu.Lock()
+			if len(resp.DebugInfo.Errors) > 0 {
+				ao.Errors = make(map[string][]string, len(resp.DebugInfo.Errors))
+				for k, v := range resp.DebugInfo.Errors {
+					ao.Errors[k] = append([]string(nil), v...)
+				}
+			}
+			resp.DebugInfo.errorsMu.Unlock()
+		}
+
+		if len(resp.BidderResults) > 0 {
+			ao.Bidders = make(map[string]*analytics.BidderObject, len(resp.BidderResults))
+			for code, result := range resp.BidderResults {
+				bo := &analytics.BidderObject{
+					BidderCode: code,
+					LatencyMs:  result.Latency.Milliseconds(),
+					BidCount:   len(result.Bids),
+					TimedOut:   result.TimedOut,
+				}
+				for _, bidErr := range result.Errors {
+					bo.Errors = append(bo.Errors, bidErr.Error())
+				}
+				ao.Bidders[code] = bo
+			}
+		}
+
+		if resp.BidResponse != nil {
+			respCopy := *resp.BidResponse
+			ao.Response = &respCopy
+		}
+	}
+
+	ao.Status = auctionStatus(resp, err)
+	if err != nil {
+		ao.Error = err.Error()
+	}
+
+	return ao
+}
+
+// analyticsRequest copies the request for analytics modules with personal data removed:
+// the recording scrub (see scrubRequest) plus the user's consent string. The device, user
+// and their geos are copied because scrubbing writes to them.
+func analyticsRequest(br *openrtb.BidRequest) *openrtb.BidRequest {
+	reqCopy := *br
+	if br.Device != nil {
+		device := *br.Device
+		device.Geo = copyGeo(device.Geo)
+		reqCopy.Device = &device
+	}
+	if br.User != nil {
+		user := *br.User
+		user.Geo = copyGeo(user.Geo)
+		user.Consent = ""
+		user.Ext = removeExtKey(user.Ext, "consent")
+		reqCopy.User = &user
+	}
+	scrubRequest(&reqCopy)
+	return &reqCopy
+}
+
+// copyGeo returns a copy of geo, or nil
+func copyGeo(geo *openrtb.Geo) *openrtb.Geo {
+	if geo == nil {
+		return nil
+	}
+	geoCopy := *geo
+	return &geoCopy
+}
+
+// auctionStatus classifies the auction outcome
+func auctionStatus(resp *AuctionResponse, err error) string {
+	if err != nil {
+		var validationErr *ValidationError
+		var requestErr *RequestValidationError
+		if errors.As(err, &validationErr) || errors.As(err, &requestErr) {
+			return analytics.AuctionStatusRejected
+		}
+		return analytics.AuctionStatusError
+	}
+	if resp == nil || resp.BidResponse == nil {
+		return analytics.AuctionStatusError
+	}
+
+	switch openrtb.NoBidReason(resp.BidResponse.NBR) {
+	case openrtb.NoBidNoBiddersAvailable:
+		return analytics.AuctionStatusNoBidders
+	case openrtb.NoBidTimeout:
+		return analytics.AuctionStatusTimeout
+	}
+
+	for _, sb := range resp.BidResponse.SeatBid {
+		if len(sb.Bid) > 0 {
+			return analytics.AuctionStatusSuccess
+		}
+	}
+	return analytics.AuctionStatusNoBids
+}
+
+// requestPublisherID returns the authenticated publisher ID, falling back to site/app publisher
+func requestPublisherID(ctx context.Context, req *openrtb.BidRequest) string {
+	if pub := middleware.PublisherFromContext(ctx); pub != nil {
+		if id, ok := extractPublisherID(pub); ok {
+			return id
+		}
+	}
+	if req.Site != nil && req.Site.Publisher != nil {
+		return req.Site.Publisher.ID
+	}
+	if req.App != nil && req.App.Publisher != nil {
+		return req.App.Publisher.ID
+	}
+	return ""
+}
+
+// deviceTypeName maps an OpenRTB device type to the name used in events
+func deviceTypeName(deviceType int) string {
+	switch deviceType {
+	case 1:
+		return "mobile"
+	case 2:
+		return "desktop"
+	case 3:
+		return "ctv"
+	default:
+		return "unknown"
+	}
+}
+
+// impMediaType returns the primary media type of an impression
+func impMediaType(imp *openrtb.Imp) string {
+	switch {
+	case imp.Banner != nil:
+		return "banner"
+	case imp.Video != nil:
+		return "video"
+	case imp.Native != nil:
+		return "native"
+	case imp.Audio != nil:
+		return "audio"
+	}
+	return ""
+}
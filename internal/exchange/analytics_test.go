@@ -0,0 +1,261 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// captureModule records auction objects synchronously
+type captureModule struct {
+	mu       sync.Mutex
+	auctions []*analytics.AuctionObject
+}
+
+func (m *captureModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.auctions = append(m.auctions, ao)
+}
+func (m *captureModule) LogVideoObject(*analytics.VideoObject)               {}
+func (m *captureModule) LogCookieSyncObject(*analytics.CookieSyncObject)     {}
+func (m *captureModule) LogNotificationObject(*analytics.NotificationObject) {}
+func (m *captureModule) Shutdown()                                           {}
+
+func TestRunAuction_AnalyticsSecondPrice(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{
+		bids: []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "bid1", ImpID: "imp1", Price: 5.00, AdM: "<div>ad</div>", DealID: "deal-1"}, BidType: adapters.BidTypeBanner}},
+	}, adapters.BidderInfo{Enabled: true})
+	registry.Register("bidder2", &mockAdapter{
+		bids: []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "bid2", ImpID: "imp1", Price: 3.00, AdM: "<div>ad</div>"}, BidType: adapters.BidTypeBanner}},
+	}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+
+	ex := New(registry, &Config{
+		DefaultTimeout:  500 * time.Millisecond,
+		DefaultCurrency: "USD",
+		AuctionType:     SecondPriceAuction,
+		PriceIncrement:  0.01,
+	})
+	module := &captureModule{}
+	ex.SetAnalytics(module)
+
+	req := &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID: "auction-analytics",
+			Site: &openrtb.Site{
+				ID:        "site-1",
+				Publisher: &openrtb.Publisher{ID: "pub-123"},
+			},
+			Device: &openrtb.Device{DeviceType: 2, Geo: &openrtb.Geo{Country: "GBR"}},
+			Imp: []openrtb.Imp{
+				{ID: "imp1", TagID: "leaderboard", Banner: &openrtb.Banner{W: 728, H: 90}},
+			},
+		},
+	}
+
+	if _, err := ex.RunAuction(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(module.auctions) != 1 {
+		t.Fatalf("expected 1 auction object, got %d", len(module.auctions))
+	}
+	ao := module.auctions[0]
+
+	if ao.AuctionID != "auction-analytics" || ao.PublisherID != "pub-123" {
+		t.Errorf("unexpected identity: auction=%s publisher=%s", ao.AuctionID, ao.PublisherID)
+	}
+	if ao.Status != analytics.AuctionStatusSuccess {
+		t.Errorf("expected success status, got %s", ao.Status)
+	}
+	if ao.Country != "GBR" || ao.DeviceType != "desktop" {
+		t.Errorf("unexpected country/device: %s/%s", ao.Country, ao.DeviceType)
+	}
+	if len(ao.Imps) != 1 || ao.Imps[0].AdUnit != "leaderboard" || ao.Imps[0].MediaType != "banner" {
+		t.Errorf("unexpected imps: %+v", ao.Imps)
+	}
+	if len(ao.Bidders) != 2 || ao.Bidders["bidder1"].BidCount != 1 {
+		t.Errorf("unexpected bidder results: %+v", ao.Bidders)
+	}
+	if ao.Response == nil || ao.Request == nil {
+		t.Error("expected request and response to be captured")
+	}
+
+	winners := ao.Winners()
+	if len(winners) != 1 {
+		t.Fatalf("expected 1 winner, got %d", len(winners))
+	}
+	w := winners[0]
+	if w.BidderCode != "bidder1" || w.DealID != "deal-1" || w.Seat != adapters.PlatformSeatName {
+		t.Errorf("unexpected winner: %+v", w)
+	}
+	if w.BidPrice != 5.00 {
+		t.Errorf("expected original bid price 5.00, got %.2f", w.BidPrice)
+	}
+	if w.GrossPrice != 3.01 || w.ClearingPrice != 3.01 {
+		t.Errorf("expected gross/clearing price 3.01, got %.2f/%.2f", w.GrossPrice, w.ClearingPrice)
+	}
+
+	for _, b := range ao.Bids {
+		if b.BidID == "bid2" && (b.Won || b.Seat != "bidder2") {
+			t.Errorf("unexpected losing bid: %+v", b)
+		}
+	}
+}
+
+func TestRunAuction_AnalyticsAlternateCodeSeat(t *testing.T) {
+	bids := multiBids("alt", "imp1", 2.0)
+	bids[0].Seat = "groupm"
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{bids: bids}, adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, DefaultCurrency: "USD", AuctionType: FirstPriceAuction})
+	module := &captureModule{}
+	ex.SetAnalytics(module)
+
+	_, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "alt-analytics",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		Ext:  json.RawMessage(`{"prebid":{"alternatebiddercodes":{"enabled":true,"bidders":{"appnexus":{"enabled":true,"allowedbiddercodes":["groupm"]}}}}}`),
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(module.auctions) != 1 || len(module.auctions[0].Bids) != 1 {
+		t.Fatalf("expected 1 auction with 1 bid, got %+v", module.auctions)
+	}
+	if b := module.auctions[0].Bids[0]; b.BidderCode != "appnexus" || b.Seat != "groupm" || !b.Won {
+		t.Errorf("expected the bid recorded under the alternate code seat, got %+v", b)
+	}
+}
+
+func TestRunAuction_AnalyticsStatuses(t *testing.T) {
+	module := &captureModule{}
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: 100 * time.Millisecond})
+	ex.SetAnalytics(module)
+
+	validReq := &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "no-bidders",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		},
+	}
+	if _, err := ex.RunAuction(context.Background(), validReq); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalidReq := &AuctionRequest{BidRequest: &openrtb.BidRequest{ID: "invalid"}}
+	if _, err := ex.RunAuction(context.Background(), invalidReq); err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	if len(module.auctions) != 2 {
+		t.Fatalf("expected 2 auction objects, got %d", len(module.auctions))
+	}
+	if module.auctions[0].Status != analytics.AuctionStatusNoBidders {
+		t.Errorf("expected no_bidders status, got %s", module.auctions[0].Status)
+	}
+	if module.auctions[1].Status != analytics.AuctionStatusRejected || module.auctions[1].Error == "" {
+		t.Errorf("expected rejected status with error, got %s/%q", module.auctions[1].Status, module.auctions[1].Error)
+	}
+}
+
+func TestAuctionStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *AuctionResponse
+		expected string
+	}{
+		{"nil response", nil, analytics.AuctionStatusError},
+		{"timeout", &AuctionResponse{BidResponse: &openrtb.BidResponse{NBR: int(openrtb.NoBidTimeout)}}, analytics.AuctionStatusTimeout},
+		{"no bids", &AuctionResponse{BidResponse: &openrtb.BidResponse{}}, analytics.AuctionStatusNoBids},
+		{"success", &AuctionResponse{BidResponse: &openrtb.BidResponse{SeatBid: []openrtb.SeatBid{{Bid: []openrtb.Bid{{ID: "b"}}}}}}, analytics.AuctionStatusSuccess},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auctionStatus(tt.resp, nil); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunAuction_AnalyticsCurrency(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{
+		bids: []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "bid1", ImpID: "imp1", Price: 4.00, AdM: "<div>ad</div>"}, BidType: adapters.BidTypeBanner}},
+	}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 500 * time.Millisecond, DefaultCurrency: "USD", AuctionType: FirstPriceAuction})
+	module := &captureModule{}
+	ex.SetAnalytics(module)
+
+	req := &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "auction-eur",
+			Site: testSite(),
+			Cur:  []string{"EUR"},
+			Ext:  json.RawMessage(testRatesExt),
+			Imp:  []openrtb.Imp{{ID: "imp1", BidFloor: 1.00, BidFloorCur: "EUR", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		},
+	}
+	resp, err := ex.RunAuction(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.BidResponse.Cur != "EUR" {
+		t.Fatalf("expected an EUR response, got %s", resp.BidResponse.Cur)
+	}
+
+	ao := module.auctions[0]
+	// Prices are captured before the response conversion, so the object stays in the auction currency
+	if ao.Currency != "USD" {
+		t.Errorf("expected the auction currency USD, got %s", ao.Currency)
+	}
+	winners := ao.Winners()
+	if len(winners) != 1 || !approxEqual(winners[0].ClearingPrice, 4.00) {
+		t.Fatalf("expected a USD clearing price of 4.00, got %+v", winners)
+	}
+	if !approxEqual(ao.Imps[0].Floor, 1.1) {
+		t.Errorf("expected the floor normalized to USD 1.10, got %v", ao.Imps[0].Floor)
+	}
+	if price := ao.Response.SeatBid[0].Bid[0].Price; !approxEqual(price, 4.00*0.9) {
+		t.Errorf("expected the response to keep the EUR price, got %v", price)
+	}
+}
+
+func TestAnalyticsRequest_ScrubsPersonalData(t *testing.T) {
+	req := &openrtb.BidRequest{
+		ID:     "scrub",
+		Device: &openrtb.Device{IP: "198.51.100.7", IFA: "ifa-1", Geo: &openrtb.Geo{Country: "GBR", Lat: 51.5, Lon: -0.1}},
+		User: &openrtb.User{
+			ID:       "user-1",
+			BuyerUID: "buyer-1",
+			Consent:  "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA",
+			Ext:      json.RawMessage(`{"consent":"CPXxRfA","eids":[{"source":"x"}],"keep":1}`),
+		},
+	}
+
+	scrubbed := analyticsRequest(req)
+
+	if scrubbed.Device.IP != "198.51.100.0" || scrubbed.Device.IFA != "" || scrubbed.Device.Geo.Lat != 0 || scrubbed.Device.Geo.Country != "GBR" {
+		t.Errorf("Expected a scrubbed device, got %+v %+v", scrubbed.Device, scrubbed.Device.Geo)
+	}
+	if scrubbed.User.ID != "" || scrubbed.User.BuyerUID != "" || scrubbed.User.Consent != "" || string(scrubbed.User.Ext) != `{"keep":1}` {
+		t.Errorf("Expected a scrubbed user, got %+v ext=%s", scrubbed.User, scrubbed.User.Ext)
+	}
+	if req.Device.IP != "198.51.100.7" || req.Device.Geo.Lat != 51.5 || req.User.BuyerUID != "buyer-1" || req.User.Consent == "" {
+		t.Error("Expected the live request to be left untouched")
+	}
+}
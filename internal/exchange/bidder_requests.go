@@ -0,0 +1,123 @@
+package exchange
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// bidderResponse is the outcome of one adapter request
+type bidderResponse struct {
+	resp *adapters.ResponseData
+	err  error
+}
+
+// requestSlotsKey carries the auction's P0-4 semaphore to fetchBidderResponses
+type requestSlotsKey struct{}
+
+// bidderTimeout returns the time bidders get: what is left of the auction minus the network
+// buffer held back to validate and return their bids. The buffer is skipped when it would
+// leave bidders less time than it holds back.
+func (e *Exchange) bidderTimeout(ctx context.Context, timeout time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if buffer := e.config.BidderNetworkBuffer; buffer > 0 && timeout > 2*buffer {
+		timeout -= buffer
+	}
+	return timeout
+}
+
+// fetchBidderResponses executes an adapter's requests in parallel, returning one response per
+// request in order. Requests are bound by ctx, so it returns by the bidder's deadline with the
+// responses that arrived in time and a timeout error for the rest.
+// The bidder's own slot in the auction semaphore covers one request at a time; further
+// requests only run in parallel while they can take a free slot, so adapters that split
+// requests per imp stay within MaxConcurrentBidders.
+func (e *Exchange) fetchBidderResponses(ctx context.Context, req *openrtb.BidRequest, bidderCode string, requests []*adapters.RequestData, timeout time.Duration) []bidderResponse {
+	// test=1 requests go to the test client (mock bidder) when one is configured
+	httpClient, testMode := e.bidderHTTPClient(req)
+
+	log := logger.FromContext(ctx)
+	responses := make([]bidderResponse, len(requests))
+	fetch := func(i int) {
+		reqData := requests[i]
+
+		// Handle mock requests (e.g., demo adapter) - use request body as response
+		if reqData.Method == "MOCK" {
+			resp := &adapters.ResponseData{
+				StatusCode: 200,
+				Body:       reqData.Body,
+				Headers:    reqData.Headers,
+			}
+			addHTTPCall(ctx, bidderCode, reqData, resp, nil)
+			responses[i] = bidderResponse{resp: resp}
+			return
+		}
+
+		log.Debug().
+			Str("uri", reqData.URI).
+			Str("method", reqData.Method).
+			Msg("Making HTTP request to bidder")
+
+		httpReq := reqData
+		if testMode {
+			httpReq = testBidderRequest(reqData, bidderCode)
+		}
+
+		resp, err := httpClient.Do(ctx, httpReq, timeout)
+		recordBidderCall(ctx, bidderCode, reqData, resp, err)
+		addHTTPCall(ctx, bidderCode, reqData, resp, err)
+		responses[i] = bidderResponse{resp: resp, err: err}
+	}
+
+	if len(requests) == 1 {
+		fetch(0)
+		return responses
+	}
+
+	pending := make(chan int, len(requests))
+	for i := range requests {
+		pending <- i
+	}
+	close(pending)
+	worker := func() {
+		for i := range pending {
+			fetch(i)
+		}
+	}
+
+	sem, _ := ctx.Value(requestSlotsKey{}).(chan struct{})
+	var wg sync.WaitGroup
+	for extra := 1; extra < len(requests); extra++ {
+		if sem != nil && !tryAcquire(sem) {
+			// The auction is at its limit: the remaining requests queue behind the bidder's slot
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			worker()
+		}()
+	}
+	worker()
+	wg.Wait()
+	return responses
+}
+
+// tryAcquire takes a slot in sem without waiting
+func tryAcquire(sem chan struct{}) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
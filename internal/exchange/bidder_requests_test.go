@@ -0,0 +1,155 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// splitAdapter makes one request per endpoint, each answering with a single bid
+type splitAdapter struct {
+	endpoints []string
+}
+
+func (a *splitAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	requests := make([]*adapters.RequestData, 0, len(a.endpoints))
+	for _, endpoint := range a.endpoints {
+		requests = append(requests, &adapters.RequestData{Method: http.MethodPost, URI: endpoint, Body: []byte(`{}`)})
+	}
+	return requests, nil
+}
+
+func (a *splitAdapter) MakeBids(request *openrtb.BidRequest, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	var bid openrtb.Bid
+	if err := json.Unmarshal(response.Body, &bid); err != nil {
+		return nil, []error{err}
+	}
+	return &adapters.BidderResponse{
+		ResponseID: request.ID,
+		Bids:       []*adapters.TypedBid{{Bid: &bid, BidType: adapters.BidTypeBanner}},
+	}, nil
+}
+
+func TestCallBidder_PartialTimeoutKeepsBids(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	bidServer := func(bidID string, delay time.Duration) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+			case <-release:
+				return
+			}
+			w.Write([]byte(`{"id":"` + bidID + `","impid":"imp1","price":1.5,"adm":"<div></div>"}`))
+		}))
+	}
+	fast1, fast2, slow := bidServer("fast1", 30*time.Millisecond), bidServer("fast2", 30*time.Millisecond), bidServer("slow", time.Second)
+	defer fast1.Close()
+	defer fast2.Close()
+	defer slow.Close()
+
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: time.Second})
+	adapter := &splitAdapter{endpoints: []string{fast1.URL, slow.URL, fast2.URL}}
+	req := &openrtb.BidRequest{ID: "partial-1", Imp: []openrtb.Imp{{ID: "imp1"}}}
+
+	result := ex.callBidder(context.Background(), req, "split", adapter, 150*time.Millisecond, ex.newAuctionCurrency(req))
+
+	if len(result.Bids) != 2 || result.Bids[0].Bid.ID != "fast1" || result.Bids[1].Bid.ID != "fast2" {
+		t.Fatalf("Expected the fast bids kept in request order, got %+v", result.Bids)
+	}
+	if !result.TimedOut || !result.PartialTimeout {
+		t.Errorf("Expected a partial timeout, got timedOut=%v partial=%v", result.TimedOut, result.PartialTimeout)
+	}
+	if len(result.Errors) != 1 {
+		t.Errorf("Expected one timeout error, got %v", result.Errors)
+	}
+	// Requests run in parallel, so the call ends at the sub-deadline rather than after each request in turn
+	if result.Latency > 500*time.Millisecond {
+		t.Errorf("Expected the call to end at the sub-deadline, took %v", result.Latency)
+	}
+
+	allSlow := &splitAdapter{endpoints: []string{slow.URL}}
+	result = ex.callBidder(context.Background(), req, "split", allSlow, 50*time.Millisecond, ex.newAuctionCurrency(req))
+	if !result.TimedOut || result.PartialTimeout || len(result.Bids) != 0 {
+		t.Errorf("Expected a full timeout, got timedOut=%v partial=%v bids=%d", result.TimedOut, result.PartialTimeout, len(result.Bids))
+	}
+
+	// A request that answered with an unparseable body doesn't make the timeout partial
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	}))
+	defer broken.Close()
+	slowAndBroken := &splitAdapter{endpoints: []string{broken.URL, slow.URL}}
+	result = ex.callBidder(context.Background(), req, "split", slowAndBroken, 50*time.Millisecond, ex.newAuctionCurrency(req))
+	if !result.TimedOut || result.PartialTimeout || len(result.Errors) != 2 {
+		t.Errorf("Expected a full failure, got timedOut=%v partial=%v errors=%v", result.TimedOut, result.PartialTimeout, result.Errors)
+	}
+}
+
+func TestFetchBidderResponses_SharesAuctionLimit(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.Write([]byte(`{"id":"b","impid":"imp1","price":1}`))
+	}))
+	defer server.Close()
+
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: time.Second})
+	requests := make([]*adapters.RequestData, 6)
+	for i := range requests {
+		requests[i] = &adapters.RequestData{Method: http.MethodPost, URI: server.URL, Body: []byte(`{}`)}
+	}
+
+	// The bidder holds one of three slots and another bidder holds a second
+	sem := make(chan struct{}, 3)
+	sem <- struct{}{}
+	sem <- struct{}{}
+	ctx := context.WithValue(context.Background(), requestSlotsKey{}, sem)
+	responses := ex.fetchBidderResponses(ctx, &openrtb.BidRequest{ID: "limit"}, "split", requests, time.Second)
+
+	for i, r := range responses {
+		if r.err != nil || r.resp == nil {
+			t.Fatalf("Expected every request to complete, request %d got %v", i, r.err)
+		}
+	}
+	if peak != 2 {
+		t.Errorf("Expected the bidder's slot plus the one free slot in use, got %d concurrent requests", peak)
+	}
+	if len(sem) != 2 {
+		t.Errorf("Expected the extra slot released, got %d held", len(sem))
+	}
+}
+
+func TestBidderTimeout(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: time.Second, BidderNetworkBuffer: 50 * time.Millisecond})
+
+	if got := ex.bidderTimeout(context.Background(), 300*time.Millisecond); got != 250*time.Millisecond {
+		t.Errorf("Expected the buffer taken off the timeout, got %v", got)
+	}
+	if got := ex.bidderTimeout(context.Background(), 80*time.Millisecond); got != 80*time.Millisecond {
+		t.Errorf("Expected no buffer on a short timeout, got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if got := ex.bidderTimeout(ctx, time.Second); got > 150*time.Millisecond || got < 100*time.Millisecond {
+		t.Errorf("Expected the remaining auction time minus the buffer, got %v", got)
+	}
+}
@@ -0,0 +1,254 @@
+package exchange
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// percentileRefreshInterval is how many observations pass between percentile recomputations
+const percentileRefreshInterval = 10
+
+// AdaptiveTimeoutConfig configures per-bidder timeouts derived from observed latency
+type AdaptiveTimeoutConfig struct {
+	Enabled    bool
+	Percentile float64       // Latency percentile the timeout tracks (0.95 or 0.99)
+	Headroom   float64       // Multiplier on the percentile so a timeout can grow back
+	MinTimeout time.Duration // Adaptive timeouts never go below this
+	MinSamples int           // Observations needed before a bidder's timeout adapts
+	WindowSize int           // Most recent observations kept per bidder
+}
+
+// DefaultAdaptiveTimeoutConfig returns the default adaptive timeout configuration (disabled)
+func DefaultAdaptiveTimeoutConfig() *AdaptiveTimeoutConfig {
+	return &AdaptiveTimeoutConfig{
+		Enabled:    false,
+		Percentile: 0.95,
+		Headroom:   1.2,
+		MinTimeout: 50 * time.Millisecond,
+		MinSamples: 50,
+		WindowSize: 500,
+	}
+}
+
+// BidderTimeoutStats reports how a bidder's timeout is derived
+type BidderTimeoutStats struct {
+	StaticMS   int64 `json:"static_ms"`   // Configured timeout (0 = auction timeout)
+	AdaptiveMS int64 `json:"adaptive_ms"` // Timeout from observed latency (0 = not adapting)
+	Samples    int   `json:"samples"`
+	P95MS      int64 `json:"p95_ms"`
+	P99MS      int64 `json:"p99_ms"`
+}
+
+// timeoutGetter is implemented by adapters with their own configured timeout
+type timeoutGetter interface {
+	GetTimeout() time.Duration
+}
+
+// latencyWindow is a rolling window of a bidder's latest call latencies
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+	full    bool
+	pending int // Observations since the percentiles were computed
+	p95     time.Duration
+	p99     time.Duration
+}
+
+func newLatencyWindow(size int) *latencyWindow {
+	return &latencyWindow{samples: make([]time.Duration, size)}
+}
+
+// count returns the number of observations in the window
+func (w *latencyWindow) count() int {
+	if w.full {
+		return len(w.samples)
+	}
+	return w.next
+}
+
+// observe adds a latency, refreshing the percentiles periodically
+func (w *latencyWindow) observe(latency time.Duration) {
+	w.samples[w.next] = latency
+	w.next++
+	if w.next == len(w.samples) {
+		w.next = 0
+		w.full = true
+	}
+	w.pending++
+	if w.pending >= percentileRefreshInterval || w.p95 == 0 {
+		w.refresh()
+	}
+}
+
+// refresh recomputes the percentiles from the current window
+func (w *latencyWindow) refresh() {
+	sorted := append([]time.Duration(nil), w.samples[:w.count()]...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	w.p95 = percentileOf(sorted, 0.95)
+	w.p99 = percentileOf(sorted, 0.99)
+	w.pending = 0
+}
+
+// percentile returns the tracked percentile closest to p
+func (w *latencyWindow) percentile(p float64) time.Duration {
+	if p >= 0.99 {
+		return w.p99
+	}
+	return w.p95
+}
+
+// percentileOf returns the p-th percentile of sorted latencies (nearest rank)
+func percentileOf(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p*float64(len(sorted))+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// bidderTimeouts resolves per-bidder timeouts from static config and observed latency
+type bidderTimeouts struct {
+	mu      sync.RWMutex
+	config  *AdaptiveTimeoutConfig
+	base    map[string]time.Duration // Startup timeouts: config, BIDDER_TIMEOUTS and the bidders table
+	runtime map[string]time.Duration // Runtime config overrides, merged onto base
+	static  map[string]time.Duration // base with runtime applied
+	windows map[string]*latencyWindow
+}
+
+func newBidderTimeouts(config *AdaptiveTimeoutConfig, static map[string]time.Duration) *bidderTimeouts {
+	t := &bidderTimeouts{config: config, windows: make(map[string]*latencyWindow)}
+	t.setStatic(static)
+	return t
+}
+
+// setStatic replaces the startup per-bidder timeouts, keeping runtime overrides on top
+func (t *bidderTimeouts) setStatic(static map[string]time.Duration) {
+	t.mu.Lock()
+	t.base = positiveTimeouts(static)
+	t.mergeLocked()
+	t.mu.Unlock()
+}
+
+// setRuntime replaces the runtime overrides. Bidders they don't mention keep their startup timeout.
+func (t *bidderTimeouts) setRuntime(runtime map[string]time.Duration) {
+	t.mu.Lock()
+	t.runtime = positiveTimeouts(runtime)
+	t.mergeLocked()
+	t.mu.Unlock()
+}
+
+// mergeLocked rebuilds the effective static timeouts. Callers hold t.mu.
+func (t *bidderTimeouts) mergeLocked() {
+	merged := make(map[string]time.Duration, len(t.base)+len(t.runtime))
+	for code, timeout := range t.base {
+		merged[code] = timeout
+	}
+	for code, timeout := range t.runtime {
+		merged[code] = timeout
+	}
+	t.static = merged
+}
+
+// positiveTimeouts copies a timeout map without zero or negative entries
+func positiveTimeouts(timeouts map[string]time.Duration) map[string]time.Duration {
+	copied := make(map[string]time.Duration, len(timeouts))
+	for code, timeout := range timeouts {
+		if timeout > 0 {
+			copied[code] = timeout
+		}
+	}
+	return copied
+}
+
+// observe records a bidder call latency when adaptive timeouts are enabled
+func (t *bidderTimeouts) observe(bidderCode string, latency time.Duration) {
+	if !t.config.Enabled || latency <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.windows[bidderCode]
+	if !ok {
+		w = newLatencyWindow(t.config.WindowSize)
+		t.windows[bidderCode] = w
+	}
+	w.observe(latency)
+}
+
+// timeout returns a bidder's timeout: its static timeout (config first, then the adapter's own),
+// lowered to the adaptive timeout once enough latency has been observed, and always capped by
+// the time left in the auction
+func (t *bidderTimeouts) timeout(bidderCode string, adapterTimeout, auctionTimeout time.Duration) time.Duration {
+	t.mu.RLock()
+	static := t.static[bidderCode]
+	adaptive := t.adaptiveLocked(bidderCode)
+	t.mu.RUnlock()
+
+	if static <= 0 {
+		static = adapterTimeout
+	}
+	timeout := auctionTimeout
+	if static > 0 && static < timeout {
+		timeout = static
+	}
+	if adaptive > 0 && adaptive < timeout {
+		timeout = adaptive
+	}
+	return timeout
+}
+
+// adaptiveLocked returns the adaptive timeout for a bidder, 0 until it has enough samples.
+// Callers hold t.mu.
+func (t *bidderTimeouts) adaptiveLocked(bidderCode string) time.Duration {
+	if !t.config.Enabled {
+		return 0
+	}
+	w, ok := t.windows[bidderCode]
+	if !ok || w.count() < t.config.MinSamples {
+		return 0
+	}
+	adaptive := time.Duration(float64(w.percentile(t.config.Percentile)) * t.config.Headroom)
+	if adaptive < t.config.MinTimeout {
+		adaptive = t.config.MinTimeout
+	}
+	return adaptive
+}
+
+// stats reports the timeout inputs for every bidder with a static timeout or observed latency
+func (t *bidderTimeouts) stats() map[string]BidderTimeoutStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	stats := make(map[string]BidderTimeoutStats)
+	for code, static := range t.static {
+		stats[code] = BidderTimeoutStats{StaticMS: static.Milliseconds()}
+	}
+	for code, w := range t.windows {
+		s := stats[code]
+		s.AdaptiveMS = t.adaptiveLocked(code).Milliseconds()
+		s.Samples = w.count()
+		s.P95MS = w.p95.Milliseconds()
+		s.P99MS = w.p99.Milliseconds()
+		stats[code] = s
+	}
+	return stats
+}
+
+// SetBidderTimeouts replaces the startup per-bidder timeouts (e.g. loaded from the bidders table).
+// Runtime config overrides still take precedence.
+func (e *Exchange) SetBidderTimeouts(timeouts map[string]time.Duration) {
+	e.timeouts.setStatic(timeouts)
+}
+
+// GetBidderTimeoutStats returns per-bidder timeout stats for the admin endpoint
+func (e *Exchange) GetBidderTimeoutStats() map[string]BidderTimeoutStats {
+	return e.timeouts.stats()
+}
@@ -0,0 +1,127 @@
+package exchange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestBidderTimeouts_Static(t *testing.T) {
+	timeouts := newBidderTimeouts(DefaultAdaptiveTimeoutConfig(), map[string]time.Duration{"slow": 300 * time.Millisecond})
+
+	if got := timeouts.timeout("slow", 0, time.Second); got != 300*time.Millisecond {
+		t.Errorf("Expected the configured timeout, got %v", got)
+	}
+	if got := timeouts.timeout("slow", 0, 200*time.Millisecond); got != 200*time.Millisecond {
+		t.Errorf("Expected the auction deadline to cap the timeout, got %v", got)
+	}
+	if got := timeouts.timeout("other", 400*time.Millisecond, time.Second); got != 400*time.Millisecond {
+		t.Errorf("Expected the adapter's own timeout, got %v", got)
+	}
+	if got := timeouts.timeout("other", 0, time.Second); got != time.Second {
+		t.Errorf("Expected the auction timeout without a static one, got %v", got)
+	}
+}
+
+func TestBidderTimeouts_RuntimeOverridesMerge(t *testing.T) {
+	timeouts := newBidderTimeouts(DefaultAdaptiveTimeoutConfig(), map[string]time.Duration{
+		"rubicon":  300 * time.Millisecond,
+		"appnexus": 400 * time.Millisecond,
+	})
+	timeouts.setRuntime(map[string]time.Duration{"rubicon": 200 * time.Millisecond})
+
+	if got := timeouts.timeout("rubicon", 0, time.Second); got != 200*time.Millisecond {
+		t.Errorf("Expected the runtime override, got %v", got)
+	}
+	if got := timeouts.timeout("appnexus", 0, time.Second); got != 400*time.Millisecond {
+		t.Errorf("Expected the startup timeout kept for bidders the runtime config omits, got %v", got)
+	}
+
+	// Reloading startup timeouts keeps the override; clearing overrides restores startup values
+	timeouts.setStatic(map[string]time.Duration{"rubicon": 350 * time.Millisecond})
+	if got := timeouts.timeout("rubicon", 0, time.Second); got != 200*time.Millisecond {
+		t.Errorf("Expected the runtime override to survive a startup reload, got %v", got)
+	}
+	timeouts.setRuntime(map[string]time.Duration{})
+	if got := timeouts.timeout("rubicon", 0, time.Second); got != 350*time.Millisecond {
+		t.Errorf("Expected the startup timeout once overrides are cleared, got %v", got)
+	}
+}
+
+func TestPercentileOf(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	if got := percentileOf(sorted, 0.95); got != 95*time.Millisecond {
+		t.Errorf("Expected P95 of 95ms, got %v", got)
+	}
+	if got := percentileOf(sorted, 0.99); got != 99*time.Millisecond {
+		t.Errorf("Expected P99 of 99ms, got %v", got)
+	}
+	if got := percentileOf(nil, 0.95); got != 0 {
+		t.Errorf("Expected 0 for no samples, got %v", got)
+	}
+}
+
+func TestBidderTimeouts_Adaptive(t *testing.T) {
+	config := DefaultAdaptiveTimeoutConfig()
+	config.Enabled = true
+	config.MinSamples = 20
+	config.WindowSize = 100
+	timeouts := newBidderTimeouts(config, map[string]time.Duration{"fast": 500 * time.Millisecond})
+
+	for i := 0; i < 19; i++ {
+		timeouts.observe("fast", 100*time.Millisecond)
+	}
+	if got := timeouts.timeout("fast", 0, time.Second); got != 500*time.Millisecond {
+		t.Errorf("Expected the static timeout before MinSamples, got %v", got)
+	}
+
+	// P95 of 100ms plus 20% headroom
+	timeouts.observe("fast", 100*time.Millisecond)
+	if got := timeouts.timeout("fast", 0, time.Second); got != 120*time.Millisecond {
+		t.Errorf("Expected the adaptive timeout, got %v", got)
+	}
+	if got := timeouts.timeout("fast", 0, 100*time.Millisecond); got != 100*time.Millisecond {
+		t.Errorf("Expected the auction deadline to cap the adaptive timeout, got %v", got)
+	}
+
+	stats := timeouts.stats()["fast"]
+	if stats.StaticMS != 500 || stats.AdaptiveMS != 120 || stats.Samples != 20 || stats.P95MS != 100 || stats.P99MS != 100 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Once the window holds only fast calls the timeout bottoms out at MinTimeout
+	for i := 0; i < 110; i++ {
+		timeouts.observe("fast", time.Millisecond)
+	}
+	if got := timeouts.timeout("fast", 0, time.Second); got != config.MinTimeout {
+		t.Errorf("Expected the adaptive timeout floored at MinTimeout, got %v", got)
+	}
+}
+
+func TestRunAuction_PerBidderTimeout(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	config := &Config{DefaultTimeout: time.Second, AdaptiveTimeouts: &AdaptiveTimeoutConfig{Enabled: true}}
+	ex := New(registry, config)
+	ex.SetBidderTimeouts(map[string]time.Duration{"bidder1": 250 * time.Millisecond})
+
+	_, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "timeouts-1",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := ex.GetBidderTimeoutStats()["bidder1"]
+	if stats.StaticMS != 250 || stats.Samples != 1 {
+		t.Errorf("Expected the static timeout and one observed call, got %+v", stats)
+	}
+}
@@ -0,0 +1,70 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+// Circuit breaker control actions
+const (
+	BreakerActionForceOpen = "force_open" // Open until the timeout passes, then probe as usual
+	BreakerActionReset     = "reset"      // Close and clear failures (also ends a drain)
+	BreakerActionDrain     = "drain"      // Hold open with no probes until reset
+)
+
+// ErrUnknownBidder is returned when a bidder has no circuit breaker
+var ErrUnknownBidder = errors.New("unknown bidder")
+
+// DefaultBidderCircuitBreakerConfig returns the breaker settings for bidders without their own
+func DefaultBidderCircuitBreakerConfig() *idr.CircuitBreakerConfig {
+	return &idr.CircuitBreakerConfig{
+		FailureThreshold: 5,                // Open after 5 consecutive failures
+		SuccessThreshold: 2,                // Close after 2 successes in half-open
+		Timeout:          30 * time.Second, // Wait 30s before testing recovery
+		MaxConcurrent:    100,              // Max concurrent requests per bidder
+		Window:           60 * time.Second, // Error rate window, when a bidder uses error-rate mode
+		MinRequests:      20,               // Requests in the window before the error rate counts
+	}
+}
+
+// bidderBreakerConfig returns a copy of a bidder's breaker settings: its own entry in
+// BidderCircuitBreakers, else the "*" entry, else the defaults
+func (e *Exchange) bidderBreakerConfig(bidderCode string) *idr.CircuitBreakerConfig {
+	config, ok := e.config.BidderCircuitBreakers[bidderCode]
+	if !ok {
+		config, ok = e.config.BidderCircuitBreakers["*"]
+	}
+	if !ok || config == nil {
+		return DefaultBidderCircuitBreakerConfig()
+	}
+	c := *config
+	if c.MaxConcurrent == 0 {
+		c.MaxConcurrent = DefaultBidderCircuitBreakerConfig().MaxConcurrent
+	}
+	return &c
+}
+
+// ControlBidderCircuitBreaker applies an operator action to a bidder's circuit breaker,
+// returning its stats before and after
+func (e *Exchange) ControlBidderCircuitBreaker(bidderCode, action string) (before, after idr.CircuitBreakerStats, err error) {
+	breaker := e.getBidderCircuitBreaker(bidderCode)
+	if breaker == nil {
+		return before, after, fmt.Errorf("%w: %s", ErrUnknownBidder, bidderCode)
+	}
+
+	before = breaker.Stats()
+	switch action {
+	case BreakerActionForceOpen:
+		breaker.ForceOpen()
+	case BreakerActionReset:
+		breaker.Reset()
+	case BreakerActionDrain:
+		breaker.Drain()
+	default:
+		return before, before, fmt.Errorf("unknown circuit breaker action %q", action)
+	}
+	return before, breaker.Stats(), nil
+}
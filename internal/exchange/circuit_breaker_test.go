@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -88,6 +89,9 @@ func TestExchange_CircuitBreakerOpensAfterFailures(t *testing.T) {
 			[]string{"failing_bidder"},
 			100*time.Millisecond,
 			fpd.BidderFPD{},
+			nil,
+			nil,
+			nil,
 		)
 	}
 
@@ -133,6 +137,9 @@ func TestExchange_CircuitBreakerSkipsBidderWhenOpen(t *testing.T) {
 		[]string{"test_bidder"},
 		100*time.Millisecond,
 		fpd.BidderFPD{},
+		nil,
+		nil,
+		nil,
 	)
 
 	// Verify result indicates circuit breaker
@@ -198,6 +205,9 @@ func TestExchange_CircuitBreakerRecordsSuccess(t *testing.T) {
 		[]string{"success_bidder"},
 		100*time.Millisecond,
 		fpd.BidderFPD{},
+		nil,
+		nil,
+		nil,
 	)
 
 	// Verify success was recorded
@@ -250,6 +260,9 @@ func TestExchange_CircuitBreakerRecordsFailure(t *testing.T) {
 		[]string{"failing_bidder"},
 		100*time.Millisecond,
 		fpd.BidderFPD{},
+		nil,
+		nil,
+		nil,
 	)
 
 	// Verify failure was recorded
@@ -330,6 +343,9 @@ func TestExchange_CircuitBreakerConcurrentAccess(t *testing.T) {
 				[]string{"concurrent_bidder"},
 				100*time.Millisecond,
 				fpd.BidderFPD{},
+				nil,
+				nil,
+				nil,
 			)
 		}()
 	}
@@ -388,5 +404,98 @@ func TestExchange_CircuitBreakerNoNilPanic(t *testing.T) {
 		[]string{"test_bidder"},
 		100*time.Millisecond,
 		fpd.BidderFPD{},
+		nil,
+		nil,
+		nil,
 	)
 }
+
+// TestExchange_PerBidderCircuitBreakerConfig tests that bidders get their own, the "*" or the default breaker settings
+func TestExchange_PerBidderCircuitBreakerConfig(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("own", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	registry.Register("other", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+
+	config := DefaultConfig()
+	config.BidderCircuitBreakers = map[string]*idr.CircuitBreakerConfig{
+		"*":   {FailureThreshold: 2, SuccessThreshold: 1, Timeout: time.Minute},
+		"own": {FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Minute, ErrorRateThreshold: 0.5, Window: time.Minute, MinRequests: 4},
+	}
+	ex := New(registry, config)
+
+	own := ex.getBidderCircuitBreaker("own")
+	own.RecordFailure()
+	if own.State() != idr.StateClosed {
+		t.Errorf("Expected error-rate breaker to ignore a single failure, got %s", own.State())
+	}
+
+	other := ex.getBidderCircuitBreaker("other")
+	other.RecordFailure()
+	other.RecordFailure()
+	if other.State() != idr.StateOpen {
+		t.Errorf("Expected the \"*\" threshold of 2 failures to open the breaker, got %s", other.State())
+	}
+}
+
+// TestExchange_CircuitBreakerRecovers tests that an open bidder is probed again after the timeout
+func TestExchange_CircuitBreakerRecovers(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("test_bidder", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 1.0}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	config := DefaultConfig()
+	config.BidderCircuitBreakers = map[string]*idr.CircuitBreakerConfig{
+		"*": {FailureThreshold: 1, SuccessThreshold: 1, Timeout: 10 * time.Millisecond},
+	}
+	ex := New(registry, config)
+	ex.getBidderCircuitBreaker("test_bidder").ForceOpen()
+	time.Sleep(20 * time.Millisecond)
+
+	bidReq := &openrtb.BidRequest{
+		ID:   "test-recover",
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{}}},
+		Site: &openrtb.Site{Domain: "example.com"},
+	}
+	results := ex.callBiddersWithFPD(context.Background(), bidReq, []string{"test_bidder"},
+		100*time.Millisecond, fpd.BidderFPD{}, nil, nil, nil)
+
+	if result := results["test_bidder"]; result == nil || len(result.Errors) > 0 {
+		t.Fatalf("Expected the bidder called after the timeout, got %+v", result)
+	}
+	if state := ex.getBidderCircuitBreaker("test_bidder").State(); state != idr.StateClosed {
+		t.Errorf("Expected breaker closed after a successful probe, got %s", state)
+	}
+}
+
+// TestExchange_ControlBidderCircuitBreaker tests operator breaker actions
+func TestExchange_ControlBidderCircuitBreaker(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("test_bidder", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, DefaultConfig())
+
+	before, after, err := ex.ControlBidderCircuitBreaker("test_bidder", BreakerActionDrain)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if before.State != idr.StateClosed || after.State != idr.StateOpen || !after.Drained {
+		t.Errorf("Unexpected drain transition: %+v -> %+v", before, after)
+	}
+
+	_, after, err = ex.ControlBidderCircuitBreaker("test_bidder", BreakerActionReset)
+	if err != nil || after.State != idr.StateClosed || after.Drained {
+		t.Errorf("Unexpected reset result: %+v, %v", after, err)
+	}
+
+	_, after, err = ex.ControlBidderCircuitBreaker("test_bidder", BreakerActionForceOpen)
+	if err != nil || after.State != idr.StateOpen || after.Drained {
+		t.Errorf("Unexpected force open result: %+v, %v", after, err)
+	}
+
+	if _, _, err := ex.ControlBidderCircuitBreaker("missing", BreakerActionReset); !errors.Is(err, ErrUnknownBidder) {
+		t.Errorf("Expected ErrUnknownBidder, got %v", err)
+	}
+	if _, _, err := ex.ControlBidderCircuitBreaker("test_bidder", "explode"); err == nil {
+		t.Error("Expected error for an unknown action")
+	}
+}
@@ -0,0 +1,298 @@
+package exchange
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/fpd"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// RuntimeConfig holds the auction settings that can change while the exchange runs.
+// Zero values leave the current setting unchanged.
+type RuntimeConfig struct {
+	FPD            *fpd.Config              // FPD and EID source allow-list
+	MaxBidders     int                      // Bidders called per ranked (IDR or fallback) selection
+	DefaultTimeout time.Duration            // Auction timeout when the request has no tmax
+	BidderTimeouts map[string]time.Duration // Overrides startup per-bidder timeouts (empty map clears the overrides)
+}
+
+// Validate checks a runtime config before it is applied
+func (rc *RuntimeConfig) Validate() error {
+	if rc.MaxBidders < 0 {
+		return fmt.Errorf("max bidders must be non-negative, got %d", rc.MaxBidders)
+	}
+	if rc.DefaultTimeout < 0 || rc.DefaultTimeout > maxAllowedTMax*time.Millisecond {
+		return fmt.Errorf("default timeout must be in range 0-%dms, got %v", maxAllowedTMax, rc.DefaultTimeout)
+	}
+	for code, timeout := range rc.BidderTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("bidder timeout for %s must be positive, got %v", code, timeout)
+		}
+	}
+	return nil
+}
+
+// ApplyRuntimeConfig validates a runtime config and swaps it in; auctions already running
+// keep the settings they started with
+func (e *Exchange) ApplyRuntimeConfig(rc *RuntimeConfig) error {
+	if rc == nil {
+		return nil
+	}
+	if err := rc.Validate(); err != nil {
+		return err
+	}
+
+	// Create new processor and filter before acquiring lock to minimize lock hold time
+	var newProcessor *fpd.Processor
+	var newFilter *fpd.EIDFilter
+	if rc.FPD != nil {
+		newProcessor = fpd.NewProcessor(rc.FPD)
+		newFilter = fpd.NewEIDFilter(rc.FPD)
+	}
+
+	e.configMu.Lock()
+	if rc.FPD != nil {
+		e.config.FPD = rc.FPD
+		e.fpdProcessor = newProcessor
+		e.eidFilter = newFilter
+	}
+	if rc.MaxBidders > 0 {
+		e.config.MaxBidders = rc.MaxBidders
+	}
+	if rc.DefaultTimeout > 0 {
+		e.config.DefaultTimeout = rc.DefaultTimeout
+	}
+	e.configMu.Unlock()
+
+	if rc.BidderTimeouts != nil {
+		e.timeouts.setRuntime(rc.BidderTimeouts)
+	}
+	return nil
+}
+
+// runtimeConfigDoc is the runtime config document served by IDR's /api/config or read from a file:
+//
+//	{"version": "42", "fpd": {...}, "auction": {"max_bidders": 20, "default_timeout_ms": 800,
+//	 "bidder_timeouts_ms": {"rubicon": 300}}}
+type runtimeConfigDoc struct {
+	Version string         `json:"version"`
+	FPD     *idr.FPDConfig `json:"fpd"`
+	Auction *struct {
+		MaxBidders       int            `json:"max_bidders"`
+		DefaultTimeoutMs int            `json:"default_timeout_ms"`
+		BidderTimeoutsMs map[string]int `json:"bidder_timeouts_ms"`
+	} `json:"auction"`
+}
+
+// ParseRuntimeConfig parses and validates a runtime config document, returning the config and
+// its version (the document's "version", or a hash of its content when it has none)
+func ParseRuntimeConfig(data []byte) (*RuntimeConfig, string, error) {
+	var doc runtimeConfigDoc
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, "", fmt.Errorf("invalid runtime config: %w", err)
+	}
+
+	rc := &RuntimeConfig{}
+	if f := doc.FPD; f != nil {
+		rc.FPD = fpd.ConfigFromIDR(f.Enabled, f.SiteEnabled, f.UserEnabled, f.ImpEnabled, f.GlobalEnabled,
+			f.BidderConfigEnabled, f.ContentEnabled, f.EIDsEnabled, f.EIDSources)
+	}
+	if a := doc.Auction; a != nil {
+		rc.MaxBidders = a.MaxBidders
+		rc.DefaultTimeout = time.Duration(a.DefaultTimeoutMs) * time.Millisecond
+		if a.BidderTimeoutsMs != nil {
+			rc.BidderTimeouts = make(map[string]time.Duration, len(a.BidderTimeoutsMs))
+			for code, ms := range a.BidderTimeoutsMs {
+				rc.BidderTimeouts[code] = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := rc.Validate(); err != nil {
+		return nil, "", fmt.Errorf("invalid runtime config: %w", err)
+	}
+
+	version := doc.Version
+	if version == "" {
+		sum := sha256.Sum256(data)
+		version = hex.EncodeToString(sum[:6])
+	}
+	return rc, version, nil
+}
+
+// ConfigSource loads runtime config documents
+type ConfigSource interface {
+	Name() string
+	Fetch(ctx context.Context) ([]byte, error)
+}
+
+// idrConfigSource reads runtime config from the IDR service
+type idrConfigSource struct {
+	client *idr.Client
+}
+
+// NewIDRConfigSource returns a source reading IDR's /api/config
+func NewIDRConfigSource(client *idr.Client) ConfigSource {
+	return &idrConfigSource{client: client}
+}
+
+func (s *idrConfigSource) Name() string { return "idr" }
+
+func (s *idrConfigSource) Fetch(ctx context.Context) ([]byte, error) {
+	config, err := s.client.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(config)
+}
+
+// fileConfigSource reads runtime config from a local JSON file
+type fileConfigSource struct {
+	path string
+}
+
+// NewFileConfigSource returns a source reading a local JSON file
+func NewFileConfigSource(path string) ConfigSource {
+	return &fileConfigSource{path: path}
+}
+
+func (s *fileConfigSource) Name() string { return "file:" + s.path }
+
+func (s *fileConfigSource) Fetch(ctx context.Context) ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runtime config: %w", err)
+	}
+	return data, nil
+}
+
+// ConfigSyncStatus describes the last runtime config sync
+type ConfigSyncStatus struct {
+	Source      string    `json:"source"`
+	Version     string    `json:"version,omitempty"`   // Version currently applied
+	LastSync    time.Time `json:"last_sync,omitempty"` // Last successful fetch
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+// ConfigSyncer periodically pulls runtime config from a source and applies new versions
+// to the exchange. Invalid documents are rejected and the current config kept.
+type ConfigSyncer struct {
+	exchange *Exchange
+	source   ConfigSource
+	interval time.Duration
+
+	mu     sync.RWMutex
+	status ConfigSyncStatus
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// NewConfigSyncer creates a syncer; call Start to begin polling
+func NewConfigSyncer(ex *Exchange, source ConfigSource, interval time.Duration) *ConfigSyncer {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ConfigSyncer{
+		exchange: ex,
+		source:   source,
+		interval: interval,
+		status:   ConfigSyncStatus{Source: source.Name()},
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start syncs once and then every interval in the background
+func (s *ConfigSyncer) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := s.Sync(ctx); err != nil {
+				logger.Log.Warn().Err(err).Str("source", s.source.Name()).Msg("Runtime config sync failed, keeping current config")
+			}
+			cancel()
+
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops background syncing
+func (s *ConfigSyncer) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	s.wg.Wait()
+}
+
+// Sync fetches the runtime config and applies it if its version changed
+func (s *ConfigSyncer) Sync(ctx context.Context) error {
+	data, err := s.source.Fetch(ctx)
+	if err == nil {
+		err = s.apply(data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.LastAttempt = time.Now()
+	if err != nil {
+		s.status.LastError = err.Error()
+		return err
+	}
+	s.status.LastSync = s.status.LastAttempt
+	s.status.LastError = ""
+	return nil
+}
+
+// apply parses a document and applies it unless its version is already applied
+func (s *ConfigSyncer) apply(data []byte) error {
+	rc, version, err := ParseRuntimeConfig(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	current := s.status.Version
+	s.mu.RUnlock()
+	if version == current {
+		return nil
+	}
+
+	if err := s.exchange.ApplyRuntimeConfig(rc); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.status.Version = version
+	s.mu.Unlock()
+	logger.Log.Info().Str("source", s.source.Name()).Str("version", version).Msg("Runtime config applied")
+	return nil
+}
+
+// Status returns the last sync's outcome
+func (s *ConfigSyncer) Status() ConfigSyncStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Stale reports whether the last successful sync is more than three intervals old
+func (s *ConfigSyncer) Stale() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status.LastSync.IsZero() || time.Since(s.status.LastSync) > 3*s.interval
+}
@@ -0,0 +1,171 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+const testRuntimeConfig = `{
+	"version": "7",
+	"fpd": {"enabled": true, "site_enabled": true, "eids_enabled": true, "eid_sources": "liveramp.com, id5-sync.com"},
+	"auction": {"max_bidders": 2, "default_timeout_ms": 800, "bidder_timeouts_ms": {"rubicon": 300}}
+}`
+
+func TestParseRuntimeConfig(t *testing.T) {
+	rc, version, err := ParseRuntimeConfig([]byte(testRuntimeConfig))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "7" || rc.MaxBidders != 2 || rc.DefaultTimeout != 800*time.Millisecond || rc.BidderTimeouts["rubicon"] != 300*time.Millisecond {
+		t.Errorf("Unexpected runtime config %q: %+v", version, rc)
+	}
+	if rc.FPD == nil || len(rc.FPD.EIDSources) != 2 || rc.FPD.UserEnabled {
+		t.Errorf("Unexpected FPD config: %+v", rc.FPD)
+	}
+
+	// Without a version the content hash identifies the document
+	_, v1, _ := ParseRuntimeConfig([]byte(`{"auction": {"max_bidders": 5}}`))
+	_, v2, _ := ParseRuntimeConfig([]byte(`{"auction": {"max_bidders": 6}}`))
+	if v1 == "" || v1 == v2 {
+		t.Errorf("Expected distinct content versions, got %q and %q", v1, v2)
+	}
+
+	for _, doc := range []string{
+		`not json`,
+		`{"auction": {"max_bidders": -1}}`,
+		`{"auction": {"default_timeout_ms": 60000}}`,
+		`{"auction": {"bidder_timeouts_ms": {"rubicon": 0}}}`,
+	} {
+		if _, _, err := ParseRuntimeConfig([]byte(doc)); err == nil {
+			t.Errorf("Expected error for %s", doc)
+		}
+	}
+}
+
+func TestConfigSyncer_FileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	if err := os.WriteFile(path, []byte(testRuntimeConfig), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: time.Second})
+	syncer := NewConfigSyncer(ex, NewFileConfigSource(path), time.Minute)
+	if err := syncer.Sync(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := ex.GetFPDConfig().EIDSources; len(got) != 2 || got[0] != "liveramp.com" {
+		t.Errorf("Expected synced EID sources, got %v", got)
+	}
+	if ex.config.MaxBidders != 2 || ex.config.DefaultTimeout != 800*time.Millisecond {
+		t.Errorf("Expected synced auction settings, got %d bidders, %v", ex.config.MaxBidders, ex.config.DefaultTimeout)
+	}
+	status := syncer.Status()
+	if status.Version != "7" || status.LastSync.IsZero() || status.LastError != "" || syncer.Stale() {
+		t.Errorf("Unexpected sync status: %+v", status)
+	}
+
+	// An invalid document is rejected and the applied config kept
+	if err := os.WriteFile(path, []byte(`{"version": "8", "auction": {"max_bidders": -3}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := syncer.Sync(context.Background()); err == nil {
+		t.Fatal("Expected error for an invalid document")
+	}
+	status = syncer.Status()
+	if status.Version != "7" || status.LastError == "" || ex.config.MaxBidders != 2 {
+		t.Errorf("Expected the previous config kept, got %+v with %d max bidders", status, ex.config.MaxBidders)
+	}
+}
+
+func TestConfigSyncer_IDRSource(t *testing.T) {
+	idrServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/config" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testRuntimeConfig))
+	}))
+	defer idrServer.Close()
+
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: time.Second})
+	syncer := NewConfigSyncer(ex, NewIDRConfigSource(idr.NewClient(idrServer.URL, time.Second, "")), time.Minute)
+	syncer.Start()
+	defer syncer.Stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for syncer.Status().Version != "7" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the first sync: %+v", syncer.Status())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// rankedSelector ranks the available bidders in reverse alphabetical order
+type rankedSelector struct{}
+
+func (rankedSelector) SelectBidders(ctx context.Context, req *openrtb.BidRequest, available []string) (*idr.SelectPartnersResponse, error) {
+	codes := append([]string(nil), available...)
+	sort.Sort(sort.Reverse(sort.StringSlice(codes)))
+	resp := &idr.SelectPartnersResponse{Mode: idr.ModeNormal}
+	for _, code := range codes {
+		resp.SelectedBidders = append(resp.SelectedBidders, idr.SelectedBidder{BidderCode: code})
+	}
+	return resp, nil
+}
+
+func TestRunAuction_MaxBiddersCap(t *testing.T) {
+	idrServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer idrServer.Close()
+
+	registry := adapters.NewRegistry()
+	for _, code := range []string{"a", "b", "c"} {
+		registry.Register(code, &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	}
+	newRequest := func() *AuctionRequest {
+		return &AuctionRequest{BidRequest: &openrtb.BidRequest{
+			ID:   "cap-1",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		}}
+	}
+
+	// Without a ranked selection the cap would drop bidders by registry order, so it isn't applied
+	unranked := New(registry, &Config{DefaultTimeout: time.Second})
+	if err := unranked.ApplyRuntimeConfig(&RuntimeConfig{MaxBidders: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := unranked.RunAuction(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.BidderResults) != 3 {
+		t.Errorf("Expected every bidder called without a ranked selection, got %d", len(resp.BidderResults))
+	}
+
+	ranked := New(registry, &Config{DefaultTimeout: time.Second, IDREnabled: true, IDRServiceURL: idrServer.URL})
+	defer ranked.Close()
+	ranked.SetFallbackSelector(rankedSelector{})
+	if err := ranked.ApplyRuntimeConfig(&RuntimeConfig{MaxBidders: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err = ranked.RunAuction(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.DebugInfo.SelectedBidders; len(got) != 2 || resp.BidderResults["a"] != nil {
+		t.Errorf("Expected the two best ranked bidders called, got %v", got)
+	}
+}
@@ -3,6 +3,7 @@ package exchange
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 	"github.com/thenexusengine/tne_springwire/pkg/currency"
@@ -11,7 +12,10 @@ import (
 
 // PrebidExt represents the ext.prebid object in OpenRTB requests
 type PrebidExt struct {
-	Currency *PrebidCurrency `json:"currency,omitempty"`
+	Currency             *PrebidCurrency          `json:"currency,omitempty"`
+	MultiBid             []ExtMultiBid            `json:"multibid,omitempty"`
+	Aliases              map[string]string        `json:"aliases,omitempty"` // Request-scoped alias code -> core bidder code
+	AlternateBidderCodes *ExtAlternateBidderCodes `json:"alternatebiddercodes,omitempty"`
 }
 
 // PrebidCurrency represents currency configuration in ext.prebid.currency
@@ -191,3 +195,129 @@ func (e *Exchange) isCurrencySupported(currencyCode string) bool {
 	_, exists := rates[currencyCode]
 	return exists
 }
+
+// auctionCurrency holds the currencies and rates used for one auction.
+// Floors and bids are compared in the auction currency; the response is returned in the target.
+type auctionCurrency struct {
+	auction     string
+	target      string
+	conversions *currency.AggregateConversions
+	customRates bool // Request supplied ext.prebid.currency.rates
+}
+
+// newAuctionCurrency resolves the auction currency, response target and rate sources for a request.
+// Custom rates from ext.prebid.currency.rates take priority over the converter unless usepbsrates is set.
+func (e *Exchange) newAuctionCurrency(req *openrtb.BidRequest) *auctionCurrency {
+	auction := e.config.DefaultCurrency
+	if auction == "" {
+		auction = "USD"
+	}
+	customRates, useExternal := extractCustomRates(req)
+	return &auctionCurrency{
+		auction:     auction,
+		target:      strings.ToUpper(e.extractTargetCurrency(req)),
+		conversions: currency.NewAggregateConversions(customRates, e.currencyConverter, useExternal),
+		customRates: len(customRates) > 0,
+	}
+}
+
+// convert converts amount between currencies using the auction's rate sources.
+// It also returns the version of the rates used (see currency.AggregateConversions.GetRateVersion).
+func (c *auctionCurrency) convert(amount float64, from, to string) (float64, string, error) {
+	if strings.EqualFold(from, to) {
+		return amount, "", nil
+	}
+	rate, version, err := c.conversions.GetRateVersion(strings.ToUpper(from), strings.ToUpper(to))
+	if err != nil {
+		return 0, "", fmt.Errorf("convert %s to %s: %w", from, to, err)
+	}
+	return amount * rate, version, nil
+}
+
+// normalizeFloors converts every imp.bidfloor to the auction currency in place.
+// Per OpenRTB 2.5 an empty bidfloorcur means USD.
+func (e *Exchange) normalizeFloors(req *openrtb.BidRequest, cur *auctionCurrency) error {
+	for i := range req.Imp {
+		imp := &req.Imp[i]
+		floorCur := imp.BidFloorCur
+		if floorCur == "" {
+			floorCur = "USD"
+		}
+		if strings.EqualFold(floorCur, cur.auction) {
+			imp.BidFloorCur = cur.auction
+			continue
+		}
+		if imp.BidFloor > 0 {
+			converted, _, err := cur.convert(imp.BidFloor, floorCur, cur.auction)
+			if err != nil {
+				return NewValidationError("invalid bid request: impression[%d] floor currency %s cannot be converted to %s", i, floorCur, cur.auction)
+			}
+			logger.Log.Debug().
+				Str("impID", imp.ID).
+				Str("from", floorCur).
+				Str("to", cur.auction).
+				Float64("originalFloor", imp.BidFloor).
+				Float64("convertedFloor", converted).
+				Msg("converted floor currency")
+			imp.BidFloor = converted
+		}
+		imp.BidFloorCur = cur.auction
+	}
+	return nil
+}
+
+// bidderCurrencies returns the currencies offered to a bidder in request.cur.
+// Bidders that declare none are offered the auction currency only.
+func (e *Exchange) bidderCurrencies(bidderCode string, cur *auctionCurrency, aliases requestAliases) []string {
+	if awi, ok := e.lookupBidder(bidderCode, aliases); ok && len(awi.Info.Currencies) > 0 {
+		return append([]string(nil), awi.Info.Currencies...)
+	}
+	return []string{cur.auction}
+}
+
+// bidderFloorCurrency picks the currency floors are sent to a bidder in:
+// the auction currency when the bidder accepts it, otherwise its preferred currency
+func bidderFloorCurrency(currencies []string, cur *auctionCurrency) string {
+	for _, c := range currencies {
+		if strings.EqualFold(c, cur.auction) {
+			return cur.auction
+		}
+	}
+	return currencies[0]
+}
+
+// convertAuctionedBids converts every auctioned bid price from the auction currency to the
+// response target. Either all bids are converted or none are, so the response has one currency.
+// It returns the response currency and the version of the rates used.
+func (e *Exchange) convertAuctionedBids(bidsByImp map[string][]ValidatedBid, cur *auctionCurrency) (string, string, error) {
+	if strings.EqualFold(cur.target, cur.auction) {
+		return cur.auction, "", nil
+	}
+	rate, version, err := cur.convert(1, cur.auction, cur.target)
+	if err != nil {
+		return cur.auction, "", err
+	}
+	for _, bids := range bidsByImp {
+		for _, vb := range bids {
+			vb.Bid.Bid.Price *= rate
+		}
+	}
+	return cur.target, version, nil
+}
+
+// setBidderFloor expresses an imp copy's floor (already in the auction currency) in floorCur.
+// If the floor can't be converted it is sent in the auction currency.
+func (e *Exchange) setBidderFloor(imp *openrtb.Imp, floorCur string, cur *auctionCurrency) {
+	imp.BidFloorCur = cur.auction
+	if floorCur == cur.auction {
+		return
+	}
+	if imp.BidFloor > 0 {
+		converted, _, err := cur.convert(imp.BidFloor, cur.auction, floorCur)
+		if err != nil {
+			return
+		}
+		imp.BidFloor = converted
+	}
+	imp.BidFloorCur = floorCur
+}
@@ -0,0 +1,216 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/currency"
+)
+
+// testRatesExt supplies EUR<->USD custom rates via ext.prebid.currency.rates
+const testRatesExt = `{"prebid":{"currency":{"rates":{"EUR":{"USD":1.1},"USD":{"EUR":0.9}}}}}`
+
+// currencyAdapter answers in a fixed currency
+type currencyAdapter struct {
+	*captureAdapter
+	currency string
+}
+
+func (c *currencyAdapter) MakeBids(internalRequest *openrtb.BidRequest, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	resp, errs := c.captureAdapter.MakeBids(internalRequest, response)
+	if resp != nil {
+		resp.Currency = c.currency
+	}
+	return resp, errs
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNormalizeFloors(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{IDREnabled: false, DefaultCurrency: "USD"})
+
+	req := &openrtb.BidRequest{
+		ID:  "floors",
+		Ext: json.RawMessage(testRatesExt),
+		Imp: []openrtb.Imp{
+			{ID: "eur", BidFloor: 2.00, BidFloorCur: "EUR"},
+			{ID: "usd", BidFloor: 1.50, BidFloorCur: "USD"},
+			{ID: "default", BidFloor: 1.00},
+			{ID: "zero", BidFloorCur: "GBP"},
+		},
+	}
+	if err := ex.normalizeFloors(req, ex.newAuctionCurrency(req)); err != nil {
+		t.Fatalf("normalizeFloors failed: %v", err)
+	}
+
+	if !approxEqual(req.Imp[0].BidFloor, 2.2) || req.Imp[0].BidFloorCur != "USD" {
+		t.Errorf("Expected EUR 2.00 floor to become USD 2.20, got %s %v", req.Imp[0].BidFloorCur, req.Imp[0].BidFloor)
+	}
+	if req.Imp[1].BidFloor != 1.50 || req.Imp[2].BidFloor != 1.00 || req.Imp[2].BidFloorCur != "USD" {
+		t.Errorf("Expected USD floors unchanged, got %+v %+v", req.Imp[1], req.Imp[2])
+	}
+	if req.Imp[3].BidFloorCur != "USD" {
+		t.Errorf("Expected zero floor to be relabelled, got %s", req.Imp[3].BidFloorCur)
+	}
+
+	// No rate available: the request is rejected rather than silently relabelled
+	bad := &openrtb.BidRequest{ID: "bad", Imp: []openrtb.Imp{{ID: "gbp", BidFloor: 1, BidFloorCur: "GBP"}}}
+	err := ex.normalizeFloors(bad, ex.newAuctionCurrency(bad))
+	if _, ok := err.(*ValidationError); !ok {
+		t.Errorf("Expected ValidationError for unconvertible floor, got %v", err)
+	}
+}
+
+func TestCloneRequestWithFPD_BidderCurrencies(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("eurbidder", &mockAdapter{}, adapters.BidderInfo{Enabled: true, Currencies: []string{"EUR"}})
+	registry.Register("multi", &mockAdapter{}, adapters.BidderInfo{Enabled: true, Currencies: []string{"EUR", "USD"}})
+	registry.Register("plain", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, &Config{IDREnabled: false, DefaultCurrency: "USD"})
+
+	req := &openrtb.BidRequest{
+		ID:   "cur",
+		Site: testSite(),
+		Ext:  json.RawMessage(testRatesExt),
+		Imp:  []openrtb.Imp{{ID: "imp1", BidFloor: 2.00, BidFloorCur: "USD", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	cur := ex.newAuctionCurrency(req)
+
+	clone := ex.cloneRequestWithFPD(req, "eurbidder", nil, nil, cur, nil)
+	if len(clone.Cur) != 1 || clone.Cur[0] != "EUR" {
+		t.Errorf("Expected cur [EUR], got %v", clone.Cur)
+	}
+	if clone.Imp[0].BidFloorCur != "EUR" || !approxEqual(clone.Imp[0].BidFloor, 1.8) {
+		t.Errorf("Expected EUR 1.80 floor, got %s %v", clone.Imp[0].BidFloorCur, clone.Imp[0].BidFloor)
+	}
+
+	clone = ex.cloneRequestWithFPD(req, "multi", nil, nil, cur, nil)
+	if len(clone.Cur) != 2 || clone.Imp[0].BidFloorCur != "USD" || clone.Imp[0].BidFloor != 2.00 {
+		t.Errorf("Expected USD floor for bidder accepting USD, got cur=%v %s %v", clone.Cur, clone.Imp[0].BidFloorCur, clone.Imp[0].BidFloor)
+	}
+
+	clone = ex.cloneRequestWithFPD(req, "plain", nil, nil, cur, nil)
+	if len(clone.Cur) != 1 || clone.Cur[0] != "USD" {
+		t.Errorf("Expected auction currency for bidder without declared currencies, got %v", clone.Cur)
+	}
+	if req.Imp[0].BidFloor != 2.00 || req.Imp[0].BidFloorCur != "USD" {
+		t.Errorf("Original request floor was mutated: %+v", req.Imp[0])
+	}
+}
+
+func TestRunAuction_CurrencyRoundTrip(t *testing.T) {
+	registry := adapters.NewRegistry()
+	eur := &captureAdapter{mockAdapter: mockAdapter{bids: []*adapters.TypedBid{{
+		Bid:     &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 3.00, AdM: "<div></div>", CRID: "cr1", W: 300, H: 250},
+		BidType: adapters.BidTypeBanner,
+	}}}}
+	registry.Register("eurbidder", &currencyAdapter{captureAdapter: eur, currency: "EUR"}, adapters.BidderInfo{Enabled: true, Currencies: []string{"EUR"}})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, IDREnabled: false, DefaultCurrency: "USD"})
+
+	bidReq := &openrtb.BidRequest{
+		ID:   "round-trip",
+		Site: testSite(),
+		Cur:  []string{"EUR"},
+		Ext:  json.RawMessage(testRatesExt),
+		Imp:  []openrtb.Imp{{ID: "imp1", BidFloor: 2.00, BidFloorCur: "EUR", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: bidReq})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sent := eur.captured()
+	if sent == nil || sent.Imp[0].BidFloorCur != "EUR" || !approxEqual(sent.Imp[0].BidFloor, 2.2*0.9) {
+		t.Fatalf("Expected bidder to receive an EUR floor, got %+v", sent)
+	}
+	if resp.BidResponse.Cur != "EUR" {
+		t.Errorf("Expected response in EUR, got %s", resp.BidResponse.Cur)
+	}
+	if len(resp.BidResponse.SeatBid) != 1 || len(resp.BidResponse.SeatBid[0].Bid) != 1 {
+		t.Fatalf("Expected one bid, got %+v (errors %v)", resp.BidResponse.SeatBid, resp.DebugInfo.Errors)
+	}
+	// EUR 3.00 -> USD 3.30 for the auction -> EUR 2.97 in the response
+	if price := resp.BidResponse.SeatBid[0].Bid[0].Price; !approxEqual(price, 3.00*1.1*0.9) {
+		t.Errorf("Expected converted price 2.97, got %v", price)
+	}
+
+	// Both conversions are priced by the request's custom rates
+	conversions := resp.DebugInfo.CurrencyConversions
+	if len(conversions) != 2 {
+		t.Fatalf("Expected bidder and response conversions, got %+v", conversions)
+	}
+	if c := conversions[0]; c.Stage != ConversionStageBidder || c.From != "EUR" || c.To != "USD" || c.RateVersion != currency.CustomRatesVersion {
+		t.Errorf("Unexpected bidder conversion: %+v", c)
+	}
+	if c := conversions[1]; c.Stage != ConversionStageResponse || c.From != "USD" || c.To != "EUR" || c.BidID != "b1" {
+		t.Errorf("Unexpected response conversion: %+v", c)
+	}
+}
+
+func TestRunAuction_RecordsRateVersion(t *testing.T) {
+	converter := currency.NewConverterWithProviders(nil, staticRates(map[string]map[string]float64{
+		"GBP": {"USD": 1.25},
+	}))
+	if err := converter.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer converter.Stop()
+	version := converter.Snapshot().Version
+
+	registry := adapters.NewRegistry()
+	gbp := &captureAdapter{mockAdapter: mockAdapter{bids: []*adapters.TypedBid{{
+		Bid:     &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 2.00, AdM: "<div></div>", CRID: "cr1", W: 300, H: 250},
+		BidType: adapters.BidTypeBanner,
+	}}}}
+	registry.Register("gbpbidder", &currencyAdapter{captureAdapter: gbp, currency: "GBP"}, adapters.BidderInfo{Enabled: true, Currencies: []string{"GBP", "USD"}})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, IDREnabled: false, DefaultCurrency: "USD", CurrencyConverter: converter})
+	module := &captureModule{}
+	ex.SetAnalytics(module)
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "versioned",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.DebugInfo.CurrencyConversions) != 1 || resp.DebugInfo.CurrencyConversions[0].RateVersion != version {
+		t.Errorf("Expected conversion priced by snapshot %s, got %+v", version, resp.DebugInfo.CurrencyConversions)
+	}
+	if len(module.auctions) != 1 || len(module.auctions[0].Bids) != 1 {
+		t.Fatalf("Expected one analytics bid, got %+v", module.auctions)
+	}
+	ao := module.auctions[0]
+	if b := ao.Bids[0]; b.OriginalCurrency != "GBP" || b.RateVersion != version || !approxEqual(b.BidPrice, 2.5) {
+		t.Errorf("Unexpected analytics bid: %+v", b)
+	}
+}
+
+// staticRates returns a provider serving a fixed rate set
+func staticRates(conversions map[string]map[string]float64) *currency.StaticProvider {
+	p := currency.NewStaticProvider("static")
+	p.Set(&currency.CurrencyFile{Conversions: conversions})
+	return p
+}
+
+func TestConvertAuctionedBids_NoRate(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{IDREnabled: false, DefaultCurrency: "USD"})
+	req := &openrtb.BidRequest{ID: "no-rate", Cur: []string{"JPY"}}
+	bids := map[string][]ValidatedBid{"imp1": {{Bid: &adapters.TypedBid{Bid: &openrtb.Bid{Price: 1.5}}}}}
+
+	cur, _, err := ex.convertAuctionedBids(bids, ex.newAuctionCurrency(req))
+	if err == nil || cur != "USD" || bids["imp1"][0].Bid.Bid.Price != 1.5 {
+		t.Errorf("Expected prices left in USD when no rate exists, got %s %v (err=%v)", cur, bids["imp1"][0].Bid.Bid.Price, err)
+	}
+}
@@ -0,0 +1,343 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/tracing"
+)
+
+// Auction stages reported in the debug trace
+const (
+	StageValidation = "validation" // Request validation and normalization
+	StageIDR        = "idr"        // Bidder selection (IDR and imp eligibility)
+	StageFPD        = "fpd"        // First-party data and EID filtering
+	StageBidders    = "bidders"    // Bidder calls and response validation
+	StageFloors     = "floors"     // Floor and minimum price enforcement
+	StageAuction    = "auction"    // Auction logic and seat limits
+	StageMultiplier = "multiplier" // Publisher bid multiplier
+)
+
+// maxDebugBodySize truncates request and response bodies in debug HTTP calls
+const maxDebugBodySize = 64 * 1024
+
+// redactedValue replaces secrets in debug output
+const redactedValue = "[REDACTED]"
+
+// HTTPCall is one outbound bidder request and its response, kept in debug mode
+type HTTPCall struct {
+	URI            string // Secret query parameters redacted
+	Method         string
+	RequestHeaders http.Header // Secret headers redacted
+	RequestBody    string
+	StatusCode     int
+	ResponseBody   string
+	Error          string
+}
+
+// TraceStage records what one auction stage did
+type TraceStage struct {
+	Stage        string
+	Duration     time.Duration
+	Messages     []string
+	RejectedBids []RejectedBid
+}
+
+// RejectedBid is a bid dropped by an auction stage
+type RejectedBid struct {
+	BidderCode string
+	BidID      string
+	ImpID      string
+	Price      float64
+	Reason     string
+}
+
+type debugInfoKey struct{}
+
+// addHTTPCall records a bidder HTTP call when the auction runs in debug mode
+func addHTTPCall(ctx context.Context, bidderCode string, reqData *adapters.RequestData, resp *adapters.ResponseData, err error) {
+	d, ok := ctx.Value(debugInfoKey{}).(*DebugInfo)
+	if !ok {
+		return
+	}
+
+	call := HTTPCall{
+		URI:            redactURI(reqData.URI),
+		Method:         reqData.Method,
+		RequestHeaders: redactHeaders(reqData.Headers),
+		RequestBody:    truncateBody(reqData.Body),
+	}
+	if err != nil {
+		call.Error = err.Error()
+	} else if resp != nil {
+		call.StatusCode = resp.StatusCode
+		call.ResponseBody = truncateBody(resp.Body)
+	}
+
+	d.errorsMu.Lock()
+	defer d.errorsMu.Unlock()
+	if d.HTTPCalls == nil {
+		d.HTTPCalls = make(map[string][]HTTPCall)
+	}
+	d.HTTPCalls[bidderCode] = append(d.HTTPCalls[bidderCode], call)
+}
+
+// isSecretName reports whether a header or query parameter name likely holds a credential
+func isSecretName(name string) bool {
+	name = strings.ToLower(name)
+	for _, marker := range []string{"auth", "key", "token", "secret", "password", "cookie", "signature"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders copies headers with credential values replaced
+func redactHeaders(headers http.Header) http.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	redacted := make(http.Header, len(headers))
+	for name, values := range headers {
+		if isSecretName(name) {
+			redacted[name] = []string{redactedValue}
+			continue
+		}
+		redacted[name] = append([]string(nil), values...)
+	}
+	return redacted
+}
+
+// redactURI replaces credential query parameter values in a bidder URI
+func redactURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.RawQuery == "" {
+		return uri
+	}
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if isSecretName(name) {
+			query.Set(name, redactedValue)
+			changed = true
+		}
+	}
+	if !changed {
+		return uri
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// truncateBody converts a body for debug output, cut at maxDebugBodySize
+func truncateBody(body []byte) string {
+	if len(body) > maxDebugBodySize {
+		return string(body[:maxDebugBodySize]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// auctionTrace builds the stage trace of a debug auction and the stage spans of a traced one.
+// A nil trace ignores all calls, so the auction path can record unconditionally.
+type auctionTrace struct {
+	debug   bool
+	stages  []TraceStage
+	pending map[string][]RejectedBid // Rejections for stages not yet recorded
+	spanCtx context.Context          // Auction span context, nil when the auction isn't traced
+}
+
+// newAuctionTrace returns a trace when debug is on or the auction span is recording, nil otherwise
+func newAuctionTrace(ctx context.Context, debug bool) *auctionTrace {
+	traced := oteltrace.SpanFromContext(ctx).IsRecording()
+	if !debug && !traced {
+		return nil
+	}
+	t := &auctionTrace{debug: debug}
+	if debug {
+		t.pending = make(map[string][]RejectedBid)
+	}
+	if traced {
+		t.spanCtx = ctx
+	}
+	return t
+}
+
+// stage records a finished stage along with the bids rejected in it so far
+func (t *auctionTrace) stage(name string, start time.Time, messages ...string) {
+	if t == nil {
+		return
+	}
+	if t.spanCtx != nil {
+		_, span := tracing.Tracer().Start(t.spanCtx, "auction."+name, oteltrace.WithTimestamp(start))
+		span.End()
+	}
+	if !t.debug {
+		return
+	}
+	t.stages = append(t.stages, TraceStage{
+		Stage:        name,
+		Duration:     time.Since(start),
+		Messages:     messages,
+		RejectedBids: t.pending[name],
+	})
+	delete(t.pending, name)
+}
+
+// reject records a bid dropped by a stage
+func (t *auctionTrace) reject(name string, bidderCode string, bid *openrtb.Bid, reason string) {
+	if t == nil || !t.debug || bid == nil {
+		return
+	}
+	rb := RejectedBid{BidderCode: bidderCode, BidID: bid.ID, ImpID: bid.ImpID, Price: bid.Price, Reason: reason}
+	for i := range t.stages {
+		if t.stages[i].Stage == name {
+			t.stages[i].RejectedBids = append(t.stages[i].RejectedBids, rb)
+			return
+		}
+	}
+	t.pending[name] = append(t.pending[name], rb)
+}
+
+// finish stores the trace on the debug info, including rejections for stages never reached
+func (t *auctionTrace) finish(d *DebugInfo) {
+	if t == nil || !t.debug || d == nil {
+		return
+	}
+	for name, rejected := range t.pending {
+		t.stages = append(t.stages, TraceStage{Stage: name, RejectedBids: rejected})
+	}
+	d.Trace = t.stages
+}
+
+// isFloorRejection reports whether a bid validation error is a floor or minimum price check
+func isFloorRejection(err *BidValidationError) bool {
+	return strings.Contains(err.Reason, "below floor") || strings.Contains(err.Reason, "below minimum")
+}
+
+// prices snapshots bid prices by bid ID, as later stages adjust them in place
+func (t *auctionTrace) prices(bids []ValidatedBid) map[string]float64 {
+	if t == nil || !t.debug {
+		return nil
+	}
+	prices := make(map[string]float64, len(bids))
+	for _, vb := range bids {
+		if vb.Bid != nil && vb.Bid.Bid != nil {
+			prices[vb.Bid.Bid.ID] = vb.Bid.Bid.Price
+		}
+	}
+	return prices
+}
+
+// impPrices snapshots the prices of bids grouped by imp
+func (t *auctionTrace) impPrices(bidsByImp map[string][]ValidatedBid) map[string]float64 {
+	if t == nil || !t.debug {
+		return nil
+	}
+	var all []ValidatedBid
+	for _, bids := range bidsByImp {
+		all = append(all, bids...)
+	}
+	return t.prices(all)
+}
+
+// traceAuction records the auction stage: clearing prices per imp and bids the auction dropped
+func (t *auctionTrace) traceAuction(start time.Time, auctionType AuctionType, validBids []ValidatedBid, bidPricesBefore map[string]float64, auctionedBids map[string][]ValidatedBid) {
+	if t == nil {
+		return
+	}
+	if !t.debug {
+		t.stage(StageAuction, start)
+		return
+	}
+	kept := make(map[string]bool)
+	var impMessages []string
+	for impID, bids := range auctionedBids {
+		for _, vb := range bids {
+			kept[vb.Bid.Bid.ID] = true
+		}
+		if len(bids) > 0 {
+			winner := bids[0]
+			impMessages = append(impMessages, fmt.Sprintf("imp %s: %d bids, %s wins at %.4f (bid %.4f)",
+				impID, len(bids), winner.BidderCode, winner.Bid.Bid.Price, bidPricesBefore[winner.Bid.Bid.ID]))
+		}
+	}
+	sort.Strings(impMessages)
+	messages := []string{"first-price auction"}
+	if auctionType == SecondPriceAuction {
+		messages[0] = "second-price auction"
+	}
+	messages = append(messages, impMessages...)
+
+	for _, vb := range validBids {
+		if !kept[vb.Bid.Bid.ID] {
+			bid := *vb.Bid.Bid
+			bid.Price = bidPricesBefore[bid.ID]
+			t.reject(StageAuction, vb.BidderCode, &bid, "clearing price exceeds bid in second-price auction")
+		}
+	}
+	t.stage(StageAuction, start, messages...)
+}
+
+// traceMultiplier records price changes made by the publisher bid multiplier
+func (t *auctionTrace) traceMultiplier(start time.Time, before map[string]float64, bidsByImp map[string][]ValidatedBid) {
+	if t == nil {
+		return
+	}
+	if !t.debug {
+		t.stage(StageMultiplier, start)
+		return
+	}
+	var messages []string
+	for _, bids := range bidsByImp {
+		for _, vb := range bids {
+			id := vb.Bid.Bid.ID
+			if price := vb.Bid.Bid.Price; price != before[id] {
+				messages = append(messages, fmt.Sprintf("bid %s from %s: %.4f -> %.4f", id, vb.BidderCode, before[id], price))
+			}
+		}
+	}
+	sort.Strings(messages)
+	if len(messages) == 0 {
+		messages = []string{"no multiplier applied"}
+	}
+	t.stage(StageMultiplier, start, messages...)
+}
+
+// bidderTraceMessages summarises each bidder's call for the trace, sorted by bidder code
+func bidderTraceMessages(results map[string]*BidderResult) []string {
+	messages := make([]string, 0, len(results))
+	for code, result := range results {
+		msg := fmt.Sprintf("%s: %d bids in %dms", code, len(result.Bids), result.Latency.Milliseconds())
+		if result.PartialTimeout {
+			msg += ", partial timeout"
+		} else if result.TimedOut {
+			msg += ", timed out"
+		}
+		if len(result.Errors) > 0 {
+			msg += fmt.Sprintf(", %d errors", len(result.Errors))
+		}
+		messages = append(messages, msg)
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// resolvedRequest serializes the request as sent to bidders for ext.debug.resolvedrequest
+func resolvedRequest(req *openrtb.BidRequest) json.RawMessage {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,154 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestRedactURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"https://bidder.example.com/bid?pub=1", "https://bidder.example.com/bid?pub=1"},
+		{"https://bidder.example.com/bid?api_key=s3cret&pub=1", "https://bidder.example.com/bid?api_key=%5BREDACTED%5D&pub=1"},
+		{"https://bidder.example.com/bid", "https://bidder.example.com/bid"},
+	}
+	for _, tt := range tests {
+		if got := redactURI(tt.uri); got != tt.want {
+			t.Errorf("redactURI(%q) = %q, want %q", tt.uri, got, tt.want)
+		}
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{
+		"Authorization": {"Bearer s3cret"},
+		"X-Api-Key":     {"s3cret"},
+		"Content-Type":  {"application/json"},
+	}
+	redacted := redactHeaders(headers)
+	if redacted.Get("Authorization") != redactedValue || redacted.Get("X-Api-Key") != redactedValue {
+		t.Errorf("Expected credentials redacted, got %v", redacted)
+	}
+	if redacted.Get("Content-Type") != "application/json" {
+		t.Error("Expected other headers kept")
+	}
+	if headers.Get("Authorization") != "Bearer s3cret" {
+		t.Error("Expected the original headers left untouched")
+	}
+}
+
+func TestRunAuction_DebugTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"debug-1"}`))
+	}))
+	defer server.Close()
+
+	registry := adapters.NewRegistry()
+	mock := &mockAdapter{
+		requests: []*adapters.RequestData{{
+			Method:  http.MethodPost,
+			URI:     server.URL + "?token=s3cret",
+			Body:    []byte(`{"id":"debug-1"}`),
+			Headers: http.Header{"Authorization": {"Bearer s3cret"}},
+		}},
+		bids: []*adapters.TypedBid{
+			{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 2.50, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+			{Bid: &openrtb.Bid{ID: "b2", ImpID: "imp1", Price: 0.40, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+			{Bid: &openrtb.Bid{ID: "b3", ImpID: "imp1", Price: 1.50}, BidType: adapters.BidTypeBanner},
+		},
+	}
+	registry.Register("bidder1", mock, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, &Config{DefaultTimeout: time.Second})
+
+	newRequest := func(debug bool) *AuctionRequest {
+		return &AuctionRequest{
+			Debug: debug,
+			BidRequest: &openrtb.BidRequest{
+				ID:   "debug-1",
+				Site: testSite(),
+				Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 1.00}},
+			},
+		}
+	}
+
+	resp, err := ex.RunAuction(context.Background(), newRequest(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	debug := resp.DebugInfo
+
+	calls := debug.HTTPCalls["bidder1"]
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 HTTP call for bidder1, got %d", len(calls))
+	}
+	call := calls[0]
+	if strings.Contains(call.URI, "s3cret") || call.RequestHeaders.Get("Authorization") != redactedValue {
+		t.Errorf("Expected secrets redacted, got uri=%s headers=%v", call.URI, call.RequestHeaders)
+	}
+	if call.RequestBody != `{"id":"debug-1"}` || call.StatusCode != http.StatusOK || call.ResponseBody != `{"id":"debug-1"}` {
+		t.Errorf("Unexpected HTTP call: %+v", call)
+	}
+	if !strings.Contains(string(debug.ResolvedRequest), `"id":"debug-1"`) {
+		t.Errorf("Expected the resolved request, got %s", debug.ResolvedRequest)
+	}
+
+	stages := make(map[string]TraceStage)
+	var order []string
+	for _, stage := range debug.Trace {
+		stages[stage.Stage] = stage
+		order = append(order, stage.Stage)
+	}
+	for _, name := range []string{StageValidation, StageIDR, StageFPD, StageFloors, StageBidders, StageAuction, StageMultiplier} {
+		if _, ok := stages[name]; !ok {
+			t.Errorf("Expected stage %s in trace %v", name, order)
+		}
+	}
+	if rejected := stages[StageFloors].RejectedBids; len(rejected) != 1 || rejected[0].BidID != "b2" || !strings.Contains(rejected[0].Reason, "below floor") {
+		t.Errorf("Expected b2 rejected by floors, got %+v", rejected)
+	}
+	if rejected := stages[StageBidders].RejectedBids; len(rejected) != 1 || rejected[0].BidID != "b3" {
+		t.Errorf("Expected b3 rejected for missing markup, got %+v", rejected)
+	}
+	if msgs := strings.Join(stages[StageAuction].Messages, "\n"); !strings.Contains(msgs, "bidder1 wins at 2.5000") {
+		t.Errorf("Expected the winner in the auction stage, got %q", msgs)
+	}
+
+	resp, err = ex.RunAuction(context.Background(), newRequest(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.DebugInfo.HTTPCalls != nil || resp.DebugInfo.Trace != nil || resp.DebugInfo.ResolvedRequest != nil {
+		t.Error("Expected no debug capture without debug")
+	}
+}
+
+func TestAuctionTrace_SecondPriceRejection(t *testing.T) {
+	trace := newAuctionTrace(context.Background(), true)
+	bid := &adapters.TypedBid{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 1.00}}
+	validBids := []ValidatedBid{{Bid: bid, BidderCode: "bidder1"}}
+
+	trace.traceAuction(time.Now(), SecondPriceAuction, validBids, trace.prices(validBids), map[string][]ValidatedBid{"imp1": nil})
+	var debug DebugInfo
+	trace.finish(&debug)
+
+	if len(debug.Trace) != 1 || len(debug.Trace[0].RejectedBids) != 1 {
+		t.Fatalf("Expected one rejected bid, got %+v", debug.Trace)
+	}
+	if rb := debug.Trace[0].RejectedBids[0]; rb.BidID != "b1" || rb.Price != 1.00 {
+		t.Errorf("Unexpected rejected bid: %+v", rb)
+	}
+
+	var nilTrace *auctionTrace
+	nilTrace.stage(StageValidation, time.Now())
+	nilTrace.finish(&debug)
+}
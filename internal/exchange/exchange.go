@@ -7,12 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/analytics"
 	"github.com/thenexusengine/tne_springwire/internal/fpd"
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
@@ -41,6 +43,9 @@ type MetricsRecorder interface {
 	RecordAuction(status, mediaType string, duration time.Duration, biddersSelected, biddersExcluded int)
 	RecordBid(bidder, mediaType string, cpm float64)
 	RecordBidderRequest(bidder string, latency time.Duration, hasError, timedOut bool)
+	RecordBidderPartialTimeout(bidder string)
+	RecordIDRShadowAuction(revenueDelta float64, lostWinners, biddersSaved int)
+	SetIDREventSpool(events, bytes int64, oldestAgeSeconds float64)
 
 	// Revenue/margin metrics
 	RecordMargin(publisher, bidder, mediaType string, originalPrice, adjustedPrice, platformCut float64)
@@ -59,12 +64,18 @@ type MetricsRecorder interface {
 type Exchange struct {
 	registry          *adapters.Registry
 	httpClient        adapters.HTTPClient
+	testClient        adapters.HTTPClient // Serves test=1 requests instead of real bidders (nil = disabled)
+	recorder          *AuctionRecorder    // Captures sampled auctions for replay (nil = disabled)
+	timeouts          *bidderTimeouts     // Per-bidder static and adaptive timeouts
 	idrClient         *idr.Client
+	selector          BidderSelector // Primary bidder selection (IDR; nil = call every bidder)
+	fallbackSelector  BidderSelector // Used when the primary selector fails (nil = call every bidder)
 	eventRecorder     *idr.EventRecorder
 	config            *Config
 	fpdProcessor      *fpd.Processor
 	eidFilter         *fpd.EIDFilter
 	metrics           MetricsRecorder
+	analytics         analytics.Module
 	currencyConverter *currency.Converter
 
 	// Per-bidder circuit breakers to prevent cascade failures
@@ -125,13 +136,19 @@ func DefaultCloneLimits() *CloneLimits {
 // Config holds exchange configuration
 type Config struct {
 	DefaultTimeout       time.Duration
-	MaxBidders           int
+	MaxBidders           int // Cap on bidders called, applied to IDR or fallback selections only
 	MaxConcurrentBidders int // P0-4: Limit concurrent bidder goroutines (0 = unlimited)
+	BidderNetworkBuffer  time.Duration // Held back from tmax to collect and return bids (0 = none)
+	BidderTimeouts       map[string]time.Duration // Static per-bidder timeouts, capped by the auction deadline
+	AdaptiveTimeouts     *AdaptiveTimeoutConfig   // Per-bidder timeouts from observed latency percentiles
+	FallbackSelector     *idr.BanditConfig        // In-process bidder selection when IDR is unavailable
+	BidderCircuitBreakers map[string]*idr.CircuitBreakerConfig // Per-bidder breaker settings ("*" = default for all bidders)
 	IDREnabled           bool
 	IDRServiceURL        string
 	IDRAPIKey            string // Internal API key for IDR service-to-service calls
 	EventRecordEnabled   bool
 	EventBufferSize      int
+	EventSpool           *idr.SpoolConfig // Durable on-disk IDR event delivery (nil = in-memory only)
 	CurrencyConv         bool
 	DefaultCurrency      string
 	CurrencyConverter    *currency.Converter // Currency conversion support
@@ -149,6 +166,7 @@ func DefaultConfig() *Config {
 		DefaultTimeout:        1000 * time.Millisecond,
 		MaxBidders:            50,
 		MaxConcurrentBidders:  10, // P0-4: Limit concurrent HTTP requests per auction
+		BidderNetworkBuffer:   50 * time.Millisecond,
 		IDREnabled:            true,
 		IDRServiceURL:         "http://localhost:5050",
 		EventRecordEnabled:    true,
@@ -183,6 +201,30 @@ func validateConfig(config *Config) *Config {
 		config.MaxConcurrentBidders = defaults.MaxConcurrentBidders
 	}
 
+	// BidderNetworkBuffer must be non-negative (0 means bidders get the whole timeout)
+	if config.BidderNetworkBuffer < 0 {
+		config.BidderNetworkBuffer = defaults.BidderNetworkBuffer
+	}
+
+	// Adaptive timeouts need a usable percentile and window
+	if config.AdaptiveTimeouts == nil {
+		config.AdaptiveTimeouts = DefaultAdaptiveTimeoutConfig()
+	} else {
+		defaultAdaptive := DefaultAdaptiveTimeoutConfig()
+		if config.AdaptiveTimeouts.Percentile <= 0 || config.AdaptiveTimeouts.Percentile > 1 {
+			config.AdaptiveTimeouts.Percentile = defaultAdaptive.Percentile
+		}
+		if config.AdaptiveTimeouts.Headroom < 1 {
+			config.AdaptiveTimeouts.Headroom = defaultAdaptive.Headroom
+		}
+		if config.AdaptiveTimeouts.WindowSize <= 0 {
+			config.AdaptiveTimeouts.WindowSize = defaultAdaptive.WindowSize
+		}
+		if config.AdaptiveTimeouts.MinSamples <= 0 || config.AdaptiveTimeouts.MinSamples > config.AdaptiveTimeouts.WindowSize {
+			config.AdaptiveTimeouts.MinSamples = min(defaultAdaptive.MinSamples, config.AdaptiveTimeouts.WindowSize)
+		}
+	}
+
 	// AuctionType must be valid
 	if config.AuctionType != FirstPriceAuction && config.AuctionType != SecondPriceAuction {
 		config.AuctionType = FirstPriceAuction
@@ -251,6 +293,7 @@ func New(registry *adapters.Registry, config *Config) *Exchange {
 		eidFilter:         fpd.NewEIDFilter(fpdConfig),
 		bidderBreakers:    make(map[string]*idr.CircuitBreaker),
 		currencyConverter: config.CurrencyConverter,
+		timeouts:          newBidderTimeouts(config.AdaptiveTimeouts, config.BidderTimeouts),
 	}
 
 	// Initialize circuit breaker for each registered bidder
@@ -260,10 +303,28 @@ func New(registry *adapters.Registry, config *Config) *Exchange {
 
 	if config.IDREnabled && config.IDRServiceURL != "" {
 		ex.idrClient = idr.NewClient(config.IDRServiceURL, 50*time.Millisecond, config.IDRAPIKey)
+		ex.selector = &idrSelector{client: ex.idrClient, minimalRequest: ex.buildMinimalIDRRequest}
 	}
 
 	if config.EventRecordEnabled && config.IDRServiceURL != "" {
 		ex.eventRecorder = idr.NewEventRecorder(config.IDRServiceURL, config.EventBufferSize)
+		if config.EventSpool != nil && config.EventSpool.Dir != "" {
+			if err := ex.eventRecorder.EnableSpool(config.EventSpool, ex.reportEventSpool); err != nil {
+				logger.Log.Warn().
+					Err(err).
+					Str("dir", config.EventSpool.Dir).
+					Msg("IDR event spool unavailable, buffering events in memory")
+			}
+		}
+	}
+
+	// The bandit stands in for IDR when it's unavailable, learning from the IDR bid events
+	if config.FallbackSelector != nil && config.FallbackSelector.Enabled {
+		bandit := idr.NewBanditSelector(config.FallbackSelector)
+		ex.fallbackSelector = &banditSelector{bandit: bandit}
+		if ex.eventRecorder != nil {
+			ex.eventRecorder.SetObserver(bandit.Observe)
+		}
 	}
 
 	return ex
@@ -276,6 +337,56 @@ func (e *Exchange) SetMetrics(m MetricsRecorder) {
 	e.metrics = m
 }
 
+// reportEventSpool publishes the IDR event spool's depth and age
+func (e *Exchange) reportEventSpool(stats idr.SpoolStats) {
+	e.configMu.RLock()
+	m := e.metrics
+	e.configMu.RUnlock()
+	if m != nil {
+		m.SetIDREventSpool(stats.Events, stats.Bytes, stats.OldestAgeSeconds)
+	}
+}
+
+// SetTestHTTPClient sets the client that serves bidder calls for test=1 requests
+// (typically a mock bidder); nil sends test requests to the real bidders
+func (e *Exchange) SetTestHTTPClient(client adapters.HTTPClient) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.testClient = client
+}
+
+// bidderHTTPClient returns the client for a bidder request and whether it's the test client
+func (e *Exchange) bidderHTTPClient(req *openrtb.BidRequest) (adapters.HTTPClient, bool) {
+	if req.Test != 1 {
+		return e.httpClient, false
+	}
+	e.configMu.RLock()
+	testClient := e.testClient
+	e.configMu.RUnlock()
+	if testClient == nil {
+		return e.httpClient, false
+	}
+	return testClient, true
+}
+
+// usesTestClient reports whether an auction's bidder calls go to the test client. Mock
+// responses must not reach circuit breakers, IDR bid events or analytics.
+func (e *Exchange) usesTestClient(req *openrtb.BidRequest) bool {
+	_, testMode := e.bidderHTTPClient(req)
+	return testMode
+}
+
+// testBidderRequest copies a bidder request for the test client, tagged with the bidder code
+func testBidderRequest(reqData *adapters.RequestData, bidderCode string) *adapters.RequestData {
+	tagged := *reqData
+	tagged.Headers = reqData.Headers.Clone()
+	if tagged.Headers == nil {
+		tagged.Headers = http.Header{}
+	}
+	tagged.Headers.Set(adapters.TestBidderHeader, bidderCode)
+	return &tagged
+}
+
 // Close shuts down the exchange and flushes pending events
 func (e *Exchange) Close() error {
 	// Close circuit breakers (wait for pending callbacks)
@@ -294,24 +405,19 @@ func (e *Exchange) Close() error {
 
 // initBidderCircuitBreaker initializes a circuit breaker for a specific bidder
 func (e *Exchange) initBidderCircuitBreaker(bidderCode string) {
-	config := &idr.CircuitBreakerConfig{
-		FailureThreshold: 5,               // Open after 5 consecutive failures
-		SuccessThreshold: 2,              // Close after 2 successes in half-open
-		Timeout:          30 * time.Second, // Wait 30s before testing recovery
-		MaxConcurrent:    100,            // Max concurrent requests per bidder
-		OnStateChange: func(from, to string) {
-			logger.Log.Warn().
-				Str("bidder_code", bidderCode).
-				Str("from_state", from).
-				Str("to_state", to).
-				Msg("Bidder circuit breaker state changed")
+	config := e.bidderBreakerConfig(bidderCode)
+	config.OnStateChange = func(from, to string) {
+		logger.Log.Warn().
+			Str("bidder_code", bidderCode).
+			Str("from_state", from).
+			Str("to_state", to).
+			Msg("Bidder circuit breaker state changed")
 
-			// Record state change metrics
-			if e.metrics != nil {
-				e.metrics.SetBidderCircuitState(bidderCode, to)
-				e.metrics.RecordBidderCircuitStateChange(bidderCode, from, to)
-			}
-		},
+		// Record state change metrics
+		if e.metrics != nil {
+			e.metrics.SetBidderCircuitState(bidderCode, to)
+			e.metrics.RecordBidderCircuitStateChange(bidderCode, from, to)
+		}
 	}
 
 	e.bidderBreakersMu.Lock()
@@ -357,6 +463,7 @@ type AuctionResponse struct {
 	BidderResults map[string]*BidderResult
 	IDRResult     *idr.SelectPartnersResponse
 	DebugInfo     *DebugInfo
+	Shadow        *ShadowComparison // Set when IDR ran in shadow mode
 }
 
 // BidderResult contains results from a single bidder
@@ -364,11 +471,16 @@ type BidderResult struct {
 	BidderCode string
 	Bids       []*adapters.TypedBid
 	Currency   string // Currency of the bids (after conversion)
+	// OriginalCurrency and RateVersion are set when the bidder's bids were converted
+	OriginalCurrency string
+	RateVersion      string
 	Errors     []error
 	Latency    time.Duration
 	Selected   bool
 	Score      float64
 	TimedOut   bool // P2-2: indicates if the bidder request timed out
+	// PartialTimeout is set when some of the bidder's requests timed out but bids from the others were kept
+	PartialTimeout bool
 }
 
 // DebugInfo contains debug information
@@ -379,8 +491,31 @@ type DebugInfo struct {
 	BidderLatencies map[string]time.Duration
 	SelectedBidders []string
 	ExcludedBidders []string
+	FilteredBidders []string // Skipped because no imp had params or a supported media type for them
 	Errors          map[string][]string
-	errorsMu        sync.Mutex // Protects concurrent access to Errors map
+	errorsMu        sync.Mutex // Protects concurrent access to Errors and HTTPCalls
+	// CurrencyConversions lists every bid price conversion with the rate version that priced it
+	CurrencyConversions []CurrencyConversion
+	// Debug auctions only: outbound bidder calls, the request as sent to bidders and the stage trace
+	HTTPCalls       map[string][]HTTPCall
+	ResolvedRequest json.RawMessage
+	Trace           []TraceStage
+}
+
+// Currency conversion stages reported in CurrencyConversion.Stage
+const (
+	ConversionStageBidder   = "bidder"   // Bidder currency to auction currency
+	ConversionStageResponse = "response" // Auction currency to the requested currency
+)
+
+// CurrencyConversion records one converted bid price
+type CurrencyConversion struct {
+	BidID       string
+	BidderCode  string
+	Stage       string
+	From        string
+	To          string
+	RateVersion string
 }
 
 // AddError safely adds errors to the Errors map with mutex protection
@@ -756,6 +891,8 @@ func (e *Exchange) validateBid(bid *openrtb.Bid, bidderCode string, req *openrtb
 // If publisher has a bid_multiplier, floors are MULTIPLIED to ensure platform gets its cut
 // Example: floor=$1, multiplier=1.05 → adjusted_floor=$1.05 (DSPs must bid at least $1.05)
 func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest) map[string]float64 {
+	log := logger.FromContext(ctx)
+
 	impFloors := make(map[string]float64, len(req.Imp))
 
 	// Get publisher's bid multiplier
@@ -778,7 +915,7 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 
 		// Validate base floor is non-negative and reasonable
 		if baseFloor < 0 {
-			logger.Log.Warn().
+			log.Warn().
 				Str("impID", imp.ID).
 				Float64("base_floor", baseFloor).
 				Msg("Negative floor price detected, setting to 0")
@@ -787,7 +924,7 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 
 		// Check for NaN or Inf in base floor
 		if math.IsNaN(baseFloor) || math.IsInf(baseFloor, 0) {
-			logger.Log.Warn().
+			log.Warn().
 				Str("impID", imp.ID).
 				Float64("base_floor", baseFloor).
 				Msg("Invalid floor price (NaN/Inf), setting to 0")
@@ -800,7 +937,7 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 
 			// Check for overflow in multiplication
 			if math.IsInf(adjustedFloor, 1) {
-				logger.Log.Error().
+				log.Error().
 					Str("impID", imp.ID).
 					Float64("base_floor", baseFloor).
 					Float64("multiplier", multiplier).
@@ -811,7 +948,7 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 
 			// Validate adjusted floor is reasonable (not > $1000 CPM)
 			if adjustedFloor > maxReasonableCPM {
-				logger.Log.Warn().
+				log.Warn().
 					Str("impID", imp.ID).
 					Float64("base_floor", baseFloor).
 					Float64("multiplier", multiplier).
@@ -824,7 +961,7 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 			impFloors[imp.ID] = roundToCents(adjustedFloor)
 			floorsAdjusted++
 
-			logger.Log.Debug().
+			log.Debug().
 				Str("impID", imp.ID).
 				Float64("base_floor", baseFloor).
 				Float64("multiplier", multiplier).
@@ -853,9 +990,18 @@ func (e *Exchange) buildImpFloorMap(ctx context.Context, req *openrtb.BidRequest
 type ValidatedBid struct {
 	Bid        *adapters.TypedBid
 	BidderCode string
+	Seat       string              // Alternate bidder code the bid was made under (empty = BidderCode)
 	DemandType adapters.DemandType // platform (obfuscated) or publisher (transparent)
 }
 
+// seatCode returns the bidder code the bid is presented under
+func (vb ValidatedBid) seatCode() string {
+	if vb.Seat != "" {
+		return vb.Seat
+	}
+	return vb.BidderCode
+}
+
 // runAuctionLogic applies auction rules (first-price or second-price) to validated bids
 // Returns bids grouped by impression with prices adjusted according to auction type
 func (e *Exchange) runAuctionLogic(validBids []ValidatedBid, impFloors map[string]float64) map[string][]ValidatedBid {
@@ -1026,6 +1172,8 @@ func roundToCents(price float64) float64 {
 // Bid prices are DIVIDED by the multiplier
 // For example: multiplier = 1.05 means publisher gets ~95%, platform keeps ~5% of bid price
 func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string][]ValidatedBid) map[string][]ValidatedBid {
+	log := logger.FromContext(ctx)
+
 	// Get publisher from context (set by publisher_auth middleware)
 	pub := middleware.PublisherFromContext(ctx)
 	if pub == nil {
@@ -1065,7 +1213,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 	// Validate multiplier is in reasonable range (1.0 to 10.0)
 	if multiplier < 1.0 || multiplier > 10.0 {
-		logger.Log.Warn().
+		log.Warn().
 			Float64("multiplier", multiplier).
 			Msg("Invalid bid multiplier, ignoring")
 		return bidsByImp
@@ -1073,7 +1221,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 	// Additional validation: check for NaN or Inf in multiplier
 	if math.IsNaN(multiplier) || math.IsInf(multiplier, 0) {
-		logger.Log.Error().
+		log.Error().
 			Float64("multiplier", multiplier).
 			Msg("Invalid bid multiplier (NaN/Inf), ignoring")
 		return bidsByImp
@@ -1087,7 +1235,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 				// Validate original price before division
 				if originalPrice < 0 {
-					logger.Log.Warn().
+					log.Warn().
 						Str("impID", impID).
 						Str("bidder", bids[i].BidderCode).
 						Float64("price", originalPrice).
@@ -1097,7 +1245,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 				// Check for NaN or Inf in original price
 				if math.IsNaN(originalPrice) || math.IsInf(originalPrice, 0) {
-					logger.Log.Warn().
+					log.Warn().
 						Str("impID", impID).
 						Str("bidder", bids[i].BidderCode).
 						Float64("price", originalPrice).
@@ -1110,7 +1258,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 				// Check for underflow (price becomes too small)
 				if adjustedPrice < 0.01 && originalPrice > 0 {
-					logger.Log.Warn().
+					log.Warn().
 						Str("impID", impID).
 						Str("bidder", bids[i].BidderCode).
 						Float64("original_price", originalPrice).
@@ -1130,7 +1278,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 				// Validate adjusted price is reasonable
 				if adjustedPrice > maxReasonableCPM {
-					logger.Log.Warn().
+					log.Warn().
 						Str("impID", impID).
 						Str("bidder", bids[i].BidderCode).
 						Float64("adjusted_price", adjustedPrice).
@@ -1143,7 +1291,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 
 				// Validate platform cut is non-negative
 				if platformCut < 0 {
-					logger.Log.Warn().
+					log.Warn().
 						Str("impID", impID).
 						Str("bidder", bids[i].BidderCode).
 						Float64("original_price", originalPrice).
@@ -1165,7 +1313,7 @@ func (e *Exchange) applyBidMultiplier(ctx context.Context, bidsByImp map[string]
 				}
 
 				// Log the adjustment for transparency (debug level)
-				logger.Log.Debug().
+				log.Debug().
 					Str("impID", impID).
 					Str("bidder", bids[i].BidderCode).
 					Float64("original_price", originalPrice).
@@ -1243,8 +1391,40 @@ func extractPublisherID(v interface{}) (string, bool) {
 	return "", false
 }
 
-// RunAuction executes the auction
+// RunAuction executes the auction and reports it to the analytics module, if one is set.
+// Auctions answered by the test client aren't reported.
 func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*AuctionResponse, error) {
+	e.configMu.RLock()
+	module := e.analytics
+	recorder := e.recorder
+	e.configMu.RUnlock()
+
+	if recorder != nil && req != nil && req.BidRequest != nil && recorder.sample() {
+		recording, err := newAuctionRecording(ctx, req)
+		if err != nil {
+			logger.Log.Warn().Err(err).Msg("Failed to snapshot auction for recording")
+		} else {
+			ctx = context.WithValue(ctx, auctionRecordingKey{}, recording)
+			defer recorder.write(recording)
+		}
+	}
+
+	ctx, span := startAuctionSpan(ctx, req)
+	if module == nil || (req != nil && req.BidRequest != nil && e.usesTestClient(req.BidRequest)) {
+		response, err := e.runAuction(ctx, req, nil)
+		endAuctionSpan(span, response, err)
+		return response, err
+	}
+
+	capture := newAuctionCapture()
+	response, err := e.runAuction(ctx, req, capture)
+	endAuctionSpan(span, response, err)
+	module.LogAuctionObject(e.buildAuctionObject(ctx, req, response, err, capture))
+	return response, err
+}
+
+// runAuction executes the auction; capture is nil when analytics is disabled
+func (e *Exchange) runAuction(ctx context.Context, req *AuctionRequest, capture *auctionCapture) (*AuctionResponse, error) {
 	startTime := time.Now()
 
 	// P0-7: Validate required BidRequest fields per OpenRTB 2.x spec
@@ -1303,6 +1483,10 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		}
 	}
 
+	// Every log line from here on carries the auction ID
+	ctx = logger.WithAuctionID(ctx, req.BidRequest.ID)
+	log := logger.FromContext(ctx)
+
 	response := &AuctionResponse{
 		BidderResults: make(map[string]*BidderResult),
 		DebugInfo: &DebugInfo{
@@ -1312,12 +1496,43 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		},
 	}
 
+	// Debug auctions keep a stage trace and every outbound bidder call; traced auctions get a
+	// span per stage
+	trace := newAuctionTrace(ctx, req.Debug)
+	defer trace.finish(response.DebugInfo)
+	if req.Debug {
+		ctx = context.WithValue(ctx, debugInfoKey{}, response.DebugInfo)
+	}
+
 	// Validate the bid request per OpenRTB 2.x specification
 	if validationErr := ValidateRequest(req.BidRequest); validationErr != nil {
 		response.DebugInfo.TotalLatency = time.Since(startTime)
+		trace.stage(StageValidation, startTime, validationErr.Error())
 		return response, validationErr
 	}
 
+	// Normalize floors to the auction currency before they're used for validation or sent to bidders
+	auctionCur := e.newAuctionCurrency(req.BidRequest)
+	if capture != nil {
+		capture.currency = auctionCur.auction
+	}
+	if err := e.normalizeFloors(req.BidRequest, auctionCur); err != nil {
+		response.DebugInfo.TotalLatency = time.Since(startTime)
+		trace.stage(StageValidation, startTime, err.Error())
+		return response, err
+	}
+
+	multiBid, multiBidWarnings := parseMultiBid(req.BidRequest)
+	for _, w := range multiBidWarnings {
+		response.DebugInfo.AppendError("multibid", w)
+	}
+
+	aliases, alternateCodes, aliasWarnings := e.parseRequestAliases(req.BidRequest)
+	for _, w := range aliasWarnings {
+		response.DebugInfo.AppendError("aliases", w)
+	}
+	trace.stage(StageValidation, startTime, append(append([]string{"request valid"}, multiBidWarnings...), aliasWarnings...)...)
+
 	// Get timeout from request or config
 	// P1-NEW-1: Validate TMax bounds to prevent abuse
 	timeout := req.Timeout
@@ -1330,20 +1545,25 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		timeout = time.Duration(tmax) * time.Millisecond
 	}
 	if timeout == 0 {
+		e.configMu.RLock()
 		timeout = e.config.DefaultTimeout
+		e.configMu.RUnlock()
 	}
 
 	// Create timeout context
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Get available bidders from static registry
+	// Get available bidders from static registry, plus the request's aliases
 	availableBidders := e.registry.ListEnabledBidders()
+	availableBidders = append(availableBidders, aliases.codes()...)
 
 	// Snapshot config-protected fields under lock for consistent view during auction
 	e.configMu.RLock()
 	fpdProcessor := e.fpdProcessor
 	eidFilter := e.eidFilter
+	fallbackSelector := e.fallbackSelector
+	maxBidders := e.config.MaxBidders
 	e.configMu.RUnlock()
 
 	if len(availableBidders) == 0 {
@@ -1352,14 +1572,14 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	}
 
 	// Run IDR selection if enabled
+	selectionStart := time.Now()
 	selectedBidders := availableBidders
-	if e.idrClient != nil && e.config.IDREnabled {
+	ranked := false // Selection ordered best first, so MaxBidders can cut from the tail
+	var selectionMessages []string
+	var shadow *shadowAuction
+	if e.selector != nil && e.config.IDREnabled {
 		idrStart := time.Now()
-
-		// P1-15: Build minimal request to reduce payload size
-		minReq := e.buildMinimalIDRRequest(req.BidRequest)
-		idrResult, err := e.idrClient.SelectPartnersMinimal(ctx, minReq, availableBidders)
-
+		idrResult, err := e.selector.SelectBidders(ctx, req.BidRequest, availableBidders)
 		response.DebugInfo.IDRLatency = time.Since(idrStart)
 
 		if err == nil && idrResult != nil {
@@ -1368,22 +1588,87 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 			for _, sb := range idrResult.SelectedBidders {
 				selectedBidders = append(selectedBidders, sb.BidderCode)
 			}
+			ranked = idrResult.Mode != idr.ModeBypass
 
 			for _, eb := range idrResult.ExcludedBidders {
 				response.DebugInfo.ExcludedBidders = append(response.DebugInfo.ExcludedBidders, eb.BidderCode)
 			}
+			selectionMessages = append(selectionMessages, fmt.Sprintf("IDR selected %d of %d bidders", len(selectedBidders), len(availableBidders)))
+			if len(response.DebugInfo.ExcludedBidders) > 0 {
+				selectionMessages = append(selectionMessages, "IDR excluded: "+strings.Join(response.DebugInfo.ExcludedBidders, ", "))
+			}
+
+			// Shadow mode: call every bidder and compare with IDR's selection after the auction
+			if idrResult.Mode == idr.ModeShadow {
+				shadow = newShadowAuction(selectedBidders)
+				selectedBidders = availableBidders
+				ranked = false
+				selectionMessages = append(selectionMessages, fmt.Sprintf("IDR shadow mode, calling all %d bidders", len(availableBidders)))
+			}
+		} else if fallbackSelector != nil {
+			// IDR failed or its circuit is open: select locally rather than call every bidder
+			reason := "circuit open"
+			if err != nil {
+				reason = err.Error()
+			}
+			if fallbackResult, fallbackErr := fallbackSelector.SelectBidders(ctx, req.BidRequest, availableBidders); fallbackErr == nil && fallbackResult != nil {
+				selectedBidders = make([]string, 0, len(fallbackResult.SelectedBidders))
+				for _, sb := range fallbackResult.SelectedBidders {
+					selectedBidders = append(selectedBidders, sb.BidderCode)
+				}
+				ranked = true
+				selectionMessages = append(selectionMessages, fmt.Sprintf("IDR unavailable (%s), fallback selector chose %d of %d bidders", reason, len(selectedBidders), len(availableBidders)))
+			} else {
+				selectionMessages = append(selectionMessages, fmt.Sprintf("IDR and fallback selector unavailable, using all %d bidders", len(availableBidders)))
+			}
+		} else if err != nil {
+			selectionMessages = append(selectionMessages, fmt.Sprintf("IDR failed, using all %d bidders: %v", len(availableBidders), err))
+		}
+		// If IDR fails without a fallback selector, fall back to all bidders
+	} else {
+		selectionMessages = append(selectionMessages, fmt.Sprintf("IDR disabled, using all %d bidders", len(availableBidders)))
+	}
+
+	// Narrow each bidder to the imps it has params and capabilities for; skip bidders with none
+	bidderImps, filteredBidders := e.planBidderImps(req.BidRequest, selectedBidders, aliases)
+	if len(filteredBidders) > 0 {
+		eligible := make([]string, 0, len(bidderImps))
+		for _, code := range selectedBidders {
+			if _, ok := bidderImps[code]; ok {
+				eligible = append(eligible, code)
+			}
+		}
+		selectedBidders = eligible
+		response.DebugInfo.FilteredBidders = filteredBidders
+		selectionMessages = append(selectionMessages, "no eligible imps: "+strings.Join(filteredBidders, ", "))
+
+		log.Debug().
+			Strs("filtered_bidders", filteredBidders).
+			Msg("Bidders skipped - no eligible impressions")
+	}
+
+	// Cap the bidders called. Only IDR and fallback selections are ordered best first; without
+	// one the list is registry order plus aliases, and cutting it would drop arbitrary bidders.
+	if maxBidders > 0 && len(selectedBidders) > maxBidders {
+		if ranked {
+			selectionMessages = append(selectionMessages, fmt.Sprintf("capped at %d bidders, skipped: %s", maxBidders, strings.Join(selectedBidders[maxBidders:], ", ")))
+			selectedBidders = selectedBidders[:maxBidders]
+		} else {
+			selectionMessages = append(selectionMessages, fmt.Sprintf("%d bidders exceed the cap of %d, not capped without a ranked selection", len(selectedBidders), maxBidders))
 		}
-		// If IDR fails, fall back to all bidders
 	}
 
 	response.DebugInfo.SelectedBidders = selectedBidders
+	trace.stage(StageIDR, selectionStart, append(selectionMessages, "calling: "+strings.Join(selectedBidders, ", "))...)
 
-	logger.Log.Debug().
+	log.Debug().
 		Strs("selected_bidders", selectedBidders).
 		Int("count", len(selectedBidders)).
 		Msg("Bidders selected for auction")
 
 	// Process FPD and filter EIDs (using snapshotted processor/filter for consistency)
+	fpdStart := time.Now()
+	var fpdMessages []string
 	var bidderFPD fpd.BidderFPD
 	if fpdProcessor != nil {
 		// Filter EIDs first
@@ -1397,45 +1682,26 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 		if err != nil {
 			// Log error but continue - FPD is not critical
 			response.DebugInfo.AddError("fpd", []string{err.Error()})
+			fpdMessages = append(fpdMessages, err.Error())
+		} else {
+			fpdMessages = append(fpdMessages, fmt.Sprintf("FPD resolved for %d bidders", len(bidderFPD)))
 		}
+	} else {
+		fpdMessages = append(fpdMessages, "FPD disabled")
+	}
+	trace.stage(StageFPD, fpdStart, fpdMessages...)
+
+	if req.Debug {
+		response.DebugInfo.ResolvedRequest = resolvedRequest(req.BidRequest)
 	}
 
 	// Call bidders in parallel
-	results := e.callBiddersWithFPD(ctx, req.BidRequest, selectedBidders, timeout, bidderFPD)
+	biddersStart := time.Now()
+	results := e.callBiddersWithFPD(ctx, req.BidRequest, selectedBidders, e.bidderTimeout(ctx, timeout), bidderFPD, bidderImps, auctionCur, aliases)
 
-	// Extract request context for event recording
-	var country, deviceType, mediaType, adSize, publisherID string
-	if req.BidRequest.Device != nil && req.BidRequest.Device.Geo != nil {
-		country = req.BidRequest.Device.Geo.Country
-	}
-	if req.BidRequest.Device != nil {
-		switch req.BidRequest.Device.DeviceType {
-		case 1:
-			deviceType = "mobile"
-		case 2:
-			deviceType = "desktop"
-		case 3:
-			deviceType = "ctv"
-		default:
-			deviceType = "unknown"
-		}
-	}
-	if len(req.BidRequest.Imp) > 0 {
-		imp := req.BidRequest.Imp[0]
-		if imp.Banner != nil {
-			mediaType = "banner"
-			if imp.Banner.W > 0 && imp.Banner.H > 0 {
-				adSize = fmt.Sprintf("%dx%d", imp.Banner.W, imp.Banner.H)
-			}
-		} else if imp.Video != nil {
-			mediaType = "video"
-		} else if imp.Native != nil {
-			mediaType = "native"
-		}
-	}
-	if req.BidRequest.Site != nil && req.BidRequest.Site.Publisher != nil {
-		publisherID = req.BidRequest.Site.Publisher.ID
-	}
+	// Extract request context for event recording (the segment the fallback selector learns on)
+	segment := requestSegment(req.BidRequest)
+	mediaType, publisherID := segment.MediaType, segment.Publisher
 
 	// P1-2: Check context deadline before expensive validation work
 	// If we've already timed out, return early with whatever we have
@@ -1449,7 +1715,24 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	}
 
 	// Build impression floor map for bid validation (with multiplier applied to floors)
+	floorsStart := time.Now()
 	impFloors := e.buildImpFloorMap(ctx, req.BidRequest)
+	var idrEvents *idrAuctionEvents
+	if e.eventRecorder != nil && !e.usesTestClient(req.BidRequest) {
+		idrEvents = newIDRAuctionEvents(req.BidRequest, segment, impFloors)
+	}
+	if trace != nil {
+		var floorMessages []string
+		for _, imp := range req.BidRequest.Imp {
+			if floor := impFloors[imp.ID]; floor > 0 {
+				floorMessages = append(floorMessages, fmt.Sprintf("imp %s: floor %.4f %s", imp.ID, floor, auctionCur.auction))
+			}
+		}
+		if len(floorMessages) == 0 {
+			floorMessages = append(floorMessages, "no floors")
+		}
+		trace.stage(StageFloors, floorsStart, floorMessages...)
+	}
 
 	// Build impression map for O(1) lookups during bid validation
 	impMap := adapters.BuildImpMap(req.BidRequest.Imp)
@@ -1469,12 +1752,19 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	// Collect results
 	for bidderCode, result := range results {
 		response.BidderResults[bidderCode] = result
+		awi, _ := e.lookupBidder(bidderCode, aliases)
 		response.DebugInfo.BidderLatencies[bidderCode] = result.Latency
 
 		// Record bidder request metrics
 		if e.metrics != nil {
 			hasError := len(result.Errors) > 0
 			e.metrics.RecordBidderRequest(bidderCode, result.Latency, hasError, result.TimedOut)
+			if result.PartialTimeout {
+				e.metrics.RecordBidderPartialTimeout(bidderCode)
+			}
+		}
+		if result.Selected && req.BidRequest.Test != 1 {
+			e.timeouts.observe(bidderCode, result.Latency)
 		}
 
 		if len(result.Errors) > 0 {
@@ -1485,45 +1775,11 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 			response.DebugInfo.AddError(bidderCode, errStrs)
 		}
 
-		// Record event to IDR
-		if e.eventRecorder != nil {
-			hadBid := len(result.Bids) > 0
-			var bidCPM *float64
-			if hadBid && len(result.Bids) > 0 {
-				cpm := result.Bids[0].Bid.Price
-				bidCPM = &cpm
-			}
-			hadError := len(result.Errors) > 0
-			var errorMsg string
-			if hadError {
-				// P2-7: Aggregate all errors instead of just the first
-				if len(result.Errors) == 1 {
-					errorMsg = result.Errors[0].Error()
-				} else {
-					errMsgs := make([]string, len(result.Errors))
-					for i, err := range result.Errors {
-						errMsgs[i] = err.Error()
-					}
-					errorMsg = fmt.Sprintf("%d errors: %s", len(result.Errors), strings.Join(errMsgs, "; "))
-				}
-			}
-
-			e.eventRecorder.RecordBidResponse(
-				req.BidRequest.ID,
-				bidderCode,
-				float64(result.Latency.Milliseconds()),
-				hadBid,
-				bidCPM,
-				nil, // floor price
-				country,
-				deviceType,
-				mediaType,
-				adSize,
-				publisherID,
-				result.TimedOut, // P2-2: use actual timeout status
-				hadError,
-				errorMsg,
-			)
+		// Collect per-imp events for IDR (recorded once the auction settles)
+		if bidderImps != nil {
+			idrEvents.addBidder(req.BidRequest, result, bidderImps[bidderCode])
+		} else {
+			idrEvents.addBidder(req.BidRequest, result, nil)
 		}
 
 		// Validate and deduplicate bids
@@ -1541,7 +1797,7 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 			// Validate bid
 			if validErr := e.validateBid(tb.Bid, bidderCode, req.BidRequest, impMap, impFloors); validErr != nil {
 				// P3-1: Log bid validation failures for debugging
-				logger.Log.Debug().
+				log.Debug().
 					Str("bidder", bidderCode).
 					Str("bidID", tb.Bid.ID).
 					Str("impID", tb.Bid.ImpID).
@@ -1550,6 +1806,25 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 					Msg("bid validation failed")
 				validationErrors = append(validationErrors, validErr) //nolint:staticcheck
 				response.DebugInfo.AppendError(bidderCode, validErr.Error())
+				if isFloorRejection(validErr) {
+					trace.reject(StageFloors, bidderCode, tb.Bid, validErr.Reason)
+				} else {
+					trace.reject(StageBidders, bidderCode, tb.Bid, validErr.Reason)
+				}
+				continue
+			}
+
+			// Bids made under another seat must be allowed by ext.prebid.alternatebiddercodes
+			if tb.Seat != "" && !alternateCodes.IsValidBidderCode(bidderCode, awi.Info.AliasOf, tb.Seat) {
+				seatErr := &BidValidationError{
+					BidID:      tb.Bid.ID,
+					ImpID:      tb.Bid.ImpID,
+					BidderCode: bidderCode,
+					Reason:     fmt.Sprintf("alternate bidder code %s not allowed", tb.Seat),
+				}
+				validationErrors = append(validationErrors, seatErr) //nolint:staticcheck
+				response.DebugInfo.AppendError(bidderCode, seatErr.Error())
+				trace.reject(StageBidders, bidderCode, tb.Bid, seatErr.Reason)
 				continue
 			}
 
@@ -1563,87 +1838,139 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 				}
 				validationErrors = append(validationErrors, dupErr) //nolint:staticcheck
 				response.DebugInfo.AppendError(bidderCode, dupErr.Error())
+				trace.reject(StageBidders, bidderCode, tb.Bid, dupErr.Reason)
 				continue
 			}
 			seenBidIDs[tb.Bid.ID] = struct{}{}
 
 			// Add to valid bids with demand type
+			demandType := e.getDemandType(bidderCode)
+			if _, ok := aliases[bidderCode]; ok {
+				demandType = awi.Info.DemandType
+			}
 			validBids = append(validBids, ValidatedBid{
 				Bid:        tb,
 				BidderCode: bidderCode,
-				DemandType: e.getDemandType(bidderCode),
+				Seat:       tb.Seat,
+				DemandType: demandType,
 			})
+			if result.OriginalCurrency != "" {
+				response.DebugInfo.CurrencyConversions = append(response.DebugInfo.CurrencyConversions, CurrencyConversion{
+					BidID:       tb.Bid.ID,
+					BidderCode:  bidderCode,
+					Stage:       ConversionStageBidder,
+					From:        result.OriginalCurrency,
+					To:          result.Currency,
+					RateVersion: result.RateVersion,
+				})
+			}
 		}
 	}
 
+	if trace != nil {
+		trace.stage(StageBidders, biddersStart, bidderTraceMessages(results)...)
+	}
+
+	if capture != nil {
+		capture.recordValidBids(validBids, impFloors)
+		capture.recordBidderConversions(results)
+	}
+
 	// Apply auction logic (first-price or second-price)
+	auctionStart := time.Now()
+	pricesBeforeAuction := trace.prices(validBids)
+	if shadow != nil {
+		shadow.snapshotBids(validBids)
+	}
 	auctionedBids := e.runAuctionLogic(validBids, impFloors)
+	trace.traceAuction(auctionStart, e.config.AuctionType, validBids, pricesBeforeAuction, auctionedBids)
+	if shadow != nil {
+		response.Shadow = e.compareShadowAuction(shadow, selectedBidders, auctionedBids, impFloors)
+		e.recordShadowComparison(req.BidRequest.ID, publisherID, response.Shadow)
+	}
+	if capture != nil {
+		capture.recordGrossPrices(auctionedBids)
+	}
 
 	// Apply bid multiplier if publisher is configured with one
+	multiplierStart := time.Now()
+	pricesBeforeMultiplier := trace.impPrices(auctionedBids)
 	auctionedBids = e.applyBidMultiplier(ctx, auctionedBids)
+	trace.traceMultiplier(multiplierStart, pricesBeforeMultiplier, auctionedBids)
+	idrEvents.settle(auctionedBids)
+	e.recordIDREvents(idrEvents)
+	if capture != nil {
+		capture.recordClearingPrices(auctionedBids)
+	}
+
+	// Convert prices to the currency the publisher asked for before building targeting
+	responseCur, rateVersion, err := e.convertAuctionedBids(auctionedBids, auctionCur)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("from", auctionCur.auction).
+			Str("to", auctionCur.target).
+			Msg("failed to convert response currency, returning auction currency")
+		response.DebugInfo.AppendError("currency", err.Error())
+	} else if responseCur != auctionCur.auction {
+		for _, bids := range auctionedBids {
+			for _, vb := range bids {
+				response.DebugInfo.CurrencyConversions = append(response.DebugInfo.CurrencyConversions, CurrencyConversion{
+					BidID:       vb.Bid.Bid.ID,
+					BidderCode:  vb.BidderCode,
+					Stage:       ConversionStageResponse,
+					From:        auctionCur.auction,
+					To:          responseCur,
+					RateVersion: rateVersion,
+				})
+			}
+		}
+		if capture != nil {
+			capture.responseRateVersion = rateVersion
+		}
+	}
 
 	// Build seat bids with demand type obfuscation:
 	// - Platform demand: aggregated into single "thenexusengine" seat (highest bid per impression)
 	// - Publisher demand: shown transparently with original bidder codes
+	// ext.prebid.multibid raises (or, for publisher seats, caps) the bids each seat returns per
+	// impression; bids ranked below first carry <prefix><rank> targeting keys.
 	seatBidMap := make(map[string]*openrtb.SeatBid)
 
 	for _, impBids := range auctionedBids {
-		// Separate platform and publisher bids for this impression
-		var platformBids []ValidatedBid
-		var publisherBids []ValidatedBid
+		// Bids are already sorted by price, so each seat's bids are ranked in order
+		seatRanks := make(map[string]int)
 
 		for _, vb := range impBids {
-			if vb.DemandType == adapters.DemandTypePublisher {
-				publisherBids = append(publisherBids, vb)
-			} else {
-				// Default to platform for obfuscation
-				platformBids = append(platformBids, vb)
-			}
-		}
-
-		// Add highest platform bid to "thenexusengine" seat (obfuscated)
-		if len(platformBids) > 0 {
-			// Find highest CPM platform bid for this impression
-			highestPlatformBid := platformBids[0]
-			for _, vb := range platformBids[1:] {
-				if vb.Bid.Bid.Price > highestPlatformBid.Bid.Bid.Price {
-					highestPlatformBid = vb
-				}
-			}
-
-			// Get or create the thenexusengine seat
-			nexusSeat, ok := seatBidMap[adapters.PlatformSeatName]
-			if !ok {
-				nexusSeat = &openrtb.SeatBid{
-					Seat: adapters.PlatformSeatName,
-					Bid:  []openrtb.Bid{},
-				}
-				seatBidMap[adapters.PlatformSeatName] = nexusSeat
+			// Platform demand is obfuscated under the "thenexusengine" seat
+			seat := vb.seatCode()
+			if vb.DemandType != adapters.DemandTypePublisher {
+				seat = adapters.PlatformSeatName
 			}
 
-			// Create obfuscated bid with "thenexusengine" branding in targeting
-			bid := *highestPlatformBid.Bid.Bid
-			bidExt := e.buildBidExtension(highestPlatformBid)
-			if extBytes, err := json.Marshal(bidExt); err == nil {
-				bid.Ext = extBytes
+			limit := seatBidLimit(seat, vb.DemandType, multiBid)
+			if limit >= 0 && seatRanks[seat] >= limit {
+				trace.reject(StageAuction, vb.BidderCode, vb.Bid.Bid, fmt.Sprintf("seat %s bid limit %d reached", seat, limit))
+				continue
 			}
-			nexusSeat.Bid = append(nexusSeat.Bid, bid)
-		}
+			seatRanks[seat]++
+			rank := seatRanks[seat]
 
-		// Add all publisher bids transparently
-		for _, vb := range publisherBids {
-			sb, ok := seatBidMap[vb.BidderCode]
+			sb, ok := seatBidMap[seat]
 			if !ok {
 				sb = &openrtb.SeatBid{
-					Seat: vb.BidderCode,
+					Seat: seat,
 					Bid:  []openrtb.Bid{},
 				}
-				seatBidMap[vb.BidderCode] = sb
+				seatBidMap[seat] = sb
 			}
 
 			// Create bid copy with Prebid extension for targeting
 			bid := *vb.Bid.Bid
 			bidExt := e.buildBidExtension(vb)
+			if targetCode, ok := rankedTargetingCode(seat, rank, multiBid); ok && rank > 1 {
+				bidExt = e.buildRankedBidExtension(vb, targetCode)
+			}
 			if extBytes, err := json.Marshal(bidExt); err == nil {
 				bid.Ext = extBytes
 			}
@@ -1661,7 +1988,7 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	response.BidResponse = &openrtb.BidResponse{
 		ID:      req.BidRequest.ID,
 		SeatBid: allBids,
-		Cur:     e.config.DefaultCurrency,
+		Cur:     responseCur,
 	}
 
 	response.DebugInfo.TotalLatency = time.Since(startTime)
@@ -1671,7 +1998,7 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 	for _, sb := range allBids {
 		totalBids += len(sb.Bid)
 	}
-	logger.Log.Debug().
+	log.Debug().
 		Str("requestID", req.BidRequest.ID).
 		Int("bidders", len(selectedBidders)).
 		Int("impressions", len(req.BidRequest.Imp)).
@@ -1700,26 +2027,40 @@ func (e *Exchange) RunAuction(ctx context.Context, req *AuctionRequest) (*Auctio
 // callBiddersWithFPD calls all selected bidders in parallel with FPD support
 // P0-1: Uses sync.Map for thread-safe result collection
 // P0-4: Uses semaphore to limit concurrent bidder goroutines
-func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidRequest, bidders []string, timeout time.Duration, bidderFPD fpd.BidderFPD) map[string]*BidderResult {
+// bidderImps narrows each bidder to its eligible imps (nil sends every imp to every bidder)
+// cur supplies the auction currency and rates (nil resolves them from the request)
+// aliases resolves bidders declared in ext.prebid.aliases
+func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidRequest, bidders []string, timeout time.Duration, bidderFPD fpd.BidderFPD, bidderImps map[string][]bidderImp, cur *auctionCurrency, aliases requestAliases) map[string]*BidderResult {
+	log := logger.FromContext(ctx)
+
+	if cur == nil {
+		cur = e.newAuctionCurrency(req)
+	}
+
 	var results sync.Map // P0-1: Thread-safe map for concurrent writes
 	var wg sync.WaitGroup
 
+	// Mock responses say nothing about the real bidders, so their breakers aren't updated
+	testMode := e.usesTestClient(req)
+
 	// P0-4: Create semaphore to limit concurrent bidder calls (0 = unlimited)
 	maxConcurrent := e.config.MaxConcurrentBidders
 	var sem chan struct{}
 	if maxConcurrent > 0 {
 		sem = make(chan struct{}, maxConcurrent)
+		// Extra requests from a bidder that splits its requests share the same limit
+		ctx = context.WithValue(ctx, requestSlotsKey{}, sem)
 	}
 	// If maxConcurrent <= 0, sem remains nil (unlimited concurrency)
 
 	for _, bidderCode := range bidders {
-		logger.Log.Debug().
+		log.Debug().
 			Str("bidder", bidderCode).
 			Msg("Processing bidder in auction")
 
 		// Check circuit breaker before calling bidder
-		breaker := e.getBidderCircuitBreaker(bidderCode)
-		if breaker != nil && breaker.IsOpen() {
+		breaker := e.bidderCircuitBreaker(bidderCode, aliases)
+		if breaker != nil && !breaker.Allow() {
 			// Circuit breaker is open - skip this bidder
 			result := &BidderResult{
 				BidderCode: bidderCode,
@@ -1733,15 +2074,15 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 				e.metrics.RecordBidderCircuitRejected(bidderCode)
 			}
 
-			logger.Log.Debug().
+			log.Debug().
 				Str("bidder_code", bidderCode).
 				Msg("Skipping bidder - circuit breaker OPEN")
 
 			continue // Don't launch goroutine
 		}
 
-		// Try static registry first
-		adapterWithInfo, ok := e.registry.Get(bidderCode)
+		// Try static registry first, then the request's aliases
+		adapterWithInfo, ok := e.lookupBidder(bidderCode, aliases)
 		if ok {
 			wg.Add(1)
 			go func(code string, awi adapters.AdapterWithInfo) {
@@ -1772,10 +2113,9 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 						regulation = middleware.DetectRegulationFromGeo(req.Device.Geo)
 					}
 
-					logger.Log.Info().
+					log.Info().
 						Str("bidder", code).
 						Int("gvl_id", gvlID).
-						Str("request_id", req.ID).
 						Str("regulation", string(regulation)).
 						Str("country", func() string {
 							if req.Device != nil && req.Device.Geo != nil {
@@ -1799,19 +2139,31 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 				}
 
 				// Clone request and apply bidder-specific FPD
-				bidderReq := e.cloneRequestWithFPD(req, code, bidderFPD)
+				bidderReq := e.cloneRequestWithFPD(req, code, bidderFPD, bidderImps[code], cur, aliases)
+
+				var adapterTimeout time.Duration
+				if tg, ok := awi.Adapter.(timeoutGetter); ok {
+					adapterTimeout = tg.GetTimeout()
+				}
+				bidderTimeout := e.timeouts.timeout(code, adapterTimeout, timeout)
 
-				result := e.callBidder(ctx, bidderReq, code, awi.Adapter, timeout)
+				result := e.callBidder(ctx, bidderReq, code, awi.Adapter, bidderTimeout, cur)
 
 				// Record result in circuit breaker
-				breaker := e.getBidderCircuitBreaker(code)
-				if breaker != nil {
+				breaker := e.bidderCircuitBreaker(code, aliases)
+				if breaker != nil && !testMode {
 					// Record request metric
 					if e.metrics != nil {
 						e.metrics.RecordBidderCircuitRequest(code)
 					}
 
-					if len(result.Errors) > 0 || result.TimedOut {
+					if result.PartialTimeout {
+						// Some requests answered in time, so the bidder is up: count it as a success
+						breaker.RecordSuccess()
+						if e.metrics != nil {
+							e.metrics.RecordBidderCircuitSuccess(code)
+						}
+					} else if len(result.Errors) > 0 || result.TimedOut {
 						breaker.RecordFailure()
 						// Record failure metric
 						if e.metrics != nil {
@@ -1847,16 +2199,23 @@ func (e *Exchange) callBiddersWithFPD(ctx context.Context, req *openrtb.BidReque
 	return finalResults
 }
 
-// cloneRequestWithFPD creates a selective copy of the request with bidder-specific FPD applied
-// and enforces USD currency for all bid requests.
+// cloneRequestWithFPD creates a selective copy of the request with bidder-specific FPD applied.
+// request.cur lists the bidder's supported currencies and floors are converted to the one
+// it's offered in (nil cur resolves the auction currency from the request).
+// When imps is non-nil only those impressions are copied, with their filtered imp.ext.
 // PERF: Only clones fields that are modified (Cur, Imp, Site/App/User if FPD applies).
 // Deep copies Device, Regs, Source to prevent cross-bidder data races.
-func (e *Exchange) cloneRequestWithFPD(req *openrtb.BidRequest, bidderCode string, bidderFPD fpd.BidderFPD) *openrtb.BidRequest {
+func (e *Exchange) cloneRequestWithFPD(req *openrtb.BidRequest, bidderCode string, bidderFPD fpd.BidderFPD, imps []bidderImp, cur *auctionCurrency, aliases requestAliases) *openrtb.BidRequest {
+	if cur == nil {
+		cur = e.newAuctionCurrency(req)
+	}
+
 	// Shallow copy of top-level struct
 	clone := *req
 
-	// Clone Cur slice (we overwrite it)
-	clone.Cur = []string{e.config.DefaultCurrency}
+	// Offer the bidder its own currencies (we overwrite Cur)
+	clone.Cur = e.bidderCurrencies(bidderCode, cur, aliases)
+	floorCur := bidderFloorCurrency(clone.Cur, cur)
 
 	// Deep copy Device to prevent adapter mutations from affecting other bidders
 	if req.Device != nil {
@@ -1901,36 +2260,45 @@ func (e *Exchange) cloneRequestWithFPD(req *openrtb.BidRequest, bidderCode strin
 		if impCount > limits.MaxImpressionsPerRequest {
 			impCount = limits.MaxImpressionsPerRequest
 		}
-		clone.Imp = make([]openrtb.Imp, impCount)
-		for i := 0; i < impCount; i++ {
-			clone.Imp[i] = req.Imp[i] // Shallow copy of Imp struct
-			clone.Imp[i].BidFloorCur = e.config.DefaultCurrency
+		if imps == nil {
+			imps = make([]bidderImp, impCount)
+			for i := range imps {
+				imps[i] = bidderImp{index: i, ext: req.Imp[i].Ext}
+			}
+		}
+		clone.Imp = make([]openrtb.Imp, len(imps))
+		for i, bi := range imps {
+			src := &req.Imp[bi.index]
+			clone.Imp[i] = *src // Shallow copy of Imp struct
+			clone.Imp[i].Ext = bi.ext
+			e.setBidderFloor(&clone.Imp[i], floorCur, cur)
 
 			// Deep copy pointer fields to prevent data corruption (CVE-2026-XXXX)
-			if req.Imp[i].Banner != nil {
-				bannerCopy := *req.Imp[i].Banner
+			if src.Banner != nil {
+				bannerCopy := *src.Banner
 				clone.Imp[i].Banner = &bannerCopy
 			}
-			if req.Imp[i].Video != nil {
-				videoCopy := *req.Imp[i].Video
+			if src.Video != nil {
+				videoCopy := *src.Video
 				clone.Imp[i].Video = &videoCopy
 			}
-			if req.Imp[i].Audio != nil {
-				audioCopy := *req.Imp[i].Audio
+			if src.Audio != nil {
+				audioCopy := *src.Audio
 				clone.Imp[i].Audio = &audioCopy
 			}
-			if req.Imp[i].Native != nil {
-				nativeCopy := *req.Imp[i].Native
+			if src.Native != nil {
+				nativeCopy := *src.Native
 				clone.Imp[i].Native = &nativeCopy
 			}
-			if req.Imp[i].PMP != nil {
-				pmpCopy := *req.Imp[i].PMP
+			if src.PMP != nil {
+				pmpCopy := *src.PMP
 				clone.Imp[i].PMP = &pmpCopy
 			}
-			if req.Imp[i].Secure != nil {
-				secureCopy := *req.Imp[i].Secure
+			if src.Secure != nil {
+				secureCopy := *src.Secure
 				clone.Imp[i].Secure = &secureCopy
 			}
+			stripMediaTypes(&clone.Imp[i], bi.strip)
 		}
 	}
 
@@ -2159,13 +2527,24 @@ func deepCloneRequest(req *openrtb.BidRequest, limits *CloneLimits) *openrtb.Bid
 }
 
 // callBidder calls a single bidder
-func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidderCode string, adapter adapters.Adapter, timeout time.Duration) *BidderResult {
+func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidderCode string, adapter adapters.Adapter, timeout time.Duration, cur *auctionCurrency) *BidderResult {
 	start := time.Now()
 	result := &BidderResult{
 		BidderCode: bidderCode,
 		Selected:   true,
 	}
 
+	ctx, span := startBidderSpan(ctx, bidderCode)
+	defer endBidderSpan(span, result)
+
+	// Log lines carry the bidder along with the request and auction IDs
+	ctx = logger.WithBidder(ctx, bidderCode)
+	log := logger.FromContext(ctx)
+
+	// The bidder's sub-deadline: requests still running at the cutoff are abandoned
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Build requests
 	extraInfo := &adapters.ExtraRequestInfo{
 		BidderCoreName: bidderCode,
@@ -2180,8 +2559,7 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 	select {
 	case <-ctx.Done():
 		// P3-1: Log bidder timeout after MakeRequests
-		logger.Log.Debug().
-			Str("bidder", bidderCode).
+		log.Debug().
 			Dur("elapsed", time.Since(start)).
 			Msg("bidder timed out after MakeRequests")
 		result.Errors = append(result.Errors, ctx.Err())
@@ -2197,54 +2575,32 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 		return result
 	}
 
-	// Execute requests (could parallelize for multi-request adapters)
+	// Execute requests in parallel; bids from requests that beat the deadline are kept
+	responses := e.fetchBidderResponses(ctx, req, bidderCode, requests, timeout)
+	timedOutRequests := 0
+	// Requests answered without any transport, parse or validation error
+	succeededRequests := 0
+
 	allBids := make([]*adapters.TypedBid, 0)
-	for _, reqData := range requests {
-		// Check if context has expired before each request to avoid wasted work
-		select {
-		case <-ctx.Done():
-			result.Errors = append(result.Errors, ctx.Err())
-			result.Latency = time.Since(start)
-			result.TimedOut = true // P2-2: mark as timed out
-			return result
-		default:
-			// Context still valid, proceed with request
-		}
-
-		// Handle mock requests (e.g., demo adapter) - use request body as response
-		var resp *adapters.ResponseData
-		if reqData.Method == "MOCK" {
-			resp = &adapters.ResponseData{
-				StatusCode: 200,
-				Body:       reqData.Body,
-				Headers:    reqData.Headers,
-			}
-		} else {
-			logger.Log.Debug().
-				Str("bidder", bidderCode).
+	for i, reqData := range requests {
+		resp, err := responses[i].resp, responses[i].err
+		errsBefore := len(result.Errors)
+		if err != nil {
+			// P3-1: Log HTTP request failures with context
+			isTimeout := errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+			log.Debug().
 				Str("uri", reqData.URI).
-				Str("method", reqData.Method).
-				Msg("Making HTTP request to bidder")
-
-			var err error
-			resp, err = e.httpClient.Do(ctx, reqData, timeout)
-			if err != nil {
-				// P3-1: Log HTTP request failures with context
-				isTimeout := errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
-				logger.Log.Debug().
-					Str("bidder", bidderCode).
-					Str("uri", reqData.URI).
-					Dur("elapsed", time.Since(start)).
-					Bool("timeout", isTimeout).
-					Err(err).
-					Msg("bidder HTTP request failed")
-				result.Errors = append(result.Errors, err)
-				// P2-2: Check if this was a timeout error
-				if isTimeout {
-					result.TimedOut = true
-				}
-				continue
+				Dur("elapsed", time.Since(start)).
+				Bool("timeout", isTimeout).
+				Err(err).
+				Msg("bidder HTTP request failed")
+			result.Errors = append(result.Errors, err)
+			// P2-2: Check if this was a timeout error
+			if isTimeout {
+				result.TimedOut = true
+				timedOutRequests++
 			}
+			continue
 		}
 
 		// Log successful HTTP response for visibility
@@ -2252,8 +2608,7 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 		if len(responsePreview) > 500 {
 			responsePreview = responsePreview[:500] + "..."
 		}
-		logger.Log.Debug().
-			Str("bidder", bidderCode).
+		log.Debug().
 			Str("uri", reqData.URI).
 			Int("status_code", resp.StatusCode).
 			Int("body_size", len(resp.Body)).
@@ -2265,8 +2620,7 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 		if len(errs) > 0 {
 			// Log MakeBids errors for visibility
 			for _, err := range errs {
-				logger.Log.Debug().
-					Str("bidder", bidderCode).
+				log.Debug().
 					Err(err).
 					Msg("bidder MakeBids error")
 			}
@@ -2317,15 +2671,12 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 				}
 			}
 
-			// P1-NEW-4: Normalize exchange currency to USD if empty
-			exchangeCurrency := e.config.DefaultCurrency
-			if exchangeCurrency == "" {
-				exchangeCurrency = "USD" // Fallback if misconfigured
-			}
+			// P1-NEW-4: Bids are compared in the auction currency
+			exchangeCurrency := cur.auction
 
 			// Convert currency if needed
-			if responseCurrency != exchangeCurrency {
-				if e.currencyConverter == nil {
+			if !strings.EqualFold(responseCurrency, exchangeCurrency) {
+				if e.currencyConverter == nil && !cur.customRates {
 					// No converter available - reject bids
 					result.Errors = append(result.Errors, fmt.Errorf(
 						"currency mismatch from %s: expected %s, got %s (no converter available, bids rejected)",
@@ -2342,21 +2693,14 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 					}
 
 					originalPrice := bid.Bid.Price
-					convertedPrice, err := e.convertBidCurrency(
-						originalPrice,
-						responseCurrency,
-						exchangeCurrency,
-						nil,   // No custom rates at adapter level
-						false, // Use external rates
-					)
+					convertedPrice, rateVersion, err := cur.convert(originalPrice, responseCurrency, exchangeCurrency)
 
 					if err != nil {
 						result.Errors = append(result.Errors, fmt.Errorf(
 							"failed to convert bid %s from %s to %s: %w (bid rejected)",
 							bid.Bid.ID, responseCurrency, exchangeCurrency, err,
 						))
-						logger.Log.Debug().
-							Str("bidder", bidderCode).
+						log.Debug().
 							Str("bidID", bid.Bid.ID).
 							Str("from", responseCurrency).
 							Str("to", exchangeCurrency).
@@ -2370,9 +2714,10 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 					// Update bid price with converted value
 					bid.Bid.Price = convertedPrice
 					convertedBids = append(convertedBids, bid)
+					result.OriginalCurrency = responseCurrency
+					result.RateVersion = rateVersion
 
-					logger.Log.Debug().
-						Str("bidder", bidderCode).
+					log.Debug().
 						Str("bidID", bid.Bid.ID).
 						Str("from", responseCurrency).
 						Str("to", exchangeCurrency).
@@ -2388,14 +2733,16 @@ func (e *Exchange) callBidder(ctx context.Context, req *openrtb.BidRequest, bidd
 				allBids = append(allBids, bidderResp.Bids...)
 			}
 		}
+		if len(result.Errors) == errsBefore {
+			succeededRequests++
+		}
 	}
 
 	result.Bids = allBids
-	result.Currency = e.config.DefaultCurrency
-	if result.Currency == "" {
-		result.Currency = "USD"
-	}
+	result.Currency = cur.auction
 	result.Latency = time.Since(start)
+	// A partial timeout needs another request that actually answered; timeouts plus errors are a failure
+	result.PartialTimeout = result.TimedOut && timedOutRequests < len(requests) && succeededRequests > 0
 	return result
 }
 
@@ -2422,7 +2769,7 @@ func (e *Exchange) buildBidExtension(vb ValidatedBid) *openrtb.BidExt {
 	// Determine display bidder code based on demand type:
 	// - Platform demand: use "thenexusengine" (obfuscated)
 	// - Publisher demand: use original bidder code (transparent)
-	displayBidderCode := vb.BidderCode
+	displayBidderCode := vb.seatCode()
 	if vb.DemandType != adapters.DemandTypePublisher {
 		displayBidderCode = adapters.PlatformSeatName // "thenexusengine"
 	}
@@ -2449,13 +2796,19 @@ func (e *Exchange) buildBidExtension(vb ValidatedBid) *openrtb.BidExt {
 		targeting["hb_deal_"+displayBidderCode] = bid.DealID
 	}
 
+	meta := &openrtb.ExtBidPrebidMeta{
+		MediaType: bidType,
+	}
+	// Publisher bids made under an alternate code name the bidder that made them
+	if displayBidderCode != vb.BidderCode && vb.DemandType == adapters.DemandTypePublisher {
+		meta.AdapterCode = vb.BidderCode
+	}
+
 	return &openrtb.BidExt{
 		Prebid: &openrtb.ExtBidPrebid{
 			Type:      bidType,
 			Targeting: targeting,
-			Meta: &openrtb.ExtBidPrebidMeta{
-				MediaType: bidType,
-			},
+			Meta:      meta,
 		},
 	}
 }
@@ -2593,16 +2946,8 @@ func (e *Exchange) UpdateFPDConfig(config *fpd.Config) {
 	if config == nil {
 		return
 	}
-
-	// Create new processor and filter before acquiring lock to minimize lock hold time
-	newProcessor := fpd.NewProcessor(config)
-	newFilter := fpd.NewEIDFilter(config)
-
-	e.configMu.Lock()
-	defer e.configMu.Unlock()
-	e.config.FPD = config
-	e.fpdProcessor = newProcessor
-	e.eidFilter = newFilter
+	//nolint:errcheck // An FPD-only runtime config always validates
+	_ = e.ApplyRuntimeConfig(&RuntimeConfig{FPD: config})
 }
 
 // GetFPDConfig returns the current FPD configuration
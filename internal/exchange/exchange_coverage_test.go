@@ -965,6 +965,10 @@ func (m *mockMetricsRecorder) RecordBidderRequest(bidder string, latency time.Du
 func (m *mockMetricsRecorder) RecordMargin(publisher, bidder, mediaType string, originalPrice, adjustedPrice, platformCut float64) {
 }
 func (m *mockMetricsRecorder) RecordFloorAdjustment(publisher string)                   {}
+func (m *mockMetricsRecorder) RecordBidderPartialTimeout(bidder string)                 {}
+func (m *mockMetricsRecorder) RecordIDRShadowAuction(revenueDelta float64, lostWinners, biddersSaved int) {
+}
+func (m *mockMetricsRecorder) SetIDREventSpool(events, bytes int64, oldestAgeSeconds float64) {}
 func (m *mockMetricsRecorder) SetBidderCircuitState(bidder, state string)               {}
 func (m *mockMetricsRecorder) RecordBidderCircuitRequest(bidder string)                 {}
 func (m *mockMetricsRecorder) RecordBidderCircuitFailure(bidder string)                 {}
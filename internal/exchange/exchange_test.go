@@ -3,8 +3,12 @@ package exchange
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/thenexusengine/tne_springwire/internal/middleware"
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 	"github.com/thenexusengine/tne_springwire/internal/storage"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
 )
 
 // mockAdapter implements adapters.Adapter for testing
@@ -1920,7 +1925,7 @@ func TestSelectiveClone_OriginalNotMutated(t *testing.T) {
 	origDeviceUA := original.Device.UA
 
 	// Clone with FPD (no FPD data, so Site/App/User won't be cloned)
-	clone := ex.cloneRequestWithFPD(original, "bidder1", nil)
+	clone := ex.cloneRequestWithFPD(original, "bidder1", nil, nil, nil, nil)
 
 	// Verify clone has modified values
 	if clone.Cur[0] != "USD" {
@@ -1988,7 +1993,7 @@ func TestSelectiveClone_WithFPD(t *testing.T) {
 
 	origSitePtr := original.Site
 
-	clone := ex.cloneRequestWithFPD(original, "bidder1", fpdData)
+	clone := ex.cloneRequestWithFPD(original, "bidder1", fpdData, nil, nil, nil)
 
 	// Site should be cloned (different pointer) since FPD modifies it
 	if clone.Site == origSitePtr {
@@ -2042,7 +2047,7 @@ func BenchmarkSelectiveClone(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ex.cloneRequestWithFPD(req, "bidder1", nil)
+		ex.cloneRequestWithFPD(req, "bidder1", nil, nil, nil, nil)
 	}
 }
 
@@ -2136,9 +2141,148 @@ func (m *mockMetrics) RecordBidderRequest(bidder string, latency time.Duration,
 func (m *mockMetrics) RecordMargin(publisher, bidder, mediaType string, originalPrice, adjustedPrice, platformCut float64) {
 }
 func (m *mockMetrics) RecordFloorAdjustment(publisher string) {}
+func (m *mockMetrics) RecordBidderPartialTimeout(bidder string) {}
+func (m *mockMetrics) RecordIDRShadowAuction(revenueDelta float64, lostWinners, biddersSaved int) {
+}
+func (m *mockMetrics) SetIDREventSpool(events, bytes int64, oldestAgeSeconds float64) {}
 func (m *mockMetrics) SetBidderCircuitState(bidder, state string) {}
 func (m *mockMetrics) RecordBidderCircuitRequest(bidder string)   {}
 func (m *mockMetrics) RecordBidderCircuitFailure(bidder string)   {}
 func (m *mockMetrics) RecordBidderCircuitSuccess(bidder string)   {}
 func (m *mockMetrics) RecordBidderCircuitRejected(bidder string)  {}
 func (m *mockMetrics) RecordBidderCircuitStateChange(bidder, fromState, toState string) {}
+
+// recordingHTTPClient records bidder requests and answers with no bid
+type recordingHTTPClient struct {
+	mu       sync.Mutex
+	requests []*adapters.RequestData
+}
+
+func (c *recordingHTTPClient) Do(ctx context.Context, req *adapters.RequestData, timeout time.Duration) (*adapters.ResponseData, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	return &adapters.ResponseData{StatusCode: http.StatusNoContent}, nil
+}
+
+func TestRunAuction_TestModeUsesTestClient(t *testing.T) {
+	var realCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&realCalls, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	registry := adapters.NewRegistry()
+	mock := &mockAdapter{requests: []*adapters.RequestData{
+		{Method: http.MethodPost, URI: server.URL, Body: []byte(`{}`), Headers: http.Header{"Content-Type": []string{"application/json"}}},
+	}}
+	registry.Register("bidder1", mock, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: time.Second})
+	testClient := &recordingHTTPClient{}
+	ex.SetTestHTTPClient(testClient)
+
+	for _, test := range []int{1, 0} {
+		_, err := ex.RunAuction(context.Background(), &AuctionRequest{
+			BidRequest: &openrtb.BidRequest{
+				ID:   fmt.Sprintf("test-mode-%d", test),
+				Site: testSite(),
+				Test: test,
+				Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(testClient.requests) != 1 {
+		t.Fatalf("Expected only the test=1 request on the test client, got %d", len(testClient.requests))
+	}
+	tagged := testClient.requests[0]
+	if got := tagged.Headers.Get(adapters.TestBidderHeader); got != "bidder1" {
+		t.Errorf("Expected %s header bidder1, got %q", adapters.TestBidderHeader, got)
+	}
+	if tagged.Headers.Get("Content-Type") != "application/json" {
+		t.Error("Expected adapter headers to be kept")
+	}
+	if mock.requests[0].Headers.Get(adapters.TestBidderHeader) != "" {
+		t.Error("Expected the adapter's request to be left untouched")
+	}
+	if atomic.LoadInt32(&realCalls) != 1 {
+		t.Errorf("Expected the test=0 request to reach the real bidder, got %d calls", realCalls)
+	}
+}
+
+// failingHTTPClient fails every bidder call
+type failingHTTPClient struct{}
+
+func (failingHTTPClient) Do(ctx context.Context, req *adapters.RequestData, timeout time.Duration) (*adapters.ResponseData, error) {
+	return nil, errors.New("mock bidder error")
+}
+
+func TestRunAuction_TestModeLeavesProductionStateAlone(t *testing.T) {
+	var eventPosts int32
+	idrServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&eventPosts, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer idrServer.Close()
+	bidder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer bidder.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{requests: []*adapters.RequestData{
+		{Method: http.MethodPost, URI: bidder.URL, Body: []byte(`{}`), Headers: http.Header{}},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	newExchange := func() (*Exchange, *captureModule) {
+		config := DefaultConfig()
+		config.IDREnabled = false
+		config.EventRecordEnabled = true
+		config.IDRServiceURL = idrServer.URL
+		config.BidderCircuitBreakers = map[string]*idr.CircuitBreakerConfig{
+			"*": {FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Minute},
+		}
+		ex := New(registry, config)
+		ex.SetTestHTTPClient(failingHTTPClient{})
+		module := &captureModule{}
+		ex.SetAnalytics(module)
+		return ex, module
+	}
+	run := func(ex *Exchange, test int) {
+		_, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+			ID:   fmt.Sprintf("test-state-%d", test),
+			Site: testSite(),
+			Test: test,
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ex, module := newExchange()
+	run(ex, 1)
+	if state := ex.getBidderCircuitBreaker("bidder1").State(); state != idr.StateClosed {
+		t.Errorf("Expected mock errors to leave the breaker closed, got %s", state)
+	}
+	if len(module.auctions) != 0 {
+		t.Errorf("Expected no analytics for a test auction, got %d", len(module.auctions))
+	}
+	ex.Close()
+	if n := atomic.LoadInt32(&eventPosts); n != 0 {
+		t.Errorf("Expected no IDR events for a test auction, got %d posts", n)
+	}
+
+	// The same auction against the real bidder is recorded
+	ex, module = newExchange()
+	run(ex, 0)
+	ex.Close()
+	if len(module.auctions) != 1 || atomic.LoadInt32(&eventPosts) == 0 {
+		t.Errorf("Expected analytics and IDR events for a live auction, got %d auctions and %d posts", len(module.auctions), eventPosts)
+	}
+}
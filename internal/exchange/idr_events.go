@@ -0,0 +1,181 @@
+package exchange
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+// idrAuctionEvents collects an auction's IDR events: one bid_response per bidder and offered
+// imp, settled with the auction's outcome before they're recorded. Bid prices are changed in
+// place by the auction and bid multiplier, so bid CPMs are captured as bidder results arrive.
+// A nil *idrAuctionEvents records nothing.
+type idrAuctionEvents struct {
+	auctionID string
+	segment   idr.Segment
+	floors    map[string]float64
+	imps      map[string]*openrtb.Imp
+	events    []idr.BidEvent
+	byBid     map[*openrtb.Bid]int // Event index of each bidder's top bid on an imp
+	wins      []idr.BidEvent
+}
+
+// newIDRAuctionEvents starts collecting events for a request
+func newIDRAuctionEvents(req *openrtb.BidRequest, segment idr.Segment, impFloors map[string]float64) *idrAuctionEvents {
+	imps := make(map[string]*openrtb.Imp, len(req.Imp))
+	for i := range req.Imp {
+		imps[req.Imp[i].ID] = &req.Imp[i]
+	}
+	return &idrAuctionEvents{
+		auctionID: req.ID,
+		segment:   segment,
+		floors:    impFloors,
+		imps:      imps,
+		byBid:     make(map[*openrtb.Bid]int),
+	}
+}
+
+// addBidder adds a bid_response event for each imp the bidder was offered, carrying the
+// bidder's highest bid on the imp. offered is nil when the bidder was offered every imp.
+func (a *idrAuctionEvents) addBidder(req *openrtb.BidRequest, result *BidderResult, offered []bidderImp) {
+	if a == nil {
+		return
+	}
+
+	topBids := make(map[string]*openrtb.Bid)
+	for _, tb := range result.Bids {
+		if tb == nil || tb.Bid == nil {
+			continue
+		}
+		if top, ok := topBids[tb.Bid.ImpID]; !ok || tb.Bid.Price > top.Price {
+			topBids[tb.Bid.ImpID] = tb.Bid
+		}
+	}
+
+	imps := req.Imp
+	if offered != nil {
+		imps = make([]openrtb.Imp, 0, len(offered))
+		for _, bi := range offered {
+			imps = append(imps, req.Imp[bi.index])
+		}
+	}
+
+	hadError := len(result.Errors) > 0
+	var errorMsg string
+	if hadError {
+		errorMsg = bidderErrorMessage(result.Errors)
+	}
+	for i := range imps {
+		event := a.impEvent(&imps[i], result.BidderCode, "bid_response")
+		event.LatencyMs = float64(result.Latency.Milliseconds())
+		event.TimedOut = result.TimedOut // P2-2: use actual timeout status
+		event.HadError = hadError
+		event.ErrorMsg = errorMsg
+		if bid, ok := topBids[imps[i].ID]; ok {
+			cpm := bid.Price
+			event.HadBid = true
+			event.BidCPM = &cpm
+			event.DealID = bid.DealID
+			a.byBid[bid] = len(a.events)
+		}
+		a.events = append(a.events, event)
+	}
+}
+
+// settle marks each imp's winner and clearing price (after the auction and bid multiplier)
+// on the imp's events and adds a win event for it
+func (a *idrAuctionEvents) settle(auctionedBids map[string][]ValidatedBid) {
+	if a == nil {
+		return
+	}
+
+	clearing := make(map[string]float64, len(auctionedBids))
+	for impID, bids := range auctionedBids {
+		if len(bids) == 0 || bids[0].Bid == nil || bids[0].Bid.Bid == nil {
+			continue
+		}
+		winner := bids[0]
+		price := winner.Bid.Bid.Price
+		clearing[impID] = price
+		if i, ok := a.byBid[winner.Bid.Bid]; ok {
+			a.events[i].Won = true
+		}
+
+		if imp, ok := a.imps[impID]; ok {
+			win := a.impEvent(imp, winner.BidderCode, "win")
+			win.WinCPM = &price
+			win.ClearingPrice = &price
+			win.DealID = winner.Bid.Bid.DealID
+			a.wins = append(a.wins, win)
+		}
+	}
+
+	for i := range a.events {
+		if price, ok := clearing[a.events[i].ImpID]; ok {
+			a.events[i].ClearingPrice = &price
+		}
+	}
+}
+
+// impEvent returns an event for an imp with the request's segment
+func (a *idrAuctionEvents) impEvent(imp *openrtb.Imp, bidderCode, eventType string) idr.BidEvent {
+	event := idr.BidEvent{
+		AuctionID:   a.auctionID,
+		BidderCode:  bidderCode,
+		EventType:   eventType,
+		ImpID:       imp.ID,
+		Country:     a.segment.Country,
+		DeviceType:  a.segment.DeviceType,
+		MediaType:   impMediaType(imp),
+		AdSize:      impAdSize(imp),
+		PublisherID: a.segment.Publisher,
+	}
+	if floor := a.floors[imp.ID]; floor > 0 {
+		event.FloorPrice = &floor
+	}
+	return event
+}
+
+// recordIDREvents records an auction's settled events
+func (e *Exchange) recordIDREvents(a *idrAuctionEvents) {
+	if a == nil || e.eventRecorder == nil {
+		return
+	}
+	for _, event := range a.events {
+		e.eventRecorder.RecordEvent(event)
+	}
+	for _, event := range a.wins {
+		e.eventRecorder.RecordEvent(event)
+	}
+}
+
+// bidderErrorMessage joins a bidder's errors into one message
+func bidderErrorMessage(errs []error) string {
+	// P2-7: Aggregate all errors instead of just the first
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	errMsgs := make([]string, len(errs))
+	for i, err := range errs {
+		errMsgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(errs), strings.Join(errMsgs, "; "))
+}
+
+// impAdSize returns an imp's banner or video size as WxH
+func impAdSize(imp *openrtb.Imp) string {
+	if banner := imp.Banner; banner != nil {
+		if banner.W > 0 && banner.H > 0 {
+			return fmt.Sprintf("%dx%d", banner.W, banner.H)
+		}
+		if len(banner.Format) > 0 {
+			return fmt.Sprintf("%dx%d", banner.Format[0].W, banner.Format[0].H)
+		}
+	}
+	if video := imp.Video; video != nil && video.W > 0 && video.H > 0 {
+		return fmt.Sprintf("%dx%d", video.W, video.H)
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+func TestRunAuction_RecordsPerImpIDREvents(t *testing.T) {
+	var mu sync.Mutex
+	var events []idr.BidEvent
+	idrServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []idr.BidEvent `json:"events"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		events = append(events, body.Events...)
+		mu.Unlock()
+	}))
+	defer idrServer.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("a", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "a1", ImpID: "imp1", Price: 2.00, DealID: "deal-1", AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+		{Bid: &openrtb.Bid{ID: "a2", ImpID: "imp2", Price: 0.50, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("b", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 1.50, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{
+		DefaultTimeout:     time.Second,
+		EventRecordEnabled: true,
+		EventBufferSize:    100,
+		IDRServiceURL:      idrServer.URL,
+		AuctionType:        SecondPriceAuction,
+		PriceIncrement:     0.01,
+	})
+	_, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:  "events-1",
+		App: &openrtb.App{ID: "app-1", Publisher: &openrtb.Publisher{ID: "app-pub"}},
+		Imp: []openrtb.Imp{
+			{ID: "imp1", BidFloor: 1.00, Banner: &openrtb.Banner{W: 300, H: 250}},
+			{ID: "imp2", Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 728, H: 90}}}},
+		},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ex.Close() // Flushes buffered events
+
+	mu.Lock()
+	defer mu.Unlock()
+	byKey := make(map[string]idr.BidEvent)
+	for _, event := range events {
+		byKey[event.EventType+"/"+event.BidderCode+"/"+event.ImpID] = event
+		if event.PublisherID != "app-pub" {
+			t.Errorf("Expected the app publisher on every event, got %+v", event)
+		}
+	}
+	if len(events) != 6 {
+		t.Fatalf("Expected 4 bid responses and 2 wins, got %d: %+v", len(events), events)
+	}
+
+	aImp1 := byKey["bid_response/a/imp1"]
+	if !aImp1.HadBid || *aImp1.BidCPM != 2.00 || !aImp1.Won || *aImp1.ClearingPrice != 1.51 ||
+		*aImp1.FloorPrice != 1.00 || aImp1.DealID != "deal-1" || aImp1.AdSize != "300x250" {
+		t.Errorf("Unexpected winning bid event: %+v", aImp1)
+	}
+	bImp1 := byKey["bid_response/b/imp1"]
+	if !bImp1.HadBid || *bImp1.BidCPM != 1.50 || bImp1.Won || *bImp1.ClearingPrice != 1.51 {
+		t.Errorf("Unexpected losing bid event: %+v", bImp1)
+	}
+	bImp2 := byKey["bid_response/b/imp2"]
+	if bImp2.HadBid || bImp2.FloorPrice != nil || bImp2.AdSize != "728x90" {
+		t.Errorf("Unexpected no-bid event: %+v", bImp2)
+	}
+	win := byKey["win/a/imp1"]
+	if win.WinCPM == nil || *win.WinCPM != 1.51 || win.DealID != "deal-1" {
+		t.Errorf("Unexpected win event: %+v", win)
+	}
+	if _, ok := byKey["win/a/imp2"]; !ok {
+		t.Errorf("Expected a win event for imp2, got %+v", events)
+	}
+}
@@ -0,0 +1,224 @@
+package exchange
+
+import (
+	"encoding/json"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// bidderImp is one impression a bidder is eligible for, with imp.ext rewritten
+// so it only carries that bidder's params
+type bidderImp struct {
+	index int                // Position in the original request's Imp slice
+	ext   json.RawMessage    // Rewritten imp.ext
+	strip []adapters.BidType // Media types the bidder doesn't support
+}
+
+// impBidderExt is the parsed imp.ext with the bidder params it declares
+type impBidderExt struct {
+	raw    json.RawMessage
+	ext    map[string]json.RawMessage
+	prebid map[string]json.RawMessage
+	// prebidParams holds imp.ext.prebid.bidder, keyed by bidder code
+	prebidParams map[string]json.RawMessage
+	// params holds params from imp.ext.prebid.bidder and imp.ext.<bidder>, keyed by bidder code
+	params map[string]json.RawMessage
+}
+
+// parseImpBidderExt extracts bidder params from imp.ext. Top-level keys are only treated
+// as bidder params when isBidder reports them as a known bidder code.
+func parseImpBidderExt(raw json.RawMessage, isBidder func(string) bool) *impBidderExt {
+	parsed := &impBidderExt{raw: raw, params: make(map[string]json.RawMessage)}
+	if len(raw) == 0 {
+		return parsed
+	}
+	if err := json.Unmarshal(raw, &parsed.ext); err != nil {
+		// Leave malformed ext untouched and unfiltered
+		parsed.ext = nil
+		return parsed
+	}
+
+	if prebidRaw, ok := parsed.ext["prebid"]; ok {
+		if err := json.Unmarshal(prebidRaw, &parsed.prebid); err == nil {
+			if err := json.Unmarshal(parsed.prebid["bidder"], &parsed.prebidParams); err == nil {
+				for code, params := range parsed.prebidParams {
+					parsed.params[code] = params
+				}
+			}
+		}
+	}
+	for key, value := range parsed.ext {
+		if key != "prebid" && isBidder(key) {
+			parsed.params[key] = value
+		}
+	}
+	return parsed
+}
+
+// forBidder returns imp.ext with every other bidder's params removed
+func (p *impBidderExt) forBidder(bidderCode string, isBidder func(string) bool) (json.RawMessage, error) {
+	if p.ext == nil || len(p.params) == 0 {
+		return p.raw, nil
+	}
+
+	out := make(map[string]json.RawMessage, len(p.ext))
+	for key, value := range p.ext {
+		if key == "prebid" || (key != bidderCode && isBidder(key)) {
+			continue
+		}
+		out[key] = value
+	}
+
+	if p.prebid != nil {
+		prebid := make(map[string]json.RawMessage, len(p.prebid))
+		for key, value := range p.prebid {
+			if key != "bidder" {
+				prebid[key] = value
+			}
+		}
+		if params, ok := p.prebidParams[bidderCode]; ok {
+			bidderJSON, err := json.Marshal(map[string]json.RawMessage{bidderCode: params})
+			if err != nil {
+				return nil, err
+			}
+			prebid["bidder"] = bidderJSON
+		}
+		if len(prebid) > 0 {
+			prebidJSON, err := json.Marshal(prebid)
+			if err != nil {
+				return nil, err
+			}
+			out["prebid"] = prebidJSON
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(out)
+}
+
+// impMediaTypes returns the media types present on an impression
+func impMediaTypes(imp *openrtb.Imp) []adapters.BidType {
+	types := make([]adapters.BidType, 0, 2)
+	if imp.Banner != nil {
+		types = append(types, adapters.BidTypeBanner)
+	}
+	if imp.Video != nil {
+		types = append(types, adapters.BidTypeVideo)
+	}
+	if imp.Audio != nil {
+		types = append(types, adapters.BidTypeAudio)
+	}
+	if imp.Native != nil {
+		types = append(types, adapters.BidTypeNative)
+	}
+	return types
+}
+
+// supportedMediaTypes returns the media types the bidder declares for the request's platform.
+// A nil result with ok=true means the bidder declares no capabilities and accepts everything.
+func supportedMediaTypes(info *adapters.BidderInfo, req *openrtb.BidRequest) (types map[adapters.BidType]bool, ok bool) {
+	if info == nil || info.Capabilities == nil {
+		return nil, true
+	}
+	platform := info.Capabilities.Site
+	if req.App != nil {
+		platform = info.Capabilities.App
+	}
+	if platform == nil {
+		return nil, false
+	}
+	types = make(map[adapters.BidType]bool, len(platform.MediaTypes))
+	for _, t := range platform.MediaTypes {
+		types[t] = true
+	}
+	return types, true
+}
+
+// planBidderImps decides which impressions each bidder receives.
+//
+// An imp is offered to a bidder when the bidder supports at least one of the imp's media
+// types on the request's platform (site or app) and, if the imp declares bidder params at
+// all, it declares params for this bidder. Media types the bidder doesn't support are
+// stripped from its copy, and imp.ext only carries the bidder's own params.
+// Bidders left with no eligible imps are returned separately and must not be called.
+// Request aliases are bidders in their own right, with params under the alias code.
+func (e *Exchange) planBidderImps(req *openrtb.BidRequest, bidders []string, aliases requestAliases) (map[string][]bidderImp, []string) {
+	isBidder := func(code string) bool {
+		_, ok := e.lookupBidder(code, aliases)
+		return ok
+	}
+
+	impCount := len(req.Imp)
+	if limit := e.config.CloneLimits.MaxImpressionsPerRequest; impCount > limit {
+		impCount = limit
+	}
+	exts := make([]*impBidderExt, impCount)
+	for i := 0; i < impCount; i++ {
+		exts[i] = parseImpBidderExt(req.Imp[i].Ext, isBidder)
+	}
+
+	plan := make(map[string][]bidderImp, len(bidders))
+	var skipped []string
+	for _, code := range bidders {
+		var info *adapters.BidderInfo
+		if awi, ok := e.lookupBidder(code, aliases); ok {
+			info = &awi.Info
+		}
+		supported, platformOK := supportedMediaTypes(info, req)
+
+		var imps []bidderImp
+		for i := 0; i < impCount && platformOK; i++ {
+			parsed := exts[i]
+			if len(parsed.params) > 0 {
+				if _, ok := parsed.params[code]; !ok {
+					continue
+				}
+			}
+
+			var strip []adapters.BidType
+			mediaTypes := impMediaTypes(&req.Imp[i])
+			if supported != nil {
+				for _, t := range mediaTypes {
+					if !supported[t] {
+						strip = append(strip, t)
+					}
+				}
+				if len(mediaTypes) > 0 && len(strip) == len(mediaTypes) {
+					continue
+				}
+			}
+
+			ext, err := parsed.forBidder(code, isBidder)
+			if err != nil {
+				ext = req.Imp[i].Ext
+			}
+			imps = append(imps, bidderImp{index: i, ext: ext, strip: strip})
+		}
+
+		if len(imps) == 0 {
+			skipped = append(skipped, code)
+			continue
+		}
+		plan[code] = imps
+	}
+	return plan, skipped
+}
+
+// stripMediaTypes removes the given media type objects from an imp copy
+func stripMediaTypes(imp *openrtb.Imp, types []adapters.BidType) {
+	for _, t := range types {
+		switch t {
+		case adapters.BidTypeBanner:
+			imp.Banner = nil
+		case adapters.BidTypeVideo:
+			imp.Video = nil
+		case adapters.BidTypeAudio:
+			imp.Audio = nil
+		case adapters.BidTypeNative:
+			imp.Native = nil
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// captureAdapter records the request it was asked to build
+type captureAdapter struct {
+	mockAdapter
+	mu  sync.Mutex
+	req *openrtb.BidRequest
+}
+
+func (c *captureAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	c.mu.Lock()
+	c.req = request
+	c.mu.Unlock()
+	return c.mockAdapter.MakeRequests(request, reqInfo)
+}
+
+func (c *captureAdapter) captured() *openrtb.BidRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.req
+}
+
+func siteCaps(types ...adapters.BidType) *adapters.CapabilitiesInfo {
+	return &adapters.CapabilitiesInfo{Site: &adapters.PlatformInfo{MediaTypes: types}}
+}
+
+func TestParseImpBidderExt(t *testing.T) {
+	isBidder := func(code string) bool { return code == "rubicon" || code == "appnexus" }
+
+	parsed := parseImpBidderExt(json.RawMessage(`{
+		"prebid": {"bidder": {"rubicon": {"zoneId": 1}}, "storedrequest": {"id": "s1"}},
+		"appnexus": {"placementId": 2},
+		"gpid": "/123/slot",
+		"data": {"pbadslot": "slot"}
+	}`), isBidder)
+
+	if len(parsed.params) != 2 || parsed.params["rubicon"] == nil || parsed.params["appnexus"] == nil {
+		t.Fatalf("Expected params for rubicon and appnexus, got %v", parsed.params)
+	}
+
+	ext, err := parsed.forBidder("rubicon", isBidder)
+	if err != nil {
+		t.Fatalf("forBidder failed: %v", err)
+	}
+	var out map[string]map[string]interface{}
+	var raw map[string]json.RawMessage
+	json.Unmarshal(ext, &raw)
+	if _, ok := raw["appnexus"]; ok {
+		t.Error("Expected appnexus params to be stripped from rubicon's ext")
+	}
+	if string(raw["gpid"]) != `"/123/slot"` || raw["data"] == nil {
+		t.Errorf("Expected non-bidder fields to be kept, got %s", ext)
+	}
+	json.Unmarshal(raw["prebid"], &out)
+	if out["bidder"]["rubicon"] == nil || len(out["bidder"]) != 1 || out["storedrequest"] == nil {
+		t.Errorf("Expected prebid.bidder to only hold rubicon, got %s", raw["prebid"])
+	}
+
+	ext, _ = parsed.forBidder("appnexus", isBidder)
+	raw = nil
+	json.Unmarshal(ext, &raw)
+	if raw["appnexus"] == nil {
+		t.Errorf("Expected appnexus to keep its own params, got %s", ext)
+	}
+	out = nil
+	json.Unmarshal(raw["prebid"], &out)
+	if _, ok := out["bidder"]; ok {
+		t.Errorf("Expected rubicon params to be removed from prebid.bidder, got %s", raw["prebid"])
+	}
+
+	// No bidder params: ext passes through untouched
+	plain := json.RawMessage(`{"gpid":"/1/a"}`)
+	parsed = parseImpBidderExt(plain, isBidder)
+	if ext, _ := parsed.forBidder("rubicon", isBidder); string(ext) != string(plain) {
+		t.Errorf("Expected unchanged ext, got %s", ext)
+	}
+
+	// Malformed ext is left alone
+	parsed = parseImpBidderExt(json.RawMessage(`not json`), isBidder)
+	if len(parsed.params) != 0 {
+		t.Error("Expected no params from malformed ext")
+	}
+}
+
+func TestPlanBidderImps(t *testing.T) {
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true, Capabilities: siteCaps(adapters.BidTypeBanner, adapters.BidTypeVideo)})
+	registry.Register("appnexus", &mockAdapter{}, adapters.BidderInfo{Enabled: true, Capabilities: siteCaps(adapters.BidTypeBanner)})
+	registry.Register("videoonly", &mockAdapter{}, adapters.BidderInfo{Enabled: true, Capabilities: siteCaps(adapters.BidTypeVideo)})
+	registry.Register("anything", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, &Config{IDREnabled: false})
+
+	req := &openrtb.BidRequest{
+		ID:   "plan",
+		Site: &openrtb.Site{Domain: "example.com"},
+		Imp: []openrtb.Imp{
+			{ID: "banner-rubicon", Banner: &openrtb.Banner{W: 300, H: 250}, Ext: json.RawMessage(`{"rubicon":{"zoneId":1}}`)},
+			{ID: "banner-open", Banner: &openrtb.Banner{W: 728, H: 90}},
+			{ID: "multi", Banner: &openrtb.Banner{W: 300, H: 250}, Video: &openrtb.Video{W: 640, H: 480},
+				Ext: json.RawMessage(`{"prebid":{"bidder":{"rubicon":{"zoneId":2},"appnexus":{"placementId":3}}}}`)},
+		},
+	}
+
+	plan, skipped := ex.planBidderImps(req, []string{"rubicon", "appnexus", "videoonly", "anything"}, nil)
+
+	indexes := func(code string) []int {
+		var idx []int
+		for _, bi := range plan[code] {
+			idx = append(idx, bi.index)
+		}
+		return idx
+	}
+	if got := indexes("rubicon"); len(got) != 3 {
+		t.Errorf("Expected rubicon on all 3 imps, got %v", got)
+	}
+	if got := indexes("appnexus"); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("Expected appnexus on imps 1 and 2, got %v", got)
+	}
+	if got := plan["appnexus"][1].strip; len(got) != 1 || got[0] != adapters.BidTypeVideo {
+		t.Errorf("Expected video stripped for banner-only appnexus, got %v", got)
+	}
+	if got := indexes("anything"); len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected bidder without params only on the open imp, got %v", got)
+	}
+	if len(skipped) != 1 || skipped[0] != "videoonly" {
+		t.Errorf("Expected videoonly to be skipped, got %v", skipped)
+	}
+
+	// App requests need App capabilities
+	req.Site = nil
+	req.App = &openrtb.App{Bundle: "com.example"}
+	plan, skipped = ex.planBidderImps(req, []string{"rubicon", "anything"}, nil)
+	if len(skipped) != 1 || skipped[0] != "rubicon" || plan["anything"] == nil {
+		t.Errorf("Expected site-only rubicon to be skipped on app, got plan=%v skipped=%v", plan, skipped)
+	}
+}
+
+func TestRunAuction_FiltersImpsPerBidder(t *testing.T) {
+	registry := adapters.NewRegistry()
+	rubicon := &captureAdapter{}
+	appnexus := &captureAdapter{}
+	videoOnly := &captureAdapter{}
+	registry.Register("rubicon", rubicon, adapters.BidderInfo{Enabled: true, Capabilities: siteCaps(adapters.BidTypeBanner)})
+	registry.Register("appnexus", appnexus, adapters.BidderInfo{Enabled: true, Capabilities: siteCaps(adapters.BidTypeBanner)})
+	registry.Register("videoonly", videoOnly, adapters.BidderInfo{Enabled: true, Capabilities: siteCaps(adapters.BidTypeVideo)})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, IDREnabled: false})
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{
+		BidRequest: &openrtb.BidRequest{
+			ID:   "filter",
+			Site: &openrtb.Site{Domain: "example.com"},
+			Imp: []openrtb.Imp{
+				{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, Ext: json.RawMessage(`{"rubicon":{"zoneId":1},"appnexus":{"placementId":2}}`)},
+				{ID: "imp2", Banner: &openrtb.Banner{W: 728, H: 90}, Ext: json.RawMessage(`{"appnexus":{"placementId":3}}`)},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rreq := rubicon.captured()
+	if rreq == nil || len(rreq.Imp) != 1 || rreq.Imp[0].ID != "imp1" {
+		t.Fatalf("Expected rubicon to receive only imp1, got %+v", rreq)
+	}
+	if string(rreq.Imp[0].Ext) != `{"rubicon":{"zoneId":1}}` {
+		t.Errorf("Expected rubicon ext without appnexus params, got %s", rreq.Imp[0].Ext)
+	}
+	if areq := appnexus.captured(); areq == nil || len(areq.Imp) != 2 {
+		t.Errorf("Expected appnexus to receive both imps, got %+v", areq)
+	}
+	if videoOnly.captured() != nil {
+		t.Error("Expected bidder with no eligible imps not to be called")
+	}
+	if len(resp.DebugInfo.FilteredBidders) != 1 || resp.DebugInfo.FilteredBidders[0] != "videoonly" {
+		t.Errorf("Expected videoonly in FilteredBidders, got %v", resp.DebugInfo.FilteredBidders)
+	}
+}
@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// Bounds for ext.prebid.multibid[].maxbids (per the Prebid multibid spec)
+const (
+	minMultiBids = 1
+	maxMultiBids = 9
+)
+
+// ExtMultiBid is one entry of ext.prebid.multibid.
+// Bidder (or Bidders) names the seat as the publisher sees it: the bidder code for publisher
+// demand, or the platform seat for obfuscated platform demand.
+type ExtMultiBid struct {
+	Bidder                 string   `json:"bidder,omitempty"`
+	Bidders                []string `json:"bidders,omitempty"`
+	MaxBids                *int     `json:"maxbids,omitempty"`
+	TargetBidderCodePrefix string   `json:"targetbiddercodeprefix,omitempty"`
+}
+
+// multiBidConfig is the resolved multibid setting for one seat
+type multiBidConfig struct {
+	maxBids int
+	prefix  string // Targeting prefix for ranked bids; bids ranked 2+ use <prefix><rank>
+}
+
+// parseMultiBid reads ext.prebid.multibid into per-seat settings.
+// Invalid entries are skipped and reported as warnings; maxbids is clamped to 1..9.
+func parseMultiBid(req *openrtb.BidRequest) (map[string]multiBidConfig, []string) {
+	if len(req.Ext) == 0 {
+		return nil, nil
+	}
+	var ext RequestExt
+	if err := json.Unmarshal(req.Ext, &ext); err != nil || ext.Prebid == nil || len(ext.Prebid.MultiBid) == 0 {
+		return nil, nil
+	}
+
+	var warnings []string
+	configs := make(map[string]multiBidConfig)
+	for i, entry := range ext.Prebid.MultiBid {
+		if entry.MaxBids == nil {
+			warnings = append(warnings, fmt.Sprintf("multibid[%d]: maxbids is required, entry ignored", i))
+			continue
+		}
+		maxBids := *entry.MaxBids
+		if maxBids < minMultiBids {
+			warnings = append(warnings, fmt.Sprintf("multibid[%d]: maxbids %d raised to %d", i, maxBids, minMultiBids))
+			maxBids = minMultiBids
+		}
+		if maxBids > maxMultiBids {
+			warnings = append(warnings, fmt.Sprintf("multibid[%d]: maxbids %d capped at %d", i, maxBids, maxMultiBids))
+			maxBids = maxMultiBids
+		}
+
+		var seats []string
+		switch {
+		case entry.Bidder != "" && len(entry.Bidders) > 0:
+			warnings = append(warnings, fmt.Sprintf("multibid[%d]: bidder and bidders are mutually exclusive, bidders ignored", i))
+			seats = []string{entry.Bidder}
+		case entry.Bidder != "":
+			seats = []string{entry.Bidder}
+		case len(entry.Bidders) > 0:
+			seats = entry.Bidders
+			if entry.TargetBidderCodePrefix != "" {
+				warnings = append(warnings, fmt.Sprintf("multibid[%d]: targetbiddercodeprefix only applies to a single bidder, ignored", i))
+			}
+		default:
+			warnings = append(warnings, fmt.Sprintf("multibid[%d]: bidder or bidders is required, entry ignored", i))
+			continue
+		}
+
+		for _, seat := range seats {
+			if _, dup := configs[seat]; dup {
+				warnings = append(warnings, fmt.Sprintf("multibid[%d]: duplicate entry for %s ignored", i, seat))
+				continue
+			}
+			prefix := seat
+			if len(seats) == 1 && entry.TargetBidderCodePrefix != "" {
+				prefix = entry.TargetBidderCodePrefix
+			}
+			configs[seat] = multiBidConfig{maxBids: maxBids, prefix: prefix}
+		}
+	}
+	return configs, warnings
+}
+
+// seatBidLimit returns how many bids a seat may return per impression.
+// Without multibid, platform demand returns its single best bid and publisher demand returns every bid.
+func seatBidLimit(seat string, demandType adapters.DemandType, multiBid map[string]multiBidConfig) int {
+	if cfg, ok := multiBid[seat]; ok {
+		return cfg.maxBids
+	}
+	if demandType == adapters.DemandTypePublisher {
+		return -1
+	}
+	return 1
+}
+
+// rankedTargetingCode returns the targeting bidder code for a seat's bid at rank (1-based).
+// Rank 1 keeps the seat code; lower ranks use <prefix><rank>, e.g. hb_pb_appnexus2.
+func rankedTargetingCode(seat string, rank int, multiBid map[string]multiBidConfig) (string, bool) {
+	if rank <= 1 {
+		return seat, true
+	}
+	cfg, ok := multiBid[seat]
+	if !ok {
+		return "", false
+	}
+	return cfg.prefix + strconv.Itoa(rank), true
+}
+
+// buildRankedBidExtension builds the extension for a multibid bid ranked below first.
+// Only the bidder-specific keys are set, under the ranked code, so the winner's hb_pb,
+// hb_bidder and hb_size are not overwritten in the ad server.
+func (e *Exchange) buildRankedBidExtension(vb ValidatedBid, targetCode string) *openrtb.BidExt {
+	ext := e.buildBidExtension(vb)
+	bid := vb.Bid.Bid
+	priceBucket := formatPriceBucket(bid.Price)
+
+	targeting := map[string]string{
+		"hb_pb_" + targetCode:     priceBucket,
+		"hb_bidder_" + targetCode: targetCode,
+	}
+	if bid.W > 0 && bid.H > 0 {
+		targeting["hb_size_"+targetCode] = fmt.Sprintf("%dx%d", bid.W, bid.H)
+	}
+	if bid.DealID != "" {
+		targeting["hb_deal_"+targetCode] = bid.DealID
+	}
+
+	ext.Prebid.Targeting = targeting
+	ext.Prebid.TargetBidderCode = targetCode
+	return ext
+}
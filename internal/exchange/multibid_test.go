@@ -0,0 +1,145 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestParseMultiBid(t *testing.T) {
+	req := &openrtb.BidRequest{Ext: json.RawMessage(`{"prebid":{"multibid":[
+		{"bidder":"appnexus","maxbids":3,"targetbiddercodeprefix":"apn"},
+		{"bidders":["rubicon","pubmatic"],"maxbids":20},
+		{"bidder":"openx","maxbids":0},
+		{"bidder":"appnexus","maxbids":2},
+		{"maxbids":2},
+		{"bidder":"ix"}
+	]}}`)}
+
+	configs, warnings := parseMultiBid(req)
+
+	if cfg := configs["appnexus"]; cfg.maxBids != 3 || cfg.prefix != "apn" {
+		t.Errorf("Unexpected appnexus config: %+v", cfg)
+	}
+	if cfg := configs["rubicon"]; cfg.maxBids != maxMultiBids || cfg.prefix != "rubicon" {
+		t.Errorf("Expected rubicon capped at %d with its own prefix, got %+v", maxMultiBids, cfg)
+	}
+	if cfg := configs["pubmatic"]; cfg.maxBids != maxMultiBids {
+		t.Errorf("Unexpected pubmatic config: %+v", cfg)
+	}
+	if cfg := configs["openx"]; cfg.maxBids != minMultiBids {
+		t.Errorf("Expected openx raised to %d, got %+v", minMultiBids, cfg)
+	}
+	if _, ok := configs["ix"]; ok {
+		t.Error("Expected entry without maxbids to be ignored")
+	}
+	// maxbids 20, maxbids 0, duplicate appnexus, missing bidder, missing maxbids
+	if len(warnings) != 5 {
+		t.Errorf("Expected 5 warnings, got %d: %v", len(warnings), warnings)
+	}
+
+	if configs, warnings := parseMultiBid(&openrtb.BidRequest{}); configs != nil || warnings != nil {
+		t.Error("Expected no config without ext")
+	}
+}
+
+func multiBids(prefix, impID string, prices ...float64) []*adapters.TypedBid {
+	bids := make([]*adapters.TypedBid, 0, len(prices))
+	for i, price := range prices {
+		bids = append(bids, &adapters.TypedBid{
+			Bid:     &openrtb.Bid{ID: prefix + "-" + string(rune('a'+i)), ImpID: impID, Price: price, AdM: "<div></div>", CRID: "cr", W: 300, H: 250},
+			BidType: adapters.BidTypeBanner,
+		})
+	}
+	return bids
+}
+
+func runMultiBidAuction(t *testing.T, ext string) *AuctionResponse {
+	t.Helper()
+	registry := adapters.NewRegistry()
+	registry.Register("appnexus", &mockAdapter{bids: multiBids("apn", "imp1", 1.0, 3.0, 2.0)},
+		adapters.BidderInfo{Enabled: true, DemandType: adapters.DemandTypePublisher})
+	registry.Register("platform1", &mockAdapter{bids: multiBids("p1", "imp1", 4.0, 1.5)}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: 100 * time.Millisecond, IDREnabled: false, DefaultCurrency: "USD", AuctionType: FirstPriceAuction})
+	bidReq := &openrtb.BidRequest{
+		ID:   "multibid",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	if ext != "" {
+		bidReq.Ext = json.RawMessage(ext)
+	}
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: bidReq})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp
+}
+
+func seatBids(resp *AuctionResponse, seat string) []openrtb.Bid {
+	for _, sb := range resp.BidResponse.SeatBid {
+		if sb.Seat == seat {
+			return sb.Bid
+		}
+	}
+	return nil
+}
+
+func bidTargeting(t *testing.T, bid openrtb.Bid) *openrtb.ExtBidPrebid {
+	t.Helper()
+	var ext openrtb.BidExt
+	if err := json.Unmarshal(bid.Ext, &ext); err != nil || ext.Prebid == nil {
+		t.Fatalf("invalid bid ext %s: %v", bid.Ext, err)
+	}
+	return ext.Prebid
+}
+
+func TestRunAuction_WithoutMultiBid(t *testing.T) {
+	resp := runMultiBidAuction(t, "")
+
+	if got := seatBids(resp, adapters.PlatformSeatName); len(got) != 1 || got[0].Price != 4.0 {
+		t.Errorf("Expected the single best platform bid, got %+v", got)
+	}
+	if got := seatBids(resp, "appnexus"); len(got) != 3 {
+		t.Errorf("Expected every publisher bid, got %d", len(got))
+	}
+}
+
+func TestRunAuction_MultiBid(t *testing.T) {
+	resp := runMultiBidAuction(t, `{"prebid":{"multibid":[
+		{"bidder":"appnexus","maxbids":2,"targetbiddercodeprefix":"apn"},
+		{"bidder":"thenexusengine","maxbids":2}
+	]}}`)
+
+	apn := seatBids(resp, "appnexus")
+	if len(apn) != 2 || apn[0].Price != 3.0 || apn[1].Price != 2.0 {
+		t.Fatalf("Expected appnexus top 2 bids in price order, got %+v", apn)
+	}
+	first := bidTargeting(t, apn[0])
+	if first.Targeting["hb_pb_appnexus"] != "3.00" || first.TargetBidderCode != "" {
+		t.Errorf("Unexpected rank 1 targeting: %+v", first)
+	}
+	second := bidTargeting(t, apn[1])
+	if second.Targeting["hb_pb_apn2"] != "2.00" || second.Targeting["hb_bidder_apn2"] != "apn2" || second.Targeting["hb_size_apn2"] != "300x250" {
+		t.Errorf("Unexpected rank 2 targeting: %v", second.Targeting)
+	}
+	if _, ok := second.Targeting["hb_pb"]; ok {
+		t.Error("Ranked bids must not set hb_pb")
+	}
+	if second.TargetBidderCode != "apn2" {
+		t.Errorf("Expected targetbiddercode apn2, got %q", second.TargetBidderCode)
+	}
+
+	platform := seatBids(resp, adapters.PlatformSeatName)
+	if len(platform) != 2 || platform[0].Price != 4.0 || platform[1].Price != 1.5 {
+		t.Fatalf("Expected 2 platform bids, got %+v", platform)
+	}
+	if got := bidTargeting(t, platform[1]).Targeting; got["hb_bidder_thenexusengine2"] != "thenexusengine2" {
+		t.Errorf("Expected platform rank 2 targeting, got %v", got)
+	}
+}
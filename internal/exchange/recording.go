@@ -0,0 +1,265 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// RecordedAuction is one captured auction: the scrubbed request plus every raw
+// bidder HTTP response, enough to re-run the auction offline (see cmd/replay)
+type RecordedAuction struct {
+	ID         string              `json:"id"`
+	RecordedAt time.Time           `json:"recordedAt"`
+	Account    string              `json:"account,omitempty"`
+	TimeoutMS  int64               `json:"timeoutMs,omitempty"`
+	Debug      bool                `json:"debug,omitempty"`
+	Publisher  *RecordedPublisher  `json:"publisher,omitempty"`
+	Request    *openrtb.BidRequest `json:"request"`
+	Calls      []RecordedCall      `json:"calls,omitempty"`
+}
+
+// RecordedPublisher is the authenticated publisher of a recorded auction. Replay puts it
+// back in the context so the bid multiplier and multiplied floors are applied again.
+type RecordedPublisher struct {
+	ID            string  `json:"id"`
+	BidMultiplier float64 `json:"bidMultiplier,omitempty"`
+}
+
+// GetPublisherID returns the publisher ID
+func (p *RecordedPublisher) GetPublisherID() string { return p.ID }
+
+// GetBidMultiplier returns the publisher's bid multiplier
+func (p *RecordedPublisher) GetBidMultiplier() float64 { return p.BidMultiplier }
+
+// RecordedCall is one bidder HTTP call and its raw response (headers are not kept)
+type RecordedCall struct {
+	Bidder     string `json:"bidder"`
+	Method     string `json:"method"`
+	URI        string `json:"uri"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Body       string `json:"body,omitempty"`
+	Error      string `json:"error,omitempty"`
+	TimedOut   bool   `json:"timedOut,omitempty"`
+}
+
+// AuctionRequest rebuilds the auction request of a recorded auction
+func (r *RecordedAuction) AuctionRequest() *AuctionRequest {
+	return &AuctionRequest{
+		BidRequest: r.Request,
+		Timeout:    time.Duration(r.TimeoutMS) * time.Millisecond,
+		Account:    r.Account,
+		Debug:      r.Debug,
+	}
+}
+
+// recorderQueueSize is the number of finished recordings waiting to be written
+const recorderQueueSize = 1024
+
+// AuctionRecorder writes a sample of auctions as JSONL RecordedAuctions. Recordings are
+// encoded by a background goroutine so auctions never wait on the output.
+type AuctionRecorder struct {
+	enc        *json.Encoder
+	sampleRate float64
+	queue      chan *auctionRecording
+	done       chan struct{}
+
+	mu     sync.RWMutex // Guards closed against writes racing Close
+	closed bool
+}
+
+// NewAuctionRecorder creates a recorder writing to w; sampleRate is the share of auctions recorded (0-1).
+// Close it to write the queued recordings before closing w.
+func NewAuctionRecorder(w io.Writer, sampleRate float64) *AuctionRecorder {
+	r := &AuctionRecorder{
+		enc:        json.NewEncoder(w),
+		sampleRate: sampleRate,
+		queue:      make(chan *auctionRecording, recorderQueueSize),
+		done:       make(chan struct{}),
+	}
+	go r.writeLoop()
+	return r
+}
+
+// Close writes the queued recordings and stops the recorder
+func (r *AuctionRecorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+	<-r.done
+}
+
+// sample reports whether the next auction should be recorded
+func (r *AuctionRecorder) sample() bool {
+	return r.sampleRate >= 1 || (r.sampleRate > 0 && rand.Float64() < r.sampleRate)
+}
+
+// write queues a finished recording; it is dropped if the queue is full
+func (r *AuctionRecorder) write(rec *auctionRecording) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.queue <- rec:
+	default:
+		logger.Log.Warn().Str("request_id", rec.auction.ID).Msg("Auction recorder queue full, recording dropped")
+	}
+}
+
+// writeLoop appends queued recordings to the output
+func (r *AuctionRecorder) writeLoop() {
+	defer close(r.done)
+	for rec := range r.queue {
+		rec.mu.Lock()
+		if err := r.enc.Encode(&rec.auction); err != nil {
+			logger.Log.Warn().Err(err).Str("request_id", rec.auction.ID).Msg("Failed to write recorded auction")
+		}
+		rec.mu.Unlock()
+	}
+}
+
+// SetAuctionRecorder sets the recorder that captures sampled auctions for replay (nil disables capture)
+func (e *Exchange) SetAuctionRecorder(r *AuctionRecorder) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.recorder = r
+}
+
+// auctionRecording collects the bidder calls of one recorded auction
+type auctionRecording struct {
+	mu      sync.Mutex
+	auction RecordedAuction
+}
+
+type auctionRecordingKey struct{}
+
+// newAuctionRecording snapshots and scrubs the request before the auction modifies it
+func newAuctionRecording(ctx context.Context, req *AuctionRequest) (*auctionRecording, error) {
+	data, err := json.Marshal(req.BidRequest)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot openrtb.BidRequest
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	scrubRequest(&snapshot)
+
+	return &auctionRecording{auction: RecordedAuction{
+		ID:         snapshot.ID,
+		RecordedAt: time.Now().UTC(),
+		Account:    req.Account,
+		TimeoutMS:  req.Timeout.Milliseconds(),
+		Debug:      req.Debug,
+		Publisher:  recordedPublisher(ctx),
+		Request:    &snapshot,
+	}}, nil
+}
+
+// recordedPublisher captures the authenticated publisher, if any
+func recordedPublisher(ctx context.Context) *RecordedPublisher {
+	pub := middleware.PublisherFromContext(ctx)
+	if pub == nil {
+		return nil
+	}
+	recorded := &RecordedPublisher{}
+	if id, ok := extractPublisherID(pub); ok {
+		recorded.ID = id
+	}
+	if multiplier, ok := extractBidMultiplier(pub); ok {
+		recorded.BidMultiplier = multiplier
+	}
+	return recorded
+}
+
+// recordBidderCall adds a bidder HTTP call to the auction's recording, if any
+func recordBidderCall(ctx context.Context, bidderCode string, reqData *adapters.RequestData, resp *adapters.ResponseData, err error) {
+	rec, ok := ctx.Value(auctionRecordingKey{}).(*auctionRecording)
+	if !ok {
+		return
+	}
+
+	call := RecordedCall{Bidder: bidderCode, Method: reqData.Method, URI: reqData.URI}
+	if err != nil {
+		call.Error = err.Error()
+		call.TimedOut = errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+	} else if resp != nil {
+		call.StatusCode = resp.StatusCode
+		call.Body = string(resp.Body)
+	}
+
+	rec.mu.Lock()
+	rec.auction.Calls = append(rec.auction.Calls, call)
+	rec.mu.Unlock()
+}
+
+// scrubRequest removes personal data from a recorded request: IPs are truncated and
+// user, device and location identifiers dropped
+func scrubRequest(req *openrtb.BidRequest) {
+	if d := req.Device; d != nil {
+		if d.IP != "" {
+			d.IP = middleware.AnonymizeIP(d.IP)
+		}
+		if d.IPv6 != "" {
+			d.IPv6 = middleware.AnonymizeIP(d.IPv6)
+		}
+		d.IFA, d.IDSHA1, d.IDMD5, d.DPIDSHA1, d.DPIDMD5, d.MacSHA1, d.MacMD5 = "", "", "", "", "", "", ""
+		scrubGeo(d.Geo)
+	}
+	if u := req.User; u != nil {
+		u.ID, u.BuyerUID, u.CustomData, u.Keywords, u.Gender = "", "", "", "", ""
+		u.YOB = 0
+		u.EIDs = nil
+		u.Ext = removeExtKey(u.Ext, "eids")
+		scrubGeo(u.Geo)
+	}
+}
+
+// scrubGeo drops precise location, keeping country/region/metro for targeting
+func scrubGeo(geo *openrtb.Geo) {
+	if geo == nil {
+		return
+	}
+	geo.Lat, geo.Lon = 0, 0
+	geo.ZIP = ""
+	geo.City = ""
+}
+
+// removeExtKey returns ext without the given top-level key
+func removeExtKey(ext json.RawMessage, key string) json.RawMessage {
+	if len(ext) == 0 {
+		return ext
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &fields); err != nil {
+		return nil
+	}
+	if _, ok := fields[key]; !ok {
+		return ext
+	}
+	delete(fields, key)
+	if len(fields) == 0 {
+		return nil
+	}
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,121 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestScrubRequest(t *testing.T) {
+	req := &openrtb.BidRequest{
+		ID: "scrub",
+		Device: &openrtb.Device{
+			UA:   "Mozilla/5.0",
+			IP:   "203.0.113.42",
+			IFA:  "6d92078a-8246-4ba4-ae5b-76104861e7dc",
+			Geo:  &openrtb.Geo{Lat: 51.5072, Lon: -0.1276, Country: "GBR", City: "London", ZIP: "EC1A"},
+			IPv6: "2001:db8:85a3::8a2e:370:7334",
+		},
+		User: &openrtb.User{
+			ID:       "user-1",
+			BuyerUID: "buyer-1",
+			YOB:      1980,
+			EIDs:     []openrtb.EID{{Source: "id5-sync.com"}},
+			Ext:      json.RawMessage(`{"consent":"CPXxRfAPXxRfAAfKABENB","eids":[{"source":"x"}]}`),
+		},
+	}
+
+	scrubRequest(req)
+
+	d := req.Device
+	if d.IP != "203.0.113.0" || d.IFA != "" || d.UA == "" {
+		t.Errorf("Unexpected device after scrub: ip=%q ifa=%q ua=%q", d.IP, d.IFA, d.UA)
+	}
+	if d.IPv6 == "2001:db8:85a3::8a2e:370:7334" {
+		t.Error("Expected IPv6 to be truncated")
+	}
+	if d.Geo.Lat != 0 || d.Geo.Lon != 0 || d.Geo.ZIP != "" || d.Geo.City != "" || d.Geo.Country != "GBR" {
+		t.Errorf("Expected precise location dropped and country kept, got %+v", d.Geo)
+	}
+	u := req.User
+	if u.ID != "" || u.BuyerUID != "" || u.YOB != 0 || u.EIDs != nil {
+		t.Errorf("Expected user identifiers dropped, got %+v", u)
+	}
+	if string(u.Ext) != `{"consent":"CPXxRfAPXxRfAAfKABENB"}` {
+		t.Errorf("Expected user.ext eids removed and consent kept, got %s", u.Ext)
+	}
+}
+
+func TestRunAuction_RecordsSampledAuctions(t *testing.T) {
+	bidderBody := `{"id":"rec-1","seatbid":[{"bid":[{"id":"b1","impid":"imp1","price":1.5}]}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(bidderBody))
+	}))
+	defer server.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{requests: []*adapters.RequestData{
+		{Method: http.MethodPost, URI: server.URL, Body: []byte(`{}`)},
+	}}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, &Config{DefaultTimeout: time.Second})
+
+	var buf bytes.Buffer
+	recorder := NewAuctionRecorder(&buf, 1)
+	ex.SetAuctionRecorder(recorder)
+
+	bidReq := &openrtb.BidRequest{
+		ID:     "rec-1",
+		Site:   testSite(),
+		Device: &openrtb.Device{IP: "198.51.100.7", IFA: "ifa-1"},
+		Imp:    []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}
+	ctx := middleware.NewContextWithPublisher(context.Background(), &RecordedPublisher{ID: "pub-1", BidMultiplier: 1.25})
+	if _, err := ex.RunAuction(ctx, &AuctionRequest{BidRequest: bidReq, Account: "pub-1", Timeout: 500 * time.Millisecond}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recorder.Close()
+
+	var rec RecordedAuction
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("invalid recording %q: %v", buf.String(), err)
+	}
+	if rec.ID != "rec-1" || rec.Account != "pub-1" || rec.TimeoutMS != 500 {
+		t.Errorf("Unexpected recording header: %+v", rec)
+	}
+	if rec.Publisher == nil || rec.Publisher.ID != "pub-1" || rec.Publisher.BidMultiplier != 1.25 {
+		t.Errorf("Expected the publisher and its multiplier recorded, got %+v", rec.Publisher)
+	}
+	if rec.Request.Device.IFA != "" || rec.Request.Device.IP != "198.51.100.0" {
+		t.Errorf("Expected scrubbed device, got %+v", rec.Request.Device)
+	}
+	if bidReq.Device.IFA != "ifa-1" {
+		t.Error("Expected the live request to be left untouched")
+	}
+	if len(rec.Calls) != 1 {
+		t.Fatalf("Expected 1 recorded call, got %d", len(rec.Calls))
+	}
+	call := rec.Calls[0]
+	if call.Bidder != "bidder1" || call.URI != server.URL || call.StatusCode != http.StatusOK || call.Body != bidderBody {
+		t.Errorf("Unexpected recorded call: %+v", call)
+	}
+
+	buf.Reset()
+	recorder = NewAuctionRecorder(&buf, 0)
+	ex.SetAuctionRecorder(recorder)
+	bidReq.ID = "rec-2"
+	if _, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: bidReq}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recorder.Close()
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing recorded at sample rate 0, got %s", buf.String())
+	}
+}
@@ -0,0 +1,71 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+// BidderSelector chooses which of the available bidders an auction calls.
+// A nil response with a nil error means no selection (call every bidder).
+type BidderSelector interface {
+	SelectBidders(ctx context.Context, req *openrtb.BidRequest, available []string) (*idr.SelectPartnersResponse, error)
+}
+
+// idrSelector selects bidders with the IDR service
+type idrSelector struct {
+	client         *idr.Client
+	minimalRequest func(*openrtb.BidRequest) *idr.MinimalRequest
+}
+
+// SelectBidders sends a minimal request to IDR; it returns nil when IDR's circuit is open
+func (s *idrSelector) SelectBidders(ctx context.Context, req *openrtb.BidRequest, available []string) (*idr.SelectPartnersResponse, error) {
+	// P1-15: Build minimal request to reduce payload size
+	return s.client.SelectPartnersMinimal(ctx, s.minimalRequest(req), available)
+}
+
+// banditSelector selects bidders in-process with a bandit trained on IDR bid events
+type banditSelector struct {
+	bandit *idr.BanditSelector
+}
+
+// SelectBidders picks bidders for the request's segment
+func (s *banditSelector) SelectBidders(ctx context.Context, req *openrtb.BidRequest, available []string) (*idr.SelectPartnersResponse, error) {
+	return s.bandit.Select(requestSegment(req), available), nil
+}
+
+// requestSegment returns the segment a request's bid events are recorded under
+func requestSegment(req *openrtb.BidRequest) idr.Segment {
+	var segment idr.Segment
+	if req.Device != nil {
+		segment.DeviceType = deviceTypeName(req.Device.DeviceType)
+		if req.Device.Geo != nil {
+			segment.Country = req.Device.Geo.Country
+		}
+	}
+	if len(req.Imp) > 0 {
+		imp := req.Imp[0]
+		if imp.Banner != nil {
+			segment.MediaType = "banner"
+		} else if imp.Video != nil {
+			segment.MediaType = "video"
+		} else if imp.Native != nil {
+			segment.MediaType = "native"
+		}
+	}
+	if req.Site != nil && req.Site.Publisher != nil {
+		segment.Publisher = req.Site.Publisher.ID
+	} else if req.App != nil && req.App.Publisher != nil {
+		segment.Publisher = req.App.Publisher.ID
+	}
+	return segment
+}
+
+// SetFallbackSelector sets the selector used when the primary selector (IDR) fails or its
+// circuit is open; nil calls every bidder in that case
+func (e *Exchange) SetFallbackSelector(selector BidderSelector) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.fallbackSelector = selector
+}
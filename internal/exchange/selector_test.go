@@ -0,0 +1,67 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+func TestRunAuction_FallbackSelectorWhenIDRFails(t *testing.T) {
+	idrServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer idrServer.Close()
+
+	registry := adapters.NewRegistry()
+	for _, code := range []string{"a", "b", "c"} {
+		registry.Register(code, &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	}
+	newRequest := func() *AuctionRequest {
+		return &AuctionRequest{BidRequest: &openrtb.BidRequest{
+			ID:   "fallback-1",
+			Site: testSite(),
+			Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+		}}
+	}
+
+	fallback := idr.DefaultBanditConfig()
+	fallback.Enabled = true
+	fallback.MaxBidders = 1
+	ex := New(registry, &Config{DefaultTimeout: time.Second, IDREnabled: true, IDRServiceURL: idrServer.URL, FallbackSelector: fallback})
+	defer ex.Close()
+
+	resp, err := ex.RunAuction(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.BidderResults) != 1 || len(resp.DebugInfo.SelectedBidders) != 1 {
+		t.Errorf("Expected the fallback selector to cap the call to 1 bidder, got %v", resp.DebugInfo.SelectedBidders)
+	}
+
+	ex.SetFallbackSelector(nil)
+	resp, err = ex.RunAuction(context.Background(), newRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.BidderResults) != 3 {
+		t.Errorf("Expected every bidder called without a fallback selector, got %d", len(resp.BidderResults))
+	}
+}
+
+func TestRequestSegment(t *testing.T) {
+	req := &openrtb.BidRequest{
+		Site:   &openrtb.Site{Publisher: &openrtb.Publisher{ID: "pub-1"}},
+		Device: &openrtb.Device{DeviceType: 2, Geo: &openrtb.Geo{Country: "GBR"}},
+		Imp:    []openrtb.Imp{{ID: "imp1", Video: &openrtb.Video{}}},
+	}
+	want := idr.Segment{Publisher: "pub-1", Country: "GBR", DeviceType: "desktop", MediaType: "video"}
+	if got := requestSegment(req); got != want {
+		t.Errorf("requestSegment() = %+v, want %+v", got, want)
+	}
+}
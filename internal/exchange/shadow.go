@@ -0,0 +1,89 @@
+package exchange
+
+import "sort"
+
+// ShadowComparison compares an IDR shadow-mode auction, where every bidder was called, with the
+// counterfactual auction in which only the bidders IDR selected were called. Revenues are sums
+// of winning prices in the auction currency, before the publisher bid multiplier.
+type ShadowComparison struct {
+	SelectedBidders []string // IDR's selection
+	ActualRevenue   float64
+	ShadowRevenue   float64
+	RevenueDelta    float64  // ActualRevenue - ShadowRevenue: revenue the selection would lose
+	LostWinners     []string // Imps whose winning bidder IDR would not have called
+	BiddersSaved    int      // Bidder calls the selection would have avoided
+}
+
+// shadowAuction holds IDR's selection while a shadow-mode auction runs on every bidder
+type shadowAuction struct {
+	selection map[string]bool
+	bids      []ValidatedBid // Copies of the selected bidders' valid bids
+}
+
+// newShadowAuction returns the shadow state for IDR's selected bidders
+func newShadowAuction(selected []string) *shadowAuction {
+	selection := make(map[string]bool, len(selected))
+	for _, code := range selected {
+		selection[code] = true
+	}
+	return &shadowAuction{selection: selection}
+}
+
+// snapshotBids copies the selected bidders' valid bids, which the real auction adjusts in place
+func (s *shadowAuction) snapshotBids(validBids []ValidatedBid) {
+	for _, vb := range validBids {
+		if !s.selection[vb.BidderCode] {
+			continue
+		}
+		bid := *vb.Bid.Bid
+		typed := *vb.Bid
+		typed.Bid = &bid
+		vb.Bid = &typed
+		s.bids = append(s.bids, vb)
+	}
+}
+
+// compareShadowAuction runs the counterfactual auction on the snapshot and compares it with the real
+// auction's outcome; called lists the bidders the real auction called
+func (e *Exchange) compareShadowAuction(s *shadowAuction, called []string, auctionedBids map[string][]ValidatedBid, impFloors map[string]float64) *ShadowComparison {
+	comparison := &ShadowComparison{}
+	for code := range s.selection {
+		comparison.SelectedBidders = append(comparison.SelectedBidders, code)
+	}
+	sort.Strings(comparison.SelectedBidders)
+	for _, code := range called {
+		if !s.selection[code] {
+			comparison.BiddersSaved++
+		}
+	}
+
+	shadowBids := e.runAuctionLogic(s.bids, impFloors)
+	for impID, bids := range auctionedBids {
+		if len(bids) == 0 {
+			continue
+		}
+		winner := bids[0]
+		comparison.ActualRevenue += winner.Bid.Bid.Price
+		if !s.selection[winner.BidderCode] {
+			comparison.LostWinners = append(comparison.LostWinners, impID)
+		}
+	}
+	for _, bids := range shadowBids {
+		if len(bids) > 0 {
+			comparison.ShadowRevenue += bids[0].Bid.Bid.Price
+		}
+	}
+	sort.Strings(comparison.LostWinners)
+	comparison.RevenueDelta = roundToCents(comparison.ActualRevenue - comparison.ShadowRevenue)
+	return comparison
+}
+
+// recordShadowComparison reports a shadow comparison to metrics and the IDR event stream
+func (e *Exchange) recordShadowComparison(auctionID, publisherID string, c *ShadowComparison) {
+	if e.metrics != nil {
+		e.metrics.RecordIDRShadowAuction(c.RevenueDelta, len(c.LostWinners), c.BiddersSaved)
+	}
+	if e.eventRecorder != nil {
+		e.eventRecorder.RecordShadowAuction(auctionID, c.ActualRevenue, c.ShadowRevenue, len(c.LostWinners), c.BiddersSaved, publisherID)
+	}
+}
@@ -0,0 +1,100 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+)
+
+// shadowMetrics records shadow comparisons reported to metrics
+type shadowMetrics struct {
+	mockMetrics
+	revenueDelta float64
+	lostWinners  int
+	biddersSaved int
+}
+
+func (m *shadowMetrics) RecordIDRShadowAuction(revenueDelta float64, lostWinners, biddersSaved int) {
+	m.revenueDelta, m.lostWinners, m.biddersSaved = revenueDelta, lostWinners, biddersSaved
+}
+
+func TestRunAuction_IDRShadowMode(t *testing.T) {
+	idrServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(idr.SelectPartnersResponse{
+			SelectedBidders: []idr.SelectedBidder{{BidderCode: "chosen"}},
+			ExcludedBidders: []idr.ExcludedBidder{{BidderCode: "skipped"}},
+			Mode:            idr.ModeShadow,
+		})
+	}))
+	defer idrServer.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("chosen", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "c1", ImpID: "imp1", Price: 2.00, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+	registry.Register("skipped", &mockAdapter{bids: []*adapters.TypedBid{
+		{Bid: &openrtb.Bid{ID: "s1", ImpID: "imp1", Price: 3.00, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner},
+	}}, adapters.BidderInfo{Enabled: true})
+
+	ex := New(registry, &Config{DefaultTimeout: time.Second, IDREnabled: true, IDRServiceURL: idrServer.URL})
+	metrics := &shadowMetrics{}
+	ex.SetMetrics(metrics)
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "shadow-1",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(resp.BidderResults) != 2 {
+		t.Errorf("Expected every bidder called in shadow mode, got %d", len(resp.BidderResults))
+	}
+	if seatBids := resp.BidResponse.SeatBid; len(seatBids) != 1 || seatBids[0].Bid[0].ID != "s1" {
+		t.Errorf("Expected the auction to run on the full set, got %+v", seatBids)
+	}
+
+	shadow := resp.Shadow
+	if shadow == nil {
+		t.Fatal("Expected a shadow comparison")
+	}
+	if shadow.ActualRevenue != 3.00 || shadow.ShadowRevenue != 2.00 || shadow.RevenueDelta != 1.00 {
+		t.Errorf("Unexpected revenues: %+v", shadow)
+	}
+	if len(shadow.LostWinners) != 1 || shadow.LostWinners[0] != "imp1" || shadow.BiddersSaved != 1 {
+		t.Errorf("Unexpected lost winners or savings: %+v", shadow)
+	}
+	if metrics.revenueDelta != 1.00 || metrics.lostWinners != 1 || metrics.biddersSaved != 1 {
+		t.Errorf("Expected the comparison recorded to metrics, got %+v", metrics)
+	}
+}
+
+func TestCompareShadowAuction_SecondPrice(t *testing.T) {
+	ex := New(adapters.NewRegistry(), &Config{DefaultTimeout: time.Second, AuctionType: SecondPriceAuction, PriceIncrement: 0.01})
+	bid := func(bidder, id string, price float64) ValidatedBid {
+		return ValidatedBid{BidderCode: bidder, Bid: &adapters.TypedBid{Bid: &openrtb.Bid{ID: id, ImpID: "imp1", Price: price}}}
+	}
+	validBids := []ValidatedBid{bid("a", "a1", 5.00), bid("b", "b1", 4.00), bid("c", "c1", 1.00)}
+
+	shadow := newShadowAuction([]string{"a", "c"})
+	shadow.snapshotBids(validBids)
+	auctioned := ex.runAuctionLogic(validBids, nil)
+	comparison := ex.compareShadowAuction(shadow, []string{"a", "b", "c"}, auctioned, nil)
+
+	// a wins either way, but without b it clears at 1.01 instead of 4.01
+	if comparison.ActualRevenue != 4.01 || comparison.ShadowRevenue != 1.01 || comparison.RevenueDelta != 3.00 {
+		t.Errorf("Unexpected revenues: %+v", comparison)
+	}
+	if len(comparison.LostWinners) != 0 || comparison.BiddersSaved != 1 {
+		t.Errorf("Unexpected lost winners or savings: %+v", comparison)
+	}
+}
@@ -0,0 +1,376 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
+)
+
+// SharedBreakerStore is the Redis client as seen by shared circuit breakers (implemented by redis.Client)
+type SharedBreakerStore interface {
+	HSetFields(ctx context.Context, key string, fields map[string]interface{}) error
+	HIncrByBatch(ctx context.Context, key string, increments map[string]int64, ttl time.Duration) error
+	HGetAllBatch(ctx context.Context, keys []string) ([]map[string]string, error)
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	Del(ctx context.Context, keys ...string) error
+}
+
+// SharedBreakerConfig holds shared circuit breaker configuration
+type SharedBreakerConfig struct {
+	KeyPrefix    string        // Namespaces breaker keys in Redis
+	SyncInterval time.Duration // How often local breakers are reconciled with Redis
+	Timeout      time.Duration // Bounds one sync
+	InstanceID   string        // Probe lease owner (default hostname-pid)
+}
+
+// DefaultSharedBreakerConfig returns recommended configuration
+func DefaultSharedBreakerConfig() *SharedBreakerConfig {
+	host, _ := os.Hostname()
+	return &SharedBreakerConfig{
+		KeyPrefix:    "tne_catalyst:breaker",
+		SyncInterval: time.Second,
+		Timeout:      500 * time.Millisecond,
+		InstanceID:   fmt.Sprintf("%s-%d", host, os.Getpid()),
+	}
+}
+
+// Fields of a bidder's shared breaker hash
+const (
+	sharedFieldState    = "state"
+	sharedFieldOpenedAt = "opened_at" // Unix ms
+	sharedFieldDrained  = "drained"
+	sharedFieldFailures = "failures" // Consecutive failures across instances
+	sharedFieldRequests = "requests" // Window bucket fields
+)
+
+// SharedBreakers shares bidder circuit breaker state across replicas through Redis: failure
+// counts are pooled, one instance opening a breaker opens it everywhere, and only the holder
+// of a probe lease sends half-open probes. Auctions only consult the local breakers; a
+// background loop reconciles them with Redis, and while Redis is unavailable the local
+// breakers work on their own.
+type SharedBreakers struct {
+	ex      *Exchange
+	store   SharedBreakerStore
+	config  *SharedBreakerConfig
+	bidders map[string]*sharedBreaker
+	healthy atomic.Bool
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// sharedBreaker is one bidder's local breaker and its sync state
+type sharedBreaker struct {
+	breaker *idr.CircuitBreaker
+	config  *idr.CircuitBreakerConfig
+	probe   atomic.Bool // This instance holds the probe lease
+
+	// Sync loop only
+	leaseUntil time.Time
+	seen       breakerView // Local view after the last sync
+	successes  int64       // Local totals at the last sync
+	failures   int64
+}
+
+// breakerView is the part of a breaker's state that is shared
+type breakerView struct {
+	state   string
+	drained bool
+}
+
+// NewSharedBreakers links the exchange's bidder circuit breakers to shared state in Redis;
+// call Start to begin syncing
+func NewSharedBreakers(ex *Exchange, store SharedBreakerStore, config *SharedBreakerConfig) *SharedBreakers {
+	if config == nil {
+		config = DefaultSharedBreakerConfig()
+	}
+	s := &SharedBreakers{
+		ex:      ex,
+		store:   store,
+		config:  config,
+		bidders: make(map[string]*sharedBreaker),
+		stopCh:  make(chan struct{}),
+	}
+	s.adoptBreakers()
+	return s
+}
+
+// adoptBreakers starts sharing the exchange's breakers that aren't shared yet, so breakers
+// created after NewSharedBreakers are picked up on the next sync
+func (s *SharedBreakers) adoptBreakers() {
+	ex := s.ex
+	ex.bidderBreakersMu.RLock()
+	defer ex.bidderBreakersMu.RUnlock()
+	for code, breaker := range ex.bidderBreakers {
+		if _, ok := s.bidders[code]; ok {
+			continue
+		}
+		stats := breaker.Stats()
+		sb := &sharedBreaker{
+			breaker:   breaker,
+			config:    ex.bidderBreakerConfig(code),
+			seen:      breakerView{state: stats.State, drained: stats.Drained},
+			successes: stats.TotalSuccesses,
+			failures:  stats.TotalFailures,
+		}
+		// Without Redis the local breaker decides alone
+		breaker.SetProbeGate(func() bool { return !s.healthy.Load() || sb.probe.Load() })
+		s.bidders[code] = sb
+	}
+}
+
+// Start syncs every SyncInterval in the background
+func (s *SharedBreakers) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.SyncInterval)
+		defer ticker.Stop()
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+			s.Sync(ctx)
+			cancel()
+
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops syncing; local breakers keep working on their own
+func (s *SharedBreakers) Stop() {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	s.wg.Wait()
+	s.healthy.Store(false)
+}
+
+// Healthy reports whether the last sync reached Redis
+func (s *SharedBreakers) Healthy() bool {
+	return s.healthy.Load()
+}
+
+// Sync reconciles every bidder's local breaker with Redis. Shared state for all bidders is
+// read in one round trip; a bidder whose writes fail is retried on the next sync without
+// holding up the others. Shared state is unhealthy only when the read itself fails.
+func (s *SharedBreakers) Sync(ctx context.Context) error {
+	s.adoptBreakers()
+
+	codes := make([]string, 0, len(s.bidders))
+	keys := make([]string, 0, len(s.bidders))
+	for code := range s.bidders {
+		codes = append(codes, code)
+		keys = append(keys, s.key(code))
+	}
+
+	states, readErr := s.store.HGetAllBatch(ctx, keys)
+	if readErr != nil {
+		readErr = fmt.Errorf("failed to read shared circuit breakers: %w", readErr)
+	}
+
+	healthy := readErr == nil
+	if was := s.healthy.Swap(healthy); was != healthy {
+		if healthy {
+			logger.Log.Info().Msg("Shared circuit breaker state available")
+		} else {
+			logger.Log.Warn().Err(readErr).Msg("Shared circuit breaker state unavailable, using local breakers")
+		}
+	}
+	if !healthy {
+		return readErr
+	}
+
+	var errs []error
+	for i, code := range codes {
+		if err := s.syncBidder(ctx, code, s.bidders[code], states[i]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync circuit breaker for %s: %w", code, err))
+		}
+	}
+	if len(errs) > 0 {
+		syncErr := errors.Join(errs...)
+		logger.Log.Warn().Err(syncErr).Int("bidders", len(errs)).Msg("Shared circuit breaker sync incomplete")
+		return syncErr
+	}
+	return nil
+}
+
+// syncBidder publishes local transitions, applies remote ones, pools failure counts and
+// renews the probe lease for one bidder, starting from its shared state as read by Sync
+func (s *SharedBreakers) syncBidder(ctx context.Context, code string, sb *sharedBreaker, shared map[string]string) error {
+	var err error
+	stats := sb.breaker.Stats()
+	local := breakerView{state: stats.State, drained: stats.Drained}
+	if local != sb.seen {
+		// Failures tripped this instance's breaker, an operator acted or a probe finished.
+		// Half-open stays local: the shared state remains open while this instance probes.
+		switch local.state {
+		case idr.StateOpen, idr.StateClosed:
+			if shared, err = s.publish(ctx, code, sb, local); err != nil {
+				return err
+			}
+		}
+	} else {
+		s.applyShared(sb, shared, local)
+	}
+
+	stats = sb.breaker.Stats()
+	sb.seen = breakerView{state: stats.State, drained: stats.Drained}
+	successes, failures := stats.TotalSuccesses-sb.successes, stats.TotalFailures-sb.failures
+	sb.successes, sb.failures = stats.TotalSuccesses, stats.TotalFailures
+
+	if sharedState(shared) == idr.StateClosed {
+		if sb.seen.state != idr.StateClosed {
+			return nil
+		}
+		trip, err := s.pool(ctx, code, sb, shared, stats.Failures, successes, failures)
+		if err != nil || !trip {
+			return err
+		}
+		sb.breaker.ForceOpen()
+		sb.seen = breakerView{state: idr.StateOpen}
+		logger.Log.Warn().Str("bidder_code", code).Msg("Bidder circuit breaker opened on shared failures")
+		_, err = s.publish(ctx, code, sb, sb.seen)
+		return err
+	}
+	return s.renewProbe(ctx, code, sb, shared)
+}
+
+// applyShared moves the local breaker to a state another instance published
+func (s *SharedBreakers) applyShared(sb *sharedBreaker, shared map[string]string, local breakerView) {
+	drained := shared[sharedFieldDrained] == "1"
+	switch state := sharedState(shared); {
+	case state == idr.StateOpen && drained && !local.drained:
+		sb.breaker.Drain()
+	case state == idr.StateOpen && !drained && (local.state == idr.StateClosed || local.drained):
+		sb.breaker.ForceOpen()
+	case state == idr.StateClosed && local.state != idr.StateClosed:
+		sb.breaker.Reset()
+	}
+}
+
+// publish writes a local state to Redis and releases the probe lease
+func (s *SharedBreakers) publish(ctx context.Context, code string, sb *sharedBreaker, view breakerView) (map[string]string, error) {
+	fields := map[string]interface{}{
+		sharedFieldState:    view.state,
+		sharedFieldOpenedAt: time.Now().UnixMilli(),
+		sharedFieldDrained:  0,
+		sharedFieldFailures: 0,
+	}
+	if view.drained {
+		fields[sharedFieldDrained] = 1
+	}
+	if err := s.store.HSetFields(ctx, s.key(code), fields); err != nil {
+		return nil, err
+	}
+	if err := s.store.Del(ctx, s.key(code)+":probe"); err != nil {
+		return nil, err
+	}
+	sb.probe.Store(false)
+
+	shared := make(map[string]string, len(fields))
+	for field, value := range fields {
+		shared[field] = fmt.Sprint(value)
+	}
+	return shared, nil
+}
+
+// pool adds this instance's outcomes since the last sync to the shared counts and reports
+// whether the pooled counts trip the breaker
+func (s *SharedBreakers) pool(ctx context.Context, code string, sb *sharedBreaker, shared map[string]string, consecutive int, successes, failures int64) (bool, error) {
+	config := sb.config
+	if config.ErrorRateThreshold <= 0 {
+		// Consecutive mode: a success anywhere restarts the count
+		sharedFailures, _ := strconv.ParseInt(shared[sharedFieldFailures], 10, 64)
+		if successes > 0 {
+			sharedFailures = int64(consecutive)
+			if err := s.store.HSetFields(ctx, s.key(code), map[string]interface{}{sharedFieldFailures: consecutive}); err != nil {
+				return false, err
+			}
+		} else if failures > 0 {
+			sharedFailures += failures
+			if err := s.store.HIncrByBatch(ctx, s.key(code), map[string]int64{sharedFieldFailures: failures}, 0); err != nil {
+				return false, err
+			}
+		}
+		return sharedFailures >= int64(config.FailureThreshold), nil
+	}
+
+	// Error-rate mode: counts go into time buckets covering the window
+	width := config.Window / 10
+	if width <= 0 {
+		width = time.Millisecond
+	}
+	now := time.Now().Truncate(width)
+	if successes+failures > 0 {
+		bucket := fmt.Sprintf("%s:w:%d", s.key(code), now.UnixMilli())
+		if err := s.store.HIncrByBatch(ctx, bucket, map[string]int64{
+			sharedFieldRequests: successes + failures,
+			sharedFieldFailures: failures,
+		}, 2*config.Window); err != nil {
+			return false, err
+		}
+	}
+
+	keys := make([]string, 10)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s:w:%d", s.key(code), now.Add(-time.Duration(i)*width).UnixMilli())
+	}
+	buckets, err := s.store.HGetAllBatch(ctx, keys)
+	if err != nil {
+		return false, err
+	}
+	var requests, failed int64
+	for _, b := range buckets {
+		r, _ := strconv.ParseInt(b[sharedFieldRequests], 10, 64)
+		f, _ := strconv.ParseInt(b[sharedFieldFailures], 10, 64)
+		requests += r
+		failed += f
+	}
+	return requests >= int64(config.MinRequests) && float64(failed)/float64(requests) >= config.ErrorRateThreshold, nil
+}
+
+// renewProbe takes the probe lease for an open bidder once its timeout has passed, so that
+// only one instance sends half-open probes
+func (s *SharedBreakers) renewProbe(ctx context.Context, code string, sb *sharedBreaker, shared map[string]string) error {
+	if sb.probe.Load() && (sb.seen.state == idr.StateHalfOpen || time.Now().Before(sb.leaseUntil)) {
+		return nil // Probing under the lease; the outcome is published on the next sync
+	}
+	openedAt, _ := strconv.ParseInt(shared[sharedFieldOpenedAt], 10, 64)
+	if shared[sharedFieldDrained] == "1" || time.Since(time.UnixMilli(openedAt)) <= sb.config.Timeout {
+		sb.probe.Store(false)
+		return nil
+	}
+	ok, err := s.store.SetNX(ctx, s.key(code)+":probe", s.config.InstanceID, sb.config.Timeout)
+	if err != nil {
+		return err
+	}
+	if ok {
+		sb.leaseUntil = time.Now().Add(sb.config.Timeout)
+	}
+	sb.probe.Store(ok)
+	return nil
+}
+
+// key returns a bidder's shared breaker hash key
+func (s *SharedBreakers) key(code string) string {
+	return s.config.KeyPrefix + ":" + code
+}
+
+// sharedState returns a shared breaker's state; bidders never opened anywhere are closed
+func sharedState(shared map[string]string) string {
+	if state := shared[sharedFieldState]; state != "" {
+		return state
+	}
+	return idr.StateClosed
+}
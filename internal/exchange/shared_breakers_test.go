@@ -0,0 +1,229 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/pkg/idr"
+	"github.com/thenexusengine/tne_springwire/pkg/redis"
+)
+
+// newSharedReplica returns an exchange with one bidder whose breaker shares state through client
+func newSharedReplica(t *testing.T, client *redis.Client, instance string, breaker *idr.CircuitBreakerConfig) (*Exchange, *SharedBreakers) {
+	t.Helper()
+	registry := adapters.NewRegistry()
+	registry.Register("rubicon", &mockAdapter{}, adapters.BidderInfo{Enabled: true})
+	config := DefaultConfig()
+	config.IDREnabled = false
+	config.BidderCircuitBreakers = map[string]*idr.CircuitBreakerConfig{"*": breaker}
+	ex := New(registry, config)
+
+	sharedConfig := DefaultSharedBreakerConfig()
+	sharedConfig.InstanceID = instance
+	return ex, NewSharedBreakers(ex, client, sharedConfig)
+}
+
+func newSharedTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.New("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func syncAll(t *testing.T, replicas ...*SharedBreakers) {
+	t.Helper()
+	for _, s := range replicas {
+		if err := s.Sync(context.Background()); err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+	}
+}
+
+func TestSharedBreakers_PoolsFailuresAndOpensEverywhere(t *testing.T) {
+	_, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{FailureThreshold: 4, SuccessThreshold: 1, Timeout: time.Minute}
+	exA, a := newSharedReplica(t, client, "a", breaker)
+	exB, b := newSharedReplica(t, client, "b", breaker)
+
+	// Two failures on each replica: neither trips locally, but the pooled count does
+	for _, ex := range []*Exchange{exA, exB} {
+		ex.getBidderCircuitBreaker("rubicon").RecordFailure()
+		ex.getBidderCircuitBreaker("rubicon").RecordFailure()
+	}
+	syncAll(t, a, b)
+	if state := exB.getBidderCircuitBreaker("rubicon").State(); state != idr.StateOpen {
+		t.Fatalf("Expected pooled failures to open replica b, got %s", state)
+	}
+
+	syncAll(t, a)
+	if state := exA.getBidderCircuitBreaker("rubicon").State(); state != idr.StateOpen {
+		t.Errorf("Expected replica a opened from shared state, got %s", state)
+	}
+}
+
+func TestSharedBreakers_OperatorActionsPropagate(t *testing.T) {
+	_, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{FailureThreshold: 5, SuccessThreshold: 1, Timeout: time.Minute}
+	exA, a := newSharedReplica(t, client, "a", breaker)
+	exB, b := newSharedReplica(t, client, "b", breaker)
+	syncAll(t, a, b)
+
+	if _, _, err := exA.ControlBidderCircuitBreaker("rubicon", BreakerActionDrain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	syncAll(t, a, b)
+	if stats := exB.GetBidderCircuitBreakerStats()["rubicon"]; stats.State != idr.StateOpen || !stats.Drained {
+		t.Errorf("Expected the drain to reach replica b, got %+v", stats)
+	}
+
+	if _, _, err := exB.ControlBidderCircuitBreaker("rubicon", BreakerActionReset); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	syncAll(t, b, a)
+	if stats := exA.GetBidderCircuitBreakerStats()["rubicon"]; stats.State != idr.StateClosed || stats.Drained {
+		t.Errorf("Expected the reset to reach replica a, got %+v", stats)
+	}
+}
+
+func TestSharedBreakers_OneReplicaProbes(t *testing.T) {
+	_, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{FailureThreshold: 1, SuccessThreshold: 1, Timeout: 20 * time.Millisecond}
+	exA, a := newSharedReplica(t, client, "a", breaker)
+	exB, b := newSharedReplica(t, client, "b", breaker)
+
+	exA.getBidderCircuitBreaker("rubicon").RecordFailure()
+	syncAll(t, a, b)
+	time.Sleep(30 * time.Millisecond)
+	syncAll(t, a, b)
+
+	allowedA := exA.getBidderCircuitBreaker("rubicon").Allow()
+	allowedB := exB.getBidderCircuitBreaker("rubicon").Allow()
+	if allowedA == allowedB {
+		t.Fatalf("Expected exactly one replica to probe, got a=%v b=%v", allowedA, allowedB)
+	}
+
+	// The prober's success closes the breaker everywhere
+	prober, other, proberSync, otherSync := exA, exB, a, b
+	if allowedB {
+		prober, other, proberSync, otherSync = exB, exA, b, a
+	}
+	prober.getBidderCircuitBreaker("rubicon").RecordSuccess()
+	syncAll(t, proberSync, otherSync)
+	if state := other.getBidderCircuitBreaker("rubicon").State(); state != idr.StateClosed {
+		t.Errorf("Expected the other replica closed after a successful probe, got %s", state)
+	}
+}
+
+func TestSharedBreakers_ErrorRateMode(t *testing.T) {
+	_, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{
+		FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Minute,
+		ErrorRateThreshold: 0.5, Window: time.Minute, MinRequests: 6,
+	}
+	exA, a := newSharedReplica(t, client, "a", breaker)
+	exB, b := newSharedReplica(t, client, "b", breaker)
+
+	for _, ex := range []*Exchange{exA, exB} {
+		cb := ex.getBidderCircuitBreaker("rubicon")
+		cb.RecordFailure()
+		cb.RecordFailure()
+		cb.RecordSuccess()
+	}
+	syncAll(t, a, b)
+	if state := exB.getBidderCircuitBreaker("rubicon").State(); state != idr.StateOpen {
+		t.Errorf("Expected 4/6 pooled failures to open the breaker, got %s", state)
+	}
+}
+
+func TestSharedBreakers_FallsBackToLocalWithoutRedis(t *testing.T) {
+	mr, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{FailureThreshold: 1, SuccessThreshold: 1, Timeout: 10 * time.Millisecond}
+	ex, s := newSharedReplica(t, client, "a", breaker)
+	syncAll(t, s)
+	if !s.Healthy() {
+		t.Fatal("Expected shared state healthy")
+	}
+
+	mr.Close()
+	if err := s.Sync(context.Background()); err == nil {
+		t.Fatal("Expected sync to fail without Redis")
+	}
+	if s.Healthy() {
+		t.Error("Expected shared state unhealthy")
+	}
+
+	// The local breaker opens and recovers on its own
+	cb := ex.getBidderCircuitBreaker("rubicon")
+	cb.RecordFailure()
+	time.Sleep(20 * time.Millisecond)
+	if !cb.Allow() {
+		t.Error("Expected the local breaker to probe while Redis is down")
+	}
+}
+
+// failingWriteStore fails writes to one bidder's shared breaker
+type failingWriteStore struct {
+	*redis.Client
+	failKey string
+}
+
+func (s *failingWriteStore) HSetFields(ctx context.Context, key string, fields map[string]interface{}) error {
+	if key == s.failKey {
+		return errors.New("write failed")
+	}
+	return s.Client.HSetFields(ctx, key, fields)
+}
+
+func TestSharedBreakers_ContinuesPastBidderErrors(t *testing.T) {
+	_, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Minute}
+	exA, _ := newSharedReplica(t, client, "a", breaker)
+	exA.initBidderCircuitBreaker("appnexus")
+	exB, b := newSharedReplica(t, client, "b", breaker)
+	exB.initBidderCircuitBreaker("appnexus")
+
+	sharedConfig := DefaultSharedBreakerConfig()
+	a := NewSharedBreakers(exA, &failingWriteStore{Client: client, failKey: sharedConfig.KeyPrefix + ":rubicon"}, sharedConfig)
+	exA.getBidderCircuitBreaker("rubicon").RecordFailure()
+	exA.getBidderCircuitBreaker("appnexus").RecordFailure()
+
+	if err := a.Sync(context.Background()); err == nil || !strings.Contains(err.Error(), "rubicon") {
+		t.Fatalf("Expected the rubicon write error reported, got %v", err)
+	}
+	if !a.Healthy() {
+		t.Error("Expected shared state to stay healthy when only one bidder's write fails")
+	}
+	syncAll(t, b)
+	if state := exB.getBidderCircuitBreaker("appnexus").State(); state != idr.StateOpen {
+		t.Errorf("Expected appnexus published despite the rubicon error, got %s", state)
+	}
+}
+
+func TestSharedBreakers_SharesBreakersCreatedLater(t *testing.T) {
+	_, client := newSharedTestRedis(t)
+	breaker := &idr.CircuitBreakerConfig{FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Minute}
+	exA, a := newSharedReplica(t, client, "a", breaker)
+	exB, b := newSharedReplica(t, client, "b", breaker)
+	syncAll(t, a, b)
+
+	// A bidder added after NewSharedBreakers is shared from the next sync on
+	for _, ex := range []*Exchange{exA, exB} {
+		ex.initBidderCircuitBreaker("appnexus")
+	}
+	syncAll(t, a, b)
+	exA.getBidderCircuitBreaker("appnexus").RecordFailure()
+	syncAll(t, a, b)
+	if state := exB.getBidderCircuitBreaker("appnexus").State(); state != idr.StateOpen {
+		t.Errorf("Expected the later breaker shared across replicas, got %s", state)
+	}
+}
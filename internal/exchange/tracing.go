@@ -0,0 +1,57 @@
+package exchange
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/thenexusengine/tne_springwire/pkg/tracing"
+)
+
+// startAuctionSpan starts the span covering one auction; stage and bidder spans are its children
+func startAuctionSpan(ctx context.Context, req *AuctionRequest) (context.Context, trace.Span) {
+	ctx, span := tracing.Tracer().Start(ctx, "auction")
+	if span.IsRecording() && req != nil && req.BidRequest != nil {
+		span.SetAttributes(
+			attribute.String("auction.request_id", req.BidRequest.ID),
+			attribute.Int("auction.imps", len(req.BidRequest.Imp)),
+		)
+	}
+	return ctx, span
+}
+
+// endAuctionSpan records the auction outcome and ends its span
+func endAuctionSpan(span trace.Span, resp *AuctionResponse, err error) {
+	if span.IsRecording() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if resp != nil {
+			span.SetAttributes(attribute.Int("auction.bidders", len(resp.BidderResults)))
+		}
+	}
+	span.End()
+}
+
+// startBidderSpan starts the span covering one bidder call, parent of its HTTP request spans
+func startBidderSpan(ctx context.Context, bidderCode string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "bidder."+bidderCode,
+		trace.WithAttributes(attribute.String("bidder", bidderCode)))
+}
+
+// endBidderSpan records a bidder call's result and ends its span
+func endBidderSpan(span trace.Span, result *BidderResult) {
+	if span.IsRecording() {
+		span.SetAttributes(
+			attribute.Int("bidder.bids", len(result.Bids)),
+			attribute.Bool("bidder.timed_out", result.TimedOut),
+			attribute.Int("bidder.errors", len(result.Errors)),
+		)
+		if len(result.Errors) > 0 {
+			span.SetStatus(codes.Error, result.Errors[0].Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,83 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestRunAuction_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	registry := adapters.NewRegistry()
+	registry.Register("bidder1", &mockAdapter{
+		requests: []*adapters.RequestData{{Method: http.MethodPost, URI: server.URL + "/bid"}},
+		bids:     []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "b1", ImpID: "imp1", Price: 1.00, AdM: "<div></div>"}, BidType: adapters.BidTypeBanner}},
+	}, adapters.BidderInfo{Enabled: true})
+	ex := New(registry, &Config{DefaultTimeout: time.Second})
+
+	resp, err := ex.RunAuction(context.Background(), &AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "traced-1",
+		Site: testSite(),
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.DebugInfo.Trace != nil {
+		t.Error("Expected no debug trace for a traced auction without debug")
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	auction, ok := spans["auction"]
+	if !ok {
+		t.Fatalf("Expected an auction span, got %v", spanNames(recorder.Ended()))
+	}
+	for _, name := range []string{"auction." + StageValidation, "auction." + StageIDR, "auction." + StageBidders, "auction." + StageAuction, "bidder.bidder1"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("Expected span %s, got %v", name, spanNames(recorder.Ended()))
+			continue
+		}
+		if span.Parent().SpanID() != auction.SpanContext().SpanID() {
+			t.Errorf("Expected %s to be a child of the auction span", name)
+		}
+	}
+	if req, ok := spans["bidder POST /bid"]; !ok || req.Parent().SpanID() != spans["bidder.bidder1"].SpanContext().SpanID() {
+		t.Errorf("Expected the bidder HTTP request under the bidder span, got %v", spanNames(recorder.Ended()))
+	}
+}
+
+func TestRunAuction_NoSpansWithoutTracing(t *testing.T) {
+	if trace := newAuctionTrace(context.Background(), false); trace != nil {
+		t.Error("Expected no trace without debug or a recording span")
+	}
+}
+
+func spanNames(spans []sdktrace.ReadOnlySpan) []string {
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	return names
+}
@@ -27,10 +27,11 @@ type Metrics struct {
 	BiddersExcluded *prometheus.HistogramVec
 
 	// Bidder metrics
-	BidderRequests *prometheus.CounterVec
-	BidderLatency  *prometheus.HistogramVec
-	BidderErrors   *prometheus.CounterVec
-	BidderTimeouts *prometheus.CounterVec
+	BidderRequests        *prometheus.CounterVec
+	BidderLatency         *prometheus.HistogramVec
+	BidderErrors          *prometheus.CounterVec
+	BidderTimeouts        *prometheus.CounterVec
+	BidderPartialTimeouts *prometheus.CounterVec
 
 	// Bidder Circuit Breaker metrics
 	BidderCircuitState        *prometheus.GaugeVec   // Current state per bidder (0=closed, 1=open, 2=half-open)
@@ -45,6 +46,17 @@ type Metrics struct {
 	IDRLatency      *prometheus.HistogramVec
 	IDRCircuitState *prometheus.GaugeVec
 
+	// IDR shadow mode metrics (counterfactual of calling only IDR's selection)
+	IDRShadowAuctions     *prometheus.CounterVec
+	IDRShadowRevenueDelta *prometheus.HistogramVec // Revenue the selection would have lost per auction
+	IDRShadowLostWinners  *prometheus.CounterVec   // Imps whose winner IDR would not have called
+	IDRShadowBiddersSaved *prometheus.CounterVec   // Bidder calls the selection would have avoided
+
+	// IDR event spool metrics (durable bid event delivery)
+	IDREventSpoolEvents    prometheus.Gauge // Events waiting on disk
+	IDREventSpoolBytes     prometheus.Gauge // Disk used by the spool
+	IDREventSpoolOldestAge prometheus.Gauge // Age of the oldest undelivered segment
+
 	// Privacy metrics
 	PrivacyFiltered *prometheus.CounterVec
 	ConsentSignals  *prometheus.CounterVec
@@ -182,6 +194,14 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{"bidder"},
 		),
+		BidderPartialTimeouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bidder_partial_timeouts_total",
+				Help:      "Bidder calls where some requests timed out but bids from the others were kept",
+			},
+			[]string{"bidder"},
+		),
 
 		// Bidder Circuit Breaker metrics
 		BidderCircuitState: prometheus.NewGaugeVec(
@@ -259,6 +279,60 @@ func NewMetrics(namespace string) *Metrics {
 			},
 			[]string{},
 		),
+		IDRShadowAuctions: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "idr_shadow_auctions_total",
+				Help:      "Auctions run in IDR shadow mode",
+			},
+			[]string{},
+		),
+		IDRShadowRevenueDelta: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "idr_shadow_revenue_delta",
+				Help:      "Winning CPM lost per shadow auction had only IDR's selection been called",
+				Buckets:   []float64{0, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+			},
+			[]string{},
+		),
+		IDRShadowLostWinners: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "idr_shadow_lost_winners_total",
+				Help:      "Shadow auction imps whose winner IDR would not have called",
+			},
+			[]string{},
+		),
+		IDRShadowBiddersSaved: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "idr_shadow_bidders_saved_total",
+				Help:      "Bidder calls IDR's selection would have avoided in shadow auctions",
+			},
+			[]string{},
+		),
+		IDREventSpoolEvents: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "idr_event_spool_events",
+				Help:      "IDR bid events spooled on disk awaiting delivery",
+			},
+		),
+		IDREventSpoolBytes: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "idr_event_spool_bytes",
+				Help:      "Disk used by the IDR bid event spool",
+			},
+		),
+		IDREventSpoolOldestAge: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "idr_event_spool_oldest_age_seconds",
+				Help:      "Age of the oldest undelivered IDR bid event segment",
+			},
+		),
 
 		// Privacy metrics
 		PrivacyFiltered: prometheus.NewCounterVec(
@@ -362,6 +436,7 @@ func NewMetrics(namespace string) *Metrics {
 		m.BidderLatency,
 		m.BidderErrors,
 		m.BidderTimeouts,
+		m.BidderPartialTimeouts,
 		m.BidderCircuitState,
 		m.BidderCircuitRequests,
 		m.BidderCircuitFailures,
@@ -371,6 +446,13 @@ func NewMetrics(namespace string) *Metrics {
 		m.IDRRequests,
 		m.IDRLatency,
 		m.IDRCircuitState,
+		m.IDRShadowAuctions,
+		m.IDRShadowRevenueDelta,
+		m.IDRShadowLostWinners,
+		m.IDRShadowBiddersSaved,
+		m.IDREventSpoolEvents,
+		m.IDREventSpoolBytes,
+		m.IDREventSpoolOldestAge,
 		m.PrivacyFiltered,
 		m.ConsentSignals,
 		m.ActiveConnections,
@@ -504,12 +586,32 @@ func (m *Metrics) RecordBidderRequest(bidder string, latency time.Duration, hasE
 	}
 }
 
+// RecordBidderPartialTimeout records a bidder call that kept bids despite some requests timing out
+func (m *Metrics) RecordBidderPartialTimeout(bidder string) {
+	m.BidderPartialTimeouts.WithLabelValues(bidder).Inc()
+}
+
 // RecordIDRRequest records an IDR service request
 func (m *Metrics) RecordIDRRequest(status string, latency time.Duration) {
 	m.IDRRequests.WithLabelValues(status).Inc()
 	m.IDRLatency.WithLabelValues().Observe(latency.Seconds())
 }
 
+// RecordIDRShadowAuction records the comparison of a shadow auction with IDR's selection
+func (m *Metrics) RecordIDRShadowAuction(revenueDelta float64, lostWinners, biddersSaved int) {
+	m.IDRShadowAuctions.WithLabelValues().Inc()
+	m.IDRShadowRevenueDelta.WithLabelValues().Observe(revenueDelta)
+	m.IDRShadowLostWinners.WithLabelValues().Add(float64(lostWinners))
+	m.IDRShadowBiddersSaved.WithLabelValues().Add(float64(biddersSaved))
+}
+
+// SetIDREventSpool sets the IDR event spool depth and age
+func (m *Metrics) SetIDREventSpool(events, bytes int64, oldestAgeSeconds float64) {
+	m.IDREventSpoolEvents.Set(float64(events))
+	m.IDREventSpoolBytes.Set(float64(bytes))
+	m.IDREventSpoolOldestAge.Set(oldestAgeSeconds)
+}
+
 // SetIDRCircuitState sets the IDR circuit breaker state metric
 func (m *Metrics) SetIDRCircuitState(state string) {
 	var value float64
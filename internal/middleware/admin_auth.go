@@ -0,0 +1,356 @@
+// Package middleware provides HTTP middleware for PBS
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// AdminRole is the role granted to an admin credential
+type AdminRole string
+
+// Admin roles
+const (
+	AdminRoleViewer  AdminRole = "viewer"  // read-only dashboards and stats
+	AdminRoleOps     AdminRole = "ops"     // circuit breakers and ad tags
+	AdminRoleFinance AdminRole = "finance" // publishers, multipliers and currency
+	AdminRoleAdmin   AdminRole = "admin"   // everything, including the audit log
+)
+
+// adminTokenPrefix marks HMAC-signed tokens so they can't be mistaken for API keys
+const adminTokenPrefix = "v1."
+
+// Admin authentication errors
+var (
+	ErrAdminCredentialsMissing = errors.New("admin credentials required")
+	ErrAdminCredentialsInvalid = errors.New("invalid admin credentials")
+	ErrAdminTokenExpired       = errors.New("admin token expired")
+)
+
+// ParseAdminRole parses a role name, returning false for unknown roles
+func ParseAdminRole(s string) (AdminRole, bool) {
+	switch role := AdminRole(strings.ToLower(strings.TrimSpace(s))); role {
+	case AdminRoleViewer, AdminRoleOps, AdminRoleFinance, AdminRoleAdmin:
+		return role, true
+	}
+	return "", false
+}
+
+// AdminPrincipal identifies the caller of an admin endpoint
+type AdminPrincipal struct {
+	Name string    `json:"sub"`
+	Role AdminRole `json:"role"`
+}
+
+// AdminRoute lists the roles allowed on routes under Prefix.
+// Read roles apply to GET and HEAD; write roles to every other method.
+// The admin role is always allowed.
+type AdminRoute struct {
+	Prefix     string
+	ReadRoles  []AdminRole
+	WriteRoles []AdminRole
+}
+
+// DefaultAdminRoutes returns the role policy for the built-in admin endpoints.
+// Paths under /admin/ that match no route are restricted to the admin role.
+func DefaultAdminRoutes() []AdminRoute {
+	all := []AdminRole{AdminRoleViewer, AdminRoleOps, AdminRoleFinance}
+	return []AdminRoute{
+		{Prefix: "/admin/dashboard", ReadRoles: all},
+		{Prefix: "/admin/metrics", ReadRoles: all},
+		{Prefix: "/admin/circuit-breaker", ReadRoles: all, WriteRoles: []AdminRole{AdminRoleOps}},
+		{Prefix: "/admin/bidder-timeouts", ReadRoles: all},
+		{Prefix: "/admin/currency", ReadRoles: all, WriteRoles: []AdminRole{AdminRoleFinance}},
+		{Prefix: "/admin/publishers", ReadRoles: all, WriteRoles: []AdminRole{AdminRoleFinance}},
+		{Prefix: "/admin/adtag", ReadRoles: all, WriteRoles: []AdminRole{AdminRoleOps}},
+		{Prefix: "/admin/audit"},
+		{Prefix: "/admin/config"},
+	}
+}
+
+// AdminAuthConfig holds admin authentication configuration
+type AdminAuthConfig struct {
+	Enabled     bool
+	PathPrefix  string                    // Paths protected by the middleware
+	APIKeys     map[string]AdminPrincipal // SHA-256 hex of the key -> principal
+	TokenSecret []byte                    // HMAC secret for signed tokens; empty disables tokens
+	Routes      []AdminRoute
+}
+
+// DefaultAdminAuthConfig returns config from environment.
+//
+//	ADMIN_AUTH_ENABLED  - set to "false" to disable (development only)
+//	ADMIN_API_KEYS      - comma-separated name:role:key entries
+//	ADMIN_TOKEN_SECRET  - HMAC secret for signed tokens
+//
+// With auth enabled and no credentials configured every admin request is rejected.
+func DefaultAdminAuthConfig() *AdminAuthConfig {
+	return &AdminAuthConfig{
+		Enabled:     os.Getenv("ADMIN_AUTH_ENABLED") != "false",
+		PathPrefix:  "/admin/",
+		APIKeys:     parseAdminAPIKeys(os.Getenv("ADMIN_API_KEYS")),
+		TokenSecret: []byte(os.Getenv("ADMIN_TOKEN_SECRET")),
+		Routes:      DefaultAdminRoutes(),
+	}
+}
+
+// parseAdminAPIKeys parses "name:role:key,..."; malformed entries are logged and skipped
+func parseAdminAPIKeys(envValue string) map[string]AdminPrincipal {
+	keys := make(map[string]AdminPrincipal)
+	for _, entry := range strings.Split(envValue, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			log.Warn().Msg("Ignoring malformed ADMIN_API_KEYS entry, expected name:role:key")
+			continue
+		}
+		role, ok := ParseAdminRole(parts[1])
+		if !ok {
+			log.Warn().Str("name", parts[0]).Str("role", parts[1]).Msg("Ignoring ADMIN_API_KEYS entry with unknown role")
+			continue
+		}
+		keys[HashAdminAPIKey(parts[2])] = AdminPrincipal{Name: parts[0], Role: role}
+	}
+	return keys
+}
+
+// HashAdminAPIKey returns the SHA-256 hex digest used to look up an admin API key
+func HashAdminAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// adminTokenClaims is the signed payload of an admin token
+type adminTokenClaims struct {
+	AdminPrincipal
+	ExpiresAt int64 `json:"exp"`
+}
+
+// SignAdminToken issues an HMAC-signed token for the principal that expires at expiresAt
+func SignAdminToken(secret []byte, principal AdminPrincipal, expiresAt time.Time) (string, error) {
+	if len(secret) == 0 {
+		return "", errors.New("admin token secret is empty")
+	}
+	payload, err := json.Marshal(adminTokenClaims{AdminPrincipal: principal, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", err
+	}
+	body := adminTokenPrefix + base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + signAdminToken(secret, body), nil
+}
+
+func signAdminToken(secret []byte, body string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type adminPrincipalKey struct{}
+
+// WithAdminPrincipal returns a context carrying the authenticated admin principal
+func WithAdminPrincipal(ctx context.Context, p *AdminPrincipal) context.Context {
+	return context.WithValue(ctx, adminPrincipalKey{}, p)
+}
+
+// AdminPrincipalFromContext returns the authenticated admin principal, or nil
+func AdminPrincipalFromContext(ctx context.Context) *AdminPrincipal {
+	p, _ := ctx.Value(adminPrincipalKey{}).(*AdminPrincipal)
+	return p
+}
+
+// AdminAuth authenticates admin requests and enforces per-route roles
+type AdminAuth struct {
+	config *AdminAuthConfig
+	mu     sync.RWMutex
+	now    func() time.Time
+}
+
+// NewAdminAuth creates a new admin auth middleware
+func NewAdminAuth(config *AdminAuthConfig) *AdminAuth {
+	if config == nil {
+		config = DefaultAdminAuthConfig()
+	}
+	if config.Enabled && len(config.APIKeys) == 0 && len(config.TokenSecret) == 0 {
+		log.Warn().Msg("Admin auth enabled without ADMIN_API_KEYS or ADMIN_TOKEN_SECRET - admin endpoints will reject all requests")
+	}
+	return &AdminAuth{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Middleware enforces authentication and roles on paths under the configured prefix
+func (a *AdminAuth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.RLock()
+		enabled := a.config.Enabled
+		prefix := a.config.PathPrefix
+		a.mu.RUnlock()
+
+		if !enabled || !strings.HasPrefix(r.URL.Path, prefix) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := a.Authenticate(r)
+		if err != nil {
+			log.Warn().
+				Str("path", r.URL.Path).
+				Str("remote_addr", r.RemoteAddr).
+				Str("error", err.Error()).
+				Msg("Admin authentication failed")
+			w.Header().Set("WWW-Authenticate", `Basic realm="admin"`)
+			writeAdminAuthError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+
+		if !a.Allowed(principal.Role, r.Method, r.URL.Path) {
+			log.Warn().
+				Str("path", r.URL.Path).
+				Str("method", r.Method).
+				Str("admin", principal.Name).
+				Str("role", string(principal.Role)).
+				Msg("Admin request forbidden for role")
+			writeAdminAuthError(w, http.StatusForbidden, "role "+string(principal.Role)+" may not access this endpoint")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithAdminPrincipal(r.Context(), principal)))
+	})
+}
+
+// Authenticate resolves the request credentials to a principal.
+// Credentials are read from "Authorization: Bearer", the Basic auth password
+// (so browsers can load the dashboard) or X-Admin-Key.
+func (a *AdminAuth) Authenticate(r *http.Request) (*AdminPrincipal, error) {
+	credential := adminCredential(r)
+	if credential == "" {
+		return nil, ErrAdminCredentialsMissing
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if strings.HasPrefix(credential, adminTokenPrefix) {
+		return a.verifyToken(credential)
+	}
+
+	hash := HashAdminAPIKey(credential)
+	for keyHash, principal := range a.config.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(keyHash), []byte(hash)) == 1 {
+			p := principal
+			return &p, nil
+		}
+	}
+	return nil, ErrAdminCredentialsInvalid
+}
+
+// verifyToken checks the signature and expiry of a signed token; caller holds a.mu
+func (a *AdminAuth) verifyToken(token string) (*AdminPrincipal, error) {
+	if len(a.config.TokenSecret) == 0 {
+		return nil, ErrAdminCredentialsInvalid
+	}
+	idx := strings.LastIndex(token, ".")
+	if idx <= len(adminTokenPrefix) {
+		return nil, ErrAdminCredentialsInvalid
+	}
+	body, sig := token[:idx], token[idx+1:]
+	if !hmac.Equal([]byte(sig), []byte(signAdminToken(a.config.TokenSecret, body))) {
+		return nil, ErrAdminCredentialsInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(body, adminTokenPrefix))
+	if err != nil {
+		return nil, ErrAdminCredentialsInvalid
+	}
+	var claims adminTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Name == "" {
+		return nil, ErrAdminCredentialsInvalid
+	}
+	if _, ok := ParseAdminRole(string(claims.Role)); !ok {
+		return nil, ErrAdminCredentialsInvalid
+	}
+	if a.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrAdminTokenExpired
+	}
+	return &claims.AdminPrincipal, nil
+}
+
+// Allowed reports whether role may call method on path, using the longest matching route
+func (a *AdminAuth) Allowed(role AdminRole, method, path string) bool {
+	if role == AdminRoleAdmin {
+		return true
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var route *AdminRoute
+	for i := range a.config.Routes {
+		rt := &a.config.Routes[i]
+		if strings.HasPrefix(path, rt.Prefix) && (route == nil || len(rt.Prefix) > len(route.Prefix)) {
+			route = rt
+		}
+	}
+	if route == nil {
+		return false
+	}
+
+	roles := route.WriteRoles
+	if method == http.MethodGet || method == http.MethodHead {
+		roles = route.ReadRoles
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEnabled returns whether admin auth is enabled
+func (a *AdminAuth) IsEnabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.config.Enabled
+}
+
+// SetEnabled enables or disables admin auth
+func (a *AdminAuth) SetEnabled(enabled bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.config.Enabled = enabled
+}
+
+// adminCredential extracts the raw credential from the request headers
+func adminCredential(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	if _, password, ok := r.BasicAuth(); ok {
+		return password
+	}
+	return strings.TrimSpace(r.Header.Get("X-Admin-Key"))
+}
+
+func writeAdminAuthError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
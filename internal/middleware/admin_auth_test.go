@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestAdminAuth() *AdminAuth {
+	return NewAdminAuth(&AdminAuthConfig{
+		Enabled:     true,
+		PathPrefix:  "/admin/",
+		APIKeys:     parseAdminAPIKeys("alice:viewer:view-key, bob:ops:ops-key,carol:finance:fin-key,root:admin:admin-key,bad:wizard:x,broken"),
+		TokenSecret: []byte("test-secret"),
+		Routes:      DefaultAdminRoutes(),
+	})
+}
+
+func TestParseAdminAPIKeys(t *testing.T) {
+	keys := parseAdminAPIKeys("alice:viewer:view-key,bad:wizard:x,broken,:admin:nokey")
+	if len(keys) != 1 {
+		t.Fatalf("Expected 1 valid key, got %d", len(keys))
+	}
+	p, ok := keys[HashAdminAPIKey("view-key")]
+	if !ok || p.Name != "alice" || p.Role != AdminRoleViewer {
+		t.Errorf("Unexpected principal: %+v", p)
+	}
+}
+
+func TestAdminAuth_Middleware(t *testing.T) {
+	auth := newTestAdminAuth()
+
+	var gotPrincipal *AdminPrincipal
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPrincipal = AdminPrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{"public path untouched", http.MethodGet, "/status", "", http.StatusOK},
+		{"missing credentials", http.MethodGet, "/admin/dashboard", "", http.StatusUnauthorized},
+		{"invalid key", http.MethodGet, "/admin/dashboard", "nope", http.StatusUnauthorized},
+		{"viewer reads dashboard", http.MethodGet, "/admin/dashboard", "view-key", http.StatusOK},
+		{"viewer cannot edit publishers", http.MethodPut, "/admin/publishers/pub-1", "view-key", http.StatusForbidden},
+		{"ops cannot edit publishers", http.MethodPost, "/admin/publishers", "ops-key", http.StatusForbidden},
+		{"finance edits publishers", http.MethodPut, "/admin/publishers/pub-1", "fin-key", http.StatusOK},
+		{"ops resets circuit breaker", http.MethodPost, "/admin/circuit-breaker", "ops-key", http.StatusOK},
+		{"finance cannot reset circuit breaker", http.MethodPost, "/admin/circuit-breaker", "fin-key", http.StatusForbidden},
+		{"ops generates ad tags", http.MethodPost, "/admin/adtag/generate", "ops-key", http.StatusOK},
+		{"audit log is admin only", http.MethodGet, "/admin/audit", "fin-key", http.StatusForbidden},
+		{"admin reads audit log", http.MethodGet, "/admin/audit", "admin-key", http.StatusOK},
+		{"unknown admin path is admin only", http.MethodGet, "/admin/secret", "view-key", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("X-Admin-Key", tt.key)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tt.want {
+				t.Errorf("Expected %d, got %d: %s", tt.want, rr.Code, rr.Body.String())
+			}
+			if rr.Code == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected WWW-Authenticate header on 401")
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/metrics", nil)
+	req.SetBasicAuth("anything", "fin-key")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if gotPrincipal == nil || gotPrincipal.Name != "carol" || gotPrincipal.Role != AdminRoleFinance {
+		t.Errorf("Expected carol/finance principal in context, got %+v", gotPrincipal)
+	}
+}
+
+func TestAdminAuth_SignedTokens(t *testing.T) {
+	auth := newTestAdminAuth()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	auth.now = func() time.Time { return now }
+
+	token, err := SignAdminToken([]byte("test-secret"), AdminPrincipal{Name: "dave", Role: AdminRoleOps}, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("SignAdminToken failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/dashboard", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	p, err := auth.Authenticate(req)
+	if err != nil || p.Name != "dave" || p.Role != AdminRoleOps {
+		t.Fatalf("Expected dave/ops, got %+v (err=%v)", p, err)
+	}
+
+	// Tampered payload
+	parts := strings.Split(token, ".")
+	forged, _ := SignAdminToken([]byte("test-secret"), AdminPrincipal{Name: "dave", Role: AdminRoleAdmin}, now.Add(time.Hour))
+	forgedParts := strings.Split(forged, ".")
+	req.Header.Set("Authorization", "Bearer "+parts[0]+"."+forgedParts[1]+"."+parts[2])
+	if _, err := auth.Authenticate(req); err != ErrAdminCredentialsInvalid {
+		t.Errorf("Expected tampered token to be rejected, got %v", err)
+	}
+
+	// Wrong secret
+	other, _ := SignAdminToken([]byte("other-secret"), AdminPrincipal{Name: "dave", Role: AdminRoleOps}, now.Add(time.Hour))
+	req.Header.Set("Authorization", "Bearer "+other)
+	if _, err := auth.Authenticate(req); err != ErrAdminCredentialsInvalid {
+		t.Errorf("Expected token with wrong secret to be rejected, got %v", err)
+	}
+
+	// Expired
+	auth.now = func() time.Time { return now.Add(2 * time.Hour) }
+	req.Header.Set("Authorization", "Bearer "+token)
+	if _, err := auth.Authenticate(req); err != ErrAdminTokenExpired {
+		t.Errorf("Expected expired token, got %v", err)
+	}
+
+	if _, err := SignAdminToken(nil, AdminPrincipal{Name: "x", Role: AdminRoleViewer}, now); err == nil {
+		t.Error("Expected error signing with empty secret")
+	}
+}
+
+func TestAdminAuth_NoCredentialsConfigured(t *testing.T) {
+	auth := NewAdminAuth(&AdminAuthConfig{Enabled: true, PathPrefix: "/admin/", Routes: DefaultAdminRoutes()})
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/dashboard", nil)
+	req.Header.Set("X-Admin-Key", "anything")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with no credentials configured, got %d", rr.Code)
+	}
+
+	auth.SetEnabled(false)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected pass-through when disabled, got %d", rr.Code)
+	}
+}
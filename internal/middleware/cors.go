@@ -213,3 +213,12 @@ func (c *CORS) SetAllowedOrigins(origins []string) {
 	defer c.mu.Unlock()
 	c.config.AllowedOrigins = origins
 }
+
+// GetConfig returns a copy of the current configuration
+func (c *CORS) GetConfig() CORSConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	config := *c.config
+	config.AllowedOrigins = append([]string(nil), c.config.AllowedOrigins...)
+	return config
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync/atomic"
 
 	"github.com/thenexusengine/tne_springwire/internal/openrtb"
 	"github.com/thenexusengine/tne_springwire/pkg/logger"
@@ -159,6 +160,59 @@ func NewPrivacyMiddleware(config PrivacyConfig) func(http.Handler) http.Handler
 	}
 }
 
+// Privacy enforces privacy regulations with a config that can be replaced at runtime
+type Privacy struct {
+	config atomic.Pointer[PrivacyConfig] // Immutable snapshot, swapped whole by SetConfig
+}
+
+// NewPrivacy creates privacy enforcement with a replaceable config
+func NewPrivacy(config PrivacyConfig) *Privacy {
+	p := &Privacy{}
+	p.SetConfig(config)
+	return p
+}
+
+// Middleware returns the privacy middleware handler; each request uses the config current when it arrives
+func (p *Privacy) Middleware(next http.Handler) http.Handler {
+	return &privacyHandler{privacy: p, next: next}
+}
+
+// privacyHandler reuses one PrivacyMiddleware per config snapshot, so requests don't allocate
+type privacyHandler struct {
+	privacy *Privacy
+	next    http.Handler
+	current atomic.Pointer[privacySnapshot]
+}
+
+// privacySnapshot is the middleware built from one config snapshot
+type privacySnapshot struct {
+	config     *PrivacyConfig
+	middleware *PrivacyMiddleware
+}
+
+func (h *privacyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	config := h.privacy.config.Load()
+	snapshot := h.current.Load()
+	if snapshot == nil || snapshot.config != config {
+		snapshot = &privacySnapshot{config: config, middleware: &PrivacyMiddleware{config: *config, next: h.next}}
+		h.current.Store(snapshot)
+	}
+	snapshot.middleware.ServeHTTP(w, r)
+}
+
+// SetConfig replaces the privacy configuration
+func (p *Privacy) SetConfig(config PrivacyConfig) {
+	config.RequiredPurposes = append([]int(nil), config.RequiredPurposes...)
+	p.config.Store(&config)
+}
+
+// GetConfig returns a copy of the current configuration
+func (p *Privacy) GetConfig() PrivacyConfig {
+	config := *p.config.Load()
+	config.RequiredPurposes = append([]int(nil), config.RequiredPurposes...)
+	return config
+}
+
 // ServeHTTP implements the http.Handler interface
 func (m *PrivacyMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Only process POST requests to auction endpoint
@@ -1297,3 +1297,49 @@ func TestValidateGeoConsent_UserGeoFallback(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rr.Code)
 	}
 }
+
+func TestPrivacy_SetConfigAppliesToNextRequest(t *testing.T) {
+	config := DefaultPrivacyConfig()
+	p := NewPrivacy(config)
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := &openrtb.BidRequest{
+		ID:   "test-coppa-reload",
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{}}},
+		Regs: &openrtb.Regs{COPPA: 1},
+	}
+	body, _ := json.Marshal(req)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(body)))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected COPPA request blocked, got %d", rr.Code)
+	}
+
+	config.EnforceCOPPA = false
+	p.SetConfig(config)
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/openrtb2/auction", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected COPPA request allowed after SetConfig, got %d", rr.Code)
+	}
+
+	// SetConfig keeps its own copy, so the caller can't change the live config
+	config.RequiredPurposes = []int{1, 2}
+	p.SetConfig(config)
+	config.RequiredPurposes[0] = 99
+	if p.GetConfig().RequiredPurposes[0] == 99 {
+		t.Error("Expected SetConfig to copy RequiredPurposes")
+	}
+
+	// GetConfig returns a copy
+	got := p.GetConfig()
+	if len(got.RequiredPurposes) > 0 {
+		got.RequiredPurposes[0] = 99
+		if p.GetConfig().RequiredPurposes[0] == 99 {
+			t.Error("Expected GetConfig to return a copy of RequiredPurposes")
+		}
+	}
+}
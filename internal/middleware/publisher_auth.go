@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/thenexusengine/tne_springwire/pkg/logger"
 )
 
 // PublisherAuthConfig holds publisher authentication configuration
@@ -282,6 +284,9 @@ func (p *PublisherAuth) Middleware(next http.Handler) http.Handler {
 		// Add publisher ID to request context (secure - can't be spoofed by client)
 		ctx := r.Context()
 		ctx = context.WithValue(ctx, publisherIDKey, publisherID)
+		if publisherID != "" {
+			ctx = logger.WithPublisherID(ctx, publisherID)
+		}
 
 		// Retrieve and store full publisher object in context for downstream use
 		if publisherID != "" && p.publisherStore != nil {
@@ -618,6 +623,16 @@ func (p *PublisherAuth) getCachedPublisher(publisherID string) string {
 	return entry.allowedDomains
 }
 
+// InvalidatePublisher drops the cached record for a publisher so the next
+// request re-reads it from Redis or PostgreSQL
+//
+// LOCK ORDERING: publisherCacheMu only (Level 2)
+func (p *PublisherAuth) InvalidatePublisher(publisherID string) {
+	p.publisherCacheMu.Lock()
+	defer p.publisherCacheMu.Unlock()
+	delete(p.publisherCache, publisherID)
+}
+
 // cleanupExpiredCache removes expired cache entries
 // CALLER MUST HOLD publisherCacheMu.Lock()
 func (p *PublisherAuth) cleanupExpiredCache() {
@@ -362,6 +362,26 @@ func TestCachePublisher_TTLWorks(t *testing.T) {
 	}
 }
 
+// TestInvalidatePublisher tests that invalidation drops only the named cache entry
+func TestInvalidatePublisher(t *testing.T) {
+	auth := NewPublisherAuth(&PublisherAuthConfig{
+		Enabled: true,
+	})
+
+	auth.cachePublisher("pub123", "example.com", time.Minute)
+	auth.cachePublisher("pub456", "other.com", time.Minute)
+
+	auth.InvalidatePublisher("pub123")
+	auth.InvalidatePublisher("missing") // no-op
+
+	if cached := auth.getCachedPublisher("pub123"); cached != "" {
+		t.Errorf("Expected invalidated entry to be gone, got %q", cached)
+	}
+	if cached := auth.getCachedPublisher("pub456"); cached != "other.com" {
+		t.Errorf("Expected other entry to remain, got %q", cached)
+	}
+}
+
 // TestRateLimitedLogging tests that fallback warnings are rate-limited
 func TestRateLimitedLogging_Redis(t *testing.T) {
 	// Clear global state
@@ -88,7 +88,7 @@ type RateLimitMetrics interface {
 type RateLimiter struct {
 	config  *RateLimitConfig
 	clients map[string]*clientState
-	mu      sync.Mutex
+	mu      sync.RWMutex
 	stopCh  chan struct{}
 	metrics RateLimitMetrics
 }
@@ -144,7 +144,13 @@ func (rl *RateLimiter) Stop() {
 // Middleware returns the rate limiting middleware handler
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !rl.config.Enabled {
+		// Settings can change at runtime (SetEnabled, SetRPS), so read them under the lock
+		rl.mu.RLock()
+		enabled := rl.config.Enabled
+		rps := rl.config.RequestsPerSecond
+		rl.mu.RUnlock()
+
+		if !enabled {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -155,6 +161,8 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 			clientID = rl.getClientIP(r)
 		}
 
+		limit := strconv.Itoa(rps)
+
 		// Check rate limit
 		if !rl.allow(clientID) {
 			// Record metric for rate limit rejection
@@ -162,14 +170,14 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 				rl.metrics.IncRateLimitRejected()
 			}
 			w.Header().Set("Retry-After", "1")
-			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.config.RequestsPerSecond))
+			w.Header().Set("X-RateLimit-Limit", limit)
 			w.Header().Set("X-RateLimit-Remaining", "0")
 			http.Error(w, `{"error":"rate limit exceeded"}`, http.StatusTooManyRequests)
 			return
 		}
 
 		// Add rate limit headers
-		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(rl.config.RequestsPerSecond))
+		w.Header().Set("X-RateLimit-Limit", limit)
 
 		next.ServeHTTP(w, r)
 	})
@@ -311,6 +319,13 @@ func (rl *RateLimiter) SetBurstSize(burst int) {
 	rl.config.BurstSize = burst
 }
 
+// GetConfig returns a copy of the current configuration
+func (rl *RateLimiter) GetConfig() RateLimitConfig {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	return *rl.config
+}
+
 // SetMetrics sets the metrics interface for the rate limiter
 func (rl *RateLimiter) SetMetrics(m RateLimitMetrics) {
 	rl.mu.Lock()
@@ -0,0 +1,84 @@
+package mockbidder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+)
+
+// Client is an in-process adapters.HTTPClient that answers every request with the
+// scripted response for the bidder named in adapters.TestBidderHeader
+type Client struct {
+	bidder *Bidder
+}
+
+// NewClient creates an in-process mock bidder client
+func NewClient(bidder *Bidder) *Client {
+	return &Client{bidder: bidder}
+}
+
+// Do scripts the response and waits out its latency, honoring ctx and timeout
+func (c *Client) Do(ctx context.Context, req *adapters.RequestData, timeout time.Duration) (*adapters.ResponseData, error) {
+	resp := c.bidder.Respond(requestBidder(req), req.Body)
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if resp.Delay > 0 {
+		timer := time.NewTimer(resp.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return &adapters.ResponseData{
+		StatusCode: resp.StatusCode,
+		Body:       resp.Body,
+		Headers:    http.Header{"Content-Type": []string{"application/json"}},
+	}, nil
+}
+
+// RemoteClient is an adapters.HTTPClient that sends every request to a cmd/mockbidder server
+type RemoteClient struct {
+	baseURL string
+	client  adapters.HTTPClient
+}
+
+// NewRemoteClient creates a client for the mock bidder server at baseURL
+func NewRemoteClient(baseURL string, timeout time.Duration) *RemoteClient {
+	return &RemoteClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  adapters.NewHTTPClient(timeout),
+	}
+}
+
+// Do forwards the request to the mock bidder's /bid/{bidder} endpoint
+func (c *RemoteClient) Do(ctx context.Context, req *adapters.RequestData, timeout time.Duration) (*adapters.ResponseData, error) {
+	bidderCode := requestBidder(req)
+	if bidderCode == "" {
+		return nil, fmt.Errorf("mock bidder request is missing the %s header", adapters.TestBidderHeader)
+	}
+
+	forwarded := *req
+	forwarded.Method = http.MethodPost
+	forwarded.URI = c.baseURL + "/bid/" + url.PathEscape(bidderCode)
+	return c.client.Do(ctx, &forwarded, timeout)
+}
+
+// requestBidder returns the bidder code a test request was made for
+func requestBidder(req *adapters.RequestData) string {
+	if req.Headers == nil {
+		return ""
+	}
+	return req.Headers.Get(adapters.TestBidderHeader)
+}
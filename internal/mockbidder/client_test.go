@@ -0,0 +1,59 @@
+package mockbidder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func testRequestData(t *testing.T, bidderCode string) *adapters.RequestData {
+	t.Helper()
+	headers := http.Header{}
+	if bidderCode != "" {
+		headers.Set(adapters.TestBidderHeader, bidderCode)
+	}
+	return &adapters.RequestData{
+		Method:  http.MethodPost,
+		URI:     "https://real-bidder.example.com/auction",
+		Body:    testRequestBody(t, openrtb.Imp{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}),
+		Headers: headers,
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	client := NewClient(New(&Config{
+		Default: Profile{Price: PriceDistribution{Type: PriceFixed, Min: 1}},
+		Bidders: map[string]Profile{"slow": {LatencyMS: 200}},
+	}))
+
+	resp, err := client.Do(context.Background(), testRequestData(t, "appnexus"), time.Second)
+	if err != nil || resp.StatusCode != http.StatusOK || len(resp.Body) == 0 {
+		t.Fatalf("Expected a bid, got %+v %v", resp, err)
+	}
+
+	_, err = client.Do(context.Background(), testRequestData(t, "slow"), 20*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the scripted latency to exceed the timeout, got %v", err)
+	}
+}
+
+func TestRemoteClient_Do(t *testing.T) {
+	server := httptest.NewServer(NewHandler(New(&Config{Default: Profile{Price: PriceDistribution{Type: PriceFixed, Min: 1}}})))
+	defer server.Close()
+	client := NewRemoteClient(server.URL, time.Second)
+
+	resp, err := client.Do(context.Background(), testRequestData(t, "pubmatic"), time.Second)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected a bid from the mock server, got %+v %v", resp, err)
+	}
+
+	if _, err := client.Do(context.Background(), testRequestData(t, ""), time.Second); err == nil {
+		t.Error("Expected error without a bidder code")
+	}
+}
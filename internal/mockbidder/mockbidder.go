@@ -0,0 +1,297 @@
+// Package mockbidder simulates bidder endpoints for test=1 auctions, QA and offline load tests.
+// Responses are scripted per bidder code with configurable latency, error rate, no-bid rate
+// and price distribution.
+package mockbidder
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// Price distribution types
+const (
+	PriceFixed   = "fixed"
+	PriceUniform = "uniform"
+	PriceNormal  = "normal"
+)
+
+// PriceDistribution describes how bid CPMs are drawn.
+// Normal prices are clamped to [Min, Max] when Max is set.
+type PriceDistribution struct {
+	Type   string  `json:"type"`
+	Min    float64 `json:"min,omitempty"`
+	Max    float64 `json:"max,omitempty"`
+	Mean   float64 `json:"mean,omitempty"`
+	StdDev float64 `json:"stddev,omitempty"`
+}
+
+// Profile scripts how one bidder responds
+type Profile struct {
+	LatencyMS  int               `json:"latencyMs"`            // Base response latency
+	JitterMS   int               `json:"jitterMs,omitempty"`   // Uniform random latency added on top
+	ErrorRate  float64           `json:"errorRate,omitempty"`  // Share of requests answered with HTTP 500
+	NoBidRate  float64           `json:"noBidRate,omitempty"`  // Share of imps left without a bid
+	Price      PriceDistribution `json:"price"`                // CPM distribution
+	Currency   string            `json:"currency,omitempty"`   // Response currency (default USD)
+	Seat       string            `json:"seat,omitempty"`       // seatbid.seat (default the bidder code)
+	DealID     string            `json:"dealId,omitempty"`     // Deal ID set on every bid
+	AdDomain   []string          `json:"adomain,omitempty"`    // Advertiser domains (default mock-advertiser.example.com)
+	StatusCode int               `json:"statusCode,omitempty"` // Error status code (default 500)
+}
+
+// Config holds the mock bidder profiles
+type Config struct {
+	Default  Profile            `json:"default"`
+	Bidders  map[string]Profile `json:"bidders,omitempty"` // Profiles by bidder code, overriding Default
+	Seed     int64              `json:"seed,omitempty"`    // Random seed for reproducible runs (0 = time-based)
+	MaxDelay time.Duration      `json:"-"`                 // Upper bound on simulated latency (0 = none)
+}
+
+// DefaultConfig returns a profile bidding on most imps at $0.50-$5.00 within 50ms
+func DefaultConfig() *Config {
+	return &Config{
+		Default: Profile{
+			LatencyMS: 30,
+			JitterMS:  20,
+			NoBidRate: 0.2,
+			Price:     PriceDistribution{Type: PriceUniform, Min: 0.50, Max: 5.00},
+		},
+	}
+}
+
+// LoadConfig reads a JSON config file. Fields missing from the file keep their defaults.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- operator-supplied config path
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock bidder config: %w", err)
+	}
+	config := DefaultConfig()
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse mock bidder config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate checks rates and price distributions
+func (c *Config) Validate() error {
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default profile: %w", err)
+	}
+	for code, p := range c.Bidders {
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("bidder %s: %w", code, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a single profile
+func (p Profile) validate() error {
+	if p.ErrorRate < 0 || p.ErrorRate > 1 || p.NoBidRate < 0 || p.NoBidRate > 1 {
+		return fmt.Errorf("errorRate and noBidRate must be between 0 and 1")
+	}
+	if p.LatencyMS < 0 || p.JitterMS < 0 {
+		return fmt.Errorf("latency must not be negative")
+	}
+	switch p.Price.Type {
+	case PriceFixed, "":
+	case PriceUniform:
+		if p.Price.Max < p.Price.Min {
+			return fmt.Errorf("uniform price max must be at least min")
+		}
+	case PriceNormal:
+		if p.Price.StdDev < 0 {
+			return fmt.Errorf("normal price stddev must not be negative")
+		}
+	default:
+		return fmt.Errorf("unknown price distribution %q", p.Price.Type)
+	}
+	return nil
+}
+
+// Response is one scripted bidder response
+type Response struct {
+	StatusCode int
+	Body       []byte
+	Delay      time.Duration
+}
+
+// Bidder generates scripted responses; it is safe for concurrent use
+type Bidder struct {
+	config *Config
+	mu     sync.Mutex
+	rng    *rand.Rand
+}
+
+// New creates a mock bidder (nil config uses DefaultConfig)
+func New(config *Config) *Bidder {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	// Bidder codes are matched case-insensitively
+	normalized := *config
+	normalized.Bidders = make(map[string]Profile, len(config.Bidders))
+	for code, p := range config.Bidders {
+		normalized.Bidders[strings.ToLower(code)] = p
+	}
+
+	return &Bidder{
+		config: &normalized,
+		rng:    rand.New(rand.NewSource(seed)), // #nosec G404 -- mock bid data, not security-sensitive
+	}
+}
+
+// profile returns the profile for a bidder code
+func (b *Bidder) profile(bidderCode string) Profile {
+	if p, ok := b.config.Bidders[strings.ToLower(bidderCode)]; ok {
+		return p
+	}
+	return b.config.Default
+}
+
+// Respond scripts bidderCode's response to an OpenRTB request body.
+// The caller is responsible for waiting out Delay before returning the response.
+func (b *Bidder) Respond(bidderCode string, body []byte) Response {
+	profile := b.profile(bidderCode)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay := time.Duration(profile.LatencyMS) * time.Millisecond
+	if profile.JitterMS > 0 {
+		delay += time.Duration(b.rng.Intn(profile.JitterMS+1)) * time.Millisecond
+	}
+	if b.config.MaxDelay > 0 && delay > b.config.MaxDelay {
+		delay = b.config.MaxDelay
+	}
+
+	var req openrtb.BidRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return Response{StatusCode: http.StatusBadRequest, Body: []byte(`{"error":"invalid bid request"}`), Delay: delay}
+	}
+
+	if profile.ErrorRate > 0 && b.rng.Float64() < profile.ErrorRate {
+		status := profile.StatusCode
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		return Response{StatusCode: status, Body: []byte(`{"error":"scripted bidder error"}`), Delay: delay}
+	}
+
+	bids := make([]openrtb.Bid, 0, len(req.Imp))
+	for _, imp := range req.Imp {
+		if profile.NoBidRate > 0 && b.rng.Float64() < profile.NoBidRate {
+			continue
+		}
+		price := b.drawPrice(profile.Price)
+		if price <= 0 || price < imp.BidFloor {
+			continue
+		}
+		bids = append(bids, b.makeBid(bidderCode, &imp, price, profile))
+	}
+	if len(bids) == 0 {
+		return Response{StatusCode: http.StatusNoContent, Delay: delay}
+	}
+
+	seat := profile.Seat
+	if seat == "" {
+		seat = bidderCode
+	}
+	currency := profile.Currency
+	if currency == "" {
+		currency = "USD"
+	}
+	resp := openrtb.BidResponse{
+		ID:      req.ID,
+		Cur:     currency,
+		SeatBid: []openrtb.SeatBid{{Seat: seat, Bid: bids}},
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		return Response{StatusCode: http.StatusInternalServerError, Delay: delay}
+	}
+	return Response{StatusCode: http.StatusOK, Body: out, Delay: delay}
+}
+
+// drawPrice draws a CPM from the distribution (caller holds b.mu)
+func (b *Bidder) drawPrice(dist PriceDistribution) float64 {
+	var price float64
+	switch dist.Type {
+	case PriceUniform:
+		price = dist.Min + b.rng.Float64()*(dist.Max-dist.Min)
+	case PriceNormal:
+		price = dist.Mean + b.rng.NormFloat64()*dist.StdDev
+		if dist.Max > 0 {
+			price = math.Min(price, dist.Max)
+		}
+		price = math.Max(price, dist.Min)
+	default:
+		price = dist.Min
+		if dist.Mean > 0 {
+			price = dist.Mean
+		}
+	}
+	return math.Round(price*10000) / 10000
+}
+
+// makeBid builds a bid for an impression (caller holds b.mu)
+func (b *Bidder) makeBid(bidderCode string, imp *openrtb.Imp, price float64, profile Profile) openrtb.Bid {
+	w, h := 300, 250
+	if imp.Banner != nil {
+		if imp.Banner.W > 0 && imp.Banner.H > 0 {
+			w, h = imp.Banner.W, imp.Banner.H
+		} else if len(imp.Banner.Format) > 0 {
+			w, h = imp.Banner.Format[0].W, imp.Banner.Format[0].H
+		}
+	} else if imp.Video != nil && imp.Video.W > 0 && imp.Video.H > 0 {
+		w, h = imp.Video.W, imp.Video.H
+	}
+
+	adomain := profile.AdDomain
+	if len(adomain) == 0 {
+		adomain = []string{"mock-advertiser.example.com"}
+	}
+
+	id := fmt.Sprintf("mock-%s-%s-%d", bidderCode, imp.ID, b.rng.Int63())
+	bid := openrtb.Bid{
+		ID:      id,
+		ImpID:   imp.ID,
+		Price:   price,
+		W:       w,
+		H:       h,
+		CRID:    "mock-creative-" + bidderCode,
+		ADomain: adomain,
+		DealID:  profile.DealID,
+	}
+	if imp.Video != nil && imp.Banner == nil {
+		bid.AdM = mockVAST(id)
+	} else {
+		bid.AdM = fmt.Sprintf(`<div style="width:%dpx;height:%dpx;background:#ddd;text-align:center;font-family:sans-serif">Test ad: %s $%.2f</div>`, w, h, bidderCode, price)
+	}
+	return bid
+}
+
+// mockVAST returns a minimal VAST 3.0 document for video imps
+func mockVAST(id string) string {
+	return `<VAST version="3.0"><Ad id="` + id + `"><InLine><AdSystem>mockbidder</AdSystem><AdTitle>Test ad</AdTitle>` +
+		`<Creatives><Creative><Linear><Duration>00:00:15</Duration><MediaFiles>` +
+		`<MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://mock-advertiser.example.com/test.mp4]]></MediaFile>` +
+		`</MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`
+}
@@ -0,0 +1,120 @@
+package mockbidder
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func testRequestBody(t *testing.T, imps ...openrtb.Imp) []byte {
+	t.Helper()
+	body, err := json.Marshal(openrtb.BidRequest{ID: "req-1", Imp: imps})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	return body
+}
+
+func TestBidder_Respond(t *testing.T) {
+	bidder := New(&Config{
+		Seed:    1,
+		Default: Profile{Price: PriceDistribution{Type: PriceFixed, Min: 1.5}},
+		Bidders: map[string]Profile{
+			"Rubicon": {LatencyMS: 40, Price: PriceDistribution{Type: PriceUniform, Min: 2, Max: 3}, Seat: "magnite", Currency: "EUR"},
+			"nobid":   {NoBidRate: 1},
+			"broken":  {ErrorRate: 1, StatusCode: http.StatusBadGateway},
+		},
+	})
+	body := testRequestBody(t,
+		openrtb.Imp{ID: "imp1", Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 728, H: 90}}}},
+		openrtb.Imp{ID: "imp2", Video: &openrtb.Video{W: 640, H: 360}},
+	)
+
+	resp := bidder.Respond("rubicon", body)
+	if resp.StatusCode != http.StatusOK || resp.Delay.Milliseconds() != 40 {
+		t.Fatalf("Unexpected response: %d after %v", resp.StatusCode, resp.Delay)
+	}
+	var bidResp openrtb.BidResponse
+	if err := json.Unmarshal(resp.Body, &bidResp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if bidResp.ID != "req-1" || bidResp.Cur != "EUR" || len(bidResp.SeatBid) != 1 || bidResp.SeatBid[0].Seat != "magnite" {
+		t.Fatalf("Unexpected bid response: %+v", bidResp)
+	}
+	bids := bidResp.SeatBid[0].Bid
+	if len(bids) != 2 {
+		t.Fatalf("Expected a bid per imp, got %d", len(bids))
+	}
+	for _, bid := range bids {
+		if bid.Price < 2 || bid.Price > 3 {
+			t.Errorf("Price %v outside the uniform range", bid.Price)
+		}
+	}
+	if bids[0].W != 728 || bids[0].H != 90 {
+		t.Errorf("Expected the banner format size, got %dx%d", bids[0].W, bids[0].H)
+	}
+	if bids[1].AdM == "" || bids[1].AdM[:5] != "<VAST" {
+		t.Errorf("Expected VAST markup for the video imp, got %q", bids[1].AdM)
+	}
+
+	if resp := bidder.Respond("unknown", body); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the default profile to bid, got %d", resp.StatusCode)
+	}
+	if resp := bidder.Respond("nobid", body); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected no-bid 204, got %d", resp.StatusCode)
+	}
+	if resp := bidder.Respond("broken", body); resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected scripted error status, got %d", resp.StatusCode)
+	}
+	if resp := bidder.Respond("rubicon", []byte("not json")); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid request, got %d", resp.StatusCode)
+	}
+}
+
+func TestBidder_RespondsBelowFloorWithNoBid(t *testing.T) {
+	bidder := New(&Config{Default: Profile{Price: PriceDistribution{Type: PriceFixed, Mean: 1.0}}})
+	body := testRequestBody(t, openrtb.Imp{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}, BidFloor: 2.0})
+
+	if resp := bidder.Respond("any", body); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected no bid below the floor, got %d", resp.StatusCode)
+	}
+}
+
+func TestBidder_NormalPricesClamped(t *testing.T) {
+	bidder := New(&Config{Seed: 7})
+	dist := PriceDistribution{Type: PriceNormal, Mean: 2, StdDev: 5, Min: 0.5, Max: 4}
+	for i := 0; i < 200; i++ {
+		if price := bidder.drawPrice(dist); price < 0.5 || price > 4 {
+			t.Fatalf("Price %v outside [0.5, 4]", price)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mock.json")
+	os.WriteFile(path, []byte(`{"bidders": {"appnexus": {"latencyMs": 80, "errorRate": 0.1, "price": {"type": "normal", "mean": 2, "stddev": 0.5}}}}`), 0o600)
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Default.Price.Type != PriceUniform {
+		t.Errorf("Expected the default profile to be kept, got %+v", config.Default)
+	}
+	if p := config.Bidders["appnexus"]; p.LatencyMS != 80 || p.Price.Mean != 2 {
+		t.Errorf("Unexpected appnexus profile: %+v", p)
+	}
+
+	os.WriteFile(path, []byte(`{"bidders": {"x": {"errorRate": 2}}}`), 0o600)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for an out-of-range rate")
+	}
+	os.WriteFile(path, []byte(`{"default": {"price": {"type": "pareto"}}}`), 0o600)
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected error for an unknown distribution")
+	}
+}
@@ -0,0 +1,79 @@
+package mockbidder
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+)
+
+// maxRequestSize limits bid request bodies accepted by the mock server
+const maxRequestSize = 1024 * 1024 // 1MB
+
+// Handler serves scripted bidder responses over HTTP
+//
+//	POST /bid/{bidder}  - OpenRTB bid request for {bidder}
+//	POST /bid           - Bidder code taken from the X-Test-Bidder header
+//	GET  /health        - Liveness check
+type Handler struct {
+	bidder *Bidder
+}
+
+// NewHandler creates a mock bidder HTTP handler
+func NewHandler(bidder *Bidder) *Handler {
+	return &Handler{bidder: bidder}
+}
+
+// ServeHTTP routes mock bidder requests
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/health":
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	case path == "/bid" || strings.HasPrefix(path, "/bid/"):
+		h.handleBid(w, r, strings.TrimPrefix(strings.TrimPrefix(path, "/bid"), "/"))
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+// handleBid answers one bid request after the scripted latency
+func (h *Handler) handleBid(w http.ResponseWriter, r *http.Request, bidderCode string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if bidderCode == "" {
+		bidderCode = r.Header.Get(adapters.TestBidderHeader)
+	}
+	if bidderCode == "" {
+		http.Error(w, "bidder code is required", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	resp := h.bidder.Respond(bidderCode, body)
+	if resp.Delay > 0 {
+		timer := time.NewTimer(resp.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if len(resp.Body) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
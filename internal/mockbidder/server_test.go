@@ -0,0 +1,50 @@
+package mockbidder
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(New(&Config{Default: Profile{Price: PriceDistribution{Type: PriceFixed, Min: 1}}}))
+	body := testRequestBody(t, openrtb.Imp{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/bid/ix", bytes.NewReader(body)))
+	var resp openrtb.BidResponse
+	json.NewDecoder(w.Body).Decode(&resp)
+	if w.Code != http.StatusOK || len(resp.SeatBid) != 1 || resp.SeatBid[0].Seat != "ix" {
+		t.Fatalf("Expected a bid for ix, got %d %+v", w.Code, resp)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/bid", bytes.NewReader(body))
+	req.Header.Set(adapters.TestBidderHeader, "openx")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the header to name the bidder, got %d", w.Code)
+	}
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodPost, "/bid", http.StatusBadRequest},
+		{http.MethodGet, "/bid/ix", http.StatusMethodNotAllowed},
+		{http.MethodGet, "/health", http.StatusOK},
+		{http.MethodGet, "/other", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, bytes.NewReader(body)))
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, w.Code)
+		}
+	}
+}
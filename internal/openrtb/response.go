@@ -80,11 +80,58 @@ const (
 
 // BidResponseExt represents PBS-specific response extensions
 type BidResponseExt struct {
-	ResponseTimeMillis map[string]int                `json:"responsetimemillis,omitempty"`
-	Errors             map[string][]ExtBidderMessage `json:"errors,omitempty"`
-	Warnings           map[string][]ExtBidderMessage `json:"warnings,omitempty"`
-	TMMaxRequest       int                           `json:"tmaxrequest,omitempty"`
-	Prebid             *ExtBidResponsePrebid         `json:"prebid,omitempty"`
+	ResponseTimeMillis  map[string]int                `json:"responsetimemillis,omitempty"`
+	Errors              map[string][]ExtBidderMessage `json:"errors,omitempty"`
+	Warnings            map[string][]ExtBidderMessage `json:"warnings,omitempty"`
+	TMMaxRequest        int                           `json:"tmaxrequest,omitempty"`
+	Prebid              *ExtBidResponsePrebid         `json:"prebid,omitempty"`
+	CurrencyConversions []ExtCurrencyConversion       `json:"currencyconversions,omitempty"`
+	Debug               *ExtResponseDebug             `json:"debug,omitempty"`
+}
+
+// ExtResponseDebug is ext.debug: what was sent to each bidder and how each auction stage went
+type ExtResponseDebug struct {
+	HTTPCalls       map[string][]ExtHTTPCall `json:"httpcalls,omitempty"`
+	ResolvedRequest json.RawMessage          `json:"resolvedrequest,omitempty"`
+	Trace           []ExtTraceStage          `json:"trace,omitempty"`
+}
+
+// ExtHTTPCall is one outbound bidder call, with secrets redacted
+type ExtHTTPCall struct {
+	URI            string              `json:"uri"`
+	Method         string              `json:"method,omitempty"`
+	RequestHeaders map[string][]string `json:"requestheaders,omitempty"`
+	RequestBody    string              `json:"requestbody"`
+	Status         int                 `json:"status,omitempty"`
+	ResponseBody   string              `json:"responsebody,omitempty"`
+	Error          string              `json:"error,omitempty"`
+}
+
+// ExtTraceStage is one auction stage in ext.debug.trace
+type ExtTraceStage struct {
+	Stage        string           `json:"stage"`
+	DurationMS   float64          `json:"durationms"`
+	Messages     []string         `json:"messages,omitempty"`
+	RejectedBids []ExtRejectedBid `json:"rejectedbids,omitempty"`
+}
+
+// ExtRejectedBid is a bid dropped by an auction stage
+type ExtRejectedBid struct {
+	Bidder string  `json:"bidder"`
+	BidID  string  `json:"bidid"`
+	ImpID  string  `json:"impid"`
+	Price  float64 `json:"price"`
+	Reason string  `json:"reason"`
+}
+
+// ExtCurrencyConversion records a converted bid price and the rate version that priced it
+type ExtCurrencyConversion struct {
+	BidID       string `json:"bidid"`
+	Bidder      string `json:"bidder"`
+	Stage       string `json:"stage"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	RateVersion string `json:"rateversion,omitempty"`
 }
 
 // ExtBidderMessage represents bidder message
@@ -112,6 +159,8 @@ type ExtBidPrebid struct {
 	Video     *ExtBidPrebidVideo  `json:"video,omitempty"`
 	Events    *ExtBidPrebidEvents `json:"events,omitempty"`
 	Meta      *ExtBidPrebidMeta   `json:"meta,omitempty"`
+	// TargetBidderCode is the targeting code of a multibid bid ranked below first, e.g. "appnexus2"
+	TargetBidderCode string `json:"targetbiddercode,omitempty"`
 }
 
 // ExtBidPrebidCache represents cache info
@@ -142,6 +191,7 @@ type ExtBidPrebidEvents struct {
 
 // ExtBidPrebidMeta represents bid metadata
 type ExtBidPrebidMeta struct {
+	AdapterCode     string          `json:"adapterCode,omitempty"` // Bidder that made a bid under an alternate code
 	AdvertiserID    int             `json:"advertiserId,omitempty"`
 	AdvertiserName  string          `json:"advertiserName,omitempty"`
 	AgencyID        int             `json:"agencyId,omitempty"`
@@ -0,0 +1,138 @@
+package replay
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// priceTolerance ignores float noise when comparing prices
+const priceTolerance = 1e-9
+
+// Report summarises the differences between two sets of replay results
+type Report struct {
+	Compared int                 // Auctions present in both sets
+	Missing  []string            // Auction IDs present in only one set
+	Changed  map[string][]string // Differences by auction ID
+}
+
+// HasDifferences reports whether any auction changed or is missing
+func (r *Report) HasDifferences() bool {
+	return len(r.Changed) > 0 || len(r.Missing) > 0
+}
+
+// String formats the report, one line per difference and a summary line
+func (r *Report) String() string {
+	var b strings.Builder
+	ids := make([]string, 0, len(r.Changed))
+	for id := range r.Changed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		for _, diff := range r.Changed[id] {
+			fmt.Fprintf(&b, "%s: %s\n", id, diff)
+		}
+	}
+	for _, id := range r.Missing {
+		fmt.Fprintf(&b, "%s: only in one result set\n", id)
+	}
+	fmt.Fprintf(&b, "compared %d auctions: %d changed, %d missing\n", r.Compared, len(r.Changed), len(r.Missing))
+	return b.String()
+}
+
+// Compare diffs baseline results against candidate results
+func Compare(baseline, candidate map[string]*Result) *Report {
+	report := &Report{Changed: make(map[string][]string)}
+	for id, base := range baseline {
+		cand, ok := candidate[id]
+		if !ok {
+			report.Missing = append(report.Missing, id)
+			continue
+		}
+		report.Compared++
+		if diffs := Diff(base, cand); len(diffs) > 0 {
+			report.Changed[id] = diffs
+		}
+	}
+	for id := range candidate {
+		if _, ok := baseline[id]; !ok {
+			report.Missing = append(report.Missing, id)
+		}
+	}
+	sort.Strings(report.Missing)
+	return report
+}
+
+// Diff lists the differences in winners, prices, targeting and errors between two results
+func Diff(a, b *Result) []string {
+	var diffs []string
+	if a.Error != b.Error {
+		diffs = append(diffs, fmt.Sprintf("error %q -> %q", a.Error, b.Error))
+	}
+	if a.NBR != b.NBR {
+		diffs = append(diffs, fmt.Sprintf("nbr %d -> %d", a.NBR, b.NBR))
+	}
+	if a.Currency != b.Currency {
+		diffs = append(diffs, fmt.Sprintf("cur %q -> %q", a.Currency, b.Currency))
+	}
+
+	for _, impID := range unionKeys(a.Bids, b.Bids) {
+		diffs = append(diffs, diffImpBids(impID, a.Bids[impID], b.Bids[impID])...)
+	}
+
+	for _, bidder := range unionKeys(a.BidderErrors, b.BidderErrors) {
+		before, after := a.BidderErrors[bidder], b.BidderErrors[bidder]
+		if strings.Join(before, "\n") != strings.Join(after, "\n") {
+			diffs = append(diffs, fmt.Sprintf("bidder %s errors %q -> %q", bidder, before, after))
+		}
+	}
+	return diffs
+}
+
+// diffImpBids compares the ranked response bids of one imp
+func diffImpBids(impID string, a, b []ResultBid) []string {
+	var diffs []string
+	if len(a) != len(b) {
+		diffs = append(diffs, fmt.Sprintf("imp %s: %d bids -> %d bids", impID, len(a), len(b)))
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		x, y := a[i], b[i]
+		prefix := fmt.Sprintf("imp %s bid %d", impID, i)
+		if x.Seat != y.Seat || x.BidID != y.BidID {
+			diffs = append(diffs, fmt.Sprintf("%s: winner %s/%s -> %s/%s", prefix, x.Seat, x.BidID, y.Seat, y.BidID))
+		}
+		if math.Abs(x.Price-y.Price) > priceTolerance {
+			diffs = append(diffs, fmt.Sprintf("%s: price %.4f -> %.4f", prefix, x.Price, y.Price))
+		}
+		if x.DealID != y.DealID {
+			diffs = append(diffs, fmt.Sprintf("%s: deal %q -> %q", prefix, x.DealID, y.DealID))
+		}
+		for _, key := range unionKeys(x.Targeting, y.Targeting) {
+			before, inA := x.Targeting[key]
+			after, inB := y.Targeting[key]
+			if before != after || inA != inB {
+				diffs = append(diffs, fmt.Sprintf("%s: targeting %s %q -> %q", prefix, key, before, after))
+			}
+		}
+	}
+	return diffs
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,66 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	base := &Result{
+		ID:           "a1",
+		Bids:         map[string][]ResultBid{"imp1": {{Seat: "alpha", BidID: "b1", Price: 2, Targeting: map[string]string{"hb_pb": "2.00"}}}},
+		BidderErrors: map[string][]string{"beta": {"timeout"}},
+	}
+	same := &Result{
+		ID:           "a1",
+		Bids:         map[string][]ResultBid{"imp1": {{Seat: "alpha", BidID: "b1", Price: 2, Targeting: map[string]string{"hb_pb": "2.00"}}}},
+		BidderErrors: map[string][]string{"beta": {"timeout"}},
+	}
+	if diffs := Diff(base, same); len(diffs) != 0 {
+		t.Errorf("Expected no differences, got %v", diffs)
+	}
+
+	changed := &Result{
+		ID:   "a1",
+		Bids: map[string][]ResultBid{"imp1": {{Seat: "beta", BidID: "b2", Price: 1.5, Targeting: map[string]string{"hb_pb": "1.50", "hb_deal": "d1"}}}},
+	}
+	got := strings.Join(Diff(base, changed), "\n")
+	for _, want := range []string{
+		"imp imp1 bid 0: winner alpha/b1 -> beta/b2",
+		"imp imp1 bid 0: price 2.0000 -> 1.5000",
+		`imp imp1 bid 0: targeting hb_deal "" -> "d1"`,
+		`imp imp1 bid 0: targeting hb_pb "2.00" -> "1.50"`,
+		"bidder beta errors",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in diff:\n%s", want, got)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	baseline := map[string]*Result{
+		"a1": {ID: "a1", NBR: 2},
+		"a2": {ID: "a2"},
+		"a3": {ID: "a3"},
+	}
+	candidate := map[string]*Result{
+		"a1": {ID: "a1"},
+		"a2": {ID: "a2"},
+		"a4": {ID: "a4"},
+	}
+
+	report := Compare(baseline, candidate)
+	if report.Compared != 2 || len(report.Changed) != 1 || len(report.Changed["a1"]) != 1 {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+	if strings.Join(report.Missing, ",") != "a3,a4" {
+		t.Errorf("Expected a3 and a4 missing, got %v", report.Missing)
+	}
+	if !report.HasDifferences() || !strings.Contains(report.String(), "compared 2 auctions: 1 changed, 2 missing") {
+		t.Errorf("Unexpected report output:\n%s", report.String())
+	}
+	if Compare(baseline, baseline).HasDifferences() {
+		t.Error("Expected no differences comparing a result set with itself")
+	}
+}
@@ -0,0 +1,225 @@
+// Package replay re-runs recorded auctions against canned bidder responses so that
+// auction-logic changes can be compared between builds
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/middleware"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// maxLineSize bounds one JSONL line (a recorded auction with all bidder responses)
+const maxLineSize = 16 * 1024 * 1024 // 16MB
+
+// ErrNoRecordedCall is returned when a bidder call has no recorded response
+var ErrNoRecordedCall = errors.New("no recorded response")
+
+// Client is an adapters.HTTPClient answering bidder calls with the responses of one
+// recorded auction. Calls are matched by bidder code, then method and URI, in order.
+// The bidder code comes from the adapters.TestBidderHeader that the exchange sets in
+// test mode, so replayed requests run with test=1 and the Client as test client.
+type Client struct {
+	mu    sync.Mutex
+	calls map[string][]*exchange.RecordedCall // by bidder code
+	used  map[*exchange.RecordedCall]bool
+}
+
+// NewClient creates a client replaying the bidder calls of a recorded auction
+func NewClient(rec *exchange.RecordedAuction) *Client {
+	c := &Client{
+		calls: make(map[string][]*exchange.RecordedCall),
+		used:  make(map[*exchange.RecordedCall]bool),
+	}
+	for i := range rec.Calls {
+		call := &rec.Calls[i]
+		c.calls[call.Bidder] = append(c.calls[call.Bidder], call)
+	}
+	return c
+}
+
+// Do returns the recorded response for a bidder request
+func (c *Client) Do(ctx context.Context, req *adapters.RequestData, timeout time.Duration) (*adapters.ResponseData, error) {
+	bidderCode := req.Headers.Get(adapters.TestBidderHeader)
+	call := c.next(bidderCode, req.Method, req.URI)
+	if call == nil {
+		return nil, fmt.Errorf("%w for %s %s %s", ErrNoRecordedCall, bidderCode, req.Method, req.URI)
+	}
+	if call.TimedOut {
+		return nil, fmt.Errorf("recorded timeout: %w", context.DeadlineExceeded)
+	}
+	if call.Error != "" {
+		return nil, errors.New(call.Error)
+	}
+	return &adapters.ResponseData{StatusCode: call.StatusCode, Body: []byte(call.Body)}, nil
+}
+
+// next takes the first unused call for the bidder, preferring one with the same method and URI
+func (c *Client) next(bidderCode, method, uri string) *exchange.RecordedCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var fallback *exchange.RecordedCall
+	for _, call := range c.calls[bidderCode] {
+		if c.used[call] {
+			continue
+		}
+		if call.Method == method && call.URI == uri {
+			c.used[call] = true
+			return call
+		}
+		if fallback == nil {
+			fallback = call
+		}
+	}
+	if fallback != nil {
+		c.used[fallback] = true
+	}
+	return fallback
+}
+
+// Result is the outcome of one replayed auction, the unit compared between builds
+type Result struct {
+	ID           string                 `json:"id"`
+	Error        string                 `json:"error,omitempty"`
+	NBR          int                    `json:"nbr,omitempty"`
+	Currency     string                 `json:"cur,omitempty"`
+	Bids         map[string][]ResultBid `json:"bids,omitempty"`         // Response bids by imp ID
+	BidderErrors map[string][]string    `json:"bidderErrors,omitempty"` // Errors by bidder code
+}
+
+// ResultBid is a bid in the auction response
+type ResultBid struct {
+	Seat      string            `json:"seat"`
+	BidID     string            `json:"bidId"`
+	Price     float64           `json:"price"`
+	DealID    string            `json:"dealId,omitempty"`
+	Targeting map[string]string `json:"targeting,omitempty"`
+}
+
+// Run replays a recorded auction through the exchange. It installs its own test client
+// on ex, so auctions must be replayed one at a time per exchange.
+func Run(ctx context.Context, ex *exchange.Exchange, rec *exchange.RecordedAuction) *Result {
+	ex.SetTestHTTPClient(NewClient(rec))
+
+	req := rec.AuctionRequest()
+	req.BidRequest.Test = 1
+	if rec.Publisher != nil {
+		// Restore the publisher so multiplier changes show up in the diff
+		ctx = middleware.NewContextWithPublisher(ctx, rec.Publisher)
+		ctx = middleware.NewContextWithPublisherID(ctx, rec.Publisher.ID)
+	}
+
+	resp, err := ex.RunAuction(ctx, req)
+	result := &Result{ID: rec.ID}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if resp == nil {
+		return result
+	}
+
+	for code, br := range resp.BidderResults {
+		for _, bidderErr := range br.Errors {
+			if result.BidderErrors == nil {
+				result.BidderErrors = make(map[string][]string)
+			}
+			result.BidderErrors[code] = append(result.BidderErrors[code], bidderErr.Error())
+		}
+	}
+
+	if resp.BidResponse == nil {
+		return result
+	}
+	result.NBR = resp.BidResponse.NBR
+	result.Currency = resp.BidResponse.Cur
+	for _, seatBid := range resp.BidResponse.SeatBid {
+		for _, bid := range seatBid.Bid {
+			if result.Bids == nil {
+				result.Bids = make(map[string][]ResultBid)
+			}
+			result.Bids[bid.ImpID] = append(result.Bids[bid.ImpID], ResultBid{
+				Seat:      seatBid.Seat,
+				BidID:     bid.ID,
+				Price:     bid.Price,
+				DealID:    bid.DealID,
+				Targeting: bidTargeting(bid.Ext),
+			})
+		}
+	}
+	for _, bids := range result.Bids {
+		sort.SliceStable(bids, func(i, j int) bool {
+			if bids[i].Price != bids[j].Price {
+				return bids[i].Price > bids[j].Price
+			}
+			return bids[i].BidID < bids[j].BidID
+		})
+	}
+	return result
+}
+
+// bidTargeting extracts ext.prebid.targeting from a response bid
+func bidTargeting(ext json.RawMessage) map[string]string {
+	if len(ext) == 0 {
+		return nil
+	}
+	var bidExt openrtb.BidExt
+	if err := json.Unmarshal(ext, &bidExt); err != nil || bidExt.Prebid == nil {
+		return nil
+	}
+	return bidExt.Prebid.Targeting
+}
+
+// ReadRecordings calls fn for each recorded auction in a JSONL capture
+func ReadRecordings(r io.Reader, fn func(*exchange.RecordedAuction) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec exchange.RecordedAuction
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if rec.Request == nil {
+			return fmt.Errorf("line %d: recorded auction %q has no request", line, rec.ID)
+		}
+		if err := fn(&rec); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ReadResults loads replay results from JSONL, keyed by auction ID
+func ReadResults(r io.Reader) (map[string]*Result, error) {
+	results := make(map[string]*Result)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var result Result
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		results[result.ID] = &result
+	}
+	return results, scanner.Err()
+}
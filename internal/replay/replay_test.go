@@ -0,0 +1,186 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thenexusengine/tne_springwire/internal/adapters"
+	"github.com/thenexusengine/tne_springwire/internal/exchange"
+	"github.com/thenexusengine/tne_springwire/internal/openrtb"
+)
+
+// ortbAdapter posts to a fixed endpoint and parses a plain OpenRTB response
+type ortbAdapter struct {
+	endpoint string
+}
+
+func (a *ortbAdapter) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, []error{err}
+	}
+	return []*adapters.RequestData{{Method: http.MethodPost, URI: a.endpoint, Body: body, Headers: http.Header{}}}, nil
+}
+
+func (a *ortbAdapter) MakeBids(request *openrtb.BidRequest, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	if response.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	var bidResp openrtb.BidResponse
+	if err := json.Unmarshal(response.Body, &bidResp); err != nil {
+		return nil, []error{err}
+	}
+	result := &adapters.BidderResponse{ResponseID: bidResp.ID, Currency: bidResp.Cur}
+	for _, seatBid := range bidResp.SeatBid {
+		for i := range seatBid.Bid {
+			result.Bids = append(result.Bids, &adapters.TypedBid{Bid: &seatBid.Bid[i], BidType: adapters.BidTypeBanner})
+		}
+	}
+	return result, nil
+}
+
+func newExchange(t *testing.T, auctionType exchange.AuctionType, endpoints map[string]string) *exchange.Exchange {
+	t.Helper()
+	registry := adapters.NewRegistry()
+	for code, endpoint := range endpoints {
+		registry.Register(code, &ortbAdapter{endpoint: endpoint}, adapters.BidderInfo{Enabled: true})
+	}
+	config := exchange.DefaultConfig()
+	config.IDREnabled = false
+	config.EventRecordEnabled = false
+	config.AuctionType = auctionType
+	return exchange.New(registry, config)
+}
+
+// recordAuction runs one live auction against two bidder servers and returns its recording
+func recordAuction(t *testing.T) *exchange.RecordedAuction {
+	t.Helper()
+	bidder := func(bidID string, price string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id":"auction-1","seatbid":[{"bid":[{"id":"` + bidID + `","impid":"imp1","price":` + price + `,"adm":"<div></div>","crid":"c1","w":300,"h":250}]}]}`))
+		}))
+	}
+	high, low := bidder("high", "2.00"), bidder("low", "1.20")
+	defer high.Close()
+	defer low.Close()
+
+	ex := newExchange(t, exchange.FirstPriceAuction, map[string]string{"alpha": high.URL, "beta": low.URL})
+	var buf bytes.Buffer
+	recorder := exchange.NewAuctionRecorder(&buf, 1)
+	ex.SetAuctionRecorder(recorder)
+
+	_, err := ex.RunAuction(context.Background(), &exchange.AuctionRequest{BidRequest: &openrtb.BidRequest{
+		ID:   "auction-1",
+		Site: &openrtb.Site{ID: "site-1", Domain: "example.com"},
+		Imp:  []openrtb.Imp{{ID: "imp1", Banner: &openrtb.Banner{W: 300, H: 250}}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	recorder.Close()
+
+	var recordings []*exchange.RecordedAuction
+	err = ReadRecordings(&buf, func(rec *exchange.RecordedAuction) error {
+		recordings = append(recordings, rec)
+		return nil
+	})
+	if err != nil || len(recordings) != 1 {
+		t.Fatalf("Expected 1 recording, got %d (%v)", len(recordings), err)
+	}
+	return recordings[0]
+}
+
+func TestRun_ReplaysRecordedResponses(t *testing.T) {
+	rec := recordAuction(t)
+	if len(rec.Calls) != 2 {
+		t.Fatalf("Expected both bidder calls recorded, got %d", len(rec.Calls))
+	}
+
+	// Replay endpoints are unreachable, so any bid must come from the recording
+	unreachable := map[string]string{"alpha": "http://127.0.0.1:1/alpha", "beta": "http://127.0.0.1:1/beta"}
+	replayOnce := func(auctionType exchange.AuctionType) *Result {
+		ex := newExchange(t, auctionType, unreachable)
+		defer ex.Close()
+		return Run(context.Background(), ex, rec)
+	}
+
+	baseline := replayOnce(exchange.FirstPriceAuction)
+	if baseline.Error != "" || len(baseline.BidderErrors) != 0 {
+		t.Fatalf("Unexpected errors: %q %v", baseline.Error, baseline.BidderErrors)
+	}
+	bids := baseline.Bids["imp1"]
+	if len(bids) == 0 || bids[0].BidID != "high" || bids[0].Price != 2.00 {
+		t.Fatalf("Expected the recorded high bid to win at 2.00, got %+v", bids)
+	}
+
+	if diffs := Diff(baseline, replayOnce(exchange.FirstPriceAuction)); len(diffs) != 0 {
+		t.Errorf("Expected identical replays, got %v", diffs)
+	}
+
+	candidate := replayOnce(exchange.SecondPriceAuction)
+	diffs := Diff(baseline, candidate)
+	if len(diffs) == 0 || !strings.Contains(strings.Join(diffs, "\n"), "price 2.0000 -> 1.2100") {
+		t.Errorf("Expected the second-price change in the diff, got %v", diffs)
+	}
+}
+
+func TestRun_RestoresRecordedPublisher(t *testing.T) {
+	rec := recordAuction(t)
+	unreachable := map[string]string{"alpha": "http://127.0.0.1:1/alpha", "beta": "http://127.0.0.1:1/beta"}
+	replayWith := func(multiplier float64) *Result {
+		ex := newExchange(t, exchange.FirstPriceAuction, unreachable)
+		defer ex.Close()
+		replayed := *rec
+		replayed.Publisher = &exchange.RecordedPublisher{ID: "pub-1", BidMultiplier: multiplier}
+		return Run(context.Background(), ex, &replayed)
+	}
+
+	baseline := replayWith(1.0)
+	candidate := replayWith(1.25)
+	if bids := candidate.Bids["imp1"]; len(bids) == 0 || bids[0].Price != 1.60 {
+		t.Fatalf("Expected the recorded multiplier applied to the winning bid, got %+v", bids)
+	}
+	if diffs := Diff(baseline, candidate); !strings.Contains(strings.Join(diffs, "\n"), "price 2.0000 -> 1.6000") {
+		t.Errorf("Expected the multiplier change in the diff, got %v", diffs)
+	}
+}
+
+func TestClient_Do(t *testing.T) {
+	client := NewClient(&exchange.RecordedAuction{Calls: []exchange.RecordedCall{
+		{Bidder: "alpha", Method: http.MethodPost, URI: "https://a.example.com/1", StatusCode: 200, Body: "one"},
+		{Bidder: "alpha", Method: http.MethodPost, URI: "https://a.example.com/2", StatusCode: 200, Body: "two"},
+		{Bidder: "beta", Method: http.MethodPost, URI: "https://b.example.com", TimedOut: true, Error: "context deadline exceeded"},
+		{Bidder: "gamma", Method: http.MethodPost, URI: "https://c.example.com", Error: "connection refused"},
+	}})
+	request := func(bidder, uri string) *adapters.RequestData {
+		headers := http.Header{}
+		headers.Set(adapters.TestBidderHeader, bidder)
+		return &adapters.RequestData{Method: http.MethodPost, URI: uri, Headers: headers}
+	}
+	ctx := context.Background()
+
+	resp, err := client.Do(ctx, request("alpha", "https://a.example.com/2"), time.Second)
+	if err != nil || string(resp.Body) != "two" {
+		t.Errorf("Expected the call matching the URI, got %v %v", resp, err)
+	}
+	resp, err = client.Do(ctx, request("alpha", "https://a.example.com/changed"), time.Second)
+	if err != nil || string(resp.Body) != "one" {
+		t.Errorf("Expected the next unused call for the bidder, got %v %v", resp, err)
+	}
+	if _, err := client.Do(ctx, request("alpha", "https://a.example.com/1"), time.Second); !errors.Is(err, ErrNoRecordedCall) {
+		t.Errorf("Expected ErrNoRecordedCall once calls are used up, got %v", err)
+	}
+	if _, err := client.Do(ctx, request("beta", "https://b.example.com"), time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a replayed timeout, got %v", err)
+	}
+	if _, err := client.Do(ctx, request("gamma", "https://c.example.com"), time.Second); err == nil || err.Error() != "connection refused" {
+		t.Errorf("Expected the recorded error, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Audit resource types. Bidders are not listed: the bidders table has no admin
+// write path in the server, and per-bidder breaker actions audit as circuit_breaker.
+const (
+	AuditResourcePublisher      = "publisher"
+	AuditResourceCircuitBreaker = "circuit_breaker"
+	AuditResourceCurrency       = "currency_rates"
+)
+
+// Default and maximum rows returned by AuditStore.List
+const (
+	defaultAuditListLimit = 100
+	maxAuditListLimit     = 1000
+)
+
+// AuditChange is the before and after value of one changed field
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry is one row of the append-only admin audit log
+type AuditEntry struct {
+	ID           int64                  `json:"id"`
+	Actor        string                 `json:"actor"`
+	ActorRole    string                 `json:"actor_role"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	Before       json.RawMessage        `json:"before,omitempty"`
+	After        json.RawMessage        `json:"after,omitempty"`
+	Diff         map[string]AuditChange `json:"diff,omitempty"`
+	RemoteAddr   string                 `json:"remote_addr,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// NewAuditEntry builds an entry from the resource state before and after a change.
+// Either state may be nil (creation or removal); the diff covers top-level JSON fields.
+func NewAuditEntry(action, resourceType, resourceID string, before, after interface{}) (*AuditEntry, error) {
+	entry := &AuditEntry{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+	}
+
+	beforeFields, err := auditState(before, &entry.Before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit before state: %w", err)
+	}
+	afterFields, err := auditState(after, &entry.After)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit after state: %w", err)
+	}
+	entry.Diff = DiffAuditFields(beforeFields, afterFields)
+	return entry, nil
+}
+
+// auditState marshals v into raw and returns its top-level fields
+func auditState(v interface{}, raw *json.RawMessage) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	*raw = data
+
+	fields := make(map[string]interface{})
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not an object; diff it as a single value
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		fields[""] = value
+	}
+	return fields, nil
+}
+
+// DiffAuditFields returns the fields whose values differ between before and after
+func DiffAuditFields(before, after map[string]interface{}) map[string]AuditChange {
+	keys := make(map[string]struct{}, len(before)+len(after))
+	for k := range before {
+		keys[k] = struct{}{}
+	}
+	for k := range after {
+		keys[k] = struct{}{}
+	}
+
+	diff := make(map[string]AuditChange)
+	for k := range keys {
+		b, a := before[k], after[k]
+		if !reflect.DeepEqual(b, a) {
+			diff[k] = AuditChange{Before: b, After: a}
+		}
+	}
+	return diff
+}
+
+// AuditFilter narrows AuditStore.List results; empty fields match everything
+type AuditFilter struct {
+	ResourceType string
+	ResourceID   string
+	Actor        string
+	Since        time.Time
+	Limit        int
+}
+
+// AuditStore writes and reads the admin audit log. Rows are never updated or deleted.
+type AuditStore struct {
+	db *sql.DB
+}
+
+// NewAuditStore creates a new audit store
+func NewAuditStore(db *sql.DB) *AuditStore {
+	return &AuditStore{db: db}
+}
+
+// Record appends an entry to the audit log and sets its ID and CreatedAt
+func (s *AuditStore) Record(ctx context.Context, e *AuditEntry) error {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	diff, err := json.Marshal(e.Diff)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit diff: %w", err)
+	}
+
+	query := `
+		INSERT INTO admin_audit_log (
+			actor, actor_role, action, resource_type, resource_id,
+			before_state, after_state, diff, remote_addr
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+
+	err = s.db.QueryRowContext(ctx, query,
+		e.Actor,
+		e.ActorRole,
+		e.Action,
+		e.ResourceType,
+		e.ResourceID,
+		nullableJSON(e.Before),
+		nullableJSON(e.After),
+		diff,
+		e.RemoteAddr,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns audit entries matching the filter, newest first
+func (s *AuditStore) List(ctx context.Context, f AuditFilter) ([]*AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultAuditListLimit
+	}
+	if limit > maxAuditListLimit {
+		limit = maxAuditListLimit
+	}
+
+	query := `
+		SELECT id, actor, actor_role, action, resource_type, resource_id,
+		       before_state, after_state, diff, COALESCE(remote_addr, ''), created_at
+		FROM admin_audit_log
+		WHERE ($1 = '' OR resource_type = $1)
+		  AND ($2 = '' OR resource_id = $2)
+		  AND ($3 = '' OR actor = $3)
+		  AND created_at >= $4
+		ORDER BY id DESC
+		LIMIT $5
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, f.ResourceType, f.ResourceID, f.Actor, f.Since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*AuditEntry
+	for rows.Next() {
+		e := &AuditEntry{}
+		var before, after, diff []byte
+		if err := rows.Scan(
+			&e.ID, &e.Actor, &e.ActorRole, &e.Action, &e.ResourceType, &e.ResourceID,
+			&before, &after, &diff, &e.RemoteAddr, &e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if len(before) > 0 {
+			e.Before = json.RawMessage(before)
+		}
+		if len(after) > 0 {
+			e.After = json.RawMessage(after)
+		}
+		if len(diff) > 0 {
+			if err := json.Unmarshal(diff, &e.Diff); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal audit diff: %w", err)
+			}
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ChangedFields returns the sorted names of the fields in the diff
+func (e *AuditEntry) ChangedFields() []string {
+	fields := make([]string, 0, len(e.Diff))
+	for k := range e.Diff {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// nullableJSON maps an empty raw message to SQL NULL
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return []byte(raw)
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestNewAuditEntry(t *testing.T) {
+	before := &Publisher{PublisherID: "pub-1", Name: "Pub", BidMultiplier: 1.05, Status: "active", Version: 1}
+	after := *before
+	after.BidMultiplier = 1.2
+	after.Version = 2
+
+	entry, err := NewAuditEntry("publisher.update", AuditResourcePublisher, "pub-1", before, &after)
+	if err != nil {
+		t.Fatalf("NewAuditEntry failed: %v", err)
+	}
+	if got := entry.ChangedFields(); !reflect.DeepEqual(got, []string{"bid_multiplier", "version"}) {
+		t.Errorf("Unexpected changed fields: %v", got)
+	}
+	if c := entry.Diff["bid_multiplier"]; c.Before != 1.05 || c.After != 1.2 {
+		t.Errorf("Unexpected multiplier change: %+v", c)
+	}
+	if len(entry.Before) == 0 || len(entry.After) == 0 {
+		t.Error("Expected before and after state to be captured")
+	}
+
+	var nilPublisher *Publisher
+	created, err := NewAuditEntry("publisher.create", AuditResourcePublisher, "pub-1", nilPublisher, before)
+	if err != nil {
+		t.Fatalf("NewAuditEntry failed: %v", err)
+	}
+	if created.Before != nil {
+		t.Errorf("Expected no before state for a typed nil, got %s", created.Before)
+	}
+	if c := created.Diff["publisher_id"]; c.Before != nil || c.After != "pub-1" {
+		t.Errorf("Expected every field as added, got %+v", created.Diff)
+	}
+
+	state, err := NewAuditEntry("circuit_breaker.reset", AuditResourceCircuitBreaker, "rubicon", "open", "closed")
+	if err != nil {
+		t.Fatalf("NewAuditEntry failed: %v", err)
+	}
+	if c := state.Diff[""]; c.Before != "open" || c.After != "closed" {
+		t.Errorf("Expected scalar state diff, got %+v", state.Diff)
+	}
+}
+
+func TestAuditStore_Record(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	entry, _ := NewAuditEntry("publisher.update", AuditResourcePublisher, "pub-1",
+		map[string]interface{}{"status": "active"}, map[string]interface{}{"status": "paused"})
+	entry.Actor = "carol"
+	entry.ActorRole = "finance"
+	entry.RemoteAddr = "10.0.0.1:1234"
+
+	now := time.Now()
+	mock.ExpectQuery("INSERT INTO admin_audit_log").
+		WithArgs("carol", "finance", "publisher.update", "publisher", "pub-1",
+			[]byte(`{"status":"active"}`), []byte(`{"status":"paused"}`), sqlmock.AnyArg(), "10.0.0.1:1234").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(42, now))
+
+	store := NewAuditStore(db)
+	if err := store.Record(context.Background(), entry); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if entry.ID != 42 || !entry.CreatedAt.Equal(now) {
+		t.Errorf("Expected ID and timestamp from insert, got %d %v", entry.ID, entry.CreatedAt)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAuditStore_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	diff, _ := json.Marshal(map[string]AuditChange{"status": {Before: "active", After: "paused"}})
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "actor", "actor_role", "action", "resource_type", "resource_id",
+		"before_state", "after_state", "diff", "remote_addr", "created_at",
+	}).AddRow(7, "carol", "finance", "publisher.update", "publisher", "pub-1",
+		[]byte(`{"status":"active"}`), []byte(`{"status":"paused"}`), diff, "", now)
+
+	mock.ExpectQuery("SELECT (.+) FROM admin_audit_log").
+		WithArgs("publisher", "pub-1", "", time.Time{}, maxAuditListLimit).
+		WillReturnRows(rows)
+
+	store := NewAuditStore(db)
+	entries, err := store.List(context.Background(), AuditFilter{ResourceType: "publisher", ResourceID: "pub-1", Limit: 5000})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if e := entries[0]; e.ID != 7 || e.Diff["status"].After != "paused" || string(e.Before) != `{"status":"active"}` {
+		t.Errorf("Unexpected entry: %+v", e)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
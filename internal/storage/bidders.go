@@ -27,11 +27,23 @@ type Bidder struct {
 	Description      string                 `json:"description,omitempty"`
 	DocumentationURL string                 `json:"documentation_url,omitempty"`
 	ContactEmail     string                 `json:"contact_email,omitempty"`
+	CircuitBreaker   *BidderCircuitBreaker  `json:"circuit_breaker,omitempty"`
 	Version          int                    `json:"version"`
 	CreatedAt        time.Time              `json:"created_at"`
 	UpdatedAt        time.Time              `json:"updated_at"`
 }
 
+// BidderCircuitBreaker holds a bidder's circuit breaker settings. Zero fields take the
+// exchange defaults; a positive ErrorRate switches the bidder to error-rate mode.
+type BidderCircuitBreaker struct {
+	FailureThreshold int     `json:"failure_threshold,omitempty"`
+	SuccessThreshold int     `json:"success_threshold,omitempty"`
+	TimeoutSeconds   int     `json:"timeout_seconds,omitempty"`
+	ErrorRate        float64 `json:"error_rate,omitempty"`
+	WindowSeconds    int     `json:"window_seconds,omitempty"`
+	MinRequests      int     `json:"min_requests,omitempty"`
+}
+
 // PublisherBidder represents a bidder with publisher-specific configuration
 type PublisherBidder struct {
 	Bidder
@@ -59,13 +71,13 @@ func (s *BidderStore) GetByCode(ctx context.Context, bidderCode string) (*Bidder
 		SELECT id, bidder_code, bidder_name, endpoint_url, timeout_ms,
 		       enabled, status, supports_banner, supports_video, supports_native, supports_audio,
 		       gvl_vendor_id, http_headers, description, documentation_url, contact_email,
-		       version, created_at, updated_at
+		       version, created_at, updated_at, circuit_breaker
 		FROM bidders
 		WHERE bidder_code = $1 AND enabled = true AND status = 'active'
 	`
 
 	var b Bidder
-	var httpHeadersJSON []byte
+	var httpHeadersJSON, circuitBreakerJSON []byte
 
 	err := s.db.QueryRowContext(ctx, query, bidderCode).Scan(
 		&b.ID,
@@ -87,6 +99,7 @@ func (s *BidderStore) GetByCode(ctx context.Context, bidderCode string) (*Bidder
 		&b.Version,
 		&b.CreatedAt,
 		&b.UpdatedAt,
+		&circuitBreakerJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -102,6 +115,9 @@ func (s *BidderStore) GetByCode(ctx context.Context, bidderCode string) (*Bidder
 			return nil, fmt.Errorf("failed to parse http_headers: %w", err)
 		}
 	}
+	if b.CircuitBreaker, err = parseCircuitBreaker(circuitBreakerJSON); err != nil {
+		return nil, err
+	}
 
 	return &b, nil
 }
@@ -115,7 +131,7 @@ func (s *BidderStore) ListActive(ctx context.Context) ([]*Bidder, error) {
 		SELECT id, bidder_code, bidder_name, endpoint_url, timeout_ms,
 		       enabled, status, supports_banner, supports_video, supports_native, supports_audio,
 		       gvl_vendor_id, http_headers, description, documentation_url, contact_email,
-		       version, created_at, updated_at
+		       version, created_at, updated_at, circuit_breaker
 		FROM bidders
 		WHERE enabled = true AND status = 'active'
 		ORDER BY bidder_code
@@ -130,7 +146,7 @@ func (s *BidderStore) ListActive(ctx context.Context) ([]*Bidder, error) {
 	bidders := make([]*Bidder, 0, 100)
 	for rows.Next() {
 		var b Bidder
-		var httpHeadersJSON []byte
+		var httpHeadersJSON, circuitBreakerJSON []byte
 
 		err := rows.Scan(
 			&b.ID,
@@ -152,6 +168,7 @@ func (s *BidderStore) ListActive(ctx context.Context) ([]*Bidder, error) {
 			&b.Version,
 			&b.CreatedAt,
 			&b.UpdatedAt,
+			&circuitBreakerJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan bidder row: %w", err)
@@ -163,6 +180,9 @@ func (s *BidderStore) ListActive(ctx context.Context) ([]*Bidder, error) {
 				return nil, fmt.Errorf("failed to parse http_headers: %w", err)
 			}
 		}
+		if b.CircuitBreaker, err = parseCircuitBreaker(circuitBreakerJSON); err != nil {
+			return nil, err
+		}
 
 		bidders = append(bidders, &b)
 	}
@@ -197,6 +217,7 @@ func (s *BidderStore) GetForPublisher(ctx context.Context, publisherID string) (
 			b.version,
 			b.created_at,
 			b.updated_at,
+			b.circuit_breaker,
 			p.publisher_id,
 			p.name as publisher_name,
 			p.bidder_params->b.bidder_code as bidder_config
@@ -219,7 +240,7 @@ func (s *BidderStore) GetForPublisher(ctx context.Context, publisherID string) (
 	bidders := make([]*PublisherBidder, 0, 100)
 	for rows.Next() {
 		var pb PublisherBidder
-		var httpHeadersJSON []byte
+		var httpHeadersJSON, circuitBreakerJSON []byte
 		var bidderConfigJSON []byte
 
 		err := rows.Scan(
@@ -242,6 +263,7 @@ func (s *BidderStore) GetForPublisher(ctx context.Context, publisherID string) (
 			&pb.Version,
 			&pb.CreatedAt,
 			&pb.UpdatedAt,
+			&circuitBreakerJSON,
 			&pb.PublisherID,
 			&pb.PublisherName,
 			&bidderConfigJSON,
@@ -256,6 +278,9 @@ func (s *BidderStore) GetForPublisher(ctx context.Context, publisherID string) (
 				return nil, fmt.Errorf("failed to parse http_headers: %w", err)
 			}
 		}
+		if pb.CircuitBreaker, err = parseCircuitBreaker(circuitBreakerJSON); err != nil {
+			return nil, err
+		}
 
 		// Parse JSONB bidder_config
 		if len(bidderConfigJSON) > 0 {
@@ -279,7 +304,7 @@ func (s *BidderStore) List(ctx context.Context) ([]*Bidder, error) {
 		SELECT id, bidder_code, bidder_name, endpoint_url, timeout_ms,
 		       enabled, status, supports_banner, supports_video, supports_native, supports_audio,
 		       gvl_vendor_id, http_headers, description, documentation_url, contact_email,
-		       version, created_at, updated_at
+		       version, created_at, updated_at, circuit_breaker
 		FROM bidders
 		ORDER BY bidder_code
 	`
@@ -293,7 +318,7 @@ func (s *BidderStore) List(ctx context.Context) ([]*Bidder, error) {
 	bidders := make([]*Bidder, 0, 10)
 	for rows.Next() {
 		var b Bidder
-		var httpHeadersJSON []byte
+		var httpHeadersJSON, circuitBreakerJSON []byte
 
 		err := rows.Scan(
 			&b.ID,
@@ -315,6 +340,7 @@ func (s *BidderStore) List(ctx context.Context) ([]*Bidder, error) {
 			&b.Version,
 			&b.CreatedAt,
 			&b.UpdatedAt,
+			&circuitBreakerJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan bidder row: %w", err)
@@ -326,6 +352,9 @@ func (s *BidderStore) List(ctx context.Context) ([]*Bidder, error) {
 				return nil, fmt.Errorf("failed to parse http_headers: %w", err)
 			}
 		}
+		if b.CircuitBreaker, err = parseCircuitBreaker(circuitBreakerJSON); err != nil {
+			return nil, err
+		}
 
 		bidders = append(bidders, &b)
 	}
@@ -342,8 +371,8 @@ func (s *BidderStore) Create(ctx context.Context, b *Bidder) error {
 		INSERT INTO bidders (
 			bidder_code, bidder_name, endpoint_url, timeout_ms,
 			enabled, status, supports_banner, supports_video, supports_native, supports_audio,
-			gvl_vendor_id, http_headers, description, documentation_url, contact_email
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			gvl_vendor_id, http_headers, description, documentation_url, contact_email, circuit_breaker
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		RETURNING id, version, created_at, updated_at
 	`
 
@@ -351,6 +380,10 @@ func (s *BidderStore) Create(ctx context.Context, b *Bidder) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal http_headers: %w", err)
 	}
+	circuitBreakerJSON, err := marshalCircuitBreaker(b.CircuitBreaker)
+	if err != nil {
+		return err
+	}
 
 	// Default status to 'active' if not set to prevent DB constraint violation
 	status := b.Status
@@ -374,6 +407,7 @@ func (s *BidderStore) Create(ctx context.Context, b *Bidder) error {
 		b.Description,
 		b.DocumentationURL,
 		b.ContactEmail,
+		circuitBreakerJSON,
 	).Scan(&b.ID, &b.Version, &b.CreatedAt, &b.UpdatedAt)
 
 	if err != nil {
@@ -415,14 +449,19 @@ func (s *BidderStore) Update(ctx context.Context, b *Bidder) error {
 		SET bidder_name = $1, endpoint_url = $2, timeout_ms = $3,
 		    enabled = $4, status = $5, supports_banner = $6, supports_video = $7,
 		    supports_native = $8, supports_audio = $9, gvl_vendor_id = $10,
-		    http_headers = $11, description = $12, documentation_url = $13, contact_email = $14
-		WHERE bidder_code = $15 AND version = $16
+		    http_headers = $11, description = $12, documentation_url = $13, contact_email = $14,
+		    circuit_breaker = $15
+		WHERE bidder_code = $16 AND version = $17
 	`
 
 	httpHeadersJSON, err := json.Marshal(b.HTTPHeaders)
 	if err != nil {
 		return fmt.Errorf("failed to marshal http_headers: %w", err)
 	}
+	circuitBreakerJSON, err := marshalCircuitBreaker(b.CircuitBreaker)
+	if err != nil {
+		return err
+	}
 
 	result, err := tx.ExecContext(ctx, query,
 		b.BidderName,
@@ -439,6 +478,7 @@ func (s *BidderStore) Update(ctx context.Context, b *Bidder) error {
 		b.Description,
 		b.DocumentationURL,
 		b.ContactEmail,
+		circuitBreakerJSON,
 		b.BidderCode,
 		b.Version,
 	)
@@ -532,7 +572,7 @@ func (s *BidderStore) GetCapabilities(ctx context.Context, banner, video, native
 		SELECT id, bidder_code, bidder_name, endpoint_url, timeout_ms,
 		       enabled, status, supports_banner, supports_video, supports_native, supports_audio,
 		       gvl_vendor_id, http_headers, description, documentation_url, contact_email,
-		       version, created_at, updated_at
+		       version, created_at, updated_at, circuit_breaker
 		FROM bidders
 		WHERE enabled = true
 		  AND status = 'active'
@@ -552,7 +592,7 @@ func (s *BidderStore) GetCapabilities(ctx context.Context, banner, video, native
 	bidders := make([]*Bidder, 0, 100)
 	for rows.Next() {
 		var b Bidder
-		var httpHeadersJSON []byte
+		var httpHeadersJSON, circuitBreakerJSON []byte
 
 		err := rows.Scan(
 			&b.ID,
@@ -574,6 +614,7 @@ func (s *BidderStore) GetCapabilities(ctx context.Context, banner, video, native
 			&b.Version,
 			&b.CreatedAt,
 			&b.UpdatedAt,
+			&circuitBreakerJSON,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan bidder row: %w", err)
@@ -585,9 +626,37 @@ func (s *BidderStore) GetCapabilities(ctx context.Context, banner, video, native
 				return nil, fmt.Errorf("failed to parse http_headers: %w", err)
 			}
 		}
+		if b.CircuitBreaker, err = parseCircuitBreaker(circuitBreakerJSON); err != nil {
+			return nil, err
+		}
 
 		bidders = append(bidders, &b)
 	}
 
 	return bidders, rows.Err()
 }
+
+// parseCircuitBreaker parses the JSONB circuit_breaker column; NULL means no bidder settings
+func parseCircuitBreaker(data []byte) (*BidderCircuitBreaker, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var cb BidderCircuitBreaker
+	if err := json.Unmarshal(data, &cb); err != nil {
+		return nil, fmt.Errorf("failed to parse circuit_breaker: %w", err)
+	}
+	return &cb, nil
+}
+
+// marshalCircuitBreaker encodes circuit breaker settings, storing NULL when there are none
+func marshalCircuitBreaker(cb *BidderCircuitBreaker) (*string, error) {
+	if cb == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(cb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal circuit_breaker: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
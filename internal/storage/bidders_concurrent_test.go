@@ -52,6 +52,7 @@ func TestBidderStore_Update_OptimisticLocking_Success(t *testing.T) {
 			bidder.Description,
 			bidder.DocumentationURL,
 			bidder.ContactEmail,
+			nil, // circuit_breaker
 			bidder.BidderCode,
 			1, // version
 		).
@@ -288,7 +289,7 @@ func TestBidderStore_GetByCode_IncludesVersion(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).AddRow(
 		expectedBidder.ID,
 		expectedBidder.BidderCode,
@@ -309,6 +310,7 @@ func TestBidderStore_GetByCode_IncludesVersion(t *testing.T) {
 		expectedBidder.Version,
 		expectedBidder.CreatedAt,
 		expectedBidder.UpdatedAt,
+		nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE bidder_code").
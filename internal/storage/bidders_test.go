@@ -44,7 +44,7 @@ func TestBidderStore_GetByCode_Success(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).AddRow(
 		expectedBidder.ID,
 		expectedBidder.BidderCode,
@@ -65,6 +65,7 @@ func TestBidderStore_GetByCode_Success(t *testing.T) {
 		1, // version
 		expectedBidder.CreatedAt,
 		expectedBidder.UpdatedAt,
+		nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE bidder_code").
@@ -167,12 +168,12 @@ func TestBidderStore_GetByCode_InvalidJSON(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).AddRow(
 		"1", "appnexus", "AppNexus", "https://example.com", 500,
 		true, "active", true, true, false, false,
 		nil, []byte("invalid json{"), "", "", "",
-		1, time.Now(), time.Now(),
+		1, time.Now(), time.Now(), nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE bidder_code").
@@ -213,19 +214,19 @@ func TestBidderStore_ListActive_Success(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).
 		AddRow(
 			bidder1.ID, bidder1.BidderCode, bidder1.BidderName, bidder1.EndpointURL, bidder1.TimeoutMs,
 			bidder1.Enabled, bidder1.Status, bidder1.SupportsBanner, bidder1.SupportsVideo, bidder1.SupportsNative, bidder1.SupportsAudio,
 			bidder1.GVLVendorID, headers1, bidder1.Description, bidder1.DocumentationURL, bidder1.ContactEmail,
-			1, bidder1.CreatedAt, bidder1.UpdatedAt,
+			1, bidder1.CreatedAt, bidder1.UpdatedAt, nil,
 		).
 		AddRow(
 			bidder2.ID, bidder2.BidderCode, bidder2.BidderName, bidder2.EndpointURL, bidder2.TimeoutMs,
 			bidder2.Enabled, bidder2.Status, bidder2.SupportsBanner, bidder2.SupportsVideo, bidder2.SupportsNative, bidder2.SupportsAudio,
 			bidder2.GVLVendorID, headers2, bidder2.Description, bidder2.DocumentationURL, bidder2.ContactEmail,
-			1, bidder2.CreatedAt, bidder2.UpdatedAt,
+			1, bidder2.CreatedAt, bidder2.UpdatedAt, []byte(`{"error_rate":0.5,"window_seconds":30}`),
 		)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled").
@@ -246,6 +247,12 @@ func TestBidderStore_ListActive_Success(t *testing.T) {
 	if bidders[1].BidderCode != "rubicon" {
 		t.Errorf("Expected second bidder 'rubicon', got '%s'", bidders[1].BidderCode)
 	}
+	if bidders[0].CircuitBreaker != nil {
+		t.Errorf("Expected no circuit breaker settings for appnexus, got %+v", bidders[0].CircuitBreaker)
+	}
+	if cb := bidders[1].CircuitBreaker; cb == nil || cb.ErrorRate != 0.5 || cb.WindowSeconds != 30 {
+		t.Errorf("Expected rubicon circuit breaker settings, got %+v", cb)
+	}
 
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %v", err)
@@ -266,7 +273,7 @@ func TestBidderStore_ListActive_Empty(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	})
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled").
@@ -330,12 +337,12 @@ func TestBidderStore_ListActive_ScanError(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).AddRow(
 		"1", "appnexus", "AppNexus", "https://example.com", "invalid_int",
 		true, "active", true, true, false, false,
 		nil, []byte("{}"), "", "", "",
-		1, time.Now(), time.Now(),
+		1, time.Now(), time.Now(), nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled").
@@ -374,12 +381,12 @@ func TestBidderStore_GetForPublisher_Success(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at", "publisher_id", "publisher_name", "bidder_config",
+		"version", "created_at", "updated_at", "circuit_breaker", "publisher_id", "publisher_name", "bidder_config",
 	}).AddRow(
 		"1", "appnexus", "AppNexus", "https://ib.adnxs.com/openrtb2", 500,
 		true, "active", true, true, false, false,
 		nil, httpHeadersJSON, "AppNexus bidder", "https://example.com", "test@example.com",
-		1, time.Now(), time.Now(), "pub123", "Test Publisher", bidderConfigJSON,
+		1, time.Now(), time.Now(), nil, "pub123", "Test Publisher", bidderConfigJSON,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders b CROSS JOIN publishers p").
@@ -428,7 +435,7 @@ func TestBidderStore_GetForPublisher_Empty(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at", "publisher_id", "publisher_name", "bidder_config",
+		"version", "created_at", "updated_at", "circuit_breaker", "publisher_id", "publisher_name", "bidder_config",
 	})
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders b CROSS JOIN publishers p").
@@ -539,16 +546,16 @@ func TestBidderStore_List_Success(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).
 		AddRow(bidder1.ID, bidder1.BidderCode, bidder1.BidderName, bidder1.EndpointURL, bidder1.TimeoutMs,
 			bidder1.Enabled, bidder1.Status, bidder1.SupportsBanner, bidder1.SupportsVideo, bidder1.SupportsNative, bidder1.SupportsAudio,
 			bidder1.GVLVendorID, httpHeadersJSON1, bidder1.Description, bidder1.DocumentationURL, bidder1.ContactEmail,
-			1, bidder1.CreatedAt, bidder1.UpdatedAt).
+			1, bidder1.CreatedAt, bidder1.UpdatedAt, nil).
 		AddRow(bidder2.ID, bidder2.BidderCode, bidder2.BidderName, bidder2.EndpointURL, bidder2.TimeoutMs,
 			bidder2.Enabled, bidder2.Status, bidder2.SupportsBanner, bidder2.SupportsVideo, bidder2.SupportsNative, bidder2.SupportsAudio,
 			bidder2.GVLVendorID, httpHeadersJSON2, bidder2.Description, bidder2.DocumentationURL, bidder2.ContactEmail,
-			1, bidder2.CreatedAt, bidder2.UpdatedAt)
+			1, bidder2.CreatedAt, bidder2.UpdatedAt, nil)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders ORDER BY bidder_code").
 		WillReturnRows(rows)
@@ -582,7 +589,7 @@ func TestBidderStore_List_Empty(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	})
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders ORDER BY bidder_code").
@@ -651,6 +658,7 @@ func TestBidderStore_Create_Success(t *testing.T) {
 			bidder.SupportsNative, bidder.SupportsAudio, bidder.GVLVendorID,
 			sqlmock.AnyArg(), // http_headers JSON
 			bidder.Description, bidder.DocumentationURL, bidder.ContactEmail,
+			nil, // circuit_breaker
 		).
 		WillReturnRows(rows)
 
@@ -727,6 +735,7 @@ func TestBidderStore_Update_Success(t *testing.T) {
 			bidder.SupportsNative, bidder.SupportsAudio, bidder.GVLVendorID,
 			sqlmock.AnyArg(), // http_headers JSON
 			bidder.Description, bidder.DocumentationURL, bidder.ContactEmail,
+			nil, // circuit_breaker
 			bidder.BidderCode,
 			1, // version
 		).
@@ -923,12 +932,12 @@ func TestBidderStore_GetCapabilities_Banner(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).AddRow(
 		bidder.ID, bidder.BidderCode, bidder.BidderName, bidder.EndpointURL, bidder.TimeoutMs,
 		bidder.Enabled, bidder.Status, bidder.SupportsBanner, bidder.SupportsVideo, bidder.SupportsNative, bidder.SupportsAudio,
 		bidder.GVLVendorID, httpHeadersJSON, bidder.Description, bidder.DocumentationURL, bidder.ContactEmail,
-		1, bidder.CreatedAt, bidder.UpdatedAt,
+		1, bidder.CreatedAt, bidder.UpdatedAt, nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled = true AND status = 'active'").
@@ -964,7 +973,7 @@ func TestBidderStore_GetCapabilities_MultipleFormats(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	})
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled = true AND status = 'active'").
@@ -1004,12 +1013,12 @@ func TestBidderStore_GetCapabilities_AllFormats(t *testing.T) {
 		"id", "bidder_code", "bidder_name", "endpoint_url", "timeout_ms",
 		"enabled", "status", "supports_banner", "supports_video", "supports_native", "supports_audio",
 		"gvl_vendor_id", "http_headers", "description", "documentation_url", "contact_email",
-		"version", "created_at", "updated_at",
+		"version", "created_at", "updated_at", "circuit_breaker",
 	}).AddRow(
 		bidder.ID, bidder.BidderCode, bidder.BidderName, bidder.EndpointURL, bidder.TimeoutMs,
 		bidder.Enabled, bidder.Status, bidder.SupportsBanner, bidder.SupportsVideo, bidder.SupportsNative, bidder.SupportsAudio,
 		bidder.GVLVendorID, httpHeadersJSON, bidder.Description, bidder.DocumentationURL, bidder.ContactEmail,
-		1, bidder.CreatedAt, bidder.UpdatedAt,
+		1, bidder.CreatedAt, bidder.UpdatedAt, nil,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM bidders WHERE enabled = true AND status = 'active'").
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/thenexusengine/tne_springwire/pkg/currency"
+)
+
+// Default and maximum rows returned by CurrencyRateStore.List
+const (
+	defaultRateSnapshotListLimit = 50
+	maxRateSnapshotListLimit     = 500
+)
+
+// CurrencyRateStore persists currency rate snapshots by version
+type CurrencyRateStore struct {
+	db *sql.DB
+}
+
+// NewCurrencyRateStore creates a new currency rate store
+func NewCurrencyRateStore(db *sql.DB) *CurrencyRateStore {
+	return &CurrencyRateStore{db: db}
+}
+
+// SaveSnapshot inserts a snapshot; a version that already exists is left unchanged
+func (s *CurrencyRateStore) SaveSnapshot(ctx context.Context, snapshot *currency.RateSnapshot) error {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	conversions, err := json.Marshal(snapshot.Conversions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rate snapshot: %w", err)
+	}
+
+	query := `
+		INSERT INTO currency_rate_snapshots (
+			version, source, generated_at, data_as_of, conversions, loaded_at
+		) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (version) DO NOTHING
+	`
+
+	_, err = s.db.ExecContext(ctx, query,
+		snapshot.Version,
+		snapshot.Source,
+		snapshot.GeneratedAt,
+		snapshot.DataAsOf,
+		conversions,
+		snapshot.LoadedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save rate snapshot %s: %w", snapshot.Version, err)
+	}
+	return nil
+}
+
+// Get retrieves a snapshot by version, returning nil if it doesn't exist
+func (s *CurrencyRateStore) Get(ctx context.Context, version string) (*currency.RateSnapshot, error) {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	query := `
+		SELECT version, source, COALESCE(generated_at, ''), COALESCE(data_as_of, ''), conversions, loaded_at
+		FROM currency_rate_snapshots
+		WHERE version = $1
+	`
+
+	snapshot, err := scanRateSnapshot(s.db.QueryRowContext(ctx, query, version))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rate snapshot %s: %w", version, err)
+	}
+	return snapshot, nil
+}
+
+// List returns the most recently loaded snapshots, newest first
+func (s *CurrencyRateStore) List(ctx context.Context, limit int) ([]*currency.RateSnapshot, error) {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	if limit <= 0 {
+		limit = defaultRateSnapshotListLimit
+	}
+	if limit > maxRateSnapshotListLimit {
+		limit = maxRateSnapshotListLimit
+	}
+
+	query := `
+		SELECT version, source, COALESCE(generated_at, ''), COALESCE(data_as_of, ''), conversions, loaded_at
+		FROM currency_rate_snapshots
+		ORDER BY loaded_at DESC
+		LIMIT $1
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rate snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := make([]*currency.RateSnapshot, 0)
+	for rows.Next() {
+		snapshot, err := scanRateSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rate snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rate snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// scanRateSnapshot scans one currency_rate_snapshots row
+func scanRateSnapshot(row rowScanner) (*currency.RateSnapshot, error) {
+	var snapshot currency.RateSnapshot
+	var conversions []byte
+	if err := row.Scan(
+		&snapshot.Version,
+		&snapshot.Source,
+		&snapshot.GeneratedAt,
+		&snapshot.DataAsOf,
+		&conversions,
+		&snapshot.LoadedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(conversions, &snapshot.Conversions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rate snapshot %s: %w", snapshot.Version, err)
+	}
+	return &snapshot, nil
+}
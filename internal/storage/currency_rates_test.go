@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/thenexusengine/tne_springwire/pkg/currency"
+)
+
+func TestCurrencyRateStore_SaveSnapshot(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	snapshot := currency.NewRateSnapshot(currency.ProviderFile, &currency.CurrencyFile{
+		DataAsOf:    "2026-10-01",
+		Conversions: map[string]map[string]float64{"USD": {"EUR": 0.9}},
+	})
+
+	mock.ExpectExec("INSERT INTO currency_rate_snapshots").
+		WithArgs(snapshot.Version, "file", "", "2026-10-01", []byte(`{"USD":{"EUR":0.9}}`), snapshot.LoadedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	store := NewCurrencyRateStore(db)
+	if err := store.SaveSnapshot(context.Background(), snapshot); err != nil {
+		t.Fatalf("SaveSnapshot failed: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCurrencyRateStore_Get(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	columns := []string{"version", "source", "generated_at", "data_as_of", "conversions", "loaded_at"}
+	mock.ExpectQuery("SELECT (.+) FROM currency_rate_snapshots").
+		WithArgs("abc123").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("abc123", "http", "", "2026-10-01", []byte(`{"USD":{"EUR":0.9}}`), now))
+	mock.ExpectQuery("SELECT (.+) FROM currency_rate_snapshots").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	store := NewCurrencyRateStore(db)
+	snapshot, err := store.Get(context.Background(), "abc123")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if snapshot.Source != "http" || snapshot.Conversions["USD"]["EUR"] != 0.9 {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+
+	snapshot, err = store.Get(context.Background(), "missing")
+	if err != nil || snapshot != nil {
+		t.Errorf("Expected nil snapshot for unknown version, got %+v %v", snapshot, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCurrencyRateStore_List(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT (.+) FROM currency_rate_snapshots").
+		WithArgs(maxRateSnapshotListLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "source", "generated_at", "data_as_of", "conversions", "loaded_at"}).
+			AddRow("v2", "admin", "", "", []byte(`{"USD":{"EUR":0.95}}`), now).
+			AddRow("v1", "http", "", "", []byte(`{"USD":{"EUR":0.9}}`), now.Add(-time.Hour)))
+
+	store := NewCurrencyRateStore(db)
+	snapshots, err := store.List(context.Background(), 10000)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Version != "v2" {
+		t.Errorf("Unexpected snapshots: %+v", snapshots)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -3,14 +3,27 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 )
 
+// Publisher store errors, usable with errors.Is
+var (
+	ErrPublisherNotFound      = errors.New("publisher not found")
+	ErrPublisherExists        = errors.New("publisher already exists")
+	ErrConcurrentModification = errors.New("concurrent modification detected")
+)
+
+// pgUniqueViolation is the PostgreSQL error code for unique constraint violations
+const pgUniqueViolation = "23505"
+
 // Publisher represents a publisher configuration from the database
 type Publisher struct {
 	ID             string                 `json:"id"`
@@ -168,6 +181,100 @@ func (s *PublisherStore) List(ctx context.Context) ([]*Publisher, error) {
 	return publishers, rows.Err()
 }
 
+// Get retrieves a publisher by publisher_id regardless of status.
+// Returns nil if the publisher does not exist.
+func (s *PublisherStore) Get(ctx context.Context, publisherID string) (*Publisher, error) {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, publisher_id, name, allowed_domains, bidder_params, bid_multiplier,
+		       status, version, created_at, updated_at, notes, contact_email
+		FROM publishers
+		WHERE publisher_id = $1
+	`
+
+	p, err := scanPublisher(s.db.QueryRowContext(ctx, query, publisherID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query publisher: %w", err)
+	}
+	return p, nil
+}
+
+// ListByStatus retrieves publishers with the given status, or all publishers if status is empty
+func (s *PublisherStore) ListByStatus(ctx context.Context, status string) ([]*Publisher, error) {
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	query := `
+		SELECT id, publisher_id, name, allowed_domains, bidder_params, bid_multiplier,
+		       status, version, created_at, updated_at, notes, contact_email
+		FROM publishers
+		WHERE $1 = '' OR status = $1
+		ORDER BY publisher_id
+	`
+
+	rows, err := s.db.QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query publishers: %w", err)
+	}
+	defer rows.Close()
+
+	publishers := make([]*Publisher, 0, 100)
+	for rows.Next() {
+		p, err := scanPublisher(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan publisher row: %w", err)
+		}
+		publishers = append(publishers, p)
+	}
+
+	return publishers, rows.Err()
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPublisher scans a full publisher row, tolerating NULL notes and contact email
+func scanPublisher(row rowScanner) (*Publisher, error) {
+	var p Publisher
+	var bidderParamsJSON []byte
+	var notes, contactEmail sql.NullString
+
+	err := row.Scan(
+		&p.ID,
+		&p.PublisherID,
+		&p.Name,
+		&p.AllowedDomains,
+		&bidderParamsJSON,
+		&p.BidMultiplier,
+		&p.Status,
+		&p.Version,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+		&notes,
+		&contactEmail,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.Notes = notes.String
+	p.ContactEmail = contactEmail.String
+
+	if len(bidderParamsJSON) > 0 {
+		if err := json.Unmarshal(bidderParamsJSON, &p.BidderParams); err != nil {
+			return nil, fmt.Errorf("failed to parse bidder_params: %w", err)
+		}
+	}
+
+	return &p, nil
+}
+
 // Create adds a new publisher
 func (s *PublisherStore) Create(ctx context.Context, p *Publisher) error {
 	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
@@ -208,6 +315,10 @@ func (s *PublisherStore) Create(ctx context.Context, p *Publisher) error {
 	).Scan(&p.ID, &p.Version, &p.CreatedAt, &p.UpdatedAt)
 
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+			return fmt.Errorf("%w: %s", ErrPublisherExists, p.PublisherID)
+		}
 		return fmt.Errorf("failed to create publisher: %w", err)
 	}
 
@@ -230,7 +341,7 @@ func (s *PublisherStore) Update(ctx context.Context, p *Publisher) error {
 	var currentVersion int
 	err = tx.QueryRowContext(ctx, "SELECT version FROM publishers WHERE publisher_id = $1", p.PublisherID).Scan(&currentVersion)
 	if err == sql.ErrNoRows {
-		return fmt.Errorf("publisher not found: %s", p.PublisherID)
+		return fmt.Errorf("%w: %s", ErrPublisherNotFound, p.PublisherID)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to check version: %w", err)
@@ -238,7 +349,7 @@ func (s *PublisherStore) Update(ctx context.Context, p *Publisher) error {
 
 	// Verify version matches (optimistic lock check)
 	if currentVersion != p.Version {
-		return fmt.Errorf("concurrent modification detected: publisher %s was updated by another process", p.PublisherID)
+		return fmt.Errorf("%w: publisher %s was updated by another process", ErrConcurrentModification, p.PublisherID)
 	}
 
 	query := `
@@ -275,7 +386,7 @@ func (s *PublisherStore) Update(ctx context.Context, p *Publisher) error {
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("concurrent modification detected: publisher %s version mismatch", p.PublisherID)
+		return fmt.Errorf("%w: publisher %s version mismatch", ErrConcurrentModification, p.PublisherID)
 	}
 
 	// Commit transaction
@@ -311,7 +422,7 @@ func (s *PublisherStore) Delete(ctx context.Context, publisherID string) error {
 	}
 
 	if rows == 0 {
-		return fmt.Errorf("publisher not found: %s", publisherID)
+		return fmt.Errorf("%w: %s", ErrPublisherNotFound, publisherID)
 	}
 
 	return nil
@@ -383,3 +494,37 @@ func NewDBConnection(ctx context.Context, host, port, user, password, dbname, ss
 
 	return db, nil
 }
+
+// HashReportAPIKey returns the digest stored in publishers.report_api_key_hash
+func HashReportAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetPublisherIDByReportAPIKey resolves an active publisher from a reporting API key.
+// Returns an empty string if no publisher matches.
+func (s *PublisherStore) GetPublisherIDByReportAPIKey(ctx context.Context, apiKey string) (string, error) {
+	if apiKey == "" {
+		return "", nil
+	}
+
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	query := `
+		SELECT publisher_id
+		FROM publishers
+		WHERE report_api_key_hash = $1 AND status = 'active'
+	`
+
+	var publisherID string
+	err := s.db.QueryRowContext(ctx, query, HashReportAPIKey(apiKey)).Scan(&publisherID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query publisher by report API key: %w", err)
+	}
+
+	return publisherID, nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 )
 
 // createTestPublisher creates a test publisher for use in tests
@@ -850,3 +851,93 @@ func TestPublisher_GetterMethods(t *testing.T) {
 		t.Errorf("Expected 1.05, got %f", publisher.GetBidMultiplier())
 	}
 }
+
+func TestPublisherStore_Get_AnyStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "publisher_id", "name", "allowed_domains", "bidder_params", "bid_multiplier",
+		"status", "version", "created_at", "updated_at", "notes", "contact_email",
+	}).AddRow("1", "pub-paused", "Paused", "example.com", []byte(`{"rubicon":{"accountId":1}}`), 1.1,
+		"paused", 3, now, now, nil, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM publishers WHERE publisher_id = \\$1$").
+		WithArgs("pub-paused").
+		WillReturnRows(rows)
+	mock.ExpectQuery("SELECT (.+) FROM publishers WHERE publisher_id = \\$1$").
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	store := NewPublisherStore(db)
+	p, err := store.Get(context.Background(), "pub-paused")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if p.Status != "paused" || p.Version != 3 || p.Notes != "" || p.BidderParams["rubicon"] == nil {
+		t.Errorf("Unexpected publisher: %+v", p)
+	}
+
+	p, err = store.Get(context.Background(), "missing")
+	if err != nil || p != nil {
+		t.Errorf("Expected nil publisher without error, got %+v (err=%v)", p, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPublisherStore_ListByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "publisher_id", "name", "allowed_domains", "bidder_params", "bid_multiplier",
+		"status", "version", "created_at", "updated_at", "notes", "contact_email",
+	}).
+		AddRow("1", "pub-a", "A", "a.com", []byte(`{}`), 1.0, "active", 1, now, now, "", "").
+		AddRow("2", "pub-b", "B", "b.com", []byte(`{}`), 1.0, "archived", 2, now, now, "", "")
+
+	mock.ExpectQuery("SELECT (.+) FROM publishers WHERE \\$1 = '' OR status = \\$1").
+		WithArgs("").
+		WillReturnRows(rows)
+
+	store := NewPublisherStore(db)
+	publishers, err := store.ListByStatus(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListByStatus failed: %v", err)
+	}
+	if len(publishers) != 2 || publishers[1].Status != "archived" {
+		t.Errorf("Unexpected publishers: %+v", publishers)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPublisherStore_Create_Duplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO publishers").
+		WillReturnError(&pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"})
+
+	store := NewPublisherStore(db)
+	err = store.Create(context.Background(), createTestPublisher("dup"))
+	if !errors.Is(err, ErrPublisherExists) {
+		t.Errorf("Expected ErrPublisherExists, got %v", err)
+	}
+}
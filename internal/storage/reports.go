@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Report dimensions accepted in ReportQuery.GroupBy
+const (
+	ReportGroupDay       = "day"
+	ReportGroupHour      = "hour"
+	ReportGroupBidder    = "bidder"
+	ReportGroupAdUnit    = "ad_unit"
+	ReportGroupMediaType = "media_type"
+	ReportGroupCountry   = "country"
+)
+
+// reportDimension maps a dimension to its column expression in the imps and bids tables
+type reportDimension struct {
+	impExpr string
+	bidExpr string
+}
+
+var reportDimensions = map[string]reportDimension{
+	ReportGroupDay:       {"date_trunc('day', i.created_at AT TIME ZONE 'UTC')", "date_trunc('day', b.created_at AT TIME ZONE 'UTC')"},
+	ReportGroupHour:      {"date_trunc('hour', i.created_at AT TIME ZONE 'UTC')", "date_trunc('hour', b.created_at AT TIME ZONE 'UTC')"},
+	ReportGroupBidder:    {"r.bidder_code", "b.bidder_code"},
+	ReportGroupAdUnit:    {"COALESCE(i.ad_unit, '')", "COALESCE(b.ad_unit, '')"},
+	ReportGroupMediaType: {"COALESCE(i.media_type, '')", "COALESCE(b.media_type, '')"},
+	ReportGroupCountry:   {"COALESCE(i.country, '')", "COALESCE(b.country, '')"},
+}
+
+// ReportQuery selects a publisher's report rows
+type ReportQuery struct {
+	PublisherID string
+	From        time.Time // inclusive
+	To          time.Time // exclusive
+	GroupBy     []string
+}
+
+// Validate checks the query for unsupported or conflicting dimensions
+func (q *ReportQuery) Validate() error {
+	if q.PublisherID == "" {
+		return fmt.Errorf("publisher ID is required")
+	}
+	if !q.To.After(q.From) {
+		return fmt.Errorf("report end must be after start")
+	}
+
+	seen := make(map[string]bool, len(q.GroupBy))
+	for _, dim := range q.GroupBy {
+		if _, ok := reportDimensions[dim]; !ok {
+			return fmt.Errorf("unsupported group_by dimension: %s", dim)
+		}
+		if seen[dim] {
+			return fmt.Errorf("duplicate group_by dimension: %s", dim)
+		}
+		seen[dim] = true
+	}
+	if seen[ReportGroupDay] && seen[ReportGroupHour] {
+		return fmt.Errorf("group_by cannot include both day and hour")
+	}
+	return nil
+}
+
+// ReportRow is one aggregated report row. Dimension fields are only set when grouped by them.
+// AdRequests are requested impression opportunities; when grouped by bidder they are the
+// opportunities offered to that bidder.
+//
+// Wins and impressions come from persisted notifications (analytics_notifications) joined to
+// the winning bids: a win is an auction winner with any win, billing or impression notification,
+// and an impression is one with a billing or impression notification. Revenue, payout and margin
+// are summed over impressions only and are in the exchange currency.
+type ReportRow struct {
+	Period       *time.Time `json:"period,omitempty"`
+	Bidder       string     `json:"bidder,omitempty"`
+	AdUnit       string     `json:"ad_unit,omitempty"`
+	MediaType    string     `json:"media_type,omitempty"`
+	Country      string     `json:"country,omitempty"`
+	AdRequests   int64      `json:"ad_requests"`
+	Bids         int64      `json:"bids"`
+	Wins         int64      `json:"wins"`
+	Impressions  int64      `json:"impressions"`
+	FillRate     float64    `json:"fill_rate"`
+	ECPM         float64    `json:"ecpm"`
+	GrossRevenue float64    `json:"gross_revenue"`
+	Payout       float64    `json:"payout"`
+	Margin       float64    `json:"margin"`
+}
+
+// ReportStore provides reporting queries over the analytics tables
+type ReportStore struct {
+	db *sql.DB
+}
+
+// NewReportStore creates a new report store
+func NewReportStore(db *sql.DB) *ReportStore {
+	return &ReportStore{db: db}
+}
+
+// Query aggregates ad requests, bids, wins, impressions and revenue for one publisher
+func (s *ReportStore) Query(ctx context.Context, q *ReportQuery) ([]*ReportRow, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withTimeout(ctx, DefaultDBTimeout)
+	defer cancel()
+
+	query := buildReportQuery(q.GroupBy)
+	rows, err := s.db.QueryContext(ctx, query, q.PublisherID, q.From, q.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query report: %w", err)
+	}
+	defer rows.Close()
+
+	var report []*ReportRow
+	for rows.Next() {
+		row := &ReportRow{}
+		var period time.Time
+		dest := make([]interface{}, 0, len(q.GroupBy)+6)
+		for _, dim := range q.GroupBy {
+			switch dim {
+			case ReportGroupDay, ReportGroupHour:
+				dest = append(dest, &period)
+			case ReportGroupBidder:
+				dest = append(dest, &row.Bidder)
+			case ReportGroupAdUnit:
+				dest = append(dest, &row.AdUnit)
+			case ReportGroupMediaType:
+				dest = append(dest, &row.MediaType)
+			case ReportGroupCountry:
+				dest = append(dest, &row.Country)
+			}
+		}
+		dest = append(dest, &row.AdRequests, &row.Bids, &row.Wins, &row.Impressions, &row.GrossRevenue, &row.Payout)
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan report row: %w", err)
+		}
+		if !period.IsZero() {
+			p := period.UTC()
+			row.Period = &p
+		}
+		row.computeDerived()
+		report = append(report, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating report rows: %w", err)
+	}
+
+	return report, nil
+}
+
+// computeDerived fills fill rate, eCPM and margin from the raw totals
+func (r *ReportRow) computeDerived() {
+	r.Margin = r.GrossRevenue - r.Payout
+	if r.AdRequests > 0 {
+		r.FillRate = float64(r.Impressions) / float64(r.AdRequests)
+	}
+	if r.Impressions > 0 {
+		r.ECPM = r.Payout / float64(r.Impressions) * 1000
+	}
+}
+
+// buildReportQuery builds the aggregate query for the given (validated) dimensions.
+// Ad requests and bids are aggregated separately and joined on the dimension values
+// so that a multi-bid auction does not inflate request counts. Notifications are
+// collapsed to one row per bid before the join so repeated pixels count once; they
+// are not bounded by the report end because delivery can follow the auction.
+func buildReportQuery(groupBy []string) string {
+	impCols := make([]string, 0, len(groupBy))
+	bidCols := make([]string, 0, len(groupBy))
+	outCols := make([]string, 0, len(groupBy))
+	joinConds := make([]string, 0, len(groupBy))
+	byBidder := false
+
+	for i, dim := range groupBy {
+		d := reportDimensions[dim]
+		alias := fmt.Sprintf("d%d", i)
+		impCols = append(impCols, d.impExpr+" AS "+alias)
+		bidCols = append(bidCols, d.bidExpr+" AS "+alias)
+		outCols = append(outCols, fmt.Sprintf("COALESCE(imps.%s, bids.%s)", alias, alias))
+		joinConds = append(joinConds, fmt.Sprintf("imps.%s = bids.%s", alias, alias))
+		if dim == ReportGroupBidder {
+			byBidder = true
+		}
+	}
+
+	impFrom := "analytics_imps i"
+	if byBidder {
+		impFrom += " JOIN analytics_bidder_results r ON r.auction_id = i.auction_id AND r.publisher_id = i.publisher_id"
+	}
+
+	impSelect := "COUNT(*) AS ad_requests"
+	bidSelect := `COUNT(*) AS bids,
+		       COUNT(*) FILTER (WHERE b.won AND n.bid_id IS NOT NULL) AS wins,
+		       COUNT(*) FILTER (WHERE b.won AND n.delivered) AS impressions,
+		       COALESCE(SUM(b.gross_price) FILTER (WHERE b.won AND n.delivered), 0) / 1000 AS gross_revenue,
+		       COALESCE(SUM(b.clearing_price) FILTER (WHERE b.won AND n.delivered), 0) / 1000 AS payout`
+	impGroup, bidGroup, join, order := "", "", "CROSS JOIN bids", ""
+	if len(groupBy) > 0 {
+		impSelect = strings.Join(impCols, ", ") + ", " + impSelect
+		bidSelect = strings.Join(bidCols, ", ") + ", " + bidSelect
+		positions := make([]string, len(groupBy))
+		for i := range groupBy {
+			positions[i] = fmt.Sprintf("%d", i+1)
+		}
+		impGroup = "GROUP BY " + strings.Join(positions, ", ")
+		bidGroup = impGroup
+		join = "FULL OUTER JOIN bids ON " + strings.Join(joinConds, " AND ")
+		order = "ORDER BY " + strings.Join(positions, ", ")
+		outCols = append(outCols, "")
+	}
+
+	return fmt.Sprintf(`
+		WITH imps AS (
+		    SELECT %s
+		    FROM %s
+		    WHERE i.publisher_id = $1 AND i.created_at >= $2 AND i.created_at < $3
+		    %s
+		), bids AS (
+		    SELECT %s
+		    FROM analytics_bids b
+		    LEFT JOIN (
+		        SELECT bid_id, bool_or(notification_type IN ('impression', 'billing')) AS delivered
+		        FROM analytics_notifications
+		        WHERE created_at >= $2
+		        GROUP BY bid_id
+		    ) n ON n.bid_id = b.bid_id AND b.won
+		    WHERE b.publisher_id = $1 AND b.created_at >= $2 AND b.created_at < $3
+		    %s
+		)
+		SELECT %sCOALESCE(imps.ad_requests, 0), COALESCE(bids.bids, 0), COALESCE(bids.wins, 0),
+		       COALESCE(bids.impressions, 0), COALESCE(bids.gross_revenue, 0), COALESCE(bids.payout, 0)
+		FROM imps %s
+		%s
+	`, impSelect, impFrom, impGroup, bidSelect, bidGroup, strings.Join(outCols, ", "), join, order)
+}
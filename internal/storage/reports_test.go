@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestReportQuery_Validate(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		query   ReportQuery
+		wantErr bool
+	}{
+		{"valid", ReportQuery{PublisherID: "pub-1", From: from, To: to, GroupBy: []string{"day", "bidder"}}, false},
+		{"no dimensions", ReportQuery{PublisherID: "pub-1", From: from, To: to}, false},
+		{"missing publisher", ReportQuery{From: from, To: to}, true},
+		{"inverted range", ReportQuery{PublisherID: "pub-1", From: to, To: from}, true},
+		{"unknown dimension", ReportQuery{PublisherID: "pub-1", From: from, To: to, GroupBy: []string{"device"}}, true},
+		{"duplicate dimension", ReportQuery{PublisherID: "pub-1", From: from, To: to, GroupBy: []string{"bidder", "bidder"}}, true},
+		{"day and hour", ReportQuery{PublisherID: "pub-1", From: from, To: to, GroupBy: []string{"day", "hour"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBuildReportQuery(t *testing.T) {
+	query := buildReportQuery([]string{"bidder", "country"})
+	if !strings.Contains(query, "JOIN analytics_bidder_results r") {
+		t.Error("expected bidder grouping to join bidder results for impressions")
+	}
+	if !strings.Contains(query, "FULL OUTER JOIN bids ON imps.d0 = bids.d0 AND imps.d1 = bids.d1") {
+		t.Errorf("expected join on both dimensions, got %s", query)
+	}
+
+	if !strings.Contains(query, "LEFT JOIN (") || !strings.Contains(query, "FROM analytics_notifications") {
+		t.Errorf("expected wins and impressions from notifications, got %s", query)
+	}
+
+	ungrouped := buildReportQuery(nil)
+	if !strings.Contains(ungrouped, "CROSS JOIN bids") || strings.Contains(ungrouped, "GROUP BY 1") {
+		t.Errorf("expected single-row totals query, got %s", ungrouped)
+	}
+}
+
+func TestReportStore_Query(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	day := from
+
+	rows := sqlmock.NewRows([]string{"d0", "d1", "ad_requests", "bids", "wins", "impressions", "gross_revenue", "payout"}).
+		AddRow(day, "rubicon", 1000, 400, 120, 100, 0.5, 0.4).
+		AddRow(day, "appnexus", 1000, 10, 2, 0, 0, 0)
+
+	mock.ExpectQuery("WITH imps AS").
+		WithArgs("pub-1", from, to).
+		WillReturnRows(rows)
+
+	store := NewReportStore(db)
+	report, err := store.Query(context.Background(), &ReportQuery{
+		PublisherID: "pub-1",
+		From:        from,
+		To:          to,
+		GroupBy:     []string{"day", "bidder"},
+	})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(report))
+	}
+
+	r := report[0]
+	if r.Period == nil || !r.Period.Equal(day) || r.Bidder != "rubicon" {
+		t.Errorf("Unexpected dimensions: %+v", r)
+	}
+	if r.Wins != 120 || r.Impressions != 100 {
+		t.Errorf("Expected 120 wins and 100 impressions, got %+v", r)
+	}
+	if r.FillRate != 0.1 {
+		t.Errorf("Expected fill rate 0.1, got %v", r.FillRate)
+	}
+	if r.ECPM < 3.999 || r.ECPM > 4.001 {
+		t.Errorf("Expected eCPM 4.00, got %v", r.ECPM)
+	}
+	if r.Margin < 0.0999 || r.Margin > 0.1001 {
+		t.Errorf("Expected margin 0.10, got %v", r.Margin)
+	}
+	if report[1].ECPM != 0 || report[1].FillRate != 0 {
+		t.Errorf("Expected zero rates without impressions, got %+v", report[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestReportStore_QueryRejectsInvalid(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	store := NewReportStore(db)
+	_, err = store.Query(context.Background(), &ReportQuery{PublisherID: "pub-1", GroupBy: []string{"day"}})
+	if err == nil {
+		t.Error("Expected error for empty date range")
+	}
+}
+
+func TestPublisherStore_GetPublisherIDByReportAPIKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock DB: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT publisher_id FROM publishers WHERE report_api_key_hash").
+		WithArgs(HashReportAPIKey("secret-key")).
+		WillReturnRows(sqlmock.NewRows([]string{"publisher_id"}).AddRow("pub-1"))
+	mock.ExpectQuery("SELECT publisher_id FROM publishers WHERE report_api_key_hash").
+		WithArgs(HashReportAPIKey("wrong-key")).
+		WillReturnRows(sqlmock.NewRows([]string{"publisher_id"}))
+
+	store := NewPublisherStore(db)
+	id, err := store.GetPublisherIDByReportAPIKey(context.Background(), "secret-key")
+	if err != nil || id != "pub-1" {
+		t.Errorf("Expected pub-1, got %q (err=%v)", id, err)
+	}
+	id, err = store.GetPublisherIDByReportAPIKey(context.Background(), "wrong-key")
+	if err != nil || id != "" {
+		t.Errorf("Expected no match, got %q (err=%v)", id, err)
+	}
+	id, err = store.GetPublisherIDByReportAPIKey(context.Background(), "")
+	if err != nil || id != "" {
+		t.Errorf("Expected empty key to be rejected without a query, got %q (err=%v)", id, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
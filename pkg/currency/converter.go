@@ -4,10 +4,9 @@ package currency
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,14 +23,16 @@ type CurrencyFile struct {
 // Converter handles currency conversions using external and custom rates
 type Converter struct {
 	// Configuration
-	fetchURL        string
+	providers       []RateProvider // Priority order, highest first
+	admin           *StaticProvider
+	store           SnapshotStore
 	refreshInterval time.Duration
 	staleThreshold  time.Duration
-	httpClient      *http.Client
 
 	// State
 	mu           sync.RWMutex
 	rates        *CurrencyFile
+	snapshot     *RateSnapshot
 	lastFetch    time.Time
 	fetchErrors  int
 	running      bool
@@ -55,6 +56,14 @@ type Config struct {
 	// HTTP client timeout
 	// Default: 10 seconds
 	FetchTimeout time.Duration
+
+	// Local Prebid currency file used by the "file" provider
+	// Default: "" (file provider disabled)
+	RatesFile string
+
+	// Rate providers by name in priority order; the first that returns rates wins
+	// Default: admin, http, file
+	Providers []string
 }
 
 // DefaultConfig returns recommended configuration
@@ -64,6 +73,7 @@ func DefaultConfig() *Config {
 		RefreshInterval: 30 * time.Minute,
 		StaleThreshold:  24 * time.Hour,
 		FetchTimeout:    10 * time.Second,
+		Providers:       []string{ProviderAdmin, ProviderHTTP, ProviderFile},
 	}
 }
 
@@ -73,15 +83,49 @@ func NewConverter(config *Config) *Converter {
 		config = DefaultConfig()
 	}
 
-	return &Converter{
-		fetchURL:        config.FetchURL,
+	c := &Converter{
+		admin:           NewStaticProvider(ProviderAdmin),
 		refreshInterval: config.RefreshInterval,
 		staleThreshold:  config.StaleThreshold,
-		httpClient: &http.Client{
-			Timeout: config.FetchTimeout,
-		},
-		stopChan: make(chan struct{}),
+		stopChan:        make(chan struct{}),
+	}
+
+	names := config.Providers
+	if len(names) == 0 {
+		names = DefaultConfig().Providers
+	}
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case ProviderAdmin:
+			c.providers = append(c.providers, c.admin)
+		case ProviderHTTP:
+			if config.FetchURL != "" {
+				c.providers = append(c.providers, NewHTTPProvider(config.FetchURL, config.FetchTimeout))
+			}
+		case ProviderFile:
+			if config.RatesFile != "" {
+				c.providers = append(c.providers, NewFileProvider(config.RatesFile))
+			}
+		default:
+			logger.Log.Warn().Str("provider", name).Msg("unknown currency rate provider ignored")
+		}
 	}
+
+	return c
+}
+
+// NewConverterWithProviders creates a converter that uses the given providers in priority order
+func NewConverterWithProviders(config *Config, providers ...RateProvider) *Converter {
+	c := NewConverter(config)
+	c.providers = providers
+	return c
+}
+
+// SetSnapshotStore sets where newly loaded rate snapshots are persisted
+func (c *Converter) SetSnapshotStore(store SnapshotStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = store
 }
 
 // Start begins background rate updates
@@ -138,56 +182,103 @@ func (c *Converter) refreshLoop(ctx context.Context) {
 	}
 }
 
-// fetchRates downloads and parses the currency file
+// fetchRates loads rates from the highest priority provider that succeeds.
+// If every provider fails the previous rates stay in use.
 func (c *Converter) fetchRates(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.fetchURL, nil)
-	if err != nil {
-		c.incrementErrors()
-		return fmt.Errorf("create request: %w", err)
+	var errs []error
+	for _, provider := range c.providers {
+		file, err := provider.FetchRates(ctx)
+		if err == nil {
+			err = file.Validate()
+		}
+		if err != nil {
+			if !errors.Is(err, ErrNoRates) {
+				errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			}
+			continue
+		}
+		c.install(ctx, NewRateSnapshot(provider.Name(), file), file)
+		return nil
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.incrementErrors()
-		return fmt.Errorf("fetch rates: %w", err)
+	c.incrementErrors()
+	if len(errs) == 0 {
+		return ErrNoRates
 	}
-	defer resp.Body.Close()
+	return errors.Join(errs...)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		c.incrementErrors()
-		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+// install makes a snapshot current and persists it when its version is new
+func (c *Converter) install(ctx context.Context, snapshot *RateSnapshot, file *CurrencyFile) {
+	c.mu.Lock()
+	changed := c.snapshot == nil || c.snapshot.Version != snapshot.Version
+	if changed {
+		c.snapshot = snapshot
+		c.rates = file
 	}
+	c.lastFetch = time.Now()
+	c.fetchErrors = 0
+	store := c.store
+	c.mu.Unlock()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.incrementErrors()
-		return fmt.Errorf("read body: %w", err)
+	if !changed {
+		return
 	}
 
-	var currencyFile CurrencyFile
-	if err := json.Unmarshal(body, &currencyFile); err != nil {
-		c.incrementErrors()
-		return fmt.Errorf("parse JSON: %w", err)
+	logger.Log.Info().
+		Int("currencies", len(file.Conversions)).
+		Str("dataAsOf", file.DataAsOf).
+		Str("source", snapshot.Source).
+		Str("version", snapshot.Version).
+		Msg("currency rates updated")
+
+	if store != nil {
+		if err := store.SaveSnapshot(ctx, snapshot); err != nil {
+			logger.Log.Error().Err(err).Str("version", snapshot.Version).Msg("failed to persist currency rate snapshot")
+		}
 	}
+}
+
+// ErrAdminProviderDisabled is returned when rates are pushed but the admin provider is not configured
+var ErrAdminProviderDisabled = errors.New("admin currency rate provider not enabled")
 
-	// Validate rates
-	if len(currencyFile.Conversions) == 0 {
-		c.incrementErrors()
-		return fmt.Errorf("no conversions in currency file")
+// PushRates installs an admin-supplied rate set. It is used ahead of any provider
+// listed after the admin provider in Config.Providers.
+func (c *Converter) PushRates(ctx context.Context, file *CurrencyFile) (*RateSnapshot, error) {
+	if !c.hasProvider(c.admin) {
+		return nil, ErrAdminProviderDisabled
+	}
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	c.admin.Set(file)
+	if err := c.fetchRates(ctx); err != nil {
+		return nil, err
 	}
+	return c.Snapshot(), nil
+}
 
-	c.mu.Lock()
-	c.rates = &currencyFile
-	c.lastFetch = time.Now()
-	c.fetchErrors = 0
-	c.mu.Unlock()
+// ClearPushedRates removes admin-supplied rates and reloads from the remaining providers
+func (c *Converter) ClearPushedRates(ctx context.Context) error {
+	c.admin.Clear()
+	return c.fetchRates(ctx)
+}
 
-	logger.Log.Info().
-		Int("currencies", len(currencyFile.Conversions)).
-		Str("dataAsOf", currencyFile.DataAsOf).
-		Msg("currency rates updated")
+// hasProvider reports whether p is one of the converter's providers
+func (c *Converter) hasProvider(p RateProvider) bool {
+	for _, provider := range c.providers {
+		if provider == p {
+			return true
+		}
+	}
+	return false
+}
 
-	return nil
+// Snapshot returns the rate snapshot currently in use, or nil if none is loaded
+func (c *Converter) Snapshot() *RateSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot
 }
 
 // incrementErrors safely increments error counter
@@ -201,16 +292,22 @@ func (c *Converter) incrementErrors() {
 // Returns 1.0 if currencies are the same
 // Returns error if conversion not available
 func (c *Converter) GetRate(from, to string) (float64, error) {
+	rate, _, err := c.GetRateVersion(from, to)
+	return rate, err
+}
+
+// GetRateVersion returns the conversion rate with the version of the snapshot it came from
+func (c *Converter) GetRateVersion(from, to string) (float64, string, error) {
 	// Same currency = 1.0 rate
 	if from == to {
-		return 1.0, nil
+		return 1.0, "", nil
 	}
 
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	if c.rates == nil {
-		return 0, fmt.Errorf("no currency rates available")
+		return 0, "", ErrNoRates
 	}
 
 	// Check if rates are stale
@@ -221,14 +318,19 @@ func (c *Converter) GetRate(from, to string) (float64, error) {
 			Msg("currency rates are stale")
 	}
 
+	version := ""
+	if c.snapshot != nil {
+		version = c.snapshot.Version
+	}
+
 	// Look up conversion rate
 	if fromRates, ok := c.rates.Conversions[from]; ok {
 		if rate, ok := fromRates[to]; ok {
-			return rate, nil
+			return rate, version, nil
 		}
 	}
 
-	return 0, fmt.Errorf("no conversion available from %s to %s", from, to)
+	return 0, "", fmt.Errorf("no conversion available from %s to %s", from, to)
 }
 
 // Convert converts an amount from one currency to another
@@ -278,6 +380,17 @@ func (c *Converter) Stats() map[string]interface{} {
 		stats["generatedAt"] = c.rates.GeneratedAt
 	}
 
+	if c.snapshot != nil {
+		stats["source"] = c.snapshot.Source
+		stats["version"] = c.snapshot.Version
+	}
+
+	providers := make([]string, 0, len(c.providers))
+	for _, p := range c.providers {
+		providers = append(providers, p.Name())
+	}
+	stats["providers"] = providers
+
 	if !c.lastFetch.IsZero() {
 		stats["lastFetch"] = c.lastFetch
 		stats["age"] = time.Since(c.lastFetch).String()
@@ -306,36 +419,47 @@ func NewAggregateConversions(customRates map[string]map[string]float64, external
 
 // GetRate returns conversion rate, checking custom then external
 func (a *AggregateConversions) GetRate(from, to string) (float64, error) {
+	rate, _, err := a.GetRateVersion(from, to)
+	return rate, err
+}
+
+// GetRateVersion returns the conversion rate and the version of the rates that priced it:
+// CustomRatesVersion for request rates, otherwise the converter's snapshot version
+func (a *AggregateConversions) GetRateVersion(from, to string) (float64, string, error) {
 	// Same currency
 	if from == to {
-		return 1.0, nil
+		return 1.0, "", nil
 	}
 
 	// Determine priority based on useExternal flag
 	if a.useExternal {
 		// Swap priority
 		if a.externalRates != nil {
-			rate, err := a.externalRates.GetRate(from, to)
+			rate, version, err := a.externalRates.GetRateVersion(from, to)
 			if err == nil {
-				return rate, nil
+				return rate, version, nil
 			}
 		}
 		// Fall back to custom
-		return a.getCustomRate(from, to)
+		rate, err := a.getCustomRate(from, to)
+		if err != nil {
+			return 0, "", err
+		}
+		return rate, CustomRatesVersion, nil
 	}
 
 	// Custom first (default)
 	rate, err := a.getCustomRate(from, to)
 	if err == nil {
-		return rate, nil
+		return rate, CustomRatesVersion, nil
 	}
 
 	// Fall back to external
 	if a.externalRates != nil {
-		return a.externalRates.GetRate(from, to)
+		return a.externalRates.GetRateVersion(from, to)
 	}
 
-	return 0, fmt.Errorf("no conversion available from %s to %s", from, to)
+	return 0, "", fmt.Errorf("no conversion available from %s to %s", from, to)
 }
 
 // getCustomRate looks up a custom rate
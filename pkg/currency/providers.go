@@ -0,0 +1,157 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Provider names used in Config.Providers
+const (
+	ProviderAdmin = "admin"
+	ProviderHTTP  = "http"
+	ProviderFile  = "file"
+)
+
+// ErrNoRates is returned by a provider that currently has no rates to offer
+var ErrNoRates = errors.New("no currency rates available")
+
+// RateProvider is a source of currency rates.
+// The converter asks providers in priority order and uses the first that succeeds.
+type RateProvider interface {
+	Name() string
+	FetchRates(ctx context.Context) (*CurrencyFile, error)
+}
+
+// HTTPProvider fetches a Prebid currency file over HTTP
+type HTTPProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProvider creates a provider that fetches rates from url
+func NewHTTPProvider(url string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the provider name
+func (p *HTTPProvider) Name() string { return ProviderHTTP }
+
+// FetchRates downloads and parses the currency file
+func (p *HTTPProvider) FetchRates(ctx context.Context) (*CurrencyFile, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return ParseCurrencyFile(body)
+}
+
+// FileProvider reads a Prebid currency file from local disk, for air-gapped deployments
+type FileProvider struct {
+	path string
+}
+
+// NewFileProvider creates a provider that reads rates from path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Name returns the provider name
+func (p *FileProvider) Name() string { return ProviderFile }
+
+// FetchRates reads and parses the currency file
+func (p *FileProvider) FetchRates(ctx context.Context) (*CurrencyFile, error) {
+	body, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, fmt.Errorf("read rates file: %w", err)
+	}
+	return ParseCurrencyFile(body)
+}
+
+// StaticProvider serves a rate set pushed at runtime, e.g. by an admin
+type StaticProvider struct {
+	name  string
+	mu    sync.RWMutex
+	rates *CurrencyFile
+}
+
+// NewStaticProvider creates an empty static provider
+func NewStaticProvider(name string) *StaticProvider {
+	return &StaticProvider{name: name}
+}
+
+// Name returns the provider name
+func (p *StaticProvider) Name() string { return p.name }
+
+// Set replaces the served rate set
+func (p *StaticProvider) Set(rates *CurrencyFile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates = rates
+}
+
+// Clear removes the served rate set so lower priority providers are used
+func (p *StaticProvider) Clear() {
+	p.Set(nil)
+}
+
+// FetchRates returns the current rate set, or ErrNoRates when none is set
+func (p *StaticProvider) FetchRates(ctx context.Context) (*CurrencyFile, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.rates == nil {
+		return nil, ErrNoRates
+	}
+	return p.rates, nil
+}
+
+// ParseCurrencyFile parses and validates a Prebid currency file
+func ParseCurrencyFile(data []byte) (*CurrencyFile, error) {
+	var currencyFile CurrencyFile
+	if err := json.Unmarshal(data, &currencyFile); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	if err := currencyFile.Validate(); err != nil {
+		return nil, err
+	}
+	return &currencyFile, nil
+}
+
+// Validate checks that the file holds at least one usable rate
+func (f *CurrencyFile) Validate() error {
+	if len(f.Conversions) == 0 {
+		return fmt.Errorf("no conversions in currency file")
+	}
+	for from, toRates := range f.Conversions {
+		for to, rate := range toRates {
+			if rate <= 0 {
+				return fmt.Errorf("invalid rate %s->%s: %v", from, to, rate)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySnapshotStore records saved snapshots
+type memorySnapshotStore struct {
+	mu        sync.Mutex
+	snapshots []*RateSnapshot
+}
+
+func (m *memorySnapshotStore) SaveSnapshot(ctx context.Context, snapshot *RateSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots = append(m.snapshots, snapshot)
+	return nil
+}
+
+func (m *memorySnapshotStore) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.snapshots)
+}
+
+func writeRatesFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write rates file: %v", err)
+	}
+	return path
+}
+
+func TestFileProvider(t *testing.T) {
+	path := writeRatesFile(t, `{"dataAsOf":"2026-10-01","conversions":{"USD":{"EUR":0.9}}}`)
+
+	file, err := NewFileProvider(path).FetchRates(context.Background())
+	if err != nil {
+		t.Fatalf("FetchRates() error = %v", err)
+	}
+	if file.Conversions["USD"]["EUR"] != 0.9 {
+		t.Errorf("unexpected rates: %v", file.Conversions)
+	}
+
+	if _, err := NewFileProvider(filepath.Join(t.TempDir(), "missing.json")).FetchRates(context.Background()); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	bad := writeRatesFile(t, `{"conversions":{"USD":{"EUR":-1}}}`)
+	if _, err := NewFileProvider(bad).FetchRates(context.Background()); err == nil {
+		t.Error("expected error for negative rate")
+	}
+}
+
+func TestConverter_ProviderFallback(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	path := writeRatesFile(t, `{"dataAsOf":"2026-10-01","conversions":{"USD":{"EUR":0.9}}}`)
+	store := &memorySnapshotStore{}
+
+	converter := NewConverter(&Config{
+		FetchURL:        failing.URL,
+		RatesFile:       path,
+		RefreshInterval: time.Hour,
+		StaleThreshold:  time.Hour,
+		FetchTimeout:    time.Second,
+	})
+	converter.SetSnapshotStore(store)
+
+	if err := converter.fetchRates(context.Background()); err != nil {
+		t.Fatalf("fetchRates() error = %v", err)
+	}
+	snapshot := converter.Snapshot()
+	if snapshot == nil || snapshot.Source != ProviderFile || snapshot.Version == "" {
+		t.Fatalf("expected snapshot from file provider, got %+v", snapshot)
+	}
+
+	rate, version, err := converter.GetRateVersion("USD", "EUR")
+	if err != nil || rate != 0.9 || version != snapshot.Version {
+		t.Errorf("GetRateVersion() = %v, %q, %v", rate, version, err)
+	}
+
+	// Reloading identical rates keeps the version and is not persisted again
+	if err := converter.fetchRates(context.Background()); err != nil {
+		t.Fatalf("fetchRates() error = %v", err)
+	}
+	if converter.Snapshot().Version != snapshot.Version || store.count() != 1 {
+		t.Errorf("expected one persisted snapshot, got %d", store.count())
+	}
+}
+
+func TestConverter_AllProvidersFail_KeepsRates(t *testing.T) {
+	static := NewStaticProvider("static")
+	static.Set(&CurrencyFile{Conversions: map[string]map[string]float64{"USD": {"GBP": 0.8}}})
+	converter := NewConverterWithProviders(nil, static)
+
+	if err := converter.fetchRates(context.Background()); err != nil {
+		t.Fatalf("fetchRates() error = %v", err)
+	}
+
+	static.Clear()
+	if err := converter.fetchRates(context.Background()); !errors.Is(err, ErrNoRates) {
+		t.Errorf("expected ErrNoRates, got %v", err)
+	}
+	if rate, err := converter.GetRate("USD", "GBP"); err != nil || rate != 0.8 {
+		t.Errorf("expected previous rates to stay in use, got %v %v", rate, err)
+	}
+}
+
+func TestConverter_PushRates(t *testing.T) {
+	path := writeRatesFile(t, `{"conversions":{"USD":{"EUR":0.9}}}`)
+	store := &memorySnapshotStore{}
+	converter := NewConverter(&Config{RatesFile: path, Providers: []string{ProviderAdmin, ProviderFile}})
+	converter.SetSnapshotStore(store)
+
+	if err := converter.fetchRates(context.Background()); err != nil {
+		t.Fatalf("fetchRates() error = %v", err)
+	}
+
+	snapshot, err := converter.PushRates(context.Background(), &CurrencyFile{
+		Conversions: map[string]map[string]float64{"USD": {"EUR": 0.95}},
+	})
+	if err != nil {
+		t.Fatalf("PushRates() error = %v", err)
+	}
+	if snapshot.Source != ProviderAdmin {
+		t.Errorf("expected admin snapshot, got %s", snapshot.Source)
+	}
+	if rate, _ := converter.GetRate("USD", "EUR"); rate != 0.95 {
+		t.Errorf("expected pushed rate 0.95, got %v", rate)
+	}
+
+	if err := converter.ClearPushedRates(context.Background()); err != nil {
+		t.Fatalf("ClearPushedRates() error = %v", err)
+	}
+	if got := converter.Snapshot(); got.Source != ProviderFile {
+		t.Errorf("expected fallback to file provider, got %s", got.Source)
+	}
+	if store.count() != 3 {
+		t.Errorf("expected 3 persisted snapshots, got %d", store.count())
+	}
+
+	// Admin provider not configured
+	fileOnly := NewConverter(&Config{RatesFile: path, Providers: []string{ProviderFile}})
+	if _, err := fileOnly.PushRates(context.Background(), &CurrencyFile{}); !errors.Is(err, ErrAdminProviderDisabled) {
+		t.Errorf("expected ErrAdminProviderDisabled, got %v", err)
+	}
+}
+
+func TestAggregateConversions_GetRateVersion(t *testing.T) {
+	external := NewConverterWithProviders(nil)
+	external.install(context.Background(), NewRateSnapshot("static", &CurrencyFile{
+		Conversions: map[string]map[string]float64{"USD": {"EUR": 0.85}, "EUR": {"USD": 1.18}},
+	}), &CurrencyFile{Conversions: map[string]map[string]float64{"USD": {"EUR": 0.85}, "EUR": {"USD": 1.18}}})
+	snapshotVersion := external.Snapshot().Version
+
+	agg := NewAggregateConversions(map[string]map[string]float64{"USD": {"EUR": 0.9}}, external, false)
+
+	if _, version, _ := agg.GetRateVersion("USD", "EUR"); version != CustomRatesVersion {
+		t.Errorf("expected custom rates version, got %q", version)
+	}
+	if _, version, _ := agg.GetRateVersion("EUR", "USD"); version != snapshotVersion {
+		t.Errorf("expected snapshot version %q, got %q", snapshotVersion, version)
+	}
+}
+
+func TestNewRateSnapshot_Version(t *testing.T) {
+	file := &CurrencyFile{DataAsOf: "2026-10-01", Conversions: map[string]map[string]float64{"USD": {"EUR": 0.9, "GBP": 0.8}}}
+	same := &CurrencyFile{DataAsOf: "2026-10-01", Conversions: map[string]map[string]float64{"USD": {"GBP": 0.8, "EUR": 0.9}}}
+	changed := &CurrencyFile{DataAsOf: "2026-10-01", Conversions: map[string]map[string]float64{"USD": {"EUR": 0.91, "GBP": 0.8}}}
+
+	v := NewRateSnapshot(ProviderHTTP, file).Version
+	if NewRateSnapshot(ProviderHTTP, same).Version != v {
+		t.Error("expected identical rates to share a version")
+	}
+	if NewRateSnapshot(ProviderHTTP, changed).Version == v {
+		t.Error("expected changed rates to get a new version")
+	}
+	if NewRateSnapshot(ProviderFile, file).Version == v {
+		t.Error("expected a different source to get a new version")
+	}
+}
@@ -0,0 +1,52 @@
+package currency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// CustomRatesVersion is reported for conversions priced by ext.prebid.currency.rates
+const CustomRatesVersion = "request"
+
+// RateSnapshot is an immutable rate set as loaded from one provider
+type RateSnapshot struct {
+	Version     string                        `json:"version"`
+	Source      string                        `json:"source"`
+	GeneratedAt string                        `json:"generated_at,omitempty"`
+	DataAsOf    string                        `json:"data_as_of,omitempty"`
+	Conversions map[string]map[string]float64 `json:"conversions"`
+	LoadedAt    time.Time                     `json:"loaded_at"`
+}
+
+// SnapshotStore persists rate snapshots so a version can be traced back to its rates
+type SnapshotStore interface {
+	SaveSnapshot(ctx context.Context, snapshot *RateSnapshot) error
+}
+
+// NewRateSnapshot builds a snapshot for a currency file loaded from source.
+// The version is derived from the source and rate content, so reloading identical
+// rates yields the same version.
+func NewRateSnapshot(source string, file *CurrencyFile) *RateSnapshot {
+	return &RateSnapshot{
+		Version:     snapshotVersion(source, file),
+		Source:      source,
+		GeneratedAt: file.GeneratedAt,
+		DataAsOf:    file.DataAsOf,
+		Conversions: file.Conversions,
+		LoadedAt:    time.Now(),
+	}
+}
+
+// snapshotVersion hashes the source and rates; json.Marshal sorts map keys so the output is stable
+func snapshotVersion(source string, file *CurrencyFile) string {
+	data, _ := json.Marshal(struct {
+		Source      string                        `json:"source"`
+		DataAsOf    string                        `json:"data_as_of"`
+		Conversions map[string]map[string]float64 `json:"conversions"`
+	}{source, file.DataAsOf, file.Conversions})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,194 @@
+package idr
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Selection reasons set by the bandit selector
+const (
+	ReasonBanditScore = "BANDIT_SCORE" // Among the highest sampled expected CPMs
+	ReasonExploration = "EXPLORATION"  // Picked at random to keep estimates fresh
+)
+
+// BanditConfig configures the in-process bandit selector
+type BanditConfig struct {
+	Enabled         bool
+	MaxBidders      int     // Bidders selected per auction
+	ExplorationRate float64 // Chance each slot goes to a random bidder instead of the best sample (0-1)
+	MinObservations int     // Segment observations needed before its stats replace the bidder's global stats
+	MaxSegments     int     // Segments tracked; further segments use global stats only
+}
+
+// DefaultBanditConfig returns the default bandit configuration (disabled)
+func DefaultBanditConfig() *BanditConfig {
+	return &BanditConfig{
+		Enabled:         false,
+		MaxBidders:      8,
+		ExplorationRate: 0.1,
+		MinObservations: 20,
+		MaxSegments:     10000,
+	}
+}
+
+// Segment is the traffic slice bidder performance is tracked for
+type Segment struct {
+	Publisher  string
+	Country    string
+	DeviceType string
+	MediaType  string
+}
+
+// armStats is one bidder's record in one segment
+type armStats struct {
+	responses int     // Bid responses observed
+	bids      int     // Responses with a bid
+	cpmSum    float64 // Sum of bid CPMs
+}
+
+// BanditSelector selects bidders with Thompson sampling over each bidder's bid rate and eCPM
+// per segment. It learns from the IDR bid event stream, so it can stand in for the IDR service
+// when that is unavailable.
+type BanditSelector struct {
+	config   *BanditConfig
+	mu       sync.Mutex
+	segments map[Segment]map[string]*armStats
+	global   map[string]*armStats
+	rng      *rand.Rand
+}
+
+// NewBanditSelector creates a bandit selector
+func NewBanditSelector(config *BanditConfig) *BanditSelector {
+	if config == nil {
+		config = DefaultBanditConfig()
+	}
+	return &BanditSelector{
+		config:   config,
+		segments: make(map[Segment]map[string]*armStats),
+		global:   make(map[string]*armStats),
+		rng:      rand.New(rand.NewSource(rand.Int63())), //nolint:gosec // Sampling, not security
+	}
+}
+
+// Observe trains the selector on a bid event; only "bid_response" events are used
+func (b *BanditSelector) Observe(event BidEvent) {
+	if event.EventType != "bid_response" || event.BidderCode == "" {
+		return
+	}
+	segment := Segment{
+		Publisher:  event.PublisherID,
+		Country:    event.Country,
+		DeviceType: event.DeviceType,
+		MediaType:  event.MediaType,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	update := func(arms map[string]*armStats) {
+		arm, ok := arms[event.BidderCode]
+		if !ok {
+			arm = &armStats{}
+			arms[event.BidderCode] = arm
+		}
+		arm.responses++
+		if event.HadBid && event.BidCPM != nil {
+			arm.bids++
+			arm.cpmSum += *event.BidCPM
+		}
+	}
+
+	update(b.global)
+	arms, ok := b.segments[segment]
+	if !ok {
+		if len(b.segments) >= b.config.MaxSegments {
+			return
+		}
+		arms = make(map[string]*armStats)
+		b.segments[segment] = arms
+	}
+	update(arms)
+}
+
+// Select picks up to MaxBidders of the available bidders for a segment. Each slot goes to a
+// random remaining bidder with probability ExplorationRate, otherwise to the highest sampled
+// expected CPM: a draw from Beta(bids+1, no-bids+1) times the bidder's mean CPM.
+func (b *BanditSelector) Select(segment Segment, available []string) *SelectPartnersResponse {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	type scored struct {
+		code  string
+		score float64
+	}
+	candidates := make([]scored, 0, len(available))
+	arms := b.segments[segment]
+	for _, code := range available {
+		arm := b.global[code]
+		if segArm, ok := arms[code]; ok && segArm.responses >= b.config.MinObservations {
+			arm = segArm
+		}
+		candidates = append(candidates, scored{code: code, score: b.sampleScore(arm)})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	maxBidders := b.config.MaxBidders
+	if maxBidders <= 0 || maxBidders > len(candidates) {
+		maxBidders = len(candidates)
+	}
+
+	resp := &SelectPartnersResponse{Mode: ModeNormal}
+	for len(resp.SelectedBidders) < maxBidders {
+		pick, reason := 0, ReasonBanditScore
+		if b.rng.Float64() < b.config.ExplorationRate {
+			pick, reason = b.rng.Intn(len(candidates)), ReasonExploration
+		}
+		c := candidates[pick]
+		candidates = append(candidates[:pick], candidates[pick+1:]...)
+		resp.SelectedBidders = append(resp.SelectedBidders, SelectedBidder{BidderCode: c.code, Score: c.score, Reason: reason})
+	}
+	for _, c := range candidates {
+		resp.ExcludedBidders = append(resp.ExcludedBidders, ExcludedBidder{BidderCode: c.code, Score: c.score, Reason: "LOW_SCORE"})
+	}
+	return resp
+}
+
+// sampleScore draws a bidder's expected CPM: a bid rate sampled from its Beta posterior times
+// its mean CPM. Bidders without bids use a CPM of 1, so unseen bidders compete on bid rate alone.
+func (b *BanditSelector) sampleScore(arm *armStats) float64 {
+	var bids, noBids int
+	meanCPM := 1.0
+	if arm != nil {
+		bids, noBids = arm.bids, arm.responses-arm.bids
+		if arm.bids > 0 {
+			meanCPM = arm.cpmSum / float64(arm.bids)
+		}
+	}
+	return b.sampleBeta(float64(bids+1), float64(noBids+1)) * meanCPM
+}
+
+// sampleBeta draws from Beta(alpha, beta) as X/(X+Y) with X ~ Gamma(alpha), Y ~ Gamma(beta)
+func (b *BanditSelector) sampleBeta(alpha, beta float64) float64 {
+	x := b.sampleGamma(alpha)
+	y := b.sampleGamma(beta)
+	return x / (x + y)
+}
+
+// sampleGamma draws from Gamma(shape, 1) with the Marsaglia-Tsang method (shape >= 1)
+func (b *BanditSelector) sampleGamma(shape float64) float64 {
+	d := shape - 1.0/3.0
+	c := 1.0 / math.Sqrt(9*d)
+	for {
+		x := b.rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := b.rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
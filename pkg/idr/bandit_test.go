@@ -0,0 +1,102 @@
+package idr
+
+import (
+	"testing"
+)
+
+func trainBandit(b *BanditSelector, segment Segment, bidder string, responses int, bidRate, cpm float64) {
+	bids := int(float64(responses) * bidRate)
+	for i := 0; i < responses; i++ {
+		event := BidEvent{
+			BidderCode:  bidder,
+			EventType:   "bid_response",
+			PublisherID: segment.Publisher,
+			Country:     segment.Country,
+			DeviceType:  segment.DeviceType,
+			MediaType:   segment.MediaType,
+		}
+		if i < bids {
+			price := cpm
+			event.HadBid = true
+			event.BidCPM = &price
+		}
+		b.Observe(event)
+	}
+}
+
+func TestBanditSelector_PrefersHigherExpectedCPM(t *testing.T) {
+	config := DefaultBanditConfig()
+	config.MaxBidders = 1
+	config.ExplorationRate = 0
+	bandit := NewBanditSelector(config)
+
+	segment := Segment{Publisher: "pub-1", Country: "GBR", DeviceType: "desktop", MediaType: "banner"}
+	trainBandit(bandit, segment, "good", 200, 0.9, 2.00)
+	trainBandit(bandit, segment, "bad", 200, 0.1, 0.50)
+
+	picks := map[string]int{}
+	for i := 0; i < 100; i++ {
+		resp := bandit.Select(segment, []string{"bad", "good"})
+		if len(resp.SelectedBidders) != 1 || len(resp.ExcludedBidders) != 1 {
+			t.Fatalf("Expected 1 selected and 1 excluded bidder, got %+v", resp)
+		}
+		picks[resp.SelectedBidders[0].BidderCode]++
+	}
+	if picks["good"] < 95 {
+		t.Errorf("Expected the better bidder nearly always selected, got %v", picks)
+	}
+}
+
+func TestBanditSelector_SegmentFallsBackToGlobal(t *testing.T) {
+	config := DefaultBanditConfig()
+	config.MaxBidders = 1
+	config.ExplorationRate = 0
+	bandit := NewBanditSelector(config)
+
+	trained := Segment{Publisher: "pub-1", Country: "USA"}
+	trainBandit(bandit, trained, "good", 200, 0.9, 2.00)
+	trainBandit(bandit, trained, "bad", 200, 0.05, 0.50)
+
+	// An unseen segment uses each bidder's global stats
+	picks := map[string]int{}
+	for i := 0; i < 100; i++ {
+		picks[bandit.Select(Segment{Publisher: "pub-2"}, []string{"bad", "good"}).SelectedBidders[0].BidderCode]++
+	}
+	if picks["good"] < 95 {
+		t.Errorf("Expected global stats to favour the better bidder, got %v", picks)
+	}
+}
+
+func TestBanditSelector_ExplorationAndCap(t *testing.T) {
+	config := DefaultBanditConfig()
+	config.MaxBidders = 2
+	config.ExplorationRate = 1
+	bandit := NewBanditSelector(config)
+
+	resp := bandit.Select(Segment{}, []string{"a", "b", "c", "d"})
+	if len(resp.SelectedBidders) != 2 || len(resp.ExcludedBidders) != 2 {
+		t.Fatalf("Expected the cap applied, got %+v", resp)
+	}
+	for _, sb := range resp.SelectedBidders {
+		if sb.Reason != ReasonExploration {
+			t.Errorf("Expected exploration picks, got %+v", sb)
+		}
+	}
+
+	if resp := bandit.Select(Segment{}, []string{"a"}); len(resp.SelectedBidders) != 1 {
+		t.Errorf("Expected a single available bidder selected, got %+v", resp)
+	}
+}
+
+func TestEventRecorder_Observer(t *testing.T) {
+	recorder := NewEventRecorder("http://localhost:8000", 100)
+	defer recorder.Close()
+
+	var seen []BidEvent
+	recorder.SetObserver(func(event BidEvent) { seen = append(seen, event) })
+	recorder.RecordWin("auction-1", "appnexus", 1.5, "US", "mobile", "banner", "320x50", "pub-1")
+
+	if len(seen) != 1 || seen[0].EventType != "win" {
+		t.Errorf("Expected the observer to see the event, got %+v", seen)
+	}
+}
@@ -3,6 +3,7 @@ package idr
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 )
@@ -24,6 +25,43 @@ type CircuitBreakerConfig struct {
 	Timeout          time.Duration // Time to wait before half-open
 	MaxConcurrent    int           // Max concurrent requests (0 = unlimited)
 	OnStateChange    func(from, to string)
+
+	// Error-rate mode: when ErrorRateThreshold is set the circuit opens once the failure
+	// rate over Window reaches it (after MinRequests), instead of after FailureThreshold
+	// consecutive failures
+	ErrorRateThreshold float64       // Failure rate that opens the circuit (0-1, 0 = consecutive mode)
+	Window             time.Duration // Error rate window
+	MinRequests        int           // Requests in the window before the rate is acted on
+}
+
+// Validate checks a circuit breaker configuration
+func (c *CircuitBreakerConfig) Validate() error {
+	if c.FailureThreshold <= 0 {
+		return fmt.Errorf("failure threshold must be positive, got %d", c.FailureThreshold)
+	}
+	if c.SuccessThreshold <= 0 {
+		return fmt.Errorf("success threshold must be positive, got %d", c.SuccessThreshold)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %v", c.Timeout)
+	}
+	if c.ErrorRateThreshold < 0 || c.ErrorRateThreshold > 1 {
+		return fmt.Errorf("error rate threshold must be in range 0-1, got %v", c.ErrorRateThreshold)
+	}
+	if c.ErrorRateThreshold > 0 && (c.Window <= 0 || c.MinRequests <= 0) {
+		return fmt.Errorf("error rate mode needs a positive window and min requests")
+	}
+	return nil
+}
+
+// windowBuckets is the number of buckets an error rate window is split into
+const windowBuckets = 10
+
+// rateBucket counts requests in one slice of the error rate window
+type rateBucket struct {
+	start    time.Time
+	requests int
+	failures int
 }
 
 // DefaultCircuitBreakerConfig returns sensible defaults
@@ -33,6 +71,8 @@ func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
 		SuccessThreshold: 2,
 		Timeout:          30 * time.Second,
 		MaxConcurrent:    100,
+		Window:           60 * time.Second,
+		MinRequests:      20,
 	}
 }
 
@@ -46,6 +86,9 @@ type CircuitBreaker struct {
 	successes       int
 	lastFailureTime time.Time
 	concurrent      int
+	drained         bool // Held open by an operator until Reset
+	probeGate       func() bool
+	buckets         [windowBuckets]rateBucket
 
 	// Metrics
 	totalRequests  int64
@@ -98,7 +141,7 @@ func (cb *CircuitBreaker) beforeRequest() error {
 
 	case StateOpen:
 		// Check if timeout has passed
-		if time.Since(cb.lastFailureTime) > cb.config.Timeout {
+		if cb.canProbe() {
 			cb.setState(StateHalfOpen)
 			cb.concurrent++
 			return nil
@@ -139,10 +182,15 @@ func (cb *CircuitBreaker) recordFailure() {
 	cb.failures++
 	cb.successes = 0
 	cb.lastFailureTime = time.Now()
+	cb.countRequest(true)
 
 	switch cb.state {
 	case StateClosed:
-		if cb.failures >= cb.config.FailureThreshold {
+		if cb.config.ErrorRateThreshold > 0 {
+			if requests, rate := cb.windowRate(); requests >= cb.config.MinRequests && rate >= cb.config.ErrorRateThreshold {
+				cb.setState(StateOpen)
+			}
+		} else if cb.failures >= cb.config.FailureThreshold {
 			cb.setState(StateOpen)
 		}
 	case StateHalfOpen:
@@ -154,6 +202,7 @@ func (cb *CircuitBreaker) recordFailure() {
 func (cb *CircuitBreaker) recordSuccess() {
 	cb.totalSuccesses++
 	cb.successes++
+	cb.countRequest(false)
 
 	switch cb.state {
 	case StateClosed:
@@ -166,6 +215,43 @@ func (cb *CircuitBreaker) recordSuccess() {
 	}
 }
 
+// countRequest adds a request to the error rate window
+func (cb *CircuitBreaker) countRequest(failed bool) {
+	if cb.config.ErrorRateThreshold <= 0 {
+		return
+	}
+	width := cb.config.Window / windowBuckets
+	if width <= 0 {
+		width = time.Millisecond
+	}
+	now := time.Now()
+	start := now.Truncate(width)
+	b := &cb.buckets[(start.UnixNano()/int64(width))%windowBuckets]
+	if !b.start.Equal(start) {
+		*b = rateBucket{start: start}
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+}
+
+// windowRate returns the requests and failure rate over the error rate window
+func (cb *CircuitBreaker) windowRate() (int, float64) {
+	var requests, failures int
+	now := time.Now()
+	for _, b := range cb.buckets {
+		if b.requests > 0 && now.Sub(b.start) < cb.config.Window {
+			requests += b.requests
+			failures += b.failures
+		}
+	}
+	if requests == 0 {
+		return 0, 0
+	}
+	return requests, float64(failures) / float64(requests)
+}
+
 // setState changes the circuit breaker state
 func (cb *CircuitBreaker) setState(newState string) {
 	if cb.state == newState {
@@ -175,6 +261,10 @@ func (cb *CircuitBreaker) setState(newState string) {
 	oldState := cb.state
 	cb.state = newState
 	cb.successes = 0
+	if newState == StateClosed {
+		// Failures from before the circuit opened shouldn't trip it again
+		cb.buckets = [windowBuckets]rateBucket{}
+	}
 
 	if cb.config.OnStateChange != nil {
 		// Track callback goroutine for graceful shutdown
@@ -226,7 +316,7 @@ func (cb *CircuitBreaker) State() string {
 func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
-	return CircuitBreakerStats{
+	stats := CircuitBreakerStats{
 		State:          cb.state,
 		TotalRequests:  cb.totalRequests,
 		TotalFailures:  cb.totalFailures,
@@ -234,7 +324,12 @@ func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
 		TotalRejected:  cb.totalRejected,
 		Failures:       cb.failures,
 		Concurrent:     cb.concurrent,
+		Drained:        cb.drained,
+	}
+	if cb.config.ErrorRateThreshold > 0 {
+		stats.WindowRequests, stats.ErrorRate = cb.windowRate()
 	}
+	return stats
 }
 
 // CircuitBreakerStats holds circuit breaker statistics
@@ -246,6 +341,11 @@ type CircuitBreakerStats struct {
 	TotalRejected  int64  `json:"total_rejected"`
 	Failures       int    `json:"current_failures"`
 	Concurrent     int    `json:"concurrent"`
+	Drained        bool   `json:"drained,omitempty"`
+
+	// Error-rate mode only
+	WindowRequests int     `json:"window_requests,omitempty"`
+	ErrorRate      float64 `json:"error_rate,omitempty"`
 }
 
 // Reset resets the circuit breaker to closed state
@@ -255,6 +355,8 @@ func (cb *CircuitBreaker) Reset() {
 	cb.setState(StateClosed)
 	cb.failures = 0
 	cb.successes = 0
+	cb.drained = false
+	cb.buckets = [windowBuckets]rateBucket{}
 }
 
 // ForceOpen forces the circuit breaker to open state
@@ -262,9 +364,54 @@ func (cb *CircuitBreaker) ForceOpen() {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 	cb.setState(StateOpen)
+	cb.drained = false
+	cb.lastFailureTime = time.Now()
+}
+
+// Drain holds the circuit open until Reset: no new requests, and no half-open probes.
+// Requests already in flight complete normally.
+func (cb *CircuitBreaker) Drain() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.setState(StateOpen)
+	cb.drained = true
 	cb.lastFailureTime = time.Now()
 }
 
+// Allow reports whether a request may proceed, for callers that record outcomes with
+// RecordSuccess and RecordFailure. An open circuit moves to half-open once Timeout has
+// passed (unless drained), letting probe requests through.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == StateOpen {
+		if !cb.canProbe() {
+			cb.totalRejected++
+			return false
+		}
+		cb.setState(StateHalfOpen)
+	}
+	return true
+}
+
+// SetProbeGate sets a check an open circuit must also pass before moving to half-open,
+// e.g. to let only one of several replicas sharing breaker state probe a recovering bidder.
+// The gate is called with the breaker locked and must not block.
+func (cb *CircuitBreaker) SetProbeGate(gate func() bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.probeGate = gate
+}
+
+// canProbe reports whether an open circuit may move to half-open
+func (cb *CircuitBreaker) canProbe() bool {
+	if cb.drained || time.Since(cb.lastFailureTime) <= cb.config.Timeout {
+		return false
+	}
+	return cb.probeGate == nil || cb.probeGate()
+}
+
 // IsOpen returns true if the circuit breaker is open
 func (cb *CircuitBreaker) IsOpen() bool {
 	cb.mu.RLock()
@@ -529,3 +529,149 @@ func TestRecordFailureSuccess_ConcurrentAccess(t *testing.T) {
 		t.Errorf("expected circuit to remain closed, got %s", cb.State())
 	}
 }
+
+func TestCircuitBreaker_ErrorRateMode(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold:   3,
+		SuccessThreshold:   1,
+		Timeout:            time.Minute,
+		ErrorRateThreshold: 0.5,
+		Window:             time.Minute,
+		MinRequests:        10,
+	})
+
+	// A burst of consecutive failures alone doesn't open the circuit below MinRequests
+	for i := 0; i < 5; i++ {
+		cb.RecordFailure()
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("expected circuit to stay closed below min requests, got %s", cb.State())
+	}
+
+	// Successes never open the circuit, whatever the rate
+	for i := 0; i < 5; i++ {
+		cb.RecordSuccess()
+	}
+	if cb.State() != StateClosed {
+		t.Fatalf("expected circuit to stay closed on success, got %s", cb.State())
+	}
+
+	// The next failure takes the rate to 6/11, over the threshold
+	cb.RecordFailure()
+	if cb.State() != StateOpen {
+		t.Fatalf("expected circuit open at 6/11 failures, got %s", cb.State())
+	}
+	if stats := cb.Stats(); stats.WindowRequests != 11 || stats.ErrorRate < 0.54 {
+		t.Errorf("unexpected window stats: %+v", stats)
+	}
+
+	// Closing clears the window
+	cb.Reset()
+	if stats := cb.Stats(); stats.WindowRequests != 0 {
+		t.Errorf("expected window cleared on reset, got %+v", stats)
+	}
+}
+
+func TestCircuitBreaker_ErrorRateWindowExpires(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold:   1,
+		SuccessThreshold:   1,
+		Timeout:            time.Minute,
+		ErrorRateThreshold: 0.5,
+		Window:             50 * time.Millisecond,
+		MinRequests:        2,
+	})
+
+	cb.RecordFailure()
+	time.Sleep(60 * time.Millisecond)
+	cb.RecordSuccess()
+	cb.RecordSuccess()
+	if cb.State() != StateClosed {
+		t.Errorf("expected the expired failure not to count, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_AllowHalfOpensAfterTimeout(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Timeout:          20 * time.Millisecond,
+	})
+
+	cb.RecordFailure()
+	if cb.Allow() {
+		t.Fatal("expected open circuit to reject requests")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !cb.Allow() {
+		t.Fatal("expected a probe request after the timeout")
+	}
+	if cb.State() != StateHalfOpen {
+		t.Errorf("expected half-open, got %s", cb.State())
+	}
+	cb.RecordSuccess()
+	if cb.State() != StateClosed {
+		t.Errorf("expected closed after a successful probe, got %s", cb.State())
+	}
+}
+
+func TestCircuitBreaker_Drain(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Timeout:          10 * time.Millisecond,
+	})
+
+	cb.Drain()
+	time.Sleep(20 * time.Millisecond)
+	if cb.Allow() {
+		t.Error("expected a drained circuit to reject probes after the timeout")
+	}
+	if err := cb.Execute(func() error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen from a drained circuit, got %v", err)
+	}
+	if stats := cb.Stats(); !stats.Drained || stats.State != StateOpen {
+		t.Errorf("unexpected drained stats: %+v", stats)
+	}
+
+	cb.Reset()
+	if !cb.Allow() || cb.Stats().Drained {
+		t.Error("expected reset to end the drain")
+	}
+}
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	if err := DefaultCircuitBreakerConfig().Validate(); err != nil {
+		t.Errorf("expected default config to be valid, got %v", err)
+	}
+	for _, c := range []CircuitBreakerConfig{
+		{FailureThreshold: 0, SuccessThreshold: 1, Timeout: time.Second},
+		{FailureThreshold: 1, SuccessThreshold: 1, Timeout: 0},
+		{FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Second, ErrorRateThreshold: 1.5},
+		{FailureThreshold: 1, SuccessThreshold: 1, Timeout: time.Second, ErrorRateThreshold: 0.5},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
+
+func TestCircuitBreaker_ProbeGate(t *testing.T) {
+	cb := NewCircuitBreaker(&CircuitBreakerConfig{
+		FailureThreshold: 1,
+		SuccessThreshold: 1,
+		Timeout:          10 * time.Millisecond,
+	})
+	var allowProbe atomic.Bool
+	cb.SetProbeGate(allowProbe.Load)
+
+	cb.RecordFailure()
+	time.Sleep(20 * time.Millisecond)
+	if cb.Allow() {
+		t.Fatal("expected the gate to hold the circuit open after the timeout")
+	}
+	allowProbe.Store(true)
+	if !cb.Allow() || cb.State() != StateHalfOpen {
+		t.Errorf("expected a probe once the gate opens, got %s", cb.State())
+	}
+}
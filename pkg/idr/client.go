@@ -10,6 +10,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/thenexusengine/tne_springwire/pkg/tracing"
 )
 
 // P2-4: Maximum IDR response size to prevent OOM from malformed responses
@@ -56,7 +58,7 @@ func NewClient(baseURL string, timeout time.Duration, apiKey string) *Client {
 		apiKey:  apiKey,
 		httpClient: &http.Client{
 			Timeout:   timeout,
-			Transport: newIDRTransport(timeout),
+			Transport: &tracing.Transport{Base: newIDRTransport(timeout), Component: "idr", Propagate: true},
 		},
 		timeout:        timeout,
 		circuitBreaker: NewCircuitBreaker(DefaultCircuitBreakerConfig()),
@@ -73,7 +75,7 @@ func NewClientWithCircuitBreaker(baseURL string, timeout time.Duration, apiKey s
 		apiKey:  apiKey,
 		httpClient: &http.Client{
 			Timeout:   timeout,
-			Transport: newIDRTransport(timeout),
+			Transport: &tracing.Transport{Base: newIDRTransport(timeout), Component: "idr", Propagate: true},
 		},
 		timeout:        timeout,
 		circuitBreaker: NewCircuitBreaker(cbConfig),
@@ -124,11 +126,18 @@ type MinimalGeo struct {
 	Region  string `json:"region,omitempty"`
 }
 
+// Partner selection modes reported in SelectPartnersResponse.Mode
+const (
+	ModeNormal = "normal" // Call only the selected bidders
+	ModeShadow = "shadow" // Call every bidder; the selection is only evaluated
+	ModeBypass = "bypass" // Selection skipped
+)
+
 // SelectPartnersResponse is the response from partner selection
 type SelectPartnersResponse struct {
 	SelectedBidders  []SelectedBidder `json:"selected_bidders"`
 	ExcludedBidders  []ExcludedBidder `json:"excluded_bidders,omitempty"`
-	Mode             string           `json:"mode"` // "normal", "shadow", "bypass"
+	Mode             string           `json:"mode"` // ModeNormal, ModeShadow or ModeBypass
 	ProcessingTimeMs float64          `json:"processing_time_ms"`
 }
 
@@ -304,6 +313,10 @@ func (c *Client) GetConfig(ctx context.Context) (map[string]interface{}, error)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("IDR service returned status %d", resp.StatusCode)
+	}
+
 	var config map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
 		return nil, fmt.Errorf("failed to decode config: %w", err)
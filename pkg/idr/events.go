@@ -29,6 +29,8 @@ type EventRecorder struct {
 	buffer     []BidEvent
 	bufferSize int
 	mu         sync.Mutex
+	observer   func(BidEvent) // Sees every recorded event (e.g. to train the bandit selector)
+	spool      *Spool         // Durable delivery; replaces the buffer and flush queue when set
 
 	// Worker pool for flush operations
 	flushQueue chan []BidEvent
@@ -46,7 +48,7 @@ type EventRecorder struct {
 type BidEvent struct {
 	AuctionID   string   `json:"auction_id"`
 	BidderCode  string   `json:"bidder_code"`
-	EventType   string   `json:"event_type"` // "bid_response" or "win"
+	EventType   string   `json:"event_type"` // "bid_response", "win" or "shadow_auction"
 	LatencyMs   float64  `json:"latency_ms,omitempty"`
 	HadBid      bool     `json:"had_bid,omitempty"`
 	BidCPM      *float64 `json:"bid_cpm,omitempty"`
@@ -60,6 +62,18 @@ type BidEvent struct {
 	TimedOut    bool     `json:"timed_out,omitempty"`
 	HadError    bool     `json:"had_error,omitempty"`
 	ErrorMsg    string   `json:"error_message,omitempty"`
+
+	// Per-imp auction outcome
+	ImpID         string   `json:"imp_id,omitempty"`
+	ClearingPrice *float64 `json:"clearing_price,omitempty"` // Imp's clearing price after the auction and bid multiplier
+	Won           bool     `json:"won,omitempty"`
+	DealID        string   `json:"deal_id,omitempty"`
+
+	// Shadow auction comparison ("shadow_auction" events)
+	ActualRevenue *float64 `json:"actual_revenue,omitempty"`
+	ShadowRevenue *float64 `json:"shadow_revenue,omitempty"`
+	LostWinners   int      `json:"lost_winners,omitempty"`
+	BiddersSaved  int      `json:"bidders_saved,omitempty"`
 }
 
 // NewEventRecorder creates a new event recorder with a bounded worker pool
@@ -177,34 +191,7 @@ func (r *EventRecorder) RecordBidResponse(
 		ErrorMsg:    errorMsg,
 	}
 
-	r.totalEvents.Add(1)
-
-	r.mu.Lock()
-	r.buffer = append(r.buffer, event)
-	shouldFlush := len(r.buffer) >= r.bufferSize
-	var eventsToFlush []BidEvent
-	if shouldFlush {
-		// Copy buffer before unlock to prevent memory corruption
-		eventsToFlush = make([]BidEvent, len(r.buffer))
-		copy(eventsToFlush, r.buffer)
-		r.buffer = make([]BidEvent, 0, r.bufferSize)
-	}
-	r.mu.Unlock()
-
-	// Queue flush if buffer was full (non-blocking send)
-	if eventsToFlush != nil {
-		batchSize := int64(len(eventsToFlush))
-		select {
-		case r.flushQueue <- eventsToFlush:
-			// Queued successfully
-			r.flushedEvents.Add(batchSize)
-		default:
-			// Queue full - drop events rather than block or leak goroutines
-			// Track dropped events for monitoring/alerting
-			r.droppedEvents.Add(batchSize)
-			r.droppedBatches.Add(1)
-		}
-	}
+	r.record(event)
 }
 
 // RecordWin records a win event
@@ -230,9 +217,75 @@ func (r *EventRecorder) RecordWin(
 		PublisherID: publisherID,
 	}
 
+	r.record(event)
+}
+
+// RecordEvent records a prebuilt event, e.g. a per-imp bid response carrying the auction outcome
+func (r *EventRecorder) RecordEvent(event BidEvent) {
+	r.record(event)
+}
+
+// RecordShadowAuction records how an IDR shadow-mode auction compares with calling only the
+// bidders IDR selected: revenue with and without the selection, imps whose winner would not
+// have been called, and bidder calls the selection would have saved
+func (r *EventRecorder) RecordShadowAuction(
+	auctionID string,
+	actualRevenue float64,
+	shadowRevenue float64,
+	lostWinners int,
+	biddersSaved int,
+	publisherID string,
+) {
+	r.record(BidEvent{
+		AuctionID:     auctionID,
+		EventType:     "shadow_auction",
+		ActualRevenue: &actualRevenue,
+		ShadowRevenue: &shadowRevenue,
+		LostWinners:   lostWinners,
+		BiddersSaved:  biddersSaved,
+		PublisherID:   publisherID,
+	})
+}
+
+// SetObserver registers a function called with every recorded event
+func (r *EventRecorder) SetObserver(observer func(BidEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
+}
+
+// EnableSpool makes event delivery durable: events are written to an on-disk spool and
+// delivered from there with retries instead of being buffered in memory. Call it before
+// recording events. onStats, if set, receives the spool's stats after every sync.
+func (r *EventRecorder) EnableSpool(config *SpoolConfig, onStats func(SpoolStats)) error {
+	spool, err := OpenSpool(config, r.sendEvents, onStats)
+	if err != nil {
+		return fmt.Errorf("failed to open event spool: %w", err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spool = spool
+	return nil
+}
+
+// record buffers an event, queueing a flush once the buffer is full
+func (r *EventRecorder) record(event BidEvent) {
 	r.totalEvents.Add(1)
 
 	r.mu.Lock()
+	observer := r.observer
+	if spool := r.spool; spool != nil {
+		r.mu.Unlock()
+		if observer != nil {
+			observer(event)
+		}
+		if err := spool.Append(event); err != nil {
+			r.droppedEvents.Add(1)
+			return
+		}
+		r.flushedEvents.Add(1)
+		return
+	}
 	r.buffer = append(r.buffer, event)
 	shouldFlush := len(r.buffer) >= r.bufferSize
 	var eventsToFlush []BidEvent
@@ -244,6 +297,10 @@ func (r *EventRecorder) RecordWin(
 	}
 	r.mu.Unlock()
 
+	if observer != nil {
+		observer(event)
+	}
+
 	// Queue flush if buffer was full (non-blocking send)
 	if eventsToFlush != nil {
 		batchSize := int64(len(eventsToFlush))
@@ -260,9 +317,14 @@ func (r *EventRecorder) RecordWin(
 	}
 }
 
-// Flush sends buffered events to the IDR service synchronously
+// Flush sends buffered events to the IDR service synchronously; with a spool it syncs the
+// spool to disk instead
 func (r *EventRecorder) Flush(ctx context.Context) error {
 	r.mu.Lock()
+	if spool := r.spool; spool != nil {
+		r.mu.Unlock()
+		return spool.Sync()
+	}
 	if len(r.buffer) == 0 {
 		r.mu.Unlock()
 		return nil
@@ -290,6 +352,16 @@ func (r *EventRecorder) Close() error {
 	close(r.flushQueue)
 	r.wg.Wait()
 
+	// Undelivered spooled events are replayed on the next start
+	r.mu.Lock()
+	spool := r.spool
+	r.mu.Unlock()
+	if spool != nil {
+		if closeErr := spool.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
 	return err
 }
 
@@ -301,6 +373,8 @@ type EventRecorderStats struct {
 	DroppedBatches int64 `json:"dropped_batches"` // Batches dropped due to full queue
 	BufferedEvents int   `json:"buffered_events"` // Events currently in buffer
 	QueuedBatches  int   `json:"queued_batches"`  // Batches waiting in flush queue
+
+	Spool *SpoolStats `json:"spool,omitempty"` // Set when delivery is spooled to disk
 }
 
 // Stats returns current metrics for the event recorder.
@@ -308,9 +382,10 @@ type EventRecorderStats struct {
 func (r *EventRecorder) Stats() EventRecorderStats {
 	r.mu.Lock()
 	buffered := len(r.buffer)
+	spool := r.spool
 	r.mu.Unlock()
 
-	return EventRecorderStats{
+	stats := EventRecorderStats{
 		TotalEvents:    r.totalEvents.Load(),
 		FlushedEvents:  r.flushedEvents.Load(),
 		DroppedEvents:  r.droppedEvents.Load(),
@@ -318,4 +393,9 @@ func (r *EventRecorder) Stats() EventRecorderStats {
 		BufferedEvents: buffered,
 		QueuedBatches:  len(r.flushQueue),
 	}
+	if spool != nil {
+		spoolStats := spool.Stats()
+		stats.Spool = &spoolStats
+	}
+	return stats
 }
@@ -167,6 +167,23 @@ func TestRecordWin(t *testing.T) {
 	}
 }
 
+func TestRecordShadowAuction(t *testing.T) {
+	recorder := NewEventRecorder("http://localhost:8000", 100)
+	defer recorder.Close()
+
+	recorder.RecordShadowAuction("auction-123", 3.00, 2.00, 1, 2, "pub-789")
+
+	recorder.mu.Lock()
+	event := recorder.buffer[0]
+	recorder.mu.Unlock()
+	if event.EventType != "shadow_auction" || *event.ActualRevenue != 3.00 || *event.ShadowRevenue != 2.00 {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.LostWinners != 1 || event.BiddersSaved != 2 || event.PublisherID != "pub-789" {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+}
+
 func TestFlush_EmptyBuffer(t *testing.T) {
 	recorder := NewEventRecorder("http://localhost:8000", 100)
 	defer recorder.Close()
@@ -0,0 +1,586 @@
+package idr
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// segmentExt is the file extension of spool segments
+const segmentExt = ".jsonl"
+
+// errSpoolFull is returned when the writer's queue is full
+var errSpoolFull = errors.New("event spool full")
+
+// SpoolConfig configures the on-disk event spool
+type SpoolConfig struct {
+	Dir           string        // Spool directory
+	SegmentBytes  int64         // Size at which a segment is sealed for delivery
+	SegmentMaxAge time.Duration // Age at which a non-empty segment is sealed for delivery
+	MaxDiskBytes  int64         // Disk quota; the oldest segments are dropped to stay under it
+	SyncInterval  time.Duration // Writes are fsynced in batches at this interval
+	BatchSize     int           // Events per request when delivering a segment
+	RetryMin      time.Duration // First delivery retry backoff
+	RetryMax      time.Duration // Delivery retry backoff cap
+	QueueSize     int           // Events waiting for the writer; appends beyond it are dropped
+}
+
+// DefaultSpoolConfig returns the default spool configuration for a directory
+func DefaultSpoolConfig(dir string) *SpoolConfig {
+	return &SpoolConfig{
+		Dir:           dir,
+		SegmentBytes:  4 << 20,
+		SegmentMaxAge: 5 * time.Second,
+		MaxDiskBytes:  512 << 20,
+		SyncInterval:  200 * time.Millisecond,
+		BatchSize:     500,
+		RetryMin:      time.Second,
+		RetryMax:      time.Minute,
+		QueueSize:     10000,
+	}
+}
+
+// spoolSegment is one segment file of newline-delimited events
+type spoolSegment struct {
+	path    string
+	created time.Time
+	bytes   int64
+	events  int64
+	sent    int64 // Leading events already delivered by this process
+}
+
+// Spool is a write-ahead log for bid events. Events are appended to segment files and
+// fsynced in batches; sealed segments are delivered oldest first, retried with backoff
+// and deleted once delivered. Segments left by a previous process are replayed on open.
+// Delivery is at-least-once: a crash mid-segment resends the segment's delivered events.
+//
+// Append only queues the event: a single writer goroutine does all writes, fsyncs and
+// seals, so callers on the auction path never wait on the disk.
+type Spool struct {
+	config *SpoolConfig
+	send   func(context.Context, []BidEvent) error
+
+	mu      sync.Mutex      // Guards the segment list shared with delivery and Stats
+	sealed  []*spoolSegment // Oldest first
+	current *spoolSegment   // Only the writer replaces it
+
+	// Owned by the writer goroutine
+	file     *os.File
+	writer   *bufio.Writer
+	dirty    bool // Writes since the last fsync
+	lastID   int64
+	closeErr error
+
+	queue   chan []byte
+	syncCh  chan chan error
+	onStats func(SpoolStats)
+	wakeCh  chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+
+	droppedEvents   atomic.Int64 // Events dropped by the quota or unreadable on replay
+	deliveredEvents atomic.Int64
+	retries         atomic.Int64
+	writeErrors     atomic.Int64
+}
+
+// SpoolStats describes the events waiting on disk
+type SpoolStats struct {
+	Segments         int     `json:"segments"`
+	Events           int64   `json:"events"` // Events not yet delivered
+	Bytes            int64   `json:"bytes"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"` // Age of the oldest undelivered segment
+	DroppedEvents    int64   `json:"dropped_events"`
+	DeliveredEvents  int64   `json:"delivered_events"`
+	Retries          int64   `json:"retries"`
+	WriteErrors      int64   `json:"write_errors"`
+}
+
+// OpenSpool opens (creating if needed) a spool directory, queues any segments found there
+// for replay and starts delivering them with send. onStats, if set, is called with the
+// spool's stats after every sync.
+func OpenSpool(config *SpoolConfig, send func(context.Context, []BidEvent) error, onStats func(SpoolStats)) (*Spool, error) {
+	if config == nil || config.Dir == "" {
+		return nil, fmt.Errorf("spool directory is required")
+	}
+	defaults := DefaultSpoolConfig(config.Dir)
+	cfg := *config
+	if cfg.SegmentBytes <= 0 {
+		cfg.SegmentBytes = defaults.SegmentBytes
+	}
+	if cfg.SegmentMaxAge <= 0 {
+		cfg.SegmentMaxAge = defaults.SegmentMaxAge
+	}
+	if cfg.MaxDiskBytes <= 0 {
+		cfg.MaxDiskBytes = defaults.MaxDiskBytes
+	}
+	if cfg.MaxDiskBytes < cfg.SegmentBytes {
+		cfg.SegmentBytes = cfg.MaxDiskBytes
+	}
+	if cfg.SyncInterval <= 0 {
+		cfg.SyncInterval = defaults.SyncInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaults.BatchSize
+	}
+	if cfg.RetryMin <= 0 {
+		cfg.RetryMin = defaults.RetryMin
+	}
+	if cfg.RetryMax < cfg.RetryMin {
+		cfg.RetryMax = cfg.RetryMin
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaults.QueueSize
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create spool directory: %w", err)
+	}
+
+	s := &Spool{
+		config:  &cfg,
+		send:    send,
+		queue:   make(chan []byte, cfg.QueueSize),
+		syncCh:  make(chan chan error),
+		onStats: onStats,
+		wakeCh:  make(chan struct{}, 1),
+		stopCh:  make(chan struct{}),
+	}
+	if err := s.loadSegments(); err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(2)
+	go s.writeLoop()
+	go s.deliverLoop()
+	return s, nil
+}
+
+// loadSegments queues the segments left in the spool directory for replay
+func (s *Spool) loadSegments() error {
+	paths, err := filepath.Glob(filepath.Join(s.config.Dir, "*"+segmentExt))
+	if err != nil {
+		return fmt.Errorf("failed to list spool segments: %w", err)
+	}
+	sort.Strings(paths) // Names are zero-padded creation times
+
+	for _, path := range paths {
+		id, err := strconv.ParseInt(strings.TrimSuffix(filepath.Base(path), segmentExt), 10, 64)
+		if err != nil {
+			continue // Not a segment
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to stat spool segment: %w", err)
+		}
+		if info.Size() == 0 {
+			_ = os.Remove(path)
+			continue
+		}
+		events, err := countLines(path)
+		if err != nil {
+			return fmt.Errorf("failed to read spool segment: %w", err)
+		}
+		s.sealed = append(s.sealed, &spoolSegment{
+			path:    path,
+			created: time.Unix(0, id),
+			bytes:   info.Size(),
+			events:  events,
+		})
+		if id > s.lastID {
+			s.lastID = id
+		}
+	}
+	return nil
+}
+
+// Append queues an event for the writer. It is durable after the next sync; events that
+// don't fit in the queue are dropped.
+func (s *Spool) Append(event BidEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	select {
+	case s.queue <- line:
+		return nil
+	default:
+		s.droppedEvents.Add(1)
+		return errSpoolFull
+	}
+}
+
+// Sync writes the queued events and fsyncs the current segment
+func (s *Spool) Sync() error {
+	reply := make(chan error, 1)
+	select {
+	case s.syncCh <- reply:
+		return <-reply
+	case <-s.stopCh:
+		return nil // Close syncs
+	}
+}
+
+// Close writes the queued events, seals the current segment and stops delivery.
+// Undelivered segments stay on disk and are replayed by the next OpenSpool.
+func (s *Spool) Close() error {
+	close(s.stopCh)
+	s.wg.Wait()
+	return s.closeErr
+}
+
+// Stats returns the spool's current depth and counters
+func (s *Spool) Stats() SpoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := SpoolStats{
+		DroppedEvents:   s.droppedEvents.Load(),
+		DeliveredEvents: s.deliveredEvents.Load(),
+		Retries:         s.retries.Load(),
+		WriteErrors:     s.writeErrors.Load(),
+	}
+	segments := s.sealed
+	if s.current != nil {
+		segments = append(segments[:len(segments):len(segments)], s.current)
+	}
+	for _, seg := range segments {
+		stats.Segments++
+		stats.Events += seg.events - seg.sent
+		stats.Bytes += seg.bytes
+	}
+	if len(segments) > 0 {
+		stats.OldestAgeSeconds = time.Since(segments[0].created).Seconds()
+	}
+	return stats
+}
+
+// diskBytesLocked returns the bytes held by all segments
+func (s *Spool) diskBytesLocked() int64 {
+	var total int64
+	for _, seg := range s.sealed {
+		total += seg.bytes
+	}
+	if s.current != nil {
+		total += s.current.bytes
+	}
+	return total
+}
+
+// writeLoop writes queued events, fsyncs them in batches and seals segments once they
+// reach SegmentMaxAge
+func (s *Spool) writeLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopCh:
+			s.drainQueue()
+			s.closeErr = s.seal()
+			return
+		case line := <-s.queue:
+			s.write(line)
+		case reply := <-s.syncCh:
+			s.drainQueue()
+			reply <- s.sync()
+		case <-ticker.C:
+			//nolint:errcheck // Counted in writeErrors
+			_ = s.sync()
+			if s.current != nil && time.Since(s.current.created) >= s.config.SegmentMaxAge {
+				//nolint:errcheck // Counted in writeErrors
+				_ = s.seal()
+			}
+
+			if s.onStats != nil {
+				s.onStats(s.Stats())
+			}
+		}
+	}
+}
+
+// drainQueue writes the events already queued
+func (s *Spool) drainQueue() {
+	for {
+		select {
+		case line := <-s.queue:
+			s.write(line)
+		default:
+			return
+		}
+	}
+}
+
+// write appends an event to the current segment, dropping the oldest sealed segments
+// to stay under the disk quota; fresh events are worth more
+func (s *Spool) write(line []byte) {
+	size := int64(len(line))
+
+	var dropped []string
+	s.mu.Lock()
+	for s.diskBytesLocked()+size > s.config.MaxDiskBytes && len(s.sealed) > 0 {
+		oldest := s.sealed[0]
+		s.sealed = s.sealed[1:]
+		s.droppedEvents.Add(oldest.events - oldest.sent)
+		dropped = append(dropped, oldest.path)
+	}
+	full := s.diskBytesLocked()+size > s.config.MaxDiskBytes
+	s.mu.Unlock()
+
+	for _, path := range dropped {
+		_ = os.Remove(path)
+	}
+	if full {
+		s.droppedEvents.Add(1)
+		return
+	}
+
+	if s.current == nil {
+		if err := s.openSegment(); err != nil {
+			s.writeErrors.Add(1)
+			s.droppedEvents.Add(1)
+			return
+		}
+	}
+	if _, err := s.writer.Write(line); err != nil {
+		s.writeErrors.Add(1)
+		s.droppedEvents.Add(1)
+		return
+	}
+	s.dirty = true
+
+	s.mu.Lock()
+	s.current.bytes += size
+	s.current.events++
+	full = s.current.bytes >= s.config.SegmentBytes
+	s.mu.Unlock()
+
+	if full {
+		//nolint:errcheck // Counted in writeErrors; the event is already written
+		_ = s.seal()
+	}
+}
+
+// openSegment starts a new current segment named after its creation time
+func (s *Spool) openSegment() error {
+	id := time.Now().UnixNano()
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	path := filepath.Join(s.config.Dir, fmt.Sprintf("%020d%s", id, segmentExt))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640) //nolint:gosec // Path built from the configured directory
+	if err != nil {
+		return fmt.Errorf("failed to create spool segment: %w", err)
+	}
+	s.lastID = id
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+
+	s.mu.Lock()
+	s.current = &spoolSegment{path: path, created: time.Unix(0, id)}
+	s.mu.Unlock()
+	return nil
+}
+
+// sync flushes and fsyncs pending writes to the current segment
+func (s *Spool) sync() error {
+	if s.current == nil || !s.dirty {
+		return nil
+	}
+	s.dirty = false
+	if err := s.writer.Flush(); err != nil {
+		s.writeErrors.Add(1)
+		return fmt.Errorf("failed to flush spool segment: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		s.writeErrors.Add(1)
+		return fmt.Errorf("failed to sync spool segment: %w", err)
+	}
+	return nil
+}
+
+// seal syncs and closes the current segment and queues it for delivery
+func (s *Spool) seal() error {
+	if s.current == nil {
+		return nil
+	}
+	err := s.sync()
+	if closeErr := s.file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close spool segment: %w", closeErr)
+	}
+	s.file, s.writer = nil, nil
+
+	s.mu.Lock()
+	s.sealed = append(s.sealed, s.current)
+	s.current = nil
+	s.mu.Unlock()
+
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+	return err
+}
+
+// deliverLoop sends sealed segments oldest first, backing off while delivery fails
+func (s *Spool) deliverLoop() {
+	defer s.wg.Done()
+	backoff := time.Duration(0)
+
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		default:
+		}
+
+		s.mu.Lock()
+		var seg *spoolSegment
+		if len(s.sealed) > 0 {
+			seg = s.sealed[0]
+		}
+		s.mu.Unlock()
+
+		if seg == nil {
+			select {
+			case <-s.stopCh:
+				return
+			case <-s.wakeCh:
+				continue
+			}
+		}
+
+		if err := s.deliver(seg); err != nil {
+			s.retries.Add(1)
+			if backoff == 0 {
+				backoff = s.config.RetryMin
+			} else if backoff *= 2; backoff > s.config.RetryMax {
+				backoff = s.config.RetryMax
+			}
+			select {
+			case <-s.stopCh:
+				return
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		backoff = 0
+	}
+}
+
+// deliver sends a segment's remaining events in batches and deletes it once all are sent
+func (s *Spool) deliver(seg *spoolSegment) error {
+	events, corrupt, err := readSegment(seg.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			s.removeSegment(seg) // Dropped by the quota
+			return nil
+		}
+		return err
+	}
+	if corrupt > 0 && seg.sent == 0 {
+		s.droppedEvents.Add(corrupt) // e.g. a torn write from a crash
+	}
+
+	for seg.sent < int64(len(events)) {
+		select {
+		case <-s.stopCh:
+			return nil
+		default:
+		}
+		end := seg.sent + int64(s.config.BatchSize)
+		if end > int64(len(events)) {
+			end = int64(len(events))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+		err := s.send(ctx, events[seg.sent:end])
+		cancel()
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.deliveredEvents.Add(end - seg.sent)
+		seg.sent = end
+		s.mu.Unlock()
+	}
+
+	s.removeSegment(seg)
+	return nil
+}
+
+// removeSegment deletes a delivered segment
+func (s *Spool) removeSegment(seg *spoolSegment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, queued := range s.sealed {
+		if queued == seg {
+			s.sealed = append(s.sealed[:i], s.sealed[i+1:]...)
+			break
+		}
+	}
+	_ = os.Remove(seg.path)
+}
+
+// readSegment decodes a segment's events, skipping lines that don't parse
+func readSegment(path string) (events []BidEvent, corrupt int64, err error) {
+	file, err := os.Open(path) //nolint:gosec // Path from the spool directory listing
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var event BidEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			corrupt++
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read spool segment: %w", err)
+	}
+	return events, corrupt, nil
+}
+
+// countLines counts the events in a segment file
+func countLines(path string) (int64, error) {
+	file, err := os.Open(path) //nolint:gosec // Path from the spool directory listing
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var lines int64
+	reader := bufio.NewReader(file)
+	for {
+		_, err := reader.ReadSlice('\n')
+		if err == nil {
+			lines++
+			continue
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+		return 0, err
+	}
+}
@@ -0,0 +1,212 @@
+package idr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// spoolSink collects delivered events, failing the first failures sends
+type spoolSink struct {
+	mu       sync.Mutex
+	events   []BidEvent
+	failures int
+}
+
+func (s *spoolSink) send(ctx context.Context, events []BidEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *spoolSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func testSpoolConfig(t *testing.T) *SpoolConfig {
+	config := DefaultSpoolConfig(t.TempDir())
+	config.SegmentMaxAge = 20 * time.Millisecond
+	config.SyncInterval = 5 * time.Millisecond
+	config.RetryMin = 5 * time.Millisecond
+	config.RetryMax = 20 * time.Millisecond
+	return config
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	return paths
+}
+
+func TestSpool_DeliversAndDeletesSegments(t *testing.T) {
+	config := testSpoolConfig(t)
+	sink := &spoolSink{failures: 2}
+	spool, err := OpenSpool(config, sink.send, nil)
+	if err != nil {
+		t.Fatalf("OpenSpool: %v", err)
+	}
+	defer spool.Close()
+
+	for i := 0; i < 3; i++ {
+		if err := spool.Append(BidEvent{AuctionID: "a", BidderCode: "b", EventType: "bid_response"}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	waitFor(t, "delivery", func() bool { return sink.count() == 3 })
+	waitFor(t, "segment removal", func() bool { return len(segmentFiles(t, config.Dir)) == 0 })
+
+	stats := spool.Stats()
+	if stats.DeliveredEvents != 3 || stats.Retries != 2 || stats.Events != 0 {
+		t.Errorf("Unexpected stats after retried delivery: %+v", stats)
+	}
+}
+
+func TestSpool_ReplaysSegmentsOnOpen(t *testing.T) {
+	config := testSpoolConfig(t)
+	down := &spoolSink{failures: 1 << 30}
+	spool, err := OpenSpool(config, down.send, nil)
+	if err != nil {
+		t.Fatalf("OpenSpool: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_ = spool.Append(BidEvent{AuctionID: "replay", EventType: "win"})
+	}
+	if err := spool.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if len(segmentFiles(t, config.Dir)) == 0 {
+		t.Fatal("Expected undelivered events left on disk")
+	}
+
+	sink := &spoolSink{}
+	spool, err = OpenSpool(config, sink.send, nil)
+	if err != nil {
+		t.Fatalf("OpenSpool: %v", err)
+	}
+	defer spool.Close()
+	waitFor(t, "replay", func() bool { return sink.count() == 5 })
+}
+
+func TestSpool_SkipsTornWrites(t *testing.T) {
+	config := testSpoolConfig(t)
+	segment := filepath.Join(config.Dir, "00000000000000000001"+segmentExt)
+	if err := os.WriteFile(segment, []byte(`{"auction_id":"ok","event_type":"win"}`+"\n"+`{"auction_id":"to`), 0o600); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+
+	sink := &spoolSink{}
+	spool, err := OpenSpool(config, sink.send, nil)
+	if err != nil {
+		t.Fatalf("OpenSpool: %v", err)
+	}
+	defer spool.Close()
+	waitFor(t, "replay", func() bool { return sink.count() == 1 })
+	waitFor(t, "segment removal", func() bool { return len(segmentFiles(t, config.Dir)) == 0 })
+	if stats := spool.Stats(); stats.DroppedEvents != 1 {
+		t.Errorf("Expected the torn event counted as dropped, got %+v", stats)
+	}
+}
+
+func TestSpool_DiskQuotaDropsOldestSegments(t *testing.T) {
+	config := testSpoolConfig(t)
+	config.SegmentBytes = 200
+	config.MaxDiskBytes = 1000
+	down := &spoolSink{failures: 1 << 30}
+	spool, err := OpenSpool(config, down.send, nil)
+	if err != nil {
+		t.Fatalf("OpenSpool: %v", err)
+	}
+	defer spool.Close()
+
+	for i := 0; i < 100; i++ {
+		if err := spool.Append(BidEvent{AuctionID: "quota", BidderCode: "appnexus", EventType: "bid_response"}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if err := spool.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	stats := spool.Stats()
+	if stats.Bytes > config.MaxDiskBytes {
+		t.Errorf("Spool uses %d bytes, over the %d quota", stats.Bytes, config.MaxDiskBytes)
+	}
+	if stats.DroppedEvents == 0 || stats.DroppedEvents+stats.Events != 100 {
+		t.Errorf("Expected the oldest events dropped and the rest kept, got %+v", stats)
+	}
+	if stats.OldestAgeSeconds <= 0 || stats.Segments == 0 {
+		t.Errorf("Expected spool depth and age reported, got %+v", stats)
+	}
+}
+
+func TestEventRecorder_Spool(t *testing.T) {
+	var mu sync.Mutex
+	var received []BidEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []BidEvent `json:"events"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		received = append(received, body.Events...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	recorder := NewEventRecorder(server.URL, 100)
+	defer recorder.Close()
+	var reported SpoolStats
+	var reportedMu sync.Mutex
+	if err := recorder.EnableSpool(testSpoolConfig(t), func(s SpoolStats) {
+		reportedMu.Lock()
+		reported = s
+		reportedMu.Unlock()
+	}); err != nil {
+		t.Fatalf("EnableSpool: %v", err)
+	}
+
+	recorder.RecordWin("auction-1", "appnexus", 1.5, "US", "mobile", "banner", "320x50", "pub-1")
+	waitFor(t, "delivery", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 1
+	})
+	waitFor(t, "stats hook", func() bool {
+		reportedMu.Lock()
+		defer reportedMu.Unlock()
+		return reported.DeliveredEvents == 1
+	})
+
+	stats := recorder.Stats()
+	if stats.Spool == nil || stats.FlushedEvents != 1 || stats.BufferedEvents != 0 {
+		t.Errorf("Expected the event spooled rather than buffered, got %+v", stats)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
 )
 
 // ContextKey is the type for context keys
@@ -18,6 +19,10 @@ const (
 	RequestIDKey ContextKey = "request_id"
 	// AuctionIDKey is the context key for auction IDs
 	AuctionIDKey ContextKey = "auction_id"
+	// PublisherIDKey is the context key for publisher IDs
+	PublisherIDKey ContextKey = "publisher_id"
+	// BidderKey is the context key for the bidder being called
+	BidderKey ContextKey = "bidder"
 )
 
 var (
@@ -30,6 +35,8 @@ type Config struct {
 	Level      string // debug, info, warn, error
 	Format     string // json, console
 	TimeFormat string // time format for console output
+	Sampling   SamplingConfig
+	RedactPII  bool // Scrub IPs, IFAs, buyer UIDs and consent strings from every field
 }
 
 // DefaultConfig returns sensible defaults for production
@@ -38,6 +45,8 @@ func DefaultConfig() Config {
 		Level:      getEnv("LOG_LEVEL", "info"),
 		Format:     getEnv("LOG_FORMAT", "json"),
 		TimeFormat: time.RFC3339,
+		Sampling:   samplingConfigFromEnv(),
+		RedactPII:  getEnv("LOG_REDACT_PII", "true") != "false",
 	}
 }
 
@@ -59,13 +68,21 @@ func Init(cfg Config) {
 		}
 	}
 
+	// Redaction sees every line as JSON, before console formatting
+	if cfg.RedactPII {
+		output = NewRedactingWriter(output)
+	}
+
 	// Create logger with common fields
-	Log = zerolog.New(output).
+	Log = cfg.Sampling.apply(zerolog.New(output).
 		Level(level).
 		With().
 		Timestamp().
 		Str("service", "pbs").
-		Logger()
+		Logger())
+
+	// Packages logging through zerolog/log get the same redaction and sampling
+	zlog.Logger = Log
 }
 
 // WithRequestID adds a request ID to the logger context
@@ -78,6 +95,16 @@ func WithAuctionID(ctx context.Context, auctionID string) context.Context {
 	return context.WithValue(ctx, AuctionIDKey, auctionID)
 }
 
+// WithPublisherID adds a publisher ID to the logger context
+func WithPublisherID(ctx context.Context, publisherID string) context.Context {
+	return context.WithValue(ctx, PublisherIDKey, publisherID)
+}
+
+// WithBidder adds the bidder being called to the logger context
+func WithBidder(ctx context.Context, bidderCode string) context.Context {
+	return context.WithValue(ctx, BidderKey, bidderCode)
+}
+
 // FromContext returns a logger with context values
 func FromContext(ctx context.Context) zerolog.Logger {
 	l := Log.With()
@@ -90,6 +117,14 @@ func FromContext(ctx context.Context) zerolog.Logger {
 		l = l.Str("auction_id", auctionID)
 	}
 
+	if publisherID, ok := ctx.Value(PublisherIDKey).(string); ok {
+		l = l.Str("publisher_id", publisherID)
+	}
+
+	if bidderCode, ok := ctx.Value(BidderKey).(string); ok {
+		l = l.Str("bidder", bidderCode)
+	}
+
 	return l.Logger()
 }
 
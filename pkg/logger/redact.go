@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces secrets and identifiers in log output
+const RedactedValue = "[REDACTED]"
+
+// Field names, normalized by normalizeKey, whose values are IP addresses. They are truncated
+// the same way as middleware.AnonymizeIPForLogging.
+var ipFields = map[string]bool{
+	"ip": true, "ipv6": true, "ipaddress": true, "clientip": true, "userip": true,
+	"remoteaddr": true, "xforwardedfor": true, "xrealip": true,
+}
+
+// Field names, normalized by normalizeKey, whose values are dropped entirely: device
+// advertising IDs, buyer UIDs and consent strings
+var secretFields = map[string]bool{
+	"ifa": true, "idfa": true, "gaid": true, "aaid": true, "adid": true,
+	"buyeruid": true, "buyeruids": true, "uids": true,
+	"consent": true, "gdprconsent": true, "consentstring": true, "tcstring": true, "addtlconsent": true,
+	"usprivacy": true, "gpp": true, "gppstring": true,
+}
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	ipv6Pattern = regexp.MustCompile(`(?i)\b(?:[0-9a-f]{1,4}:){7}[0-9a-f]{1,4}\b|(?:[0-9a-f]{1,4}:)*[0-9a-f]{0,4}::(?:[0-9a-f]{1,4}:?)*`)
+
+	// Secrets embedded in string values: serialized JSON ("consent":"...") and query strings (gdpr_consent=...)
+	embeddedJSONPattern  = regexp.MustCompile(`(?i)"(ifa|idfa|gaid|buyeruid|consent|gdpr_consent|us_privacy|gpp)"\s*:\s*"[^"]*"`)
+	embeddedQueryPattern = regexp.MustCompile(`(?i)\b(ifa|idfa|gaid|buyeruid|consent|gdpr_consent|us_privacy|gpp|gpp_sid|ip)=[^&\s"]*`)
+
+	// suspectPattern cheaply finds lines that may need redaction, so others pass through untouched
+	suspectPattern = regexp.MustCompile(`(?i)` + ipv4Pattern.String() + `|::|(?:[0-9a-f]{1,4}:){4}|ifa|idfa|gaid|aaid|adid|uid|consent|privacy|gpp|tcstring|"ip|ip=|addr|forwarded|real_?ip`)
+)
+
+// redactingWriter scrubs PII from each JSON log line before passing it on. Lines are parsed
+// only when they contain something that looks like an IP or a sensitive field name.
+type redactingWriter struct {
+	next io.Writer
+}
+
+// NewRedactingWriter returns a writer that redacts IPs, IFAs, buyer UIDs and consent strings
+// from every field of the JSON log lines written to it, at any nesting depth
+func NewRedactingWriter(next io.Writer) io.Writer {
+	return &redactingWriter{next: next}
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if !suspectPattern.Match(p) {
+		return w.next.Write(p)
+	}
+	if _, err := w.next.Write(RedactLine(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// RedactLine redacts one JSON log line. Lines that aren't a JSON object are scrubbed as text.
+func RedactLine(line []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return []byte(scrubString(string(line)))
+	}
+	for key, value := range fields {
+		fields[key] = redactField(key, value)
+	}
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return []byte(scrubString(string(line)))
+	}
+	return append(out, '\n')
+}
+
+// normalizeKey lowercases a field name and strips separators, so client_ip, clientIP and
+// client-ip are treated alike
+func normalizeKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '-', '.':
+			return -1
+		}
+		return r
+	}, strings.ToLower(key))
+}
+
+// redactField redacts a value according to its field name, then recursively
+func redactField(key string, value interface{}) interface{} {
+	name := normalizeKey(key)
+	if secretFields[name] {
+		if s, ok := value.(string); ok && s == "" {
+			return s
+		}
+		return RedactedValue
+	}
+	if ipFields[name] {
+		if s, ok := value.(string); ok {
+			return anonymizeIPList(s)
+		}
+	}
+	return redactValue(value)
+}
+
+// redactValue scrubs a value whose field name isn't sensitive
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			v[key] = redactField(key, nested)
+		}
+		return v
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = redactValue(nested)
+		}
+		return v
+	case string:
+		return scrubString(v)
+	}
+	return value
+}
+
+// scrubString truncates IPs and removes embedded secrets from free text
+func scrubString(s string) string {
+	s = embeddedJSONPattern.ReplaceAllString(s, `"$1":"`+RedactedValue+`"`)
+	s = embeddedQueryPattern.ReplaceAllString(s, `$1=`+RedactedValue)
+	s = ipv4Pattern.ReplaceAllStringFunc(s, anonymizeIPMatch)
+	return ipv6Pattern.ReplaceAllStringFunc(s, anonymizeIPMatch)
+}
+
+// anonymizeIPMatch truncates a pattern match that parses as an IP and leaves anything else
+func anonymizeIPMatch(match string) string {
+	if net.ParseIP(match) == nil {
+		return match
+	}
+	return anonymizeIP(match)
+}
+
+// anonymizeIPList truncates an IP field value: a single IP, host:port or a comma-separated list
+func anonymizeIPList(value string) string {
+	if value == "" {
+		return value
+	}
+	parts := strings.Split(value, ",")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if host, _, err := net.SplitHostPort(part); err == nil {
+			part = host
+		}
+		parts[i] = anonymizeIP(part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// anonymizeIP masks the last octet of an IPv4 address or the last 80 bits of an IPv6 one
+func anonymizeIP(value string) string {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return RedactedValue
+	}
+	if ipv4 := ip.To4(); ipv4 != nil {
+		ipv4[3] = 0
+		return ipv4.String()
+	}
+	for i := 6; i < 16; i++ {
+		ip[i] = 0
+	}
+	return ip.String()
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+)
+
+func TestRedactingWriter_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	l := zerolog.New(NewRedactingWriter(&buf))
+
+	l.Info().
+		Str("ip", "203.0.113.77").
+		Str("remote_addr", "[2001:db8:85a3::8a2e:370:7334]:51234").
+		Str("X-Forwarded-For", "198.51.100.9, 10.0.0.1").
+		Str("ifa", "6D92078A-8246-4BA4-AE5B-76104861E7DC").
+		Str("gdpr_consent", "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA").
+		Interface("user", map[string]interface{}{
+			"buyeruid": "u-123",
+			"ext":      map[string]interface{}{"consent": "CPabc"},
+		}).
+		Str("bidder", "rubicon").
+		Msg("request from 192.0.2.44")
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+		t.Fatalf("Expected valid JSON, got %s: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"ip":              "203.0.113.0",
+		"remote_addr":     "2001:db8:85a3::",
+		"X-Forwarded-For": "198.51.100.0, 10.0.0.0",
+		"ifa":             RedactedValue,
+		"gdpr_consent":    RedactedValue,
+		"bidder":          "rubicon",
+		"message":         "request from 192.0.2.0",
+	}
+	for key, value := range want {
+		if fields[key] != value {
+			t.Errorf("%s = %v, want %v", key, fields[key], value)
+		}
+	}
+	user := fields["user"].(map[string]interface{})
+	if user["buyeruid"] != RedactedValue || user["ext"].(map[string]interface{})["consent"] != RedactedValue {
+		t.Errorf("Expected nested identifiers redacted, got %v", user)
+	}
+}
+
+func TestRedactingWriter_EmbeddedSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	l := zerolog.New(NewRedactingWriter(&buf))
+
+	l.Debug().
+		Str("response_preview", `{"user":{"buyeruid":"abc123"},"device":{"ip":"203.0.113.5","ifa":"ffff"}}`).
+		Str("uri", "https://bidder.example.com/bid?gdpr_consent=CPabc&us_privacy=1YNN&pub=1").
+		Msg("bidder response")
+
+	out := buf.String()
+	for _, secret := range []string{"abc123", "203.0.113.5", "ffff", "CPabc", "1YNN"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected %q redacted, got %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "pub=1") {
+		t.Errorf("Expected other query parameters kept, got %s", out)
+	}
+}
+
+func TestRedactingWriter_PassesCleanLinesThrough(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewRedactingWriter(&buf)
+	line := []byte(`{"level":"info","time":"2026-01-02T15:04:05Z","bidder":"rubicon","message":"auction complete"}` + "\n")
+	if _, err := w.Write(line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), line) {
+		t.Errorf("Expected the line untouched, got %s", buf.String())
+	}
+}
+
+func TestFromContext_RequestScopedFields(t *testing.T) {
+	var buf bytes.Buffer
+	original := Log
+	defer func() { Log = original }()
+	Log = zerolog.New(&buf)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithAuctionID(ctx, "auction-1")
+	ctx = WithPublisherID(ctx, "pub-1")
+	ctx = WithBidder(ctx, "rubicon")
+	l := FromContext(ctx)
+	l.Info().Msg("bidder call")
+
+	var fields map[string]interface{}
+	json.Unmarshal(buf.Bytes(), &fields)
+	for key, value := range map[string]string{"request_id": "req-1", "auction_id": "auction-1", "publisher_id": "pub-1", "bidder": "rubicon"} {
+		if fields[key] != value {
+			t.Errorf("%s = %v, want %s", key, fields[key], value)
+		}
+	}
+}
+
+func TestInit_RedactsGlobalZerologLogger(t *testing.T) {
+	originalLog, originalGlobal := Log, zlog.Logger
+	defer func() { Log, zlog.Logger = originalLog, originalGlobal }()
+
+	output := captureLogOutput(t, func() {
+		Init(Config{Level: "info", Format: "json", RedactPII: true})
+		zlog.Info().Str("ip", "203.0.113.77").Msg("from zerolog/log")
+	})
+
+	fields := parseLogLine(t, output)
+	if fields == nil {
+		t.Fatal("Expected log output, got none")
+	}
+	if fields["ip"] != "203.0.113.0" || fields["service"] != "pbs" {
+		t.Errorf("Expected zerolog/log to go through the configured logger, got %v", fields)
+	}
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// SamplingConfig thins out hot-path logs. Level sampling drops events before any fields
+// are built; message sampling applies on top, to listed messages at any level.
+type SamplingConfig struct {
+	DebugEvery uint32            // Keep 1 in N debug events (0 or 1 = all)
+	InfoEvery  uint32            // Keep 1 in N info events (0 or 1 = all)
+	Messages   map[string]uint32 // Keep 1 in N events with this exact message
+}
+
+// samplingConfigFromEnv reads LOG_SAMPLE_DEBUG_EVERY, LOG_SAMPLE_INFO_EVERY and
+// LOG_SAMPLE_MESSAGES (JSON object of message -> N). Invalid values disable that sampling.
+func samplingConfigFromEnv() SamplingConfig {
+	var c SamplingConfig
+	if n, err := strconv.ParseUint(os.Getenv("LOG_SAMPLE_DEBUG_EVERY"), 10, 32); err == nil {
+		c.DebugEvery = uint32(n)
+	}
+	if n, err := strconv.ParseUint(os.Getenv("LOG_SAMPLE_INFO_EVERY"), 10, 32); err == nil {
+		c.InfoEvery = uint32(n)
+	}
+	if messages := os.Getenv("LOG_SAMPLE_MESSAGES"); messages != "" {
+		if err := json.Unmarshal([]byte(messages), &c.Messages); err != nil {
+			c.Messages = nil
+		}
+	}
+	return c
+}
+
+// apply returns l with the configured samplers installed
+func (c SamplingConfig) apply(l zerolog.Logger) zerolog.Logger {
+	var levels zerolog.LevelSampler
+	if c.DebugEvery > 1 {
+		levels.DebugSampler = &zerolog.BasicSampler{N: c.DebugEvery}
+	}
+	if c.InfoEvery > 1 {
+		levels.InfoSampler = &zerolog.BasicSampler{N: c.InfoEvery}
+	}
+	if levels.DebugSampler != nil || levels.InfoSampler != nil {
+		l = l.Sample(levels)
+	}
+	if hook := newMessageSampler(c.Messages); hook != nil {
+		l = l.Hook(hook)
+	}
+	return l
+}
+
+// messageSampler keeps 1 in N events per configured message
+type messageSampler struct {
+	every    map[string]uint32
+	counters map[string]*uint32 // Fixed at construction, so lookups need no lock
+}
+
+func newMessageSampler(messages map[string]uint32) *messageSampler {
+	s := &messageSampler{every: make(map[string]uint32), counters: make(map[string]*uint32)}
+	for msg, n := range messages {
+		if n > 1 {
+			s.every[msg] = n
+			s.counters[msg] = new(uint32)
+		}
+	}
+	if len(s.every) == 0 {
+		return nil
+	}
+	return s
+}
+
+// Run implements zerolog.Hook
+func (s *messageSampler) Run(e *zerolog.Event, _ zerolog.Level, msg string) {
+	n, ok := s.every[msg]
+	if !ok {
+		return
+	}
+	if (atomic.AddUint32(s.counters[msg], 1)-1)%n != 0 {
+		e.Discard()
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestSamplingConfig_LevelSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := SamplingConfig{DebugEvery: 10}.apply(zerolog.New(&buf).Level(zerolog.DebugLevel))
+
+	for i := 0; i < 100; i++ {
+		l.Debug().Msg("hot path")
+		l.Warn().Msg("always")
+	}
+	if got := strings.Count(buf.String(), "hot path"); got != 10 {
+		t.Errorf("Expected 10 of 100 debug events, got %d", got)
+	}
+	if got := strings.Count(buf.String(), "always"); got != 100 {
+		t.Errorf("Expected every warn event, got %d", got)
+	}
+}
+
+func TestSamplingConfig_MessageSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := SamplingConfig{Messages: map[string]uint32{"bidder HTTP request failed": 4, "ignored": 1}}.
+		apply(zerolog.New(&buf))
+
+	for i := 0; i < 20; i++ {
+		l.Info().Str("bidder", "rubicon").Msg("bidder HTTP request failed")
+		l.Info().Msg("other message")
+	}
+	if got := strings.Count(buf.String(), "bidder HTTP request failed"); got != 5 {
+		t.Errorf("Expected 5 of 20 sampled events, got %d", got)
+	}
+	if got := strings.Count(buf.String(), "other message"); got != 20 {
+		t.Errorf("Expected unlisted messages unsampled, got %d", got)
+	}
+}
+
+func TestSamplingConfigFromEnv(t *testing.T) {
+	t.Setenv("LOG_SAMPLE_DEBUG_EVERY", "100")
+	t.Setenv("LOG_SAMPLE_INFO_EVERY", "nope")
+	t.Setenv("LOG_SAMPLE_MESSAGES", `{"bidder returned no bids": 50}`)
+
+	c := samplingConfigFromEnv()
+	if c.DebugEvery != 100 || c.InfoEvery != 0 || c.Messages["bidder returned no bids"] != 50 {
+		t.Errorf("Unexpected sampling config: %+v", c)
+	}
+
+	t.Setenv("LOG_SAMPLE_MESSAGES", `{`)
+	if c := samplingConfigFromEnv(); c.Messages != nil {
+		t.Errorf("Expected invalid message sampling ignored, got %v", c.Messages)
+	}
+}
@@ -145,3 +145,59 @@ func (c *Client) PoolStats() *redis.PoolStats {
 func (c *Client) Do(ctx context.Context, args ...interface{}) *redis.Cmd {
 	return c.client.Do(ctx, args...)
 }
+
+// HIncrByBatch increments several hash fields in one round trip and refreshes the key TTL
+func (c *Client) HIncrByBatch(ctx context.Context, key string, increments map[string]int64, ttl time.Duration) error {
+	if len(increments) == 0 {
+		return nil
+	}
+	pipe := c.client.Pipeline()
+	for field, incr := range increments {
+		pipe.HIncrBy(ctx, key, field, incr)
+	}
+	if ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// HGetAllBatch gets all fields of several hashes in one round trip.
+// Results are returned in key order; missing keys yield empty maps.
+func (c *Client) HGetAllBatch(ctx context.Context, keys []string) ([]map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.HGetAll(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	results := make([]map[string]string, len(keys))
+	for i, cmd := range cmds {
+		results[i] = cmd.Val()
+	}
+	return results, nil
+}
+
+// HSetFields sets several hash fields in one command
+func (c *Client) HSetFields(ctx context.Context, key string, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return c.client.HSet(ctx, key, fields).Err()
+}
+
+// SetNX sets a key with a TTL only if it doesn't exist, reporting whether it was set
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Del deletes keys
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	return c.client.Del(ctx, keys...).Err()
+}
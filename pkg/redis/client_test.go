@@ -597,3 +597,109 @@ func TestClient_MultipleOperations(t *testing.T) {
 		t.Errorf("Expected 2 fields after delete, got %d", len(all))
 	}
 }
+
+func TestClient_HIncrByBatch(t *testing.T) {
+	mr, redisURL := setupTestRedis(t)
+	defer mr.Close()
+
+	client, err := New(redisURL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	incrs := map[string]int64{"a": 2, "b": 5}
+	if err := client.HIncrByBatch(ctx, "counters", incrs, time.Hour); err != nil {
+		t.Fatalf("HIncrByBatch failed: %v", err)
+	}
+	if err := client.HIncrByBatch(ctx, "counters", map[string]int64{"a": 3}, time.Hour); err != nil {
+		t.Fatalf("HIncrByBatch failed: %v", err)
+	}
+
+	if got := mr.HGet("counters", "a"); got != "5" {
+		t.Errorf("Expected a=5, got %s", got)
+	}
+	if got := mr.HGet("counters", "b"); got != "5" {
+		t.Errorf("Expected b=5, got %s", got)
+	}
+	if ttl := mr.TTL("counters"); ttl != time.Hour {
+		t.Errorf("Expected TTL of 1h, got %v", ttl)
+	}
+
+	if err := client.HIncrByBatch(ctx, "counters", nil, time.Hour); err != nil {
+		t.Errorf("Expected empty batch to be a no-op, got %v", err)
+	}
+}
+
+func TestClient_HGetAllBatch(t *testing.T) {
+	mr, redisURL := setupTestRedis(t)
+	defer mr.Close()
+
+	client, err := New(redisURL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	mr.HSet("h1", "x", "1")
+	mr.HSet("h2", "y", "2")
+
+	results, err := client.HGetAllBatch(context.Background(), []string{"h1", "missing", "h2"})
+	if err != nil {
+		t.Fatalf("HGetAllBatch failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0]["x"] != "1" || len(results[1]) != 0 || results[2]["y"] != "2" {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
+
+func TestClient_HSetFields(t *testing.T) {
+	mr, redisURL := setupTestRedis(t)
+	defer mr.Close()
+
+	client, err := New(redisURL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.HSetFields(context.Background(), "h", map[string]interface{}{"state": "open", "opened_at": 42}); err != nil {
+		t.Fatalf("HSetFields failed: %v", err)
+	}
+	if mr.HGet("h", "state") != "open" || mr.HGet("h", "opened_at") != "42" {
+		t.Errorf("Unexpected hash: state=%s opened_at=%s", mr.HGet("h", "state"), mr.HGet("h", "opened_at"))
+	}
+}
+
+func TestClient_SetNXAndDel(t *testing.T) {
+	mr, redisURL := setupTestRedis(t)
+	defer mr.Close()
+
+	client, err := New(redisURL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if ok, err := client.SetNX(ctx, "lease", "pod-a", time.Minute); err != nil || !ok {
+		t.Fatalf("Expected first SetNX to succeed, got %v, %v", ok, err)
+	}
+	if ok, _ := client.SetNX(ctx, "lease", "pod-b", time.Minute); ok {
+		t.Error("Expected second SetNX to fail while the key exists")
+	}
+	if ttl := mr.TTL("lease"); ttl != time.Minute {
+		t.Errorf("Expected TTL of 1m, got %v", ttl)
+	}
+
+	if err := client.Del(ctx, "lease"); err != nil {
+		t.Fatalf("Del failed: %v", err)
+	}
+	if mr.Exists("lease") {
+		t.Error("Expected key deleted")
+	}
+}
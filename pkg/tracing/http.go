@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for each request, continuing the caller's trace when the
+// request carries a W3C traceparent
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := Tracer().Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		if !span.IsRecording() {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		wrapped := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(wrapped, r.WithContext(ctx))
+		span.SetAttributes(semconv.HTTPResponseStatusCode(wrapped.status))
+		if wrapped.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(wrapped.status))
+		}
+	})
+}
+
+// statusRecorder captures the response status for the server span
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush passes through to the underlying writer when it supports flushing
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Transport traces outbound requests made within a traced operation: each gets a client
+// span with DNS, connect, TLS and time-to-first-byte timings. Requests without a recording
+// parent span pass straight through.
+type Transport struct {
+	Base      http.RoundTripper // Defaults to http.DefaultTransport
+	Component string            // Span name prefix, e.g. "idr" or the bidder code
+	Propagate bool              // Send W3C traceparent (internal services only)
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if !trace.SpanFromContext(req.Context()).IsRecording() {
+		return base.RoundTrip(req)
+	}
+
+	name := req.Method + " " + req.URL.Path
+	if t.Component != "" {
+		name = t.Component + " " + name
+	}
+	ctx, span := Tracer().Start(req.Context(), name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.ServerAddress(req.URL.Hostname()),
+			semconv.URLPath(req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	ctx = httptrace.WithClientTrace(ctx, clientTrace(span))
+	req = req.Clone(ctx)
+	if t.Propagate {
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// clientTrace records connection timings on a client span: durations as attributes and
+// each phase as an event
+func clientTrace(span trace.Span) *httptrace.ClientTrace {
+	start := time.Now()
+	var dnsStart, connectStart, tlsStart time.Time
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+
+	return &httptrace.ClientTrace{
+		GetConn: func(string) { start = time.Now() },
+		GotConn: func(info httptrace.GotConnInfo) {
+			span.SetAttributes(attribute.Bool("http.conn.reused", info.Reused))
+			span.AddEvent("got_conn")
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			span.SetAttributes(attribute.Float64("http.dns_ms", ms(time.Since(dnsStart))))
+			span.AddEvent("dns_done")
+			if info.Err != nil {
+				span.RecordError(info.Err)
+			}
+		},
+		ConnectStart: func(string, string) { connectStart = time.Now() },
+		ConnectDone: func(_, _ string, err error) {
+			span.SetAttributes(attribute.Float64("http.connect_ms", ms(time.Since(connectStart))))
+			span.AddEvent("connect_done")
+			if err != nil {
+				span.RecordError(err)
+			}
+		},
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			span.SetAttributes(attribute.Float64("http.tls_ms", ms(time.Since(tlsStart))))
+			span.AddEvent("tls_done")
+			if err != nil {
+				span.RecordError(err)
+			}
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) { span.AddEvent("wrote_request") },
+		GotFirstResponseByte: func() {
+			span.SetAttributes(attribute.Float64("http.ttfb_ms", ms(time.Since(start))))
+			span.AddEvent("first_byte")
+		},
+	}
+}
@@ -0,0 +1,157 @@
+// Package tracing provides OpenTelemetry tracing for PBS: an OTLP exporter with per-endpoint
+// head sampling, HTTP server middleware and a traced HTTP transport
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer used for all PBS spans
+const instrumentationName = "github.com/thenexusengine/tne_springwire"
+
+// Config holds tracing configuration
+type Config struct {
+	Enabled     bool
+	Endpoint    string // OTLP/HTTP collector host:port
+	Insecure    bool   // Plain HTTP to the collector (local collectors)
+	ServiceName string
+
+	// Head sampling: the share of requests traced, by URL path prefix (longest match wins).
+	// Requests under no prefix use SampleRate.
+	SampleRate          float64
+	EndpointSampleRates map[string]float64
+}
+
+// DefaultConfig returns defaults for a local collector
+func DefaultConfig() *Config {
+	return &Config{
+		Enabled:     false,
+		Endpoint:    "localhost:4318",
+		Insecure:    true,
+		ServiceName: "catalyst",
+		SampleRate:  0.01,
+	}
+}
+
+// Validate checks a tracing configuration
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Endpoint == "" {
+		return fmt.Errorf("tracing endpoint is required")
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return fmt.Errorf("tracing sample rate must be in range 0-1, got %v", c.SampleRate)
+	}
+	for prefix, rate := range c.EndpointSampleRates {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("tracing sample rate path %q must start with /", prefix)
+		}
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("tracing sample rate for %s must be in range 0-1, got %v", prefix, rate)
+		}
+	}
+	return nil
+}
+
+// Init installs the global tracer provider and W3C trace context propagation. The returned
+// function flushes and stops the exporter. When tracing is disabled spans are no-ops.
+func Init(ctx context.Context, config *Config) (func(context.Context) error, error) {
+	if config == nil || !config.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(NewSampler(config)),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the PBS tracer from the global provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// NewSampler returns the head sampler for a configuration: root spans are sampled by their
+// url.path attribute's endpoint rate, child spans follow their local parent. Remote parents'
+// decisions are not trusted, so callers can't force sampling on.
+func NewSampler(config *Config) sdktrace.Sampler {
+	root := newEndpointSampler(config)
+	return sdktrace.ParentBased(root,
+		sdktrace.WithRemoteParentSampled(root),
+		sdktrace.WithRemoteParentNotSampled(root),
+	)
+}
+
+// endpointSampler samples root spans at the rate of their URL path prefix
+type endpointSampler struct {
+	prefixes []string // Longest first
+	samplers map[string]sdktrace.Sampler
+	fallback sdktrace.Sampler
+}
+
+func newEndpointSampler(config *Config) *endpointSampler {
+	s := &endpointSampler{
+		samplers: make(map[string]sdktrace.Sampler, len(config.EndpointSampleRates)),
+		fallback: sdktrace.TraceIDRatioBased(config.SampleRate),
+	}
+	for prefix, rate := range config.EndpointSampleRates {
+		s.prefixes = append(s.prefixes, prefix)
+		s.samplers[prefix] = sdktrace.TraceIDRatioBased(rate)
+	}
+	sort.Slice(s.prefixes, func(i, j int) bool { return len(s.prefixes[i]) > len(s.prefixes[j]) })
+	return s
+}
+
+func (s *endpointSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.sampler(p.Attributes).ShouldSample(p)
+}
+
+// sampler returns the sampler for a span's url.path
+func (s *endpointSampler) sampler(attrs []attribute.KeyValue) sdktrace.Sampler {
+	for _, attr := range attrs {
+		if attr.Key != semconv.URLPathKey {
+			continue
+		}
+		path := attr.Value.AsString()
+		for _, prefix := range s.prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return s.samplers[prefix]
+			}
+		}
+	}
+	return s.fallback
+}
+
+func (s *endpointSampler) Description() string {
+	return "EndpointSampler"
+}
@@ -0,0 +1,237 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// installRecorder installs a tracer provider that records every span
+func installRecorder(t *testing.T, config *Config) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(NewSampler(config)),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+	return recorder
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr bool
+	}{
+		{"disabled", func(c *Config) {}, false},
+		{"enabled", func(c *Config) { c.Enabled = true }, false},
+		{"no endpoint", func(c *Config) { c.Enabled = true; c.Endpoint = "" }, true},
+		{"rate too high", func(c *Config) { c.Enabled = true; c.SampleRate = 1.5 }, true},
+		{"path without slash", func(c *Config) {
+			c.Enabled = true
+			c.EndpointSampleRates = map[string]float64{"openrtb2/auction": 0.1}
+		}, true},
+		{"endpoint rate negative", func(c *Config) {
+			c.Enabled = true
+			c.EndpointSampleRates = map[string]float64{"/openrtb2/auction": -0.1}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultConfig()
+			tt.modify(c)
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInitDisabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), DefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestSampler_EndpointRates(t *testing.T) {
+	sampler := NewSampler(&Config{
+		SampleRate: 0,
+		EndpointSampleRates: map[string]float64{
+			"/openrtb2":         0,
+			"/openrtb2/auction": 1,
+		},
+	})
+
+	sample := func(path string) sdktrace.SamplingDecision {
+		return sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: context.Background(),
+			TraceID:       trace.TraceID{1},
+			Name:          "GET " + path,
+			Attributes:    []attribute.KeyValue{attribute.String("url.path", path)},
+		}).Decision
+	}
+
+	if got := sample("/openrtb2/auction"); got != sdktrace.RecordAndSample {
+		t.Errorf("Expected the longest prefix to sample the auction, got %v", got)
+	}
+	if got := sample("/openrtb2/amp"); got != sdktrace.Drop {
+		t.Errorf("Expected /openrtb2 rate to drop, got %v", got)
+	}
+	if got := sample("/health"); got != sdktrace.Drop {
+		t.Errorf("Expected fallback rate to drop, got %v", got)
+	}
+}
+
+func TestSampler_IgnoresRemoteDecision(t *testing.T) {
+	recorder := installRecorder(t, &Config{SampleRate: 0})
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/openrtb2/auction", nil)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if spans := recorder.Ended(); len(spans) != 0 {
+		t.Errorf("Expected a sampled remote parent not to force sampling, got %d spans", len(spans))
+	}
+}
+
+func TestMiddleware_ContinuesTraceAndRecordsStatus(t *testing.T) {
+	recorder := installRecorder(t, &Config{SampleRate: 1})
+
+	var handlerSpan trace.SpanContext
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/openrtb2/auction", nil)
+	req.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status passed through, got %d", w.Code)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "POST /openrtb2/auction" || span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("Unexpected span %q kind %v", span.Name(), span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("Expected the caller's trace continued, got %s", span.SpanContext().TraceID())
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("Expected the handler context to carry the server span")
+	}
+	if span.Status().Code.String() != "Error" {
+		t.Errorf("Expected a 5xx to mark the span as an error, got %v", span.Status())
+	}
+	if !hasAttr(span.Attributes(), "http.response.status_code", "502") {
+		t.Errorf("Expected the status code attribute, got %v", span.Attributes())
+	}
+}
+
+func TestTransport_TracesAndPropagates(t *testing.T) {
+	recorder := installRecorder(t, &Config{SampleRate: 1})
+
+	var gotTraceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceparent = r.Header.Get("traceparent")
+	}))
+	defer upstream.Close()
+
+	ctx, parent := Tracer().Start(context.Background(), "auction")
+	client := &http.Client{Transport: &Transport{Component: "idr", Propagate: true}}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, upstream.URL+"/api/select", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+
+	var span sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.SpanKind() == trace.SpanKindClient {
+			span = s
+		}
+	}
+	if span == nil {
+		t.Fatal("Expected a client span")
+	}
+	if span.Name() != "idr POST /api/select" || span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Unexpected client span %q parent %s", span.Name(), span.Parent().SpanID())
+	}
+	if !hasAttr(span.Attributes(), "http.connect_ms", "") || !hasAttr(span.Attributes(), "http.ttfb_ms", "") {
+		t.Errorf("Expected connection timings, got %v", span.Attributes())
+	}
+	wantPrefix := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String()
+	if len(gotTraceparent) < len(wantPrefix) || gotTraceparent[:len(wantPrefix)] != wantPrefix {
+		t.Errorf("Expected traceparent %s..., got %q", wantPrefix, gotTraceparent)
+	}
+}
+
+func TestTransport_NoParentOrNoPropagation(t *testing.T) {
+	recorder := installRecorder(t, &Config{SampleRate: 1})
+
+	var gotTraceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceparent = r.Header.Get("traceparent")
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: &Transport{Component: "idr", Propagate: true}}
+	resp, err := client.Get(upstream.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if len(recorder.Ended()) != 0 || gotTraceparent != "" {
+		t.Errorf("Expected untraced requests to pass through, got %d spans, traceparent %q", len(recorder.Ended()), gotTraceparent)
+	}
+
+	// Bidders get a span but never see our trace context
+	ctx, parent := Tracer().Start(context.Background(), "auction")
+	client = &http.Client{Transport: &Transport{Component: "rubicon"}}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	parent.End()
+	if len(recorder.Ended()) != 2 || gotTraceparent != "" {
+		t.Errorf("Expected a client span without propagation, got %d spans, traceparent %q", len(recorder.Ended()), gotTraceparent)
+	}
+}
+
+// hasAttr reports whether attrs has key, with value when value is non-empty
+func hasAttr(attrs []attribute.KeyValue, key, value string) bool {
+	for _, attr := range attrs {
+		if string(attr.Key) == key {
+			return value == "" || attr.Value.Emit() == value
+		}
+	}
+	return false
+}
@@ -25,6 +25,7 @@ var (
 	qps      = flag.Int("qps", 1000, "Target queries per second")
 	duration = flag.Duration("duration", 5*time.Minute, "Test duration")
 	workers  = flag.Int("workers", 100, "Number of concurrent workers")
+	testMode = flag.Bool("test-mode", false, "Send test=1 requests so bidder calls go to the mock bidder")
 )
 
 // Stats tracks load test metrics
@@ -291,8 +292,14 @@ func generateBidRequest() *openrtb.BidRequest {
 	pubID := publisherIDs[rand.Intn(len(publisherIDs))]
 	domain := domains[rand.Intn(len(domains))]
 
+	test := 0
+	if *testMode {
+		test = 1
+	}
+
 	return &openrtb.BidRequest{
-		ID: fmt.Sprintf("req-%d-%d", time.Now().UnixNano(), rand.Intn(100000)),
+		ID:   fmt.Sprintf("req-%d-%d", time.Now().UnixNano(), rand.Intn(100000)),
+		Test: test,
 		Imp: []openrtb.Imp{
 			{
 				ID:       "1",